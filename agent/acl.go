@@ -151,6 +151,25 @@ func (a *Agent) vetCheckUpdateWithAuthorizer(authz acl.Authorizer, checkID struc
 	return nil
 }
 
+// vetCheckReadWithAuthorizer makes sure that a check can be read with the
+// given authorizer.
+func (a *Agent) vetCheckReadWithAuthorizer(authz acl.Authorizer, checkID structs.CheckID) error {
+	var authzContext acl.AuthorizerContext
+	checkID.FillAuthzContext(&authzContext)
+
+	existing := a.State.Check(checkID)
+	if existing == nil {
+		return HTTPError{
+			StatusCode: http.StatusNotFound,
+			Reason:     fmt.Sprintf("Unknown check ID %q. Ensure that the check ID is passed, not the check name.", checkID.String()),
+		}
+	}
+	if len(existing.ServiceName) > 0 {
+		return authz.ToAllowAuthorizer().ServiceReadAllowed(existing.ServiceName, &authzContext)
+	}
+	return authz.ToAllowAuthorizer().NodeReadAllowed(a.config.NodeName, &authzContext)
+}
+
 // filterMembers redacts members that the token doesn't have access to.
 func (a *Agent) filterMembers(token string, members *[]serf.Member) error {
 	// Resolve the token and bail if ACLs aren't enabled.
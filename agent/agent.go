@@ -8,6 +8,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -261,6 +262,12 @@ type Agent struct {
 	// checkUDPs maps the check ID to an associated UDP check
 	checkUDPs map[structs.CheckID]*checks.CheckUDP
 
+	// checkDNSs maps the check ID to an associated DNS check
+	checkDNSs map[structs.CheckID]*checks.CheckDNS
+
+	// checkTLSs maps the check ID to an associated TLS check
+	checkTLSs map[structs.CheckID]*checks.CheckTLS
+
 	// checkGRPCs maps the check ID to an associated GRPC check
 	checkGRPCs map[structs.CheckID]*checks.CheckGRPC
 
@@ -273,6 +280,9 @@ type Agent struct {
 	// checkAliases maps the check ID to an associated Alias checks
 	checkAliases map[structs.CheckID]*checks.CheckAlias
 
+	// checkAggregates maps the check ID to an associated Aggregate checks
+	checkAggregates map[structs.CheckID]*checks.CheckAggregate
+
 	// checkOSServices maps the check ID to an associated OS Service check
 	checkOSServices map[structs.CheckID]*checks.CheckOSService
 
@@ -430,9 +440,12 @@ func New(bd BaseDeps) (*Agent, error) {
 		checkH2PINGs:    make(map[structs.CheckID]*checks.CheckH2PING),
 		checkTCPs:       make(map[structs.CheckID]*checks.CheckTCP),
 		checkUDPs:       make(map[structs.CheckID]*checks.CheckUDP),
+		checkDNSs:       make(map[structs.CheckID]*checks.CheckDNS),
+		checkTLSs:       make(map[structs.CheckID]*checks.CheckTLS),
 		checkGRPCs:      make(map[structs.CheckID]*checks.CheckGRPC),
 		checkDockers:    make(map[structs.CheckID]*checks.CheckDocker),
 		checkAliases:    make(map[structs.CheckID]*checks.CheckAlias),
+		checkAggregates: make(map[structs.CheckID]*checks.CheckAggregate),
 		checkOSServices: make(map[structs.CheckID]*checks.CheckOSService),
 		eventCh:         make(chan serf.UserEvent, 1024),
 		eventBuf:        make([]*UserEvent, 256),
@@ -762,8 +775,16 @@ func (a *Agent) Start(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	dohServers, err := a.listenDNSOverHTTPS()
+	if err != nil {
+		for _, srv := range servers {
+			srv.Shutdown(context.Background())
+		}
+		return err
+	}
+	servers = append(servers, dohServers...)
 
-	// Start HTTP and HTTPS servers.
+	// Start HTTP, HTTPS and DNS over HTTPS servers.
 	for _, srv := range servers {
 		a.apiServers.Start(srv)
 	}
@@ -924,8 +945,13 @@ func (a *Agent) listenAndServeGRPC() error {
 }
 
 func (a *Agent) listenAndServeDNS() error {
-	notif := make(chan net.Addr, len(a.config.DNSAddrs))
-	errCh := make(chan error, len(a.config.DNSAddrs))
+	type started struct {
+		addr    net.Addr
+		network string
+	}
+	numServers := len(a.config.DNSAddrs) + len(a.config.DNSTLSAddrs)
+	notif := make(chan started, numServers)
+	errCh := make(chan error, numServers)
 	for _, addr := range a.config.DNSAddrs {
 		// create server
 		s, err := NewDNSServer(a)
@@ -938,7 +964,24 @@ func (a *Agent) listenAndServeDNS() error {
 		a.wgServers.Add(1)
 		go func(addr net.Addr) {
 			defer a.wgServers.Done()
-			err := s.ListenAndServe(addr.Network(), addr.String(), func() { notif <- addr })
+			err := s.ListenAndServe(addr.Network(), addr.String(), func() { notif <- started{addr, addr.Network()} })
+			if err != nil && !strings.Contains(err.Error(), "accept") {
+				errCh <- err
+			}
+		}(addr)
+	}
+	for _, addr := range a.config.DNSTLSAddrs {
+		s, err := NewDNSServer(a)
+		if err != nil {
+			return err
+		}
+		a.dnsServers = append(a.dnsServers, s)
+
+		a.wgServers.Add(1)
+		go func(addr net.Addr) {
+			defer a.wgServers.Done()
+			tlsConfig := a.tlsConfigurator.IncomingDNSConfig()
+			err := s.ListenAndServeTLS(addr.String(), tlsConfig, func() { notif <- started{addr, "tcp-tls"} })
 			if err != nil && !strings.Contains(err.Error(), "accept") {
 				errCh <- err
 			}
@@ -957,12 +1000,12 @@ func (a *Agent) listenAndServeDNS() error {
 	// wait for servers to be up
 	timeout := time.After(time.Second)
 	var merr *multierror.Error
-	for range a.config.DNSAddrs {
+	for i := 0; i < numServers; i++ {
 		select {
-		case addr := <-notif:
+		case s := <-notif:
 			a.logger.Info("Started DNS server",
-				"address", addr.String(),
-				"network", addr.Network(),
+				"address", s.addr.String(),
+				"network", s.network,
 			)
 
 		case err := <-errCh:
@@ -975,6 +1018,53 @@ func (a *Agent) listenAndServeDNS() error {
 	return merr.ErrorOrNil()
 }
 
+// listenDNSOverHTTPS creates the listeners and unstarted servers for DNS over
+// HTTPS (RFC 8484) queries, see listenHTTP. The servers use the TLS
+// configuration of the HTTPS interface.
+func (a *Agent) listenDNSOverHTTPS() ([]apiServer, error) {
+	if len(a.config.DNSHTTPSAddrs) == 0 {
+		return nil, nil
+	}
+
+	s, err := NewDNSServer(a)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners, err := a.startListeners(a.config.DNSHTTPSAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []apiServer
+	for _, l := range listeners {
+		var tlscfg *tls.Config
+		if _, isTCP := l.(*tcpKeepAliveListener); isTCP {
+			tlscfg = a.tlsConfigurator.IncomingHTTPSConfig()
+			l = tls.NewListener(l, tlscfg)
+		}
+
+		httpServer := &http.Server{
+			Addr:           l.Addr().String(),
+			TLSConfig:      tlscfg,
+			Handler:        s.dnsOverHTTPSHandler(),
+			MaxHeaderBytes: a.config.HTTPMaxHeaderBytes,
+		}
+		connLimitFn := a.httpConnLimiter.HTTPConnStateFuncWithDefault429Handler(10 * time.Millisecond)
+		if err := setupHTTPS(httpServer, connLimitFn, a.config.HTTPSHandshakeTimeout); err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		servers = append(servers, newAPIServerHTTP("dns_https", l, httpServer))
+	}
+
+	// The server only handles queries, the HTTP servers are stopped with the
+	// other apiServers. It is tracked with the DNS servers so that it picks
+	// up reloaded configuration.
+	a.dnsServers = append(a.dnsServers, s)
+	return servers, nil
+}
+
 // startListeners will return a net.Listener for every address unless an
 // error is encountered, in which case it will close all previously opened
 // listeners and return the error.
@@ -1207,20 +1297,19 @@ func (a *Agent) reloadWatches(cfg *config.RuntimeConfig) error {
 			return fmt.Errorf("Handler type '%s' not recognized", params["handler_type"])
 		}
 
-		// Don't let people use connect watches via this mechanism for now as it
-		// needs thought about how to do securely and shouldn't be necessary. Note
-		// that if the type assertion fails an type is not a string then
-		// ParseExample below will error so we don't need to handle that case.
-		if typ, ok := params["type"].(string); ok {
-			if strings.HasPrefix(typ, "connect_") {
-				return fmt.Errorf("Watch type %s is not allowed in agent config", typ)
-			}
-		}
-
 		wp, err := makeWatchPlan(a.logger, params)
 		if err != nil {
 			return err
 		}
+
+		// connect_leaf watches hand the private key of the leaf certificate
+		// to their handler, so don't let it be sent in the clear.
+		if wp.Type == "connect_leaf" && wp.HandlerType == "http" {
+			httpConfig := wp.Exempt["http_handler_config"].(*watch.HttpHandlerConfig)
+			if u, err := url.Parse(httpConfig.Path); err != nil || u.Scheme != "https" {
+				return fmt.Errorf("Watch type connect_leaf requires an https handler path or a script handler")
+			}
+		}
 		watchPlans = append(watchPlans, wp)
 	}
 
@@ -1240,7 +1329,7 @@ func (a *Agent) reloadWatches(cfg *config.RuntimeConfig) error {
 				wp.Handler = makeWatchHandler(a.logger, h)
 			} else {
 				httpConfig := wp.Exempt["http_handler_config"].(*watch.HttpHandlerConfig)
-				wp.Handler = makeHTTPWatchHandler(a.logger, httpConfig)
+				wp.RetryHandler = makeHTTPWatchHandler(a.logger, httpConfig)
 			}
 			wp.Logger = a.logger.Named("watch")
 
@@ -1491,6 +1580,13 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	cfg.RequestLimitsMode = runtimeCfg.RequestLimitsMode.String()
 	cfg.RequestLimitsReadRate = runtimeCfg.RequestLimitsReadRate
 	cfg.RequestLimitsWriteRate = runtimeCfg.RequestLimitsWriteRate
+	cfg.RequestLimitsIPMode = runtimeCfg.RequestLimitsIPMode.String()
+	cfg.RequestLimitsIPReadRate = runtimeCfg.RequestLimitsIPReadRate
+	cfg.RequestLimitsIPWriteRate = runtimeCfg.RequestLimitsIPWriteRate
+	cfg.RequestLimitsIPAllowlist = runtimeCfg.RequestLimitsIPAllowlist
+	cfg.RequestLimitsIPPrefixes = runtimeCfg.RequestLimitsIPPrefixes
+	cfg.RequestLimitsEndpoints = runtimeCfg.RequestLimitsEndpoints
+	cfg.RequestLimitsTokens = runtimeCfg.RequestLimitsTokens
 
 	enterpriseConsulConfig(cfg, runtimeCfg)
 	return cfg, nil
@@ -1626,6 +1722,12 @@ func (a *Agent) ShutdownAgent() error {
 	for _, chk := range a.checkUDPs {
 		chk.Stop()
 	}
+	for _, chk := range a.checkDNSs {
+		chk.Stop()
+	}
+	for _, chk := range a.checkTLSs {
+		chk.Stop()
+	}
 	for _, chk := range a.checkGRPCs {
 		chk.Stop()
 	}
@@ -1635,6 +1737,9 @@ func (a *Agent) ShutdownAgent() error {
 	for _, chk := range a.checkAliases {
 		chk.Stop()
 	}
+	for _, chk := range a.checkAggregates {
+		chk.Stop()
+	}
 	for _, chk := range a.checkH2PINGs {
 		chk.Stop()
 	}
@@ -2843,6 +2948,9 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 		}
 
 		statusHandler := checks.NewStatusHandler(a.State, a.logger, chkType.SuccessBeforePassing, chkType.FailuresBeforeWarning, chkType.FailuresBeforeCritical)
+		if chkType.FlapThreshold > 0 {
+			statusHandler.EnableFlapDetection(chkType.FlapThreshold, chkType.FlapWindow)
+		}
 		sid := check.CompoundServiceID()
 
 		cid := check.CompoundCheckID()
@@ -2891,19 +2999,24 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			tlsClientConfig := a.tlsConfigurator.OutgoingTLSConfigForCheck(chkType.TLSSkipVerify, chkType.TLSServerName)
 
 			http := &checks.CheckHTTP{
-				CheckID:          cid,
-				ServiceID:        sid,
-				HTTP:             chkType.HTTP,
-				Header:           chkType.Header,
-				Method:           chkType.Method,
-				Body:             chkType.Body,
-				DisableRedirects: chkType.DisableRedirects,
-				Interval:         chkType.Interval,
-				Timeout:          chkType.Timeout,
-				Logger:           a.logger,
-				OutputMaxSize:    maxOutputSize,
-				TLSClientConfig:  tlsClientConfig,
-				StatusHandler:    statusHandler,
+				CheckID:             cid,
+				ServiceID:           sid,
+				HTTP:                chkType.HTTP,
+				Header:              chkType.Header,
+				Method:              chkType.Method,
+				Body:                chkType.Body,
+				DisableRedirects:    chkType.DisableRedirects,
+				Interval:            chkType.Interval,
+				Timeout:             chkType.Timeout,
+				Logger:              a.logger,
+				OutputMaxSize:       maxOutputSize,
+				TLSClientConfig:     tlsClientConfig,
+				StatusHandler:       statusHandler,
+				ResponseBodyRegex:   chkType.ResponseBodyRegex,
+				ResponseJSONPath:    chkType.ResponseJSONPath,
+				ResponseJSONValue:   chkType.ResponseJSONValue,
+				ResponseHeaders:     chkType.ResponseHeaders,
+				ResponseTimeWarning: chkType.ResponseTimeWarning,
 			}
 
 			if proxy != nil && proxy.Proxy.Expose.Checks {
@@ -2947,6 +3060,38 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			tcp.Start()
 			a.checkTCPs[cid] = tcp
 
+		case chkType.IsTLS():
+			if existing, ok := a.checkTLSs[cid]; ok {
+				existing.Stop()
+				delete(a.checkTLSs, cid)
+			}
+			if chkType.Interval < checks.MinInterval {
+				a.logger.Warn("check has interval below minimum",
+					"check", cid.String(),
+					"minimum_interval", checks.MinInterval,
+				)
+				chkType.Interval = checks.MinInterval
+			}
+
+			tlsCheck := &checks.CheckTLS{
+				CheckID:         cid,
+				ServiceID:       sid,
+				TLS:             chkType.TLS,
+				ServerName:      chkType.TLSServerName,
+				StartTLS:        chkType.TLSStartTLS,
+				CAFile:          chkType.TLSCAFile,
+				ExpectedSANs:    chkType.TLSExpectedSANs,
+				ExpiryWarning:   chkType.TLSExpiryWarning,
+				ExpiryCritical:  chkType.TLSExpiryCritical,
+				Interval:        chkType.Interval,
+				Timeout:         chkType.Timeout,
+				Logger:          a.logger,
+				TLSClientConfig: a.tlsConfigurator.OutgoingTLSConfigForCheck(chkType.TLSSkipVerify, chkType.TLSServerName),
+				StatusHandler:   statusHandler,
+			}
+			tlsCheck.Start()
+			a.checkTLSs[cid] = tlsCheck
+
 		case chkType.IsUDP():
 			if existing, ok := a.checkUDPs[cid]; ok {
 				existing.Stop()
@@ -2972,6 +3117,37 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			udp.Start()
 			a.checkUDPs[cid] = udp
 
+		case chkType.IsDNS():
+			if existing, ok := a.checkDNSs[cid]; ok {
+				existing.Stop()
+				delete(a.checkDNSs, cid)
+			}
+			if chkType.Interval < checks.MinInterval {
+				a.logger.Warn("check has interval below minimum",
+					"check", cid.String(),
+					"minimum_interval", checks.MinInterval,
+				)
+				chkType.Interval = checks.MinInterval
+			}
+
+			dnsCheck := &checks.CheckDNS{
+				CheckID:        cid,
+				ServiceID:      sid,
+				DNS:            chkType.DNS,
+				Query:          chkType.DNSQuery,
+				RecordType:     chkType.DNSRecordType,
+				Rcode:          chkType.DNSRcode,
+				MinAnswers:     chkType.DNSMinAnswers,
+				ExpectedValues: chkType.DNSExpectedValues,
+				UseTCP:         chkType.DNSUseTCP,
+				Interval:       chkType.Interval,
+				Timeout:        chkType.Timeout,
+				Logger:         a.logger,
+				StatusHandler:  statusHandler,
+			}
+			dnsCheck.Start()
+			a.checkDNSs[cid] = dnsCheck
+
 		case chkType.IsGRPC():
 			if existing, ok := a.checkGRPCs[cid]; ok {
 				existing.Stop()
@@ -3180,6 +3356,25 @@ func (a *Agent) addCheck(check *structs.HealthCheck, chkType *structs.CheckType,
 			chkImpl.Start()
 			a.checkAliases[cid] = chkImpl
 
+		case chkType.IsAggregate():
+			if existing, ok := a.checkAggregates[cid]; ok {
+				existing.Stop()
+				delete(a.checkAggregates, cid)
+			}
+
+			chkImpl := &checks.CheckAggregate{
+				Notify:         a.State,
+				CheckID:        cid,
+				CheckIDs:       chkType.AggregateCheckIDs,
+				ServiceID:      chkType.AggregateService,
+				ServiceTag:     chkType.AggregateServiceTag,
+				Rule:           chkType.AggregateRule,
+				Threshold:      chkType.AggregateThreshold,
+				EnterpriseMeta: check.EnterpriseMeta,
+			}
+			chkImpl.Start()
+			a.checkAggregates[cid] = chkImpl
+
 		default:
 			return fmt.Errorf("Check type is not valid")
 		}
@@ -3308,6 +3503,14 @@ func (a *Agent) cancelCheckMonitors(checkID structs.CheckID) {
 		check.Stop()
 		delete(a.checkUDPs, checkID)
 	}
+	if check, ok := a.checkDNSs[checkID]; ok {
+		check.Stop()
+		delete(a.checkDNSs, checkID)
+	}
+	if check, ok := a.checkTLSs[checkID]; ok {
+		check.Stop()
+		delete(a.checkTLSs, checkID)
+	}
 	if check, ok := a.checkGRPCs[checkID]; ok {
 		check.Stop()
 		delete(a.checkGRPCs, checkID)
@@ -3328,6 +3531,10 @@ func (a *Agent) cancelCheckMonitors(checkID structs.CheckID) {
 		check.Stop()
 		delete(a.checkAliases, checkID)
 	}
+	if check, ok := a.checkAggregates[checkID]; ok {
+		check.Stop()
+		delete(a.checkAggregates, checkID)
+	}
 }
 
 // updateTTLCheck is used to update the status of a TTL check via the Agent API.
@@ -4169,9 +4376,16 @@ func (a *Agent) reloadConfigInternal(newCfg *config.RuntimeConfig) error {
 
 	cc := consul.ReloadableConfig{
 		RequestLimits: &consul.RequestLimits{
-			Mode:      newCfg.RequestLimitsMode,
-			ReadRate:  newCfg.RequestLimitsReadRate,
-			WriteRate: newCfg.RequestLimitsWriteRate,
+			Mode:        newCfg.RequestLimitsMode,
+			ReadRate:    newCfg.RequestLimitsReadRate,
+			WriteRate:   newCfg.RequestLimitsWriteRate,
+			IPMode:      newCfg.RequestLimitsIPMode,
+			IPReadRate:  newCfg.RequestLimitsIPReadRate,
+			IPWriteRate: newCfg.RequestLimitsIPWriteRate,
+			IPAllowlist: newCfg.RequestLimitsIPAllowlist,
+			IPPrefixes:  newCfg.RequestLimitsIPPrefixes,
+			Endpoints:   newCfg.RequestLimitsEndpoints,
+			Tokens:      newCfg.RequestLimitsTokens,
 		},
 		RPCClientTimeout:      newCfg.RPCClientTimeout,
 		RPCRateLimit:          newCfg.RPCRateLimit,
@@ -4291,6 +4505,7 @@ func (a *Agent) registerCache() {
 		RPC:                              a,
 		Cache:                            a.cache,
 		Datacenter:                       a.config.Datacenter,
+		NodeName:                         a.config.NodeName,
 		TestOverrideCAChangeInitialDelay: a.config.ConnectTestCALeafRootChangeSpread,
 	})
 
@@ -939,6 +939,44 @@ func (s *HTTPHandlers) agentCheckUpdate(resp http.ResponseWriter, req *http.Requ
 	return nil, nil
 }
 
+// AgentCheckHistory returns the most recent status transitions of a check
+// registered with the agent, oldest first.
+func (s *HTTPHandlers) AgentCheckHistory(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/agent/check/")
+	id := strings.TrimSuffix(path, "/history")
+	if id == path || id == "" {
+		return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: fmt.Sprintf("Unknown check endpoint %q", req.URL.Path)}
+	}
+
+	entMeta := acl.NewEnterpriseMetaWithPartition(s.agent.config.PartitionOrDefault(), "")
+	cid := structs.NewCheckID(types.CheckID(id), &entMeta)
+
+	// Get the provided token, if any, and vet against any ACL policies.
+	var token string
+	s.parseToken(req, &token)
+
+	if err := s.parseEntMetaNoWildcard(req, &cid.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, &cid.EnterpriseMeta, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cid.Normalize()
+
+	if !s.validateRequestPartition(resp, &cid.EnterpriseMeta) {
+		return nil, nil
+	}
+
+	if err := s.agent.vetCheckReadWithAuthorizer(authz, cid); err != nil {
+		return nil, err
+	}
+
+	return s.agent.State.CheckHistory(cid), nil
+}
+
 // agentHealthService Returns Health for a given service ID
 func agentHealthService(serviceID structs.ServiceID, s *HTTPHandlers) (int, string, api.HealthChecks) {
 	checks := s.agent.State.ChecksForService(serviceID, true)
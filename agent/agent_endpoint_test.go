@@ -798,6 +798,54 @@ func TestAgent_Checks(t *testing.T) {
 	}
 }
 
+func TestAgent_CheckHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	chk1 := &structs.HealthCheck{
+		Node:    a.Config.NodeName,
+		CheckID: "mysql",
+		Name:    "mysql",
+		Status:  api.HealthCritical,
+	}
+	require.NoError(t, a.State.AddCheck(chk1, "", false))
+	a.State.UpdateCheck(chk1.CompoundCheckID(), api.HealthPassing, "ok")
+
+	t.Run("history", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/check/mysql/history", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var val []*api.AgentCheckTransition
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&val))
+		require.Len(t, val, 2)
+		require.Equal(t, api.HealthCritical, val[0].Status)
+		require.Equal(t, api.HealthPassing, val[1].Status)
+		require.Equal(t, "ok", val[1].Output)
+	})
+
+	t.Run("unknown check", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/check/nope/history", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("unknown endpoint", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/check/mysql", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
 func TestAgent_ChecksWithFilter(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -7768,6 +7816,65 @@ func TestAgentConnectAuthorize_deny(t *testing.T) {
 	assert.Contains(t, obj.Reason, "Matched")
 }
 
+func TestAgentConnectAuthorize_revoked(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	target := "db"
+
+	// Create an intention allowing the source
+	{
+		req := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention:  structs.TestIntention(t),
+		}
+		req.Intention.SourceNS = structs.IntentionDefaultNamespace
+		req.Intention.SourceName = "web"
+		req.Intention.DestinationNS = structs.IntentionDefaultNamespace
+		req.Intention.DestinationName = target
+		req.Intention.Action = structs.IntentionActionAllow
+
+		var reply string
+		require.NoError(t, a.RPC(context.Background(), "Intention.Apply", &req, &reply))
+	}
+
+	// Revoke the client certificate
+	{
+		req := structs.CARevokeLeafRequest{
+			Datacenter:  "dc1",
+			Op:          structs.CAOpRevokeLeaf,
+			RevokedLeaf: structs.CARevokedLeaf{SerialNumber: "0a:0b"},
+		}
+		var reply interface{}
+		require.NoError(t, a.RPC(context.Background(), "ConnectCA.RevokeLeaf", &req, &reply))
+	}
+
+	args := &structs.ConnectAuthorizeRequest{
+		Target:           target,
+		ClientCertURI:    connect.TestSpiffeIDService(t, "web").URI().String(),
+		ClientCertSerial: "0a:0b",
+	}
+	retry.Run(t, func(r *retry.R) {
+		req, _ := http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(r, 200, resp.Code)
+
+		obj := &connectAuthorizeResp{}
+		require.NoError(r, json.NewDecoder(resp.Body).Decode(obj))
+		require.False(r, obj.Authorized)
+		require.Contains(r, obj.Reason, "revoked")
+	})
+}
+
 // Test when there is an intention allowing service with a different trust
 // domain. We allow this because migration between trust domains shouldn't cause
 // an outage even if we have stale info about current trusted domains. It's safe
@@ -1768,6 +1768,79 @@ func TestAgent_AddCheck_GRPC(t *testing.T) {
 	requireCheckExistsMap(t, a.checkGRPCs, "grpchealth")
 }
 
+func TestAgent_AddCheck_DNS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	health := &structs.HealthCheck{
+		Node:    "foo",
+		CheckID: "resolver",
+		Name:    "resolver answers",
+		Status:  api.HealthCritical,
+	}
+	chk := &structs.CheckType{
+		DNS:      "127.0.0.1:12345",
+		DNSQuery: "web.example.com",
+		Interval: 15 * time.Second,
+	}
+	err := a.AddCheck(health, chk, false, "", ConfigSourceLocal)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure we have a check mapping
+	sChk := requireCheckExists(t, a, "resolver")
+
+	// Ensure our check is in the right state
+	if sChk.Status != api.HealthCritical {
+		t.Fatalf("check not critical")
+	}
+
+	// Ensure a check is setup
+	requireCheckExistsMap(t, a.checkDNSs, "resolver")
+}
+
+func TestAgent_AddCheck_TLS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	health := &structs.HealthCheck{
+		Node:    "foo",
+		CheckID: "cert",
+		Name:    "certificate expiry",
+		Status:  api.HealthCritical,
+	}
+	chk := &structs.CheckType{
+		TLS:      "localhost:12345",
+		Interval: 15 * time.Second,
+	}
+	err := a.AddCheck(health, chk, false, "", ConfigSourceLocal)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure we have a check mapping
+	sChk := requireCheckExists(t, a, "cert")
+
+	// Ensure our check is in the right state
+	if sChk.Status != api.HealthCritical {
+		t.Fatalf("check not critical")
+	}
+
+	// Ensure a check is setup
+	requireCheckExistsMap(t, a.checkTLSs, "cert")
+}
+
 func TestAgent_RestoreServiceWithAliasCheck(t *testing.T) {
 	// t.Parallel() don't even think about making this parallel
 
@@ -1959,6 +2032,65 @@ func TestAgent_Alias_AddRemove(t *testing.T) {
 	})
 }
 
+func TestAgent_Aggregate_AddRemove(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	cid := structs.NewCheckID("aggregatehealth", nil)
+
+	testutil.RunStep(t, "add checks", func(t *testing.T) {
+		for _, id := range []types.CheckID{"db", "cache"} {
+			health := &structs.HealthCheck{
+				Node:    "foo",
+				CheckID: id,
+				Name:    string(id),
+				Status:  api.HealthPassing,
+			}
+			chk := &structs.CheckType{TTL: time.Minute}
+			require.NoError(t, a.AddCheck(health, chk, false, "", ConfigSourceLocal))
+		}
+
+		health := &structs.HealthCheck{
+			Node:    "foo",
+			CheckID: cid.ID,
+			Name:    "Aggregate health check",
+			Status:  api.HealthCritical,
+		}
+		chk := &structs.CheckType{
+			AggregateCheckIDs: []string{"db", "cache"},
+		}
+		require.NoError(t, a.AddCheck(health, chk, false, "", ConfigSourceLocal))
+		requireCheckExistsMap(t, a.checkAggregates, cid.ID)
+
+		retry.Run(t, func(r *retry.R) {
+			require.Equal(r, api.HealthPassing, a.State.Check(cid).Status)
+		})
+	})
+
+	testutil.RunStep(t, "update selected check", func(t *testing.T) {
+		require.NoError(t, a.updateTTLCheck(structs.NewCheckID("cache", nil), api.HealthCritical, "down"))
+
+		retry.Run(t, func(r *retry.R) {
+			chk := a.State.Check(cid)
+			require.Equal(r, api.HealthCritical, chk.Status)
+			require.Equal(r, "1 of 2 checks critical: cache", chk.Output)
+		})
+	})
+
+	testutil.RunStep(t, "remove check", func(t *testing.T) {
+		require.NoError(t, a.RemoveCheck(cid, false))
+
+		requireCheckMissing(t, a, cid.ID)
+		requireCheckMissingMap(t, a.checkAggregates, cid.ID)
+	})
+}
+
 func TestAgent_AddCheck_Alias_setToken(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -3890,15 +4022,57 @@ func TestAgent_reloadWatches(t *testing.T) {
 		t.Fatalf("bad: %s", err)
 	}
 
-	// Should fail to reload with connect watches
+	// Connect watches are allowed too
 	newConf.Watches = []map[string]interface{}{
 		{
 			"type": "connect_roots",
-			"key":  "asdf",
 			"args": []interface{}{"ls"},
 		},
 	}
-	if err := a.reloadWatches(&newConf); err == nil || !strings.Contains(err.Error(), "not allowed in agent config") {
+	if err := a.reloadWatches(&newConf); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Leaf watches with script handlers are allowed, as the private key never
+	// leaves the host
+	newConf.Watches = []map[string]interface{}{
+		{
+			"type":    "connect_leaf",
+			"service": "web",
+			"args":    []interface{}{"ls"},
+		},
+	}
+	if err := a.reloadWatches(&newConf); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Should fail to reload leaf watches sending the private key over plain http
+	newConf.Watches = []map[string]interface{}{
+		{
+			"type":         "connect_leaf",
+			"service":      "web",
+			"handler_type": "http",
+			"http_handler_config": map[string]interface{}{
+				"path": "http://127.0.0.1:0/leaf",
+			},
+		},
+	}
+	if err := a.reloadWatches(&newConf); err == nil || !strings.Contains(err.Error(), "requires an https handler path") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Leaf watches with https handlers are allowed
+	newConf.Watches = []map[string]interface{}{
+		{
+			"type":         "connect_leaf",
+			"service":      "web",
+			"handler_type": "http",
+			"http_handler_config": map[string]interface{}{
+				"path": "https://127.0.0.1:0/leaf",
+			},
+		},
+	}
+	if err := a.reloadWatches(&newConf); err != nil {
 		t.Fatalf("bad: %s", err)
 	}
 
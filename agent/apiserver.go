@@ -30,7 +30,7 @@ type apiServers struct {
 }
 
 type apiServer struct {
-	// Protocol supported by this server. One of: dns, http, https
+	// Protocol supported by this server. One of: dns, dns_https, http, https
 	Protocol string
 	// Addr the server is listening on
 	Addr net.Addr
@@ -99,7 +99,12 @@ func newAPIServerHTTP(proto string, l net.Listener, httpServer *http.Server) api
 	return apiServer{
 		Protocol: proto,
 		Addr:     l.Addr(),
-		Shutdown: httpServer.Shutdown,
+		Shutdown: func(ctx context.Context) error {
+			err := httpServer.Shutdown(ctx)
+			// Shutdown only closes the listener once the server is started.
+			l.Close()
+			return err
+		},
 		Run: func() error {
 			err := httpServer.Serve(l)
 			if err == nil || err == http.ErrServerClosed {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"testing"
 	"time"
 
@@ -63,3 +64,17 @@ func newAPIServerStub() (apiServer, chan error) {
 		},
 	}, chErr
 }
+
+func TestAPIServerHTTP_ShutdownClosesUnstartedListener(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+
+	srv := newAPIServerHTTP("http", l, &http.Server{})
+	require.NoError(t, srv.Shutdown(context.Background()))
+
+	// The port is free again once the listener is closed.
+	l, err = net.Listen("tcp", addr)
+	require.NoError(t, err)
+	l.Close()
+}
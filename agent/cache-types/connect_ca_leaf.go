@@ -78,6 +78,7 @@ type ConnectCALeaf struct {
 	RPC        RPC          // RPC client for remote requests
 	Cache      *cache.Cache // Cache that has CA root certs via ConnectCARoot
 	Datacenter string       // This agent's datacenter
+	NodeName   string       // This agent's node name
 
 	// TestOverrideCAChangeInitialDelay allows overriding the random jitter after a
 	// root change with a fixed delay. So far ths is only done in tests. If it's
@@ -234,7 +235,11 @@ func (c *ConnectCALeaf) rootWatcher(ctx context.Context) {
 			// don't know if it changed or not, but there is no point waking up all
 			// Fetch calls to check this if we know none of them will need to act on
 			// this update.
-			if oldRoots != nil && oldRoots.ActiveRootID == roots.ActiveRootID {
+			//
+			// A newly revoked leaf also wakes them up so that a revoked cert is
+			// replaced right away.
+			if oldRoots != nil && oldRoots.ActiveRootID == roots.ActiveRootID &&
+				revokedLeafsIndex(oldRoots) == revokedLeafsIndex(roots) {
 				continue
 			}
 
@@ -393,7 +398,7 @@ func (c *ConnectCALeaf) Fetch(opts cache.FetchOptions, req cache.Request) (cache
 		if err != nil {
 			return lastResultWithNewState(), err
 		}
-		if activeRootHasKey(roots, state.authorityKeyID) {
+		if activeRootHasKey(roots, state.authorityKeyID) && roots.LeafRevoked(existing.SerialNumber, "") == nil {
 			return lastResultWithNewState(), nil
 		}
 
@@ -454,6 +459,11 @@ func (c *ConnectCALeaf) Fetch(opts cache.FetchOptions, req cache.Request) (cache
 			// rootsWatcher didn't know about the CA we were signed by. We also rely
 			// on this on every request to do the initial check that the current roots
 			// are the same ones the current cert was signed by.
+			if roots.LeafRevoked(existing.SerialNumber, "") != nil {
+				// Our current cert has been revoked, replace it immediately rather
+				// than jittering since it is no longer trusted by other services.
+				return c.generateNewLeaf(reqReal, lastResultWithNewState())
+			}
 			if activeRootHasKey(roots, state.authorityKeyID) {
 				// Current active CA is the same one that signed our current cert so
 				// keep waiting for a change.
@@ -495,6 +505,18 @@ func activeRootHasKey(roots *structs.IndexedCARoots, currentSigningKeyID string)
 	return false
 }
 
+// revokedLeafsIndex returns the highest modify index of the revoked leafs,
+// which changes whenever a leaf is revoked.
+func revokedLeafsIndex(roots *structs.IndexedCARoots) uint64 {
+	var idx uint64
+	for _, revoked := range roots.RevokedLeafs {
+		if revoked.ModifyIndex > idx {
+			idx = revoked.ModifyIndex
+		}
+	}
+	return idx
+}
+
 func (c *ConnectCALeaf) rootsFromCache() (*structs.IndexedCARoots, error) {
 	// Background is fine here because this isn't a blocking query as no index is set.
 	// Therefore this will just either be a cache hit or return once the non-blocking query returns.
@@ -617,6 +639,7 @@ func (c *ConnectCALeaf) generateNewLeaf(req *ConnectCALeafRequest,
 		WriteRequest: structs.WriteRequest{Token: req.Token},
 		Datacenter:   req.Datacenter,
 		CSR:          csr,
+		Node:         c.NodeName,
 	}
 	if err := c.RPC.RPC(context.Background(), "ConnectCA.Sign", &args, &reply); err != nil {
 		if err.Error() == consul.ErrRateLimited.Error() {
@@ -260,6 +260,97 @@ func TestConnectCALeaf_changingRoots(t *testing.T) {
 	}
 }
 
+// Test that a leaf whose serial number is revoked is replaced immediately.
+func TestConnectCALeaf_revokedLeaf(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	rpc := TestRPC(t)
+	defer rpc.AssertExpectations(t)
+
+	typ, rootsCh := testCALeafType(t, rpc)
+	defer close(rootsCh)
+
+	caRoot := connect.TestCA(t, nil)
+	caRoot.Active = true
+	roots := structs.IndexedCARoots{
+		ActiveRootID: caRoot.ID,
+		TrustDomain:  "fake-trust-domain.consul",
+		Roots: []*structs.CARoot{
+			caRoot,
+		},
+		QueryMeta: structs.QueryMeta{Index: 1},
+	}
+	rootsCh <- roots
+
+	// Instrument ConnectCA.Sign to return signed cert
+	var resp *structs.IssuedCert
+	var idx uint64
+
+	rpc.On("RPC", mock.Anything, "ConnectCA.Sign", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			cIdx := atomic.AddUint64(&idx, 1)
+			reply := args.Get(3).(*structs.IssuedCert)
+			leaf, _ := connect.TestLeaf(t, "web", caRoot)
+			reply.SerialNumber = fmt.Sprintf("%02x", cIdx)
+			reply.CertPEM = leaf
+			reply.ValidAfter = time.Now().Add(-1 * time.Hour)
+			reply.ValidBefore = time.Now().Add(11 * time.Hour)
+			reply.CreateIndex = cIdx
+			reply.ModifyIndex = reply.CreateIndex
+			resp = reply
+		})
+
+	// We'll reuse the fetch options and request
+	opts := cache.FetchOptions{MinIndex: 0, Timeout: 10 * time.Second}
+	req := &ConnectCALeafRequest{Datacenter: "dc1", Service: "web"}
+
+	// First fetch should return immediately
+	fetchCh := TestFetchCh(t, typ, opts, req)
+	select {
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("shouldn't block waiting for fetch")
+	case result := <-fetchCh:
+		v := mustFetchResult(t, result)
+		require.Equal(t, resp, v.Value)
+		require.Equal(t, uint64(1), v.Index)
+		// Set the LastResult for subsequent fetches
+		opts.LastResult = &v
+	}
+
+	// Second fetch should block with set index
+	opts.MinIndex = 1
+	fetchCh = TestFetchCh(t, typ, opts, req)
+	select {
+	case result := <-fetchCh:
+		t.Fatalf("should not return: %#v", result)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Revoke the current leaf, which should trigger the sign req right away
+	// without waiting for the root change jitter.
+	roots.RevokedLeafs = []*structs.CARevokedLeaf{
+		{
+			ID:           "01",
+			SerialNumber: "01",
+			RaftIndex:    structs.RaftIndex{CreateIndex: 2, ModifyIndex: 2},
+		},
+	}
+	roots.QueryMeta.Index = 2
+	rootsCh <- roots
+	select {
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("shouldn't block waiting for fetch")
+	case result := <-fetchCh:
+		v := mustFetchResult(t, result)
+		require.Equal(t, resp, v.Value)
+		require.Equal(t, "02", v.Value.(*structs.IssuedCert).SerialNumber)
+	}
+}
+
 // Tests that if the root change jitter is longer than the time left on the
 // timeout, we return normally but then still renew the cert on a subsequent
 // call.
@@ -0,0 +1,184 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/lib/stringslice"
+)
+
+// CheckAggregate is a check type that derives its health from a set of other
+// checks registered with the local agent. Checks are selected by their ID, by
+// the ID of the service they belong to or by a tag of that service, and the
+// status of the selected checks is combined according to Rule.
+type CheckAggregate struct {
+	CheckID structs.CheckID // ID of this check
+
+	CheckIDs   []string // IDs of the checks to aggregate
+	ServiceID  string   // ID of the service whose checks are aggregated
+	ServiceTag string   // Tag of the services whose checks are aggregated
+
+	Rule      string // One of the structs.Aggregate* rules, defaults to critical-if-any
+	Threshold int    // Number of critical checks tolerated by critical-if-more-than
+
+	Notify AggregateNotifier // For updating the check state
+
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+	stopWg   sync.WaitGroup
+
+	acl.EnterpriseMeta
+}
+
+// AggregateNotifier is a CheckNotifier specifically for the Aggregate check.
+// This requires additional methods that are satisfied by the agent
+// local state.
+type AggregateNotifier interface {
+	CheckNotifier
+
+	AddAggregateCheck(structs.CheckID, chan<- struct{}) error
+	RemoveAggregateCheck(structs.CheckID)
+	Checks(*acl.EnterpriseMeta) map[structs.CheckID]*structs.HealthCheck
+}
+
+// Start is used to start the check, runs until Stop()
+func (c *CheckAggregate) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	c.stopWg.Add(1)
+	go c.run(c.stopCh)
+}
+
+// Stop is used to stop the check.
+func (c *CheckAggregate) Stop() {
+	c.stopLock.Lock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+	c.stopLock.Unlock()
+
+	// Wait until the associated goroutine is definitely complete so that an
+	// old and new check never both update the state of the aggregate check.
+	c.stopWg.Wait()
+}
+
+// run is invoked in a goroutine until Stop() is called.
+func (c *CheckAggregate) run(stopCh chan struct{}) {
+	defer c.stopWg.Done()
+
+	// Buffered as 1 so that we do not lose any queued updates, see
+	// CheckAlias.runLocal.
+	notifyCh := make(chan struct{}, 1)
+	if err := c.Notify.AddAggregateCheck(c.CheckID, notifyCh); err != nil {
+		c.Notify.UpdateCheck(c.CheckID, api.HealthCritical, err.Error())
+		return
+	}
+	defer c.Notify.RemoveAggregateCheck(c.CheckID)
+
+	// maxDurationBetweenUpdates is the maximum time we go between explicit
+	// notifications before we re-evaluate the selected checks anyway.
+	const maxDurationBetweenUpdates = 1 * time.Minute
+
+	for {
+		checks := c.Notify.Checks(&c.EnterpriseMeta)
+		status, output := c.evaluate(c.selectChecks(checks))
+		c.Notify.UpdateCheck(c.CheckID, status, output)
+
+		select {
+		case <-time.After(maxDurationBetweenUpdates):
+		case <-notifyCh:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// selectChecks returns the checks matching any of the selectors, sorted by
+// check ID. The aggregate check itself is never selected.
+func (c *CheckAggregate) selectChecks(checks map[structs.CheckID]*structs.HealthCheck) []*structs.HealthCheck {
+	var selected []*structs.HealthCheck
+	for id, chk := range checks {
+		if id == c.CheckID {
+			continue
+		}
+		switch {
+		case stringslice.Contains(c.CheckIDs, string(chk.CheckID)):
+		case c.ServiceID != "" && chk.ServiceID == c.ServiceID:
+		case c.ServiceTag != "" && chk.ServiceID != "" && stringslice.Contains(chk.ServiceTags, c.ServiceTag):
+		default:
+			continue
+		}
+		selected = append(selected, chk)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].CheckID < selected[j].CheckID
+	})
+	return selected
+}
+
+// evaluate applies the aggregation rule to the selected checks and returns
+// the resulting status and output.
+func (c *CheckAggregate) evaluate(checks []*structs.HealthCheck) (string, string) {
+	if len(checks) == 0 {
+		return api.HealthCritical, "No checks matched the aggregate selector."
+	}
+
+	var passing int
+	var warning, critical []string
+	for _, chk := range checks {
+		switch chk.Status {
+		case api.HealthPassing:
+			passing++
+		case api.HealthWarning:
+			warning = append(warning, string(chk.CheckID))
+		default:
+			critical = append(critical, string(chk.CheckID))
+		}
+	}
+	total := len(checks)
+	failing := append(append([]string{}, critical...), warning...)
+
+	switch c.Rule {
+	case structs.AggregateCriticalIfMoreThan:
+		if len(critical) > c.Threshold {
+			return api.HealthCritical, fmt.Sprintf("%d of %d checks critical, more than %d: %s",
+				len(critical), total, c.Threshold, strings.Join(critical, ", "))
+		}
+		if len(failing) > 0 {
+			return api.HealthWarning, fmt.Sprintf("%d of %d checks failing: %s",
+				len(failing), total, strings.Join(failing, ", "))
+		}
+
+	case structs.AggregateWarningIfQuorumLost:
+		quorum := total/2 + 1
+		if passing < quorum {
+			return api.HealthWarning, fmt.Sprintf("Quorum lost, %d of %d checks passing, %d needed: %s failing",
+				passing, total, quorum, strings.Join(failing, ", "))
+		}
+		if len(failing) > 0 {
+			return api.HealthPassing, fmt.Sprintf("Quorum held, %d of %d checks passing: %s failing",
+				passing, total, strings.Join(failing, ", "))
+		}
+
+	default:
+		if len(critical) > 0 {
+			return api.HealthCritical, fmt.Sprintf("%d of %d checks critical: %s",
+				len(critical), total, strings.Join(critical, ", "))
+		}
+		if len(warning) > 0 {
+			return api.HealthWarning, fmt.Sprintf("%d of %d checks warning: %s",
+				len(warning), total, strings.Join(warning, ", "))
+		}
+	}
+	return api.HealthPassing, fmt.Sprintf("All %d checks passing.", total)
+}
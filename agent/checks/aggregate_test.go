@@ -0,0 +1,258 @@
+package checks
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/mock"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/types"
+)
+
+func TestCheckAggregate_selectChecks(t *testing.T) {
+	checks := map[structs.CheckID]*structs.HealthCheck{}
+	for _, chk := range []*structs.HealthCheck{
+		{CheckID: "self"},
+		{CheckID: "node"},
+		{CheckID: "web", ServiceID: "web", ServiceTags: []string{"frontend"}},
+		{CheckID: "db", ServiceID: "db-sidecar", ServiceTags: []string{"backend"}},
+		{CheckID: "cache", ServiceID: "cache", ServiceTags: []string{"backend"}},
+	} {
+		checks[chk.CompoundCheckID()] = chk
+	}
+
+	tests := []struct {
+		desc  string
+		check *CheckAggregate
+		want  []types.CheckID
+	}{
+		{
+			desc:  "by check ID",
+			check: &CheckAggregate{CheckIDs: []string{"node", "web", "missing"}},
+			want:  []types.CheckID{"node", "web"},
+		},
+		{
+			desc:  "by service",
+			check: &CheckAggregate{ServiceID: "db-sidecar"},
+			want:  []types.CheckID{"db"},
+		},
+		{
+			desc:  "by service tag",
+			check: &CheckAggregate{ServiceTag: "backend"},
+			want:  []types.CheckID{"cache", "db"},
+		},
+		{
+			desc:  "union of selectors",
+			check: &CheckAggregate{CheckIDs: []string{"node"}, ServiceID: "web", ServiceTag: "backend"},
+			want:  []types.CheckID{"cache", "db", "node", "web"},
+		},
+		{
+			desc:  "excludes itself",
+			check: &CheckAggregate{CheckIDs: []string{"self", "node"}},
+			want:  []types.CheckID{"node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tt.check.CheckID = structs.NewCheckID("self", nil)
+
+			var got []types.CheckID
+			for _, chk := range tt.check.selectChecks(checks) {
+				got = append(got, chk.CheckID)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckAggregate_evaluate(t *testing.T) {
+	statuses := func(s ...string) []*structs.HealthCheck {
+		var checks []*structs.HealthCheck
+		for i, status := range s {
+			checks = append(checks, &structs.HealthCheck{
+				CheckID: types.CheckID(string(rune('a' + i))),
+				Status:  status,
+			})
+		}
+		return checks
+	}
+	const (
+		passing  = api.HealthPassing
+		warning  = api.HealthWarning
+		critical = api.HealthCritical
+	)
+
+	tests := []struct {
+		desc      string
+		rule      string
+		threshold int
+		checks    []*structs.HealthCheck
+		status    string
+		output    string
+	}{
+		{
+			desc:   "no checks",
+			checks: nil,
+			status: critical,
+			output: "No checks matched the aggregate selector.",
+		},
+		{
+			desc:   "critical if any, all passing",
+			checks: statuses(passing, passing),
+			status: passing,
+			output: "All 2 checks passing.",
+		},
+		{
+			desc:   "critical if any, warning",
+			rule:   structs.AggregateCriticalIfAny,
+			checks: statuses(passing, warning),
+			status: warning,
+			output: "1 of 2 checks warning: b",
+		},
+		{
+			desc:   "critical if any, critical",
+			checks: statuses(warning, critical, critical),
+			status: critical,
+			output: "2 of 3 checks critical: b, c",
+		},
+		{
+			desc:      "critical if more than, within threshold",
+			rule:      structs.AggregateCriticalIfMoreThan,
+			threshold: 1,
+			checks:    statuses(passing, critical, warning),
+			status:    warning,
+			output:    "2 of 3 checks failing: b, c",
+		},
+		{
+			desc:      "critical if more than, above threshold",
+			rule:      structs.AggregateCriticalIfMoreThan,
+			threshold: 1,
+			checks:    statuses(critical, critical, passing),
+			status:    critical,
+			output:    "2 of 3 checks critical, more than 1: a, b",
+		},
+		{
+			desc:      "critical if more than, all passing",
+			rule:      structs.AggregateCriticalIfMoreThan,
+			threshold: 1,
+			checks:    statuses(passing),
+			status:    passing,
+			output:    "All 1 checks passing.",
+		},
+		{
+			desc:   "quorum held",
+			rule:   structs.AggregateWarningIfQuorumLost,
+			checks: statuses(passing, critical, passing),
+			status: passing,
+			output: "Quorum held, 2 of 3 checks passing: b failing",
+		},
+		{
+			desc:   "quorum lost",
+			rule:   structs.AggregateWarningIfQuorumLost,
+			checks: statuses(passing, critical, warning, passing),
+			status: warning,
+			output: "Quorum lost, 2 of 4 checks passing, 3 needed: b, c failing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := &CheckAggregate{Rule: tt.rule, Threshold: tt.threshold}
+			status, output := c.evaluate(tt.checks)
+			require.Equal(t, tt.status, status)
+			require.Equal(t, tt.output, output)
+		})
+	}
+}
+
+func TestCheckAggregate_local(t *testing.T) {
+	t.Parallel()
+
+	notify := newMockAggregateNotify()
+	notify.setCheck(&structs.HealthCheck{CheckID: "db", ServiceID: "db", Status: api.HealthPassing})
+	notify.setCheck(&structs.HealthCheck{CheckID: "cache", ServiceID: "cache", Status: api.HealthPassing})
+
+	chkID := structs.NewCheckID("web-deps", nil)
+	chk := &CheckAggregate{
+		CheckID:  chkID,
+		CheckIDs: []string{"db", "cache"},
+		Notify:   notify,
+	}
+	chk.Start()
+	defer chk.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notify.State(chkID), api.HealthPassing; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+	})
+
+	notify.setCheck(&structs.HealthCheck{CheckID: "cache", ServiceID: "cache", Status: api.HealthCritical})
+	retry.Run(t, func(r *retry.R) {
+		if got, want := notify.State(chkID), api.HealthCritical; got != want {
+			r.Fatalf("got state %q want %q", got, want)
+		}
+		if got, want := notify.Output(chkID), "1 of 2 checks critical: cache"; got != want {
+			r.Fatalf("got output %q want %q", got, want)
+		}
+	})
+}
+
+// mockAggregateNotify is an AggregateNotifier which notifies the registered
+// aggregate checks whenever a check is set.
+type mockAggregateNotify struct {
+	*mock.Notify
+
+	lock      sync.Mutex
+	checks    map[structs.CheckID]*structs.HealthCheck
+	notifyChs map[structs.CheckID]chan<- struct{}
+}
+
+func newMockAggregateNotify() *mockAggregateNotify {
+	return &mockAggregateNotify{
+		Notify:    mock.NewNotify(),
+		checks:    make(map[structs.CheckID]*structs.HealthCheck),
+		notifyChs: make(map[structs.CheckID]chan<- struct{}),
+	}
+}
+
+func (m *mockAggregateNotify) setCheck(chk *structs.HealthCheck) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.checks[chk.CompoundCheckID()] = chk
+	for _, ch := range m.notifyChs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (m *mockAggregateNotify) AddAggregateCheck(chkID structs.CheckID, ch chan<- struct{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.notifyChs[chkID] = ch
+	return nil
+}
+
+func (m *mockAggregateNotify) RemoveAggregateCheck(chkID structs.CheckID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.notifyChs, chkID)
+}
+
+func (m *mockAggregateNotify) Checks(*acl.EnterpriseMeta) map[structs.CheckID]*structs.HealthCheck {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	checks := make(map[structs.CheckID]*structs.HealthCheck, len(m.checks))
+	for id, chk := range m.checks {
+		checks[id] = chk
+	}
+	return checks
+}
@@ -4,13 +4,18 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/textproto"
 	"os"
 	osexec "os/exec"
+	"regexp"
 	"strings"
 	"sync"
 	"syscall"
@@ -20,11 +25,14 @@ import (
 
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/go-hclog"
+	"github.com/miekg/dns"
 
 	"github.com/armon/circbuf"
 	"github.com/hashicorp/consul/agent/exec"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/lib/jsonpath"
+	"github.com/hashicorp/consul/lib/stringslice"
 	"github.com/hashicorp/go-cleanhttp"
 )
 
@@ -42,6 +50,14 @@ const (
 	// UserAgent is the value of the User-Agent header
 	// for HTTP health checks.
 	UserAgent = "Consul Health Check"
+
+	// maxAssertedBodySize is the maximum size of an HTTP response body
+	// which is matched against the body assertions of a check.
+	maxAssertedBodySize = 1024 * 1024 // 1MB
+
+	// DefaultFlapWindow is the window over which status changes are counted
+	// for flap detection if none is configured.
+	DefaultFlapWindow = 10 * time.Minute
 )
 
 // RPC is an interface that an RPC client must implement. This is a helper
@@ -330,9 +346,11 @@ func (c *CheckTTL) SetStatus(status, output string) string {
 // CheckHTTP is used to periodically make an HTTP request to
 // determine the health of a given check.
 // The check is passing if the response code is 2XX.
-// The check is warning if the response code is 429.
-// The check is critical if the response code is anything else
-// or if the request returns an error
+// The check is warning if the response code is 429, or if a 2XX
+// response takes longer than ResponseTimeWarning.
+// The check is critical if the response code is anything else,
+// if the request returns an error or if a 2XX response fails
+// any of the response assertions.
 // Supports failures_before_critical and success_before_passing.
 type CheckHTTP struct {
 	CheckID          structs.CheckID
@@ -349,6 +367,22 @@ type CheckHTTP struct {
 	StatusHandler    *StatusHandler
 	DisableRedirects bool
 
+	// Assertions on a 2XX response. The body must match ResponseBodyRegex,
+	// the value at ResponseJSONPath must exist and equal ResponseJSONValue
+	// if set, and each of the ResponseHeaders must be present with each of
+	// its values.
+	ResponseBodyRegex   string
+	ResponseJSONPath    string
+	ResponseJSONValue   string
+	ResponseHeaders     map[string][]string
+	ResponseTimeWarning time.Duration
+
+	// The assertions compiled when the check is started, or the error
+	// compiling them.
+	bodyRegex    *regexp.Regexp
+	jsonPath     jsonpath.Path
+	assertionErr error
+
 	httpClient *http.Client
 	stop       bool
 	stopCh     chan struct{}
@@ -407,6 +441,7 @@ func (c *CheckHTTP) Start() {
 			c.OutputMaxSize = DefaultBufSize
 		}
 	}
+	c.compileAssertions()
 
 	c.stop = false
 	c.stopCh = make(chan struct{})
@@ -414,6 +449,29 @@ func (c *CheckHTTP) Start() {
 	go c.run()
 }
 
+// compileAssertions compiles the body assertions once rather than on every
+// run of the check. They are validated when the check is registered, so an
+// error here is only reported as a failed assertion.
+func (c *CheckHTTP) compileAssertions() {
+	c.bodyRegex, c.jsonPath, c.assertionErr = nil, nil, nil
+	if c.ResponseBodyRegex != "" {
+		re, err := regexp.Compile(c.ResponseBodyRegex)
+		if err != nil {
+			c.assertionErr = fmt.Errorf("invalid body regex: %v", err)
+			return
+		}
+		c.bodyRegex = re
+	}
+	if c.ResponseJSONPath != "" {
+		path, err := jsonpath.Parse(c.ResponseJSONPath)
+		if err != nil {
+			c.assertionErr = err
+			return
+		}
+		c.jsonPath = path
+	}
+}
+
 // Stop is used to stop an HTTP check.
 func (c *CheckHTTP) Stop() {
 	c.stopLock.Lock()
@@ -481,6 +539,7 @@ func (c *CheckHTTP) check() {
 		req.Header.Set("Accept", "text/plain, text/*, */*")
 	}
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		c.StatusHandler.updateCheck(c.CheckID, api.HealthCritical, err.Error())
@@ -488,21 +547,42 @@ func (c *CheckHTTP) check() {
 	}
 	defer resp.Body.Close()
 
-	// Read the response into a circular buffer to limit the size
+	// Read the response into a circular buffer to limit the size. The start
+	// of the body is also kept to match the body assertions against.
 	output, _ := circbuf.NewBuffer(int64(c.OutputMaxSize))
-	if _, err := io.Copy(output, resp.Body); err != nil {
+	var body []byte
+	if c.ResponseBodyRegex != "" || c.ResponseJSONPath != "" {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxAssertedBodySize))
+		output.Write(body)
+	}
+	if err == nil {
+		_, err = io.Copy(output, resp.Body)
+	}
+	if err != nil {
 		c.Logger.Warn("Check error while reading body",
 			"check", c.CheckID.String(),
 			"error", err,
 		)
 	}
+	elapsed := time.Since(start)
 
 	// Format the response body
-	result := fmt.Sprintf("HTTP %s %s: %s Output: %s", method, target, resp.Status, output.String())
+	prefix := fmt.Sprintf("HTTP %s %s: %s", method, target, resp.Status)
+	result := fmt.Sprintf("%s Output: %s", prefix, output.String())
 
 	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
-		// PASSING (2xx)
-		c.StatusHandler.updateCheck(c.CheckID, api.HealthPassing, result)
+		if err := c.assertResponse(resp.Header, body); err != nil {
+			// CRITICAL
+			c.StatusHandler.updateCheck(c.CheckID, api.HealthCritical,
+				fmt.Sprintf("%s Assertion failed: %s Output: %s", prefix, err, output.String()))
+		} else if c.ResponseTimeWarning > 0 && elapsed > c.ResponseTimeWarning {
+			// WARNING
+			c.StatusHandler.updateCheck(c.CheckID, api.HealthWarning,
+				fmt.Sprintf("%s Response time %s exceeded %s Output: %s", prefix, elapsed.Round(time.Millisecond), c.ResponseTimeWarning, output.String()))
+		} else {
+			// PASSING (2xx)
+			c.StatusHandler.updateCheck(c.CheckID, api.HealthPassing, result)
+		}
 	} else if resp.StatusCode == 429 {
 		// WARNING
 		// 429 Too Many Requests (RFC 6585)
@@ -514,6 +594,51 @@ func (c *CheckHTTP) check() {
 	}
 }
 
+// assertResponse checks the headers and body of a response against the
+// response assertions.
+func (c *CheckHTTP) assertResponse(header http.Header, body []byte) error {
+	if c.assertionErr != nil {
+		return c.assertionErr
+	}
+	for name, values := range c.ResponseHeaders {
+		got := header.Values(name)
+		if len(got) == 0 {
+			return fmt.Errorf("header %q is missing", name)
+		}
+		for _, v := range values {
+			if !stringslice.Contains(got, v) {
+				return fmt.Errorf("header %q does not have value %q", name, v)
+			}
+		}
+	}
+
+	if c.bodyRegex != nil && !c.bodyRegex.Match(body) {
+		return fmt.Errorf("body does not match %q", c.ResponseBodyRegex)
+	}
+
+	if c.ResponseJSONPath != "" {
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Errorf("body is not valid JSON: %v", err)
+		}
+		v, ok := c.jsonPath.Eval(doc)
+		if !ok {
+			return fmt.Errorf("JSONPath %s not found in body", c.ResponseJSONPath)
+		}
+		if c.ResponseJSONValue != "" {
+			got, ok := v.(string)
+			if !ok {
+				raw, _ := json.Marshal(v)
+				got = string(raw)
+			}
+			if got != c.ResponseJSONValue {
+				return fmt.Errorf("JSONPath %s is %q, expected %q", c.ResponseJSONPath, got, c.ResponseJSONValue)
+			}
+		}
+	}
+	return nil
+}
+
 type CheckH2PING struct {
 	CheckID         structs.CheckID
 	ServiceID       structs.ServiceID
@@ -704,6 +829,293 @@ func (c *CheckTCP) check() {
 	c.StatusHandler.updateCheck(c.CheckID, api.HealthPassing, fmt.Sprintf("TCP connect %s: Success", c.TCP))
 }
 
+const (
+	// defaultTLSExpiryWarning and defaultTLSExpiryCritical are the windows
+	// before a certificate expires in which a TLS check is set to warning
+	// and critical respectively.
+	defaultTLSExpiryWarning  = 30 * 24 * time.Hour
+	defaultTLSExpiryCritical = 7 * 24 * time.Hour
+)
+
+// CheckTLS is used to periodically perform a TLS handshake to determine the
+// health of the certificate served at a given address.
+// The check is passing if the handshake succeeds, the certificate chain
+// verifies, the leaf certificate has each of the expected SANs and it
+// doesn't expire within ExpiryWarning.
+// The check is warning if the leaf certificate expires within ExpiryWarning.
+// The check is critical if it expires within ExpiryCritical or any of the
+// other assertions fail.
+// Supports failures_before_critical and success_before_passing.
+type CheckTLS struct {
+	CheckID         structs.CheckID
+	ServiceID       structs.ServiceID
+	TLS             string
+	ServerName      string
+	StartTLS        string
+	CAFile          string
+	ExpectedSANs    []string
+	ExpiryWarning   time.Duration
+	ExpiryCritical  time.Duration
+	Interval        time.Duration
+	Timeout         time.Duration
+	Logger          hclog.Logger
+	TLSClientConfig *tls.Config
+	StatusHandler   *StatusHandler
+
+	dialer   *net.Dialer
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+}
+
+// Start is used to start a TLS check.
+// The check runs until stop is called
+func (c *CheckTLS) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+
+	if c.dialer == nil {
+		// Create the socket dialer
+		c.dialer = &net.Dialer{
+			Timeout: 10 * time.Second,
+		}
+		if c.Timeout > 0 {
+			c.dialer.Timeout = c.Timeout
+		}
+	}
+
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	go c.run()
+}
+
+// Stop is used to stop a TLS check.
+func (c *CheckTLS) Stop() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+}
+
+// run is invoked by a goroutine to run until Stop() is called
+func (c *CheckTLS) run() {
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// check is invoked periodically to perform the TLS check
+func (c *CheckTLS) check() {
+	status, output := c.doCheck()
+	if status != api.HealthPassing {
+		c.Logger.Warn("Check TLS certificate failed",
+			"check", c.CheckID.String(),
+			"status", status,
+			"output", output,
+		)
+	}
+	c.StatusHandler.updateCheck(c.CheckID, status, output)
+}
+
+func (c *CheckTLS) doCheck() (string, string) {
+	serverName := c.ServerName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(c.TLS)
+		if err != nil {
+			return api.HealthCritical, err.Error()
+		}
+		serverName = host
+	}
+
+	var cfg *tls.Config
+	if c.TLSClientConfig != nil {
+		cfg = c.TLSClientConfig.Clone()
+	} else {
+		cfg = &tls.Config{}
+	}
+	skipVerify := cfg.InsecureSkipVerify
+	roots := cfg.RootCAs
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return api.HealthCritical, fmt.Sprintf("Failed to read CA file: %s", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return api.HealthCritical, fmt.Sprintf("Failed to parse CA file %s: no certificates found", c.CAFile)
+		}
+	}
+
+	// The chain is verified below rather than during the handshake, so that
+	// an expired certificate can be reported as such.
+	cfg.ServerName = serverName
+	cfg.InsecureSkipVerify = true
+
+	conn, err := c.dialer.Dial("tcp", c.TLS)
+	if err != nil {
+		return api.HealthCritical, err.Error()
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.dialer.Timeout))
+
+	switch c.StartTLS {
+	case "":
+	case "smtp":
+		err = startTLSSMTP(conn)
+	case "postgres":
+		err = startTLSPostgres(conn)
+	default:
+		err = fmt.Errorf("Unsupported STARTTLS protocol %q", c.StartTLS)
+	}
+	if err != nil {
+		return api.HealthCritical, fmt.Sprintf("STARTTLS %s: %s", c.StartTLS, err)
+	}
+
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.Handshake(); err != nil {
+		return api.HealthCritical, fmt.Sprintf("TLS handshake with %s failed: %s", c.TLS, err)
+	}
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return api.HealthCritical, fmt.Sprintf("TLS handshake with %s: no certificates presented", c.TLS)
+	}
+	leaf := certs[0]
+	now := time.Now()
+
+	if !skipVerify {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			DNSName:       serverName,
+			CurrentTime:   now,
+		})
+		if err != nil {
+			return api.HealthCritical, fmt.Sprintf("TLS certificate for %s failed verification: %s", c.TLS, err)
+		}
+	}
+
+	if missing := missingSANs(leaf, c.ExpectedSANs); len(missing) > 0 {
+		return api.HealthCritical, fmt.Sprintf("TLS certificate for %s is missing SANs: %s", c.TLS, strings.Join(missing, ", "))
+	}
+
+	warning, critical := c.ExpiryWarning, c.ExpiryCritical
+	if warning == 0 {
+		warning = defaultTLSExpiryWarning
+	}
+	if critical == 0 {
+		critical = defaultTLSExpiryCritical
+	}
+
+	remaining := leaf.NotAfter.Sub(now)
+	output := fmt.Sprintf("TLS certificate for %s (CN=%s) expires at %s, in %s",
+		c.TLS, leaf.Subject.CommonName, leaf.NotAfter.UTC().Format(time.RFC3339), remaining.Round(time.Minute))
+	switch {
+	case now.Before(leaf.NotBefore):
+		return api.HealthCritical, fmt.Sprintf("TLS certificate for %s (CN=%s) is not valid until %s",
+			c.TLS, leaf.Subject.CommonName, leaf.NotBefore.UTC().Format(time.RFC3339))
+	case remaining <= 0:
+		return api.HealthCritical, fmt.Sprintf("TLS certificate for %s (CN=%s) expired at %s",
+			c.TLS, leaf.Subject.CommonName, leaf.NotAfter.UTC().Format(time.RFC3339))
+	case remaining <= critical:
+		return api.HealthCritical, output
+	case remaining <= warning:
+		return api.HealthWarning, output
+	}
+	return api.HealthPassing, output
+}
+
+// missingSANs returns the expected subject alternative names which aren't in
+// the certificate. DNS names are compared without case.
+func missingSANs(cert *x509.Certificate, expected []string) []string {
+	var missing []string
+	for _, san := range expected {
+		found := false
+		for _, name := range cert.DNSNames {
+			if strings.EqualFold(name, san) {
+				found = true
+			}
+		}
+		for _, ip := range cert.IPAddresses {
+			if ip.Equal(net.ParseIP(san)) {
+				found = true
+			}
+		}
+		for _, uri := range cert.URIs {
+			if uri.String() == san {
+				found = true
+			}
+		}
+		for _, email := range cert.EmailAddresses {
+			if email == san {
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, san)
+		}
+	}
+	return missing
+}
+
+// startTLSSMTP upgrades an SMTP connection to TLS using the STARTTLS command.
+func startTLSSMTP(conn net.Conn) error {
+	text := textproto.NewConn(conn)
+	if _, _, err := text.ReadResponse(220); err != nil {
+		return err
+	}
+	if err := text.PrintfLine("EHLO localhost"); err != nil {
+		return err
+	}
+	if _, _, err := text.ReadResponse(250); err != nil {
+		return err
+	}
+	if err := text.PrintfLine("STARTTLS"); err != nil {
+		return err
+	}
+	_, _, err := text.ReadResponse(220)
+	return err
+}
+
+// startTLSPostgres upgrades a PostgreSQL connection to TLS by sending an
+// SSLRequest message.
+func startTLSPostgres(conn net.Conn) error {
+	// The message is its length followed by the SSLRequest code.
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req[0:4], 8)
+	binary.BigEndian.PutUint32(req[4:8], 80877103)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return err
+	}
+	switch resp[0] {
+	case 'S':
+		return nil
+	case 'N':
+		return errors.New("server does not support SSL")
+	default:
+		return fmt.Errorf("unexpected response %q to SSLRequest", resp[0])
+	}
+}
+
 // CheckUDP is used to periodically send a UDP datagram to determine the health of a given check.
 // The check is passing if the connection succeeds, the response is bytes.Equal to the bytes passed
 // in or if the error returned is a timeout error
@@ -833,6 +1245,179 @@ func (c *CheckUDP) check() {
 	}
 }
 
+// CheckDNS is used to periodically query a DNS server to determine the
+// health of a given check.
+// The check is passing if the response has the expected rcode, at least
+// MinAnswers answers and, when ExpectedValues is set, an answer matching
+// each of the expected values.
+// The check is critical if the query fails or any of these assertions fail.
+// Supports failures_before_critical and success_before_passing.
+type CheckDNS struct {
+	CheckID        structs.CheckID
+	ServiceID      structs.ServiceID
+	DNS            string
+	Query          string
+	RecordType     string
+	Rcode          string
+	MinAnswers     int
+	ExpectedValues []string
+	UseTCP         bool
+	Interval       time.Duration
+	Timeout        time.Duration
+	Logger         hclog.Logger
+	StatusHandler  *StatusHandler
+
+	client   *dns.Client
+	stop     bool
+	stopCh   chan struct{}
+	stopLock sync.Mutex
+}
+
+// Start is used to start a DNS check.
+// The check runs until stop is called
+func (c *CheckDNS) Start() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+
+	if c.client == nil {
+		c.client = &dns.Client{
+			Net:     "udp",
+			Timeout: 10 * time.Second,
+		}
+		if c.UseTCP {
+			c.client.Net = "tcp"
+		}
+		if c.Timeout > 0 {
+			c.client.Timeout = c.Timeout
+		}
+	}
+
+	c.stop = false
+	c.stopCh = make(chan struct{})
+	go c.run()
+}
+
+// Stop is used to stop a DNS check.
+func (c *CheckDNS) Stop() {
+	c.stopLock.Lock()
+	defer c.stopLock.Unlock()
+	if !c.stop {
+		c.stop = true
+		close(c.stopCh)
+	}
+}
+
+// run is invoked by a goroutine to run until Stop() is called
+func (c *CheckDNS) run() {
+	// Get the randomized initial pause time
+	initialPauseTime := lib.RandomStagger(c.Interval)
+	next := time.After(initialPauseTime)
+	for {
+		select {
+		case <-next:
+			c.check()
+			next = time.After(c.Interval)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// check is invoked periodically to perform the DNS check
+func (c *CheckDNS) check() {
+	output, err := c.doCheck()
+	if err != nil {
+		c.Logger.Warn("Check DNS query failed",
+			"check", c.CheckID.String(),
+			"error", err,
+		)
+		c.StatusHandler.updateCheck(c.CheckID, api.HealthCritical, err.Error())
+		return
+	}
+	c.StatusHandler.updateCheck(c.CheckID, api.HealthPassing, output)
+}
+
+func (c *CheckDNS) doCheck() (string, error) {
+	recordType := strings.ToUpper(c.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+	qtype, ok := dns.StringToType[recordType]
+	if !ok {
+		return "", fmt.Errorf("Unknown DNS record type %q", c.RecordType)
+	}
+	rcodeName := strings.ToUpper(c.Rcode)
+	if rcodeName == "" {
+		rcodeName = "NOERROR"
+	}
+	rcode, ok := dns.StringToRcode[rcodeName]
+	if !ok {
+		return "", fmt.Errorf("Unknown DNS rcode %q", c.Rcode)
+	}
+
+	// The resolver defaults to the standard DNS port.
+	addr := c.DNS
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "53")
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(c.Query), qtype)
+
+	resp, _, err := c.client.Exchange(msg, addr)
+	if err == nil && resp.Truncated && c.client.Net == "udp" {
+		// Retry over TCP to get the full answer section.
+		tcp := &dns.Client{Net: "tcp", Timeout: c.client.Timeout}
+		resp, _, err = tcp.Exchange(msg, addr)
+	}
+	if err != nil {
+		return "", fmt.Errorf("DNS query %s %s to %s failed: %v", msg.Question[0].Name, recordType, addr, err)
+	}
+
+	prefix := fmt.Sprintf("DNS query %s %s to %s", msg.Question[0].Name, recordType, addr)
+	if resp.Rcode != rcode {
+		return "", fmt.Errorf("%s: expected rcode %s, got %s", prefix, rcodeName, dns.RcodeToString[resp.Rcode])
+	}
+	if len(resp.Answer) < c.MinAnswers {
+		return "", fmt.Errorf("%s: expected at least %d answers, got %d", prefix, c.MinAnswers, len(resp.Answer))
+	}
+
+	values := make([]string, 0, len(resp.Answer))
+	for _, rr := range resp.Answer {
+		values = append(values, dnsAnswerValue(rr))
+	}
+	for _, expected := range c.ExpectedValues {
+		if !containsDNSValue(values, expected) {
+			return "", fmt.Errorf("%s: expected answer %q not found in %s", prefix, expected, strings.Join(values, ", "))
+		}
+	}
+
+	return fmt.Sprintf("%s: %s with %d answers: %s", prefix, rcodeName, len(resp.Answer), strings.Join(values, ", ")), nil
+}
+
+// dnsAnswerValue returns the data of a resource record without its header,
+// such as the address of an A record or the target of a CNAME record. The
+// strings of a TXT record are joined without quoting.
+func dnsAnswerValue(rr dns.RR) string {
+	if txt, ok := rr.(*dns.TXT); ok {
+		return strings.Join(txt.Txt, "")
+	}
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+// containsDNSValue reports whether the expected value is one of the answer
+// values. Names are compared case-insensitively, with or without the
+// trailing dot.
+func containsDNSValue(values []string, expected string) bool {
+	expected = strings.TrimSuffix(expected, ".")
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSuffix(v, "."), expected) {
+			return true
+		}
+	}
+	return false
+}
+
 // CheckDocker is used to periodically invoke a script to
 // determine the health of an application running inside a
 // Docker Container. We assume that the script is compatible
@@ -1169,6 +1754,9 @@ func (c *CheckOSService) check() {
 // StatusHandler keep tracks of successive error/success counts and ensures
 // that status can be set to critical/passing only once the successive number of event
 // reaches the given threshold.
+//
+// When flap detection is enabled, it also holds the check in warning while
+// its status changes more than flapThreshold times within flapWindow.
 type StatusHandler struct {
 	inner                  CheckNotifier
 	logger                 hclog.Logger
@@ -1177,6 +1765,11 @@ type StatusHandler struct {
 	failuresBeforeWarning  int
 	failuresBeforeCritical int
 	failuresCounter        int
+
+	flapThreshold int
+	flapWindow    time.Duration
+	lastStatus    string
+	transitions   []time.Time
 }
 
 // NewStatusHandler set counters values to threshold in order to immediatly update status after first check.
@@ -1192,6 +1785,17 @@ func NewStatusHandler(inner CheckNotifier, logger hclog.Logger, successBeforePas
 	}
 }
 
+// EnableFlapDetection holds the check in warning while its status changes
+// more than threshold times within window. The window defaults to
+// DefaultFlapWindow.
+func (s *StatusHandler) EnableFlapDetection(threshold int, window time.Duration) {
+	if window <= 0 {
+		window = DefaultFlapWindow
+	}
+	s.flapThreshold = threshold
+	s.flapWindow = window
+}
+
 func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output string) {
 
 	if status == api.HealthPassing || status == api.HealthWarning {
@@ -1202,7 +1806,7 @@ func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output stri
 				"check", checkID.String(),
 				"status", status,
 			)
-			s.inner.UpdateCheck(checkID, status, output)
+			s.report(checkID, status, output)
 			return
 		}
 		s.logger.Warn("Check passed but has not reached success threshold",
@@ -1216,13 +1820,13 @@ func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output stri
 		s.successCounter = 0
 		if s.failuresCounter >= s.failuresBeforeCritical {
 			s.logger.Warn("Check is now critical", "check", checkID.String())
-			s.inner.UpdateCheck(checkID, status, output)
+			s.report(checkID, status, output)
 			return
 		}
 		// Defaults to same value as failuresBeforeCritical if not set.
 		if s.failuresCounter >= s.failuresBeforeWarning {
 			s.logger.Warn("Check is now warning", "check", checkID.String())
-			s.inner.UpdateCheck(checkID, api.HealthWarning, output)
+			s.report(checkID, api.HealthWarning, output)
 			return
 		}
 		s.logger.Warn("Check failed but has not reached warning/failure threshold",
@@ -1234,3 +1838,40 @@ func (s *StatusHandler) updateCheck(checkID structs.CheckID, status, output stri
 		)
 	}
 }
+
+// report passes the status on to the inner notifier, replacing it with a
+// warning while the check is flapping.
+func (s *StatusHandler) report(checkID structs.CheckID, status, output string) {
+	if s.flapThreshold <= 0 {
+		s.inner.UpdateCheck(checkID, status, output)
+		return
+	}
+
+	now := time.Now()
+	if s.lastStatus != "" && s.lastStatus != status {
+		s.transitions = append(s.transitions, now)
+	}
+	s.lastStatus = status
+
+	// Forget the transitions which are outside of the window.
+	cutoff := now.Add(-s.flapWindow)
+	i := 0
+	for i < len(s.transitions) && s.transitions[i].Before(cutoff) {
+		i++
+	}
+	s.transitions = s.transitions[i:]
+
+	if len(s.transitions) > s.flapThreshold {
+		s.logger.Warn("Check is flapping",
+			"check", checkID.String(),
+			"status", status,
+			"transitions", len(s.transitions),
+			"flap_threshold", s.flapThreshold,
+			"flap_window", s.flapWindow,
+		)
+		output = fmt.Sprintf("Check is flapping, %d status changes in the last %s. Current status %s: %s",
+			len(s.transitions), s.flapWindow, status, output)
+		status = api.HealthWarning
+	}
+	s.inner.UpdateCheck(checkID, status, output)
+}
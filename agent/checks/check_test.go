@@ -3,12 +3,20 @@ package checks
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -18,6 +26,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-uuid"
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -28,6 +37,7 @@ import (
 	"github.com/hashicorp/consul/sdk/freeport"
 	"github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/tlsutil"
 )
 
 func uniqueID() string {
@@ -503,6 +513,148 @@ func TestCheckHTTP_DisableRedirects(t *testing.T) {
 	})
 }
 
+func TestCheckHTTP_ResponseAssertions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("X-Role", "leader")
+		w.Header().Add("X-Role", "voter")
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		fmt.Fprint(w, `{"status":"ok","members":[{"name":"a","healthy":true},{"name":"b","healthy":false}]}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		desc   string
+		path   string
+		check  *CheckHTTP
+		status string
+		output string
+	}{
+		{
+			desc:   "no assertions",
+			check:  &CheckHTTP{},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "body regex matches",
+			check:  &CheckHTTP{ResponseBodyRegex: `"status":\s*"ok"`},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "body regex does not match",
+			check:  &CheckHTTP{ResponseBodyRegex: `"status":\s*"degraded"`},
+			status: api.HealthCritical,
+			output: "Assertion failed: body does not match",
+		},
+		{
+			desc:   "json path exists",
+			check:  &CheckHTTP{ResponseJSONPath: "$.members[1].name"},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "json path missing",
+			check:  &CheckHTTP{ResponseJSONPath: "$.members[2].name"},
+			status: api.HealthCritical,
+			output: "Assertion failed: JSONPath $.members[2].name not found in body",
+		},
+		{
+			desc:   "json path root",
+			check:  &CheckHTTP{ResponseJSONPath: "$"},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "invalid json path",
+			check:  &CheckHTTP{ResponseJSONPath: "$.members[0"},
+			status: api.HealthCritical,
+			output: "Assertion failed: JSONPath \"$.members[0\" has an unterminated [",
+		},
+		{
+			desc:   "json path string value",
+			check:  &CheckHTTP{ResponseJSONPath: "$.status", ResponseJSONValue: "ok"},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "json path bool value",
+			check:  &CheckHTTP{ResponseJSONPath: "$.members[-1]['healthy']", ResponseJSONValue: "true"},
+			status: api.HealthCritical,
+			output: `Assertion failed: JSONPath $.members[-1]['healthy'] is "false", expected "true"`,
+		},
+		{
+			desc:   "headers present",
+			check:  &CheckHTTP{ResponseHeaders: map[string][]string{"Content-Type": nil, "X-Role": {"voter"}}},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "header missing",
+			check:  &CheckHTTP{ResponseHeaders: map[string][]string{"X-Version": nil}},
+			status: api.HealthCritical,
+			output: `Assertion failed: header "X-Version" is missing`,
+		},
+		{
+			desc:   "header value missing",
+			check:  &CheckHTTP{ResponseHeaders: map[string][]string{"X-Role": {"follower"}}},
+			status: api.HealthCritical,
+			output: `Assertion failed: header "X-Role" does not have value "follower"`,
+		},
+		{
+			desc:   "response time within threshold",
+			check:  &CheckHTTP{ResponseTimeWarning: 5 * time.Second},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "response time exceeds threshold",
+			path:   "/slow",
+			check:  &CheckHTTP{ResponseTimeWarning: 10 * time.Millisecond},
+			status: api.HealthWarning,
+			output: "exceeded 10ms",
+		},
+		{
+			desc:   "failed assertion takes precedence over response time",
+			path:   "/slow",
+			check:  &CheckHTTP{ResponseBodyRegex: "degraded", ResponseTimeWarning: 10 * time.Millisecond},
+			status: api.HealthCritical,
+			output: "Assertion failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			notif := mock.NewNotify()
+			logger := testutil.Logger(t)
+			cid := structs.NewCheckID("foo", nil)
+
+			check := &CheckHTTP{
+				CheckID:             cid,
+				HTTP:                server.URL + tt.path,
+				OutputMaxSize:       DefaultBufSize,
+				Interval:            10 * time.Millisecond,
+				Logger:              logger,
+				StatusHandler:       NewStatusHandler(notif, logger, 0, 0, 0),
+				ResponseBodyRegex:   tt.check.ResponseBodyRegex,
+				ResponseJSONPath:    tt.check.ResponseJSONPath,
+				ResponseJSONValue:   tt.check.ResponseJSONValue,
+				ResponseHeaders:     tt.check.ResponseHeaders,
+				ResponseTimeWarning: tt.check.ResponseTimeWarning,
+			}
+			check.Start()
+			defer check.Stop()
+
+			retry.Run(t, func(r *retry.R) {
+				if got, want := notif.State(cid), tt.status; got != want {
+					r.Fatalf("got state %q want %q", got, want)
+				}
+				if got, want := notif.Output(cid), tt.output; !strings.Contains(got, want) {
+					r.Fatalf("got output %q want %q", got, want)
+				}
+			})
+		})
+	}
+}
+
 func TestCheckHTTPTCP_BigTimeout(t *testing.T) {
 	testCases := []struct {
 		timeoutIn, intervalIn, timeoutWant time.Duration
@@ -1123,6 +1275,38 @@ func TestStatusHandlerMaintainWarningStatusWhenCheckIsFlapping(t *testing.T) {
 	})
 }
 
+func TestStatusHandlerFlapDetection(t *testing.T) {
+	t.Parallel()
+	cid := structs.NewCheckID("foo", nil)
+	notif := mock.NewNotify()
+	logger := testutil.Logger(t)
+	statusHandler := NewStatusHandler(notif, logger, 0, 0, 0)
+	statusHandler.EnableFlapDetection(2, time.Minute)
+
+	// Two status changes are tolerated.
+	statusHandler.updateCheck(cid, api.HealthPassing, "up")
+	statusHandler.updateCheck(cid, api.HealthCritical, "down")
+	require.Equal(t, api.HealthCritical, notif.State(cid))
+	statusHandler.updateCheck(cid, api.HealthPassing, "up")
+	require.Equal(t, api.HealthPassing, notif.State(cid))
+
+	// The third one within the window holds the check in warning.
+	statusHandler.updateCheck(cid, api.HealthCritical, "down")
+	require.Equal(t, api.HealthWarning, notif.State(cid))
+	require.Equal(t, "Check is flapping, 3 status changes in the last 1m0s. Current status critical: down", notif.Output(cid))
+
+	statusHandler.updateCheck(cid, api.HealthPassing, "up")
+	require.Equal(t, api.HealthWarning, notif.State(cid))
+
+	// Once the transitions are outside of the window the real status is
+	// reported again.
+	statusHandler.flapWindow = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	statusHandler.updateCheck(cid, api.HealthPassing, "up")
+	require.Equal(t, api.HealthPassing, notif.State(cid))
+	require.Equal(t, "up", notif.Output(cid))
+}
+
 func TestCheckTCPCritical(t *testing.T) {
 	t.Parallel()
 	var (
@@ -1155,6 +1339,197 @@ func TestCheckTCPPassing(t *testing.T) {
 	tcpServer.Close()
 }
 
+// mockTLSServer serves a certificate signed by a new CA, valid for the given
+// number of days. It returns the server address and the path of the CA file.
+// When startTLS is set the server expects the protocol's STARTTLS exchange
+// before the handshake.
+func mockTLSServer(t *testing.T, days int, startTLS string) (string, string) {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca, _, err := tlsutil.GenerateCA(tlsutil.CAOpts{Signer: signer})
+	require.NoError(t, err)
+	certPEM, keyPEM, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+		Signer:      signer,
+		CA:          ca,
+		Name:        "web",
+		Days:        days,
+		DNSNames:    []string{"localhost", "web.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	require.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte(ca), 0600))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				switch startTLS {
+				case "postgres":
+					if _, err := io.ReadFull(conn, make([]byte, 8)); err != nil {
+						return
+					}
+					conn.Write([]byte("S"))
+				case "smtp":
+					text := textproto.NewConn(conn)
+					text.PrintfLine("220 localhost ESMTP")
+					text.ReadLine()
+					text.PrintfLine("250-localhost")
+					text.PrintfLine("250 STARTTLS")
+					text.ReadLine()
+					text.PrintfLine("220 Ready to start TLS")
+				}
+				tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}})
+				tlsConn.Handshake()
+			}()
+		}
+	}()
+	return ln.Addr().String(), caFile
+}
+
+func TestCheckTLS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		days     int
+		startTLS string
+		check    *CheckTLS
+		status   string
+		output   string
+	}{
+		{
+			desc:   "passing",
+			days:   90,
+			check:  &CheckTLS{ExpectedSANs: []string{"web.example.com", "127.0.0.1"}},
+			status: api.HealthPassing,
+			output: "(CN=web) expires at",
+		},
+		{
+			desc:   "expiry warning",
+			days:   10,
+			check:  &CheckTLS{},
+			status: api.HealthWarning,
+		},
+		{
+			desc:   "expiry critical",
+			days:   3,
+			check:  &CheckTLS{},
+			status: api.HealthCritical,
+		},
+		{
+			desc:   "custom expiry windows",
+			days:   10,
+			check:  &CheckTLS{ExpiryWarning: 5 * 24 * time.Hour, ExpiryCritical: 24 * time.Hour},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "missing SAN",
+			days:   90,
+			check:  &CheckTLS{ExpectedSANs: []string{"db.example.com"}},
+			status: api.HealthCritical,
+			output: "missing SANs: db.example.com",
+		},
+		{
+			desc:   "server name mismatch",
+			days:   90,
+			check:  &CheckTLS{ServerName: "db.example.com"},
+			status: api.HealthCritical,
+			output: "failed verification",
+		},
+		{
+			desc:     "postgres",
+			days:     90,
+			startTLS: "postgres",
+			check:    &CheckTLS{StartTLS: "postgres"},
+			status:   api.HealthPassing,
+		},
+		{
+			desc:     "smtp",
+			days:     90,
+			startTLS: "smtp",
+			check:    &CheckTLS{StartTLS: "smtp"},
+			status:   api.HealthPassing,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			addr, caFile := mockTLSServer(t, tt.days, tt.startTLS)
+
+			notif := mock.NewNotify()
+			logger := testutil.Logger(t)
+			cid := structs.NewCheckID("foo", nil)
+
+			check := tt.check
+			check.CheckID = cid
+			check.TLS = addr
+			check.CAFile = caFile
+			check.Interval = 10 * time.Millisecond
+			check.Timeout = time.Second
+			check.Logger = logger
+			check.StatusHandler = NewStatusHandler(notif, logger, 0, 0, 0)
+			check.Start()
+			defer check.Stop()
+
+			retry.Run(t, func(r *retry.R) {
+				if got, want := notif.State(cid), tt.status; got != want {
+					r.Fatalf("got state %q want %q", got, want)
+				}
+				if got := notif.Output(cid); !strings.Contains(got, tt.output) {
+					r.Fatalf("got output %q want %q", got, tt.output)
+				}
+			})
+		})
+	}
+}
+
+func TestCheckTLS_Untrusted(t *testing.T) {
+	t.Parallel()
+
+	addr, _ := mockTLSServer(t, 90, "")
+
+	for _, skipVerify := range []bool{false, true} {
+		notif := mock.NewNotify()
+		logger := testutil.Logger(t)
+		cid := structs.NewCheckID("foo", nil)
+
+		check := &CheckTLS{
+			CheckID:         cid,
+			TLS:             addr,
+			Interval:        10 * time.Millisecond,
+			Logger:          logger,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: skipVerify},
+			StatusHandler:   NewStatusHandler(notif, logger, 0, 0, 0),
+		}
+		check.Start()
+
+		want := api.HealthCritical
+		if skipVerify {
+			want = api.HealthPassing
+		}
+		retry.Run(t, func(r *retry.R) {
+			if got := notif.State(cid); got != want {
+				r.Fatalf("got state %q want %q", got, want)
+			}
+		})
+		check.Stop()
+	}
+}
+
 func sendResponse(conn *net.UDPConn, addr *net.UDPAddr) {
 	_, err := conn.WriteToUDP([]byte("healthy"), addr)
 	if err != nil {
@@ -1301,6 +1676,127 @@ func TestCheckUDPPassing(t *testing.T) {
 	expectUDPStatus(t, serverUrl, api.HealthPassing)
 }
 
+func mockDNSServer(t *testing.T) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		q := req.Question[0]
+		switch {
+		case q.Name == "web.example." && q.Qtype == dns.TypeA:
+			for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+				resp.Answer = append(resp.Answer, &dns.A{
+					Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 30},
+					A:   net.ParseIP(ip),
+				})
+			}
+		case q.Name == "web.example." && q.Qtype == dns.TypeTXT:
+			resp.Answer = append(resp.Answer, &dns.TXT{
+				Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 30},
+				Txt: []string{"v=1"},
+			})
+		case q.Name != "web.example.":
+			resp.SetRcode(req, dns.RcodeNameError)
+		}
+		w.WriteMsg(resp)
+	})
+
+	server := &dns.Server{PacketConn: pc, Handler: mux}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+	return pc.LocalAddr().String()
+}
+
+func TestCheckDNS(t *testing.T) {
+	t.Parallel()
+
+	addr := mockDNSServer(t)
+
+	tests := []struct {
+		desc   string
+		check  *CheckDNS
+		status string
+		output string
+	}{
+		{
+			desc:   "passing",
+			check:  &CheckDNS{Query: "web.example"},
+			status: api.HealthPassing,
+			output: "NOERROR with 2 answers: 10.0.0.1, 10.0.0.2",
+		},
+		{
+			desc:   "expected values",
+			check:  &CheckDNS{Query: "web.example", MinAnswers: 2, ExpectedValues: []string{"10.0.0.2"}},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "txt record",
+			check:  &CheckDNS{Query: "web.example", RecordType: "txt", ExpectedValues: []string{"v=1"}},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "expected nxdomain",
+			check:  &CheckDNS{Query: "db.example", Rcode: "NXDOMAIN"},
+			status: api.HealthPassing,
+		},
+		{
+			desc:   "unexpected rcode",
+			check:  &CheckDNS{Query: "db.example"},
+			status: api.HealthCritical,
+			output: "expected rcode NOERROR, got NXDOMAIN",
+		},
+		{
+			desc:   "too few answers",
+			check:  &CheckDNS{Query: "web.example", MinAnswers: 3},
+			status: api.HealthCritical,
+			output: "expected at least 3 answers, got 2",
+		},
+		{
+			desc:   "missing expected value",
+			check:  &CheckDNS{Query: "web.example", ExpectedValues: []string{"10.0.0.3"}},
+			status: api.HealthCritical,
+			output: `expected answer "10.0.0.3" not found`,
+		},
+		{
+			desc:   "unknown record type",
+			check:  &CheckDNS{Query: "web.example", RecordType: "BOGUS"},
+			status: api.HealthCritical,
+			output: `Unknown DNS record type "BOGUS"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			notif := mock.NewNotify()
+			logger := testutil.Logger(t)
+			cid := structs.NewCheckID("foo", nil)
+
+			check := tt.check
+			check.CheckID = cid
+			check.DNS = addr
+			check.Interval = 10 * time.Millisecond
+			check.Timeout = time.Second
+			check.Logger = logger
+			check.StatusHandler = NewStatusHandler(notif, logger, 0, 0, 0)
+			check.Start()
+			defer check.Stop()
+
+			retry.Run(t, func(r *retry.R) {
+				if got, want := notif.State(cid), tt.status; got != want {
+					r.Fatalf("got state %q want %q", got, want)
+				}
+				if got := notif.Output(cid); !strings.Contains(got, tt.output) {
+					r.Fatalf("got output %q want %q", got, tt.output)
+				}
+			})
+		})
+	}
+}
+
 func TestCheckH2PING(t *testing.T) {
 	t.Parallel()
 
@@ -22,6 +22,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-sockaddr/template"
+	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/memberlist"
 	"golang.org/x/time/rate"
 
@@ -403,6 +404,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		}
 	}
 
+	dnsZoneTransferKeys := b.dnsZoneTransferKeysVal(c.DNS.ZoneTransferKeys)
+
 	leaveOnTerm := !boolVal(c.ServerMode)
 	if c.LeaveOnTerm != nil {
 		leaveOnTerm = boolVal(c.LeaveOnTerm)
@@ -439,6 +442,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 
 	// determine port values and replace values <= 0 and > 65535 with -1
 	dnsPort := b.portVal("ports.dns", c.Ports.DNS)
+	dnsTLSPort := b.portVal("ports.dns_tls", c.Ports.DNSTLS)
+	dnsHTTPSPort := b.portVal("ports.dns_https", c.Ports.DNSHTTPS)
 	httpPort := b.portVal("ports.http", c.Ports.HTTP)
 	httpsPort := b.portVal("ports.https", c.Ports.HTTPS)
 	serverPort := b.portVal("ports.server", c.Ports.Server)
@@ -575,6 +580,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		b.warn("client_addr is empty, client services (DNS, HTTP, HTTPS, GRPC) will not be listening for connections")
 	}
 	dnsAddrs := b.makeAddrs(b.expandAddrs("addresses.dns", c.Addresses.DNS), clientAddrs, dnsPort)
+	dnsTLSAddrs := b.makeAddrs(b.expandAddrs("addresses.dns_tls", c.Addresses.DNSTLS), clientAddrs, dnsTLSPort)
+	dnsHTTPSAddrs := b.makeAddrs(b.expandAddrs("addresses.dns_https", c.Addresses.DNSHTTPS), clientAddrs, dnsHTTPSPort)
 	httpAddrs := b.makeAddrs(b.expandAddrs("addresses.http", c.Addresses.HTTP), clientAddrs, httpPort)
 	httpsAddrs := b.makeAddrs(b.expandAddrs("addresses.https", c.Addresses.HTTPS), clientAddrs, httpsPort)
 	grpcAddrs := b.makeAddrs(b.expandAddrs("addresses.grpc", c.Addresses.GRPC), clientAddrs, grpcPort)
@@ -729,6 +736,17 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 			"existing_arn":   "ExistingARN",
 			"delete_on_exit": "DeleteOnExit",
 
+			// PKCS#11 CA config
+			"token_label":  "TokenLabel",
+			"token_serial": "TokenSerial",
+			"key_label":    "KeyLabel",
+
+			// ACME CA config
+			"directory_url":       "DirectoryURL",
+			"eab_key_id":          "EABKeyID",
+			"eab_hmac_key":        "EABHMACKey",
+			"http_challenge_addr": "HTTPChallengeAddr",
+
 			// Common CA config
 			"leaf_cert_ttl":      "LeafCertTTL",
 			"csr_max_per_second": "CSRMaxPerSecond",
@@ -885,6 +903,8 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 
 		// DNS
 		DNSAddrs:              dnsAddrs,
+		DNSTLSAddrs:           dnsTLSAddrs,
+		DNSHTTPSAddrs:         dnsHTTPSAddrs,
 		DNSAllowStale:         boolVal(c.DNS.AllowStale),
 		DNSARecordLimit:       intVal(c.DNS.ARecordLimit),
 		DNSDisableCompression: boolVal(c.DNS.DisableCompression),
@@ -895,11 +915,14 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		DNSNodeTTL:            b.durationVal("dns_config.node_ttl", c.DNS.NodeTTL),
 		DNSOnlyPassing:        boolVal(c.DNS.OnlyPassing),
 		DNSPort:               dnsPort,
+		DNSTLSPort:            dnsTLSPort,
+		DNSHTTPSPort:          dnsHTTPSPort,
 		DNSRecursorStrategy:   b.dnsRecursorStrategyVal(stringVal(c.DNS.RecursorStrategy)),
 		DNSRecursorTimeout:    b.durationVal("recursor_timeout", c.DNS.RecursorTimeout),
 		DNSRecursors:          dnsRecursors,
 		DNSServiceTTL:         dnsServiceTTL,
 		DNSSOA:                soa,
+		DNSZoneTransferKeys:   dnsZoneTransferKeys,
 		DNSUDPAnswerLimit:     intVal(c.DNS.UDPAnswerLimit),
 		DNSNodeMetaTXT:        boolValWithDefault(c.DNS.NodeMetaTXT, true),
 		DNSUseCache:           boolVal(c.DNS.UseCache),
@@ -1058,6 +1081,13 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		RequestLimitsMode:                 b.requestsLimitsModeVal(stringVal(c.Limits.RequestLimits.Mode)),
 		RequestLimitsReadRate:             limitVal(c.Limits.RequestLimits.ReadRate),
 		RequestLimitsWriteRate:            limitVal(c.Limits.RequestLimits.WriteRate),
+		RequestLimitsIPMode:               b.requestsLimitsModeValWithName("limits.request_limits.ip_limits.mode", stringVal(c.Limits.RequestLimits.IPLimits.Mode)),
+		RequestLimitsIPReadRate:           limitVal(c.Limits.RequestLimits.IPLimits.ReadRate),
+		RequestLimitsIPWriteRate:          limitVal(c.Limits.RequestLimits.IPLimits.WriteRate),
+		RequestLimitsIPAllowlist:          b.ipNetsVal("limits.request_limits.ip_limits.allowlist", c.Limits.RequestLimits.IPLimits.Allowlist),
+		RequestLimitsIPPrefixes:           b.ipPrefixRequestLimitsVal(c.Limits.RequestLimits.IPLimits.Prefixes),
+		RequestLimitsEndpoints:            b.endpointRequestLimitsVal(c.Limits.RequestLimits.Endpoints),
+		RequestLimitsTokens:               b.tokenRequestLimitsVal(c.Limits.RequestLimits.Tokens),
 		RetryJoinIntervalLAN:              b.durationVal("retry_interval", c.RetryJoinIntervalLAN),
 		RetryJoinIntervalWAN:              b.durationVal("retry_interval_wan", c.RetryJoinIntervalWAN),
 		RetryJoinLAN:                      b.expandAllOptionalAddrs("retry_join", c.RetryJoinLAN),
@@ -1291,6 +1321,16 @@ func (b *builder) validate(rt RuntimeConfig) error {
 			return fmt.Errorf("DNS address cannot be a unix socket")
 		}
 	}
+	for _, a := range rt.DNSTLSAddrs {
+		if _, ok := a.(*net.UnixAddr); ok {
+			return fmt.Errorf("DNS over TLS address cannot be a unix socket")
+		}
+	}
+	// DNS over TLS and HTTPS are served with the certificate of the HTTPS
+	// interface, so every handshake would fail without one.
+	if (len(rt.DNSTLSAddrs) > 0 || len(rt.DNSHTTPSAddrs) > 0) && rt.TLS.HTTPS.CertFile == "" && !rt.TLS.AutoTLS {
+		return fmt.Errorf("ports.dns_tls and ports.dns_https require a TLS certificate, set tls.https.cert_file or tls.defaults.cert_file")
+	}
 	for _, a := range rt.DNSRecursors {
 		if ipaddr.IsAny(a) {
 			return fmt.Errorf("DNS recursor address cannot be 0.0.0.0, :: or [::]")
@@ -1392,6 +1432,12 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		// we leave this for consistency
 		return err
 	}
+	if err := addrsUnique(inuse, "DNS over TLS", rt.DNSTLSAddrs); err != nil {
+		return err
+	}
+	if err := addrsUnique(inuse, "DNS over HTTPS", rt.DNSHTTPSAddrs); err != nil {
+		return err
+	}
 	if err := addrsUnique(inuse, "HTTP", rt.HTTPAddrs); err != nil {
 		return err
 	}
@@ -1587,6 +1633,11 @@ func (b *builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 		Method:                         stringVal(v.Method),
 		Body:                           stringVal(v.Body),
 		DisableRedirects:               boolVal(v.DisableRedirects),
+		ResponseBodyRegex:              stringVal(v.ResponseBodyRegex),
+		ResponseJSONPath:               stringVal(v.ResponseJSONPath),
+		ResponseJSONValue:              stringVal(v.ResponseJSONValue),
+		ResponseHeaders:                v.ResponseHeaders,
+		ResponseTimeWarning:            b.durationVal(fmt.Sprintf("check[%s].response_time_warning", id), v.ResponseTimeWarning),
 		TCP:                            stringVal(v.TCP),
 		UDP:                            stringVal(v.UDP),
 		Interval:                       b.durationVal(fmt.Sprintf("check[%s].interval", id), v.Interval),
@@ -1598,14 +1649,34 @@ func (b *builder) checkVal(v *CheckDefinition) *structs.CheckDefinition {
 		TLSSkipVerify:                  boolVal(v.TLSSkipVerify),
 		AliasNode:                      stringVal(v.AliasNode),
 		AliasService:                   stringVal(v.AliasService),
+		AggregateCheckIDs:              v.AggregateCheckIDs,
+		AggregateService:               stringVal(v.AggregateService),
+		AggregateServiceTag:            stringVal(v.AggregateServiceTag),
+		AggregateRule:                  stringVal(v.AggregateRule),
+		AggregateThreshold:             intVal(v.AggregateThreshold),
 		Timeout:                        b.durationVal(fmt.Sprintf("check[%s].timeout", id), v.Timeout),
 		TTL:                            b.durationVal(fmt.Sprintf("check[%s].ttl", id), v.TTL),
 		SuccessBeforePassing:           intVal(v.SuccessBeforePassing),
 		FailuresBeforeCritical:         intVal(v.FailuresBeforeCritical),
 		FailuresBeforeWarning:          intValWithDefault(v.FailuresBeforeWarning, intVal(v.FailuresBeforeCritical)),
+		FlapThreshold:                  intVal(v.FlapThreshold),
+		FlapWindow:                     b.durationVal(fmt.Sprintf("check[%s].flap_window", id), v.FlapWindow),
 		H2PING:                         stringVal(v.H2PING),
 		H2PingUseTLS:                   H2PingUseTLSVal,
 		OSService:                      stringVal(v.OSService),
+		DNS:                            stringVal(v.DNS),
+		DNSQuery:                       stringVal(v.DNSQuery),
+		DNSRecordType:                  stringVal(v.DNSRecordType),
+		DNSRcode:                       stringVal(v.DNSRcode),
+		DNSMinAnswers:                  intVal(v.DNSMinAnswers),
+		DNSExpectedValues:              v.DNSExpectedValues,
+		DNSUseTCP:                      boolVal(v.DNSUseTCP),
+		TLS:                            stringVal(v.TLS),
+		TLSStartTLS:                    stringVal(v.TLSStartTLS),
+		TLSCAFile:                      stringVal(v.TLSCAFile),
+		TLSExpectedSANs:                v.TLSExpectedSANs,
+		TLSExpiryWarning:               b.durationVal(fmt.Sprintf("check[%s].tls_expiry_warning", id), v.TLSExpiryWarning),
+		TLSExpiryCritical:              b.durationVal(fmt.Sprintf("check[%s].tls_expiry_critical", id), v.TLSExpiryCritical),
 		DeregisterCriticalServiceAfter: b.durationVal(fmt.Sprintf("check[%s].deregister_critical_service_after", id), v.DeregisterCriticalServiceAfter),
 		OutputMaxSize:                  intValWithDefault(v.OutputMaxSize, checks.DefaultBufSize),
 		EnterpriseMeta:                 v.EnterpriseMeta.ToStructs(),
@@ -1786,12 +1857,65 @@ func (b *builder) dnsRecursorStrategyVal(v string) dns.RecursorStrategy {
 	return out
 }
 
+// dnsTSIGAlgorithms are the TSIG algorithms supported for zone transfers.
+var dnsTSIGAlgorithms = []string{"hmac-sha1.", "hmac-sha224.", "hmac-sha256.", "hmac-sha384.", "hmac-sha512."}
+
+func (b *builder) dnsZoneTransferKeysVal(v []DNSTSIGKey) []RuntimeDNSTSIGKey {
+	var out []RuntimeDNSTSIGKey
+	seen := make(map[string]bool)
+	for i, k := range v {
+		name := fmt.Sprintf("dns_config.zone_transfer_keys[%d]", i)
+
+		key := RuntimeDNSTSIGKey{
+			Name:      strings.ToLower(stringVal(k.Name)),
+			Algorithm: strings.ToLower(stringValWithDefault(k.Algorithm, "hmac-sha256")),
+			Secret:    stringVal(k.Secret),
+			Token:     stringVal(k.Token),
+		}
+		if key.Name == "" {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.name is required", name))
+			continue
+		}
+		if !strings.HasSuffix(key.Name, ".") {
+			key.Name += "."
+		}
+		if seen[key.Name] {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.name: duplicate key name %q", name, key.Name))
+			continue
+		}
+		seen[key.Name] = true
+
+		if !strings.HasSuffix(key.Algorithm, ".") {
+			key.Algorithm += "."
+		}
+		if !stringslice.Contains(dnsTSIGAlgorithms, key.Algorithm) {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.algorithm: invalid algorithm: %q", name, stringVal(k.Algorithm)))
+			continue
+		}
+
+		if key.Secret == "" {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.secret is required", name))
+			continue
+		}
+		if _, err := base64.StdEncoding.DecodeString(key.Secret); err != nil {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.secret: must be base64 encoded", name))
+			continue
+		}
+		out = append(out, key)
+	}
+	return out
+}
+
 func (b *builder) requestsLimitsModeVal(v string) consulrate.Mode {
+	return b.requestsLimitsModeValWithName("limits.request_limits.mode", v)
+}
+
+func (b *builder) requestsLimitsModeValWithName(name, v string) consulrate.Mode {
 	var out consulrate.Mode
 
 	mode, ok := consulrate.RequestLimitsModeFromName(v)
 	if !ok {
-		b.err = multierror.Append(b.err, fmt.Errorf("limits.request_limits.mode: invalid mode: %q", v))
+		b.err = multierror.Append(b.err, fmt.Errorf("%s: invalid mode: %q", name, v))
 	} else {
 		out = mode
 	}
@@ -1799,6 +1923,74 @@ func (b *builder) requestsLimitsModeVal(v string) consulrate.Mode {
 	return out
 }
 
+func (b *builder) endpointRequestLimitsVal(v []EndpointRequestLimits) map[string]consul.RequestLimitsPolicy {
+	if len(v) == 0 {
+		return nil
+	}
+	out := make(map[string]consul.RequestLimitsPolicy, len(v))
+	for i, e := range v {
+		field := fmt.Sprintf("limits.request_limits.endpoints[%d]", i)
+		name := stringVal(e.Name)
+		if name == "" {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.name: cannot be empty", field))
+			continue
+		}
+		if _, ok := out[name]; ok {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.name: duplicate endpoint %q", field, name))
+			continue
+		}
+		out[name] = consul.RequestLimitsPolicy{
+			Mode:      b.requestsLimitsModeValWithName(field+".mode", stringVal(e.Mode)),
+			ReadRate:  limitVal(e.ReadRate),
+			WriteRate: limitVal(e.WriteRate),
+		}
+	}
+	return out
+}
+
+func (b *builder) tokenRequestLimitsVal(v []TokenRequestLimits) map[string]consul.RequestLimitsPolicy {
+	if len(v) == 0 {
+		return nil
+	}
+	out := make(map[string]consul.RequestLimitsPolicy, len(v))
+	for i, t := range v {
+		field := fmt.Sprintf("limits.request_limits.tokens[%d]", i)
+		accessorID := stringVal(t.AccessorID)
+		if _, err := uuid.ParseUUID(accessorID); err != nil {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.accessor_id: invalid accessor ID %q", field, accessorID))
+			continue
+		}
+		if _, ok := out[accessorID]; ok {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s.accessor_id: duplicate token %q", field, accessorID))
+			continue
+		}
+		out[accessorID] = consul.RequestLimitsPolicy{
+			Mode:      b.requestsLimitsModeValWithName(field+".mode", stringVal(t.Mode)),
+			ReadRate:  limitVal(t.ReadRate),
+			WriteRate: limitVal(t.WriteRate),
+		}
+	}
+	return out
+}
+
+func (b *builder) ipPrefixRequestLimitsVal(v []IPPrefixRequestLimits) []consul.IPPrefixRequestLimits {
+	var out []consul.IPPrefixRequestLimits
+	for i, p := range v {
+		name := fmt.Sprintf("limits.request_limits.ip_limits.prefixes[%d].cidr", i)
+		_, cidr, err := net.ParseCIDR(strings.TrimSpace(stringVal(p.CIDR)))
+		if err != nil {
+			b.err = multierror.Append(b.err, fmt.Errorf("%s: invalid cidr: %s", name, stringVal(p.CIDR)))
+			continue
+		}
+		out = append(out, consul.IPPrefixRequestLimits{
+			CIDR:      *cidr,
+			ReadRate:  limitVal(p.ReadRate),
+			WriteRate: limitVal(p.WriteRate),
+		})
+	}
+	return out
+}
+
 func (b *builder) exposeConfVal(v *ExposeConfig) structs.ExposeConfig {
 	var out structs.ExposeConfig
 	if v == nil {
@@ -2039,6 +2231,15 @@ func (b *builder) cidrsVal(name string, v []string) (nets []*net.IPNet) {
 	return
 }
 
+func (b *builder) ipNetsVal(name string, v []string) (nets []net.IPNet) {
+	for _, cidr := range b.cidrsVal(name, v) {
+		if cidr != nil {
+			nets = append(nets, *cidr)
+		}
+	}
+	return
+}
+
 func (b *builder) tlsVersion(name string, v *string) types.TLSVersion {
 	// Handles unspecified config and empty string case.
 	//
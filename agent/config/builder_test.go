@@ -327,6 +327,25 @@ func TestBuilder_ServiceVal_MultiError(t *testing.T) {
 	require.Contains(t, b.err.Error(), "cannot have both socket path")
 }
 
+func TestBuilder_RequestLimitsPolicies_MultiError(t *testing.T) {
+	b := builder{}
+	b.endpointRequestLimitsVal([]EndpointRequestLimits{
+		{Name: strPtr("KVS.Apply"), Mode: strPtr("enforcing")},
+		{Name: strPtr("KVS.Apply"), Mode: strPtr("enforcing")},
+		{Mode: strPtr("enforcing")},
+	})
+	b.tokenRequestLimitsVal([]TokenRequestLimits{
+		{AccessorID: strPtr("not-a-uuid"), Mode: strPtr("enforcing")},
+		{AccessorID: strPtr("a0c4ab50-5a11-4f0e-8b39-1b6b5e3c2d19"), Mode: strPtr("bogus")},
+	})
+	require.Error(t, b.err)
+	require.Contains(t, b.err.Error(), "4 errors")
+	require.Contains(t, b.err.Error(), `limits.request_limits.endpoints[1].name: duplicate endpoint "KVS.Apply"`)
+	require.Contains(t, b.err.Error(), "limits.request_limits.endpoints[2].name: cannot be empty")
+	require.Contains(t, b.err.Error(), `limits.request_limits.tokens[0].accessor_id: invalid accessor ID "not-a-uuid"`)
+	require.Contains(t, b.err.Error(), `limits.request_limits.tokens[1].mode: invalid mode: "bogus"`)
+}
+
 func TestBuilder_ServiceVal_with_Check(t *testing.T) {
 	b := builder{}
 	svc := b.serviceVal(&ServiceDefinition{
@@ -332,11 +332,13 @@ type Consul struct {
 }
 
 type Addresses struct {
-	DNS     *string `mapstructure:"dns"`
-	HTTP    *string `mapstructure:"http"`
-	HTTPS   *string `mapstructure:"https"`
-	GRPC    *string `mapstructure:"grpc"`
-	GRPCTLS *string `mapstructure:"grpc_tls"`
+	DNS      *string `mapstructure:"dns"`
+	DNSTLS   *string `mapstructure:"dns_tls"`
+	DNSHTTPS *string `mapstructure:"dns_https"`
+	HTTP     *string `mapstructure:"http"`
+	HTTPS    *string `mapstructure:"https"`
+	GRPC     *string `mapstructure:"grpc"`
+	GRPCTLS  *string `mapstructure:"grpc_tls"`
 }
 
 type AdvertiseAddrsConfig struct {
@@ -405,6 +407,11 @@ type CheckDefinition struct {
 	Method                         *string             `mapstructure:"method"`
 	Body                           *string             `mapstructure:"body"`
 	DisableRedirects               *bool               `mapstructure:"disable_redirects"`
+	ResponseBodyRegex              *string             `mapstructure:"response_body_regex"`
+	ResponseJSONPath               *string             `mapstructure:"response_json_path"`
+	ResponseJSONValue              *string             `mapstructure:"response_json_value"`
+	ResponseHeaders                map[string][]string `mapstructure:"response_headers"`
+	ResponseTimeWarning            *string             `mapstructure:"response_time_warning"`
 	OutputMaxSize                  *int                `mapstructure:"output_max_size"`
 	TCP                            *string             `mapstructure:"tcp"`
 	UDP                            *string             `mapstructure:"udp"`
@@ -417,14 +424,34 @@ type CheckDefinition struct {
 	TLSSkipVerify                  *bool               `mapstructure:"tls_skip_verify" alias:"tlsskipverify"`
 	AliasNode                      *string             `mapstructure:"alias_node"`
 	AliasService                   *string             `mapstructure:"alias_service"`
+	AggregateCheckIDs              []string            `mapstructure:"aggregate_check_ids"`
+	AggregateService               *string             `mapstructure:"aggregate_service"`
+	AggregateServiceTag            *string             `mapstructure:"aggregate_service_tag"`
+	AggregateRule                  *string             `mapstructure:"aggregate_rule"`
+	AggregateThreshold             *int                `mapstructure:"aggregate_threshold"`
 	Timeout                        *string             `mapstructure:"timeout"`
 	TTL                            *string             `mapstructure:"ttl"`
 	H2PING                         *string             `mapstructure:"h2ping"`
 	H2PingUseTLS                   *bool               `mapstructure:"h2ping_use_tls"`
 	OSService                      *string             `mapstructure:"os_service"`
+	DNS                            *string             `mapstructure:"dns"`
+	DNSQuery                       *string             `mapstructure:"dns_query"`
+	DNSRecordType                  *string             `mapstructure:"dns_record_type"`
+	DNSRcode                       *string             `mapstructure:"dns_rcode"`
+	DNSMinAnswers                  *int                `mapstructure:"dns_min_answers"`
+	DNSExpectedValues              []string            `mapstructure:"dns_expected_values"`
+	DNSUseTCP                      *bool               `mapstructure:"dns_use_tcp"`
+	TLS                            *string             `mapstructure:"tls"`
+	TLSStartTLS                    *string             `mapstructure:"tls_starttls"`
+	TLSCAFile                      *string             `mapstructure:"tls_ca_file"`
+	TLSExpectedSANs                []string            `mapstructure:"tls_expected_sans"`
+	TLSExpiryWarning               *string             `mapstructure:"tls_expiry_warning"`
+	TLSExpiryCritical              *string             `mapstructure:"tls_expiry_critical"`
 	SuccessBeforePassing           *int                `mapstructure:"success_before_passing"`
 	FailuresBeforeWarning          *int                `mapstructure:"failures_before_warning"`
 	FailuresBeforeCritical         *int                `mapstructure:"failures_before_critical"`
+	FlapThreshold                  *int                `mapstructure:"flap_threshold"`
+	FlapWindow                     *string             `mapstructure:"flap_window"`
 	DeregisterCriticalServiceAfter *string             `mapstructure:"deregister_critical_service_after" alias:"deregistercriticalserviceafter"`
 
 	EnterpriseMeta `mapstructure:",squash"`
@@ -626,6 +653,13 @@ type SOA struct {
 	Minttl  *uint32 `mapstructure:"min_ttl"`
 }
 
+type DNSTSIGKey struct {
+	Name      *string `mapstructure:"name"`
+	Algorithm *string `mapstructure:"algorithm"`
+	Secret    *string `mapstructure:"secret"`
+	Token     *string `mapstructure:"token"`
+}
+
 type DNS struct {
 	AllowStale         *bool             `mapstructure:"allow_stale"`
 	ARecordLimit       *int              `mapstructure:"a_record_limit"`
@@ -642,6 +676,7 @@ type DNS struct {
 	SOA                *SOA              `mapstructure:"soa"`
 	UseCache           *bool             `mapstructure:"use_cache"`
 	CacheMaxAge        *string           `mapstructure:"cache_max_age"`
+	ZoneTransferKeys   []DNSTSIGKey      `mapstructure:"zone_transfer_keys"`
 
 	// Enterprise Only
 	PreferNamespace *bool `mapstructure:"prefer_namespace"`
@@ -689,6 +724,8 @@ type Telemetry struct {
 
 type Ports struct {
 	DNS            *int `mapstructure:"dns" json:"dns,omitempty"`
+	DNSTLS         *int `mapstructure:"dns_tls" json:"dns_tls,omitempty"`
+	DNSHTTPS       *int `mapstructure:"dns_https" json:"dns_https,omitempty"`
 	HTTP           *int `mapstructure:"http" json:"http,omitempty"`
 	HTTPS          *int `mapstructure:"https" json:"https,omitempty"`
 	SerfLAN        *int `mapstructure:"serf_lan" json:"serf_lan,omitempty"`
@@ -711,11 +748,42 @@ type UnixSocket struct {
 }
 
 type RequestLimits struct {
+	Mode      *string                 `mapstructure:"mode"`
+	ReadRate  *float64                `mapstructure:"read_rate"`
+	WriteRate *float64                `mapstructure:"write_rate"`
+	IPLimits  IPRequestLimits         `mapstructure:"ip_limits"`
+	Endpoints []EndpointRequestLimits `mapstructure:"endpoints"`
+	Tokens    []TokenRequestLimits    `mapstructure:"tokens"`
+}
+
+type EndpointRequestLimits struct {
+	Name      *string  `mapstructure:"name"`
 	Mode      *string  `mapstructure:"mode"`
 	ReadRate  *float64 `mapstructure:"read_rate"`
 	WriteRate *float64 `mapstructure:"write_rate"`
 }
 
+type TokenRequestLimits struct {
+	AccessorID *string  `mapstructure:"accessor_id"`
+	Mode       *string  `mapstructure:"mode"`
+	ReadRate   *float64 `mapstructure:"read_rate"`
+	WriteRate  *float64 `mapstructure:"write_rate"`
+}
+
+type IPRequestLimits struct {
+	Mode      *string                 `mapstructure:"mode"`
+	ReadRate  *float64                `mapstructure:"read_rate"`
+	WriteRate *float64                `mapstructure:"write_rate"`
+	Allowlist []string                `mapstructure:"allowlist"`
+	Prefixes  []IPPrefixRequestLimits `mapstructure:"prefixes"`
+}
+
+type IPPrefixRequestLimits struct {
+	CIDR      *string  `mapstructure:"cidr"`
+	ReadRate  *float64 `mapstructure:"read_rate"`
+	WriteRate *float64 `mapstructure:"write_rate"`
+}
+
 type Limits struct {
 	HTTPMaxConnsPerClient *int          `mapstructure:"http_max_conns_per_client"`
 	HTTPSHandshakeTimeout *string       `mapstructure:"https_handshake_timeout"`
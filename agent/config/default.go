@@ -101,6 +101,11 @@ func DefaultSource() Source {
 				mode = "disabled"
 				read_rate = -1
 				write_rate = -1
+				ip_limits = {
+					mode = "disabled"
+					read_rate = -1
+					write_rate = -1
+				}
 			}
 			rpc_handshake_timeout = "5s"
 			rpc_client_timeout = "60s"
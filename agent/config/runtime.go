@@ -31,6 +31,16 @@ type RuntimeSOAConfig struct {
 	Minttl  uint32 // 0,
 }
 
+// RuntimeDNSTSIGKey is a TSIG key which authorizes zone transfers. The
+// transfer is served with the permissions of Token, or of the agent's
+// default token when it is empty.
+type RuntimeDNSTSIGKey struct {
+	Name      string // fully qualified and lower case
+	Algorithm string // fully qualified, hmac-sha256. by default
+	Secret    string // base64 encoded
+	Token     string
+}
+
 // StaticRuntimeConfig specifies the subset of configuration the consul agent actually
 // uses and that are not reloadable by configuration auto reload.
 type StaticRuntimeConfig struct {
@@ -298,6 +308,13 @@ type RuntimeConfig struct {
 	// hcl: dns_config { cache_max_age = "duration" }
 	DNSCacheMaxAge time.Duration
 
+	// DNSZoneTransferKeys are the TSIG keys allowed to transfer the zone of
+	// the DNS interface with AXFR and IXFR. Zone transfers are disabled
+	// when no keys are configured.
+	//
+	// hcl: dns_config { zone_transfer_keys = [{ name = string algorithm = string secret = string token = string }] }
+	DNSZoneTransferKeys []RuntimeDNSTSIGKey
+
 	// HTTPUseCache whether or not to use cache for http queries. Defaults
 	// to true.
 	//
@@ -434,6 +451,11 @@ type RuntimeConfig struct {
 	//     header = map[string][]string
 	//     method = string
 	//     disable_redirects = (true|false)
+	//     response_body_regex = string
+	//     response_json_path = string
+	//     response_json_value = string
+	//     response_headers = map[string][]string
+	//     response_time_warning = "duration"
 	//     tcp = string
 	//     h2ping = string
 	//     interval = string
@@ -443,9 +465,29 @@ type RuntimeConfig struct {
 	//     timeout = "duration"
 	//     ttl = "duration"
 	//     os_service = string
+	//     dns = string
+	//     dns_query = string
+	//     dns_record_type = string
+	//     dns_rcode = string
+	//     dns_min_answers = int
+	//     dns_expected_values = []string
+	//     dns_use_tcp = (true|false)
+	//     tls = string
+	//     tls_starttls = (smtp|postgres)
+	//     tls_ca_file = string
+	//     tls_expected_sans = []string
+	//     tls_expiry_warning = "duration"
+	//     tls_expiry_critical = "duration"
+	//     aggregate_check_ids = []string
+	//     aggregate_service = string
+	//     aggregate_service_tag = string
+	//     aggregate_rule = (critical-if-any|critical-if-more-than|warning-if-quorum-lost)
+	//     aggregate_threshold = int
 	//     success_before_passing = int
 	//     failures_before_warning = int
 	//     failures_before_critical = int
+	//     flap_threshold = int
+	//     flap_window = "duration"
 	//     deregister_critical_service_after = "duration"
 	//   },
 	//   ...
@@ -552,6 +594,40 @@ type RuntimeConfig struct {
 	// flags: -dns-port int
 	DNSPort int
 
+	// DNSTLSAddrs contains the list of TCP addresses the DNS over TLS
+	// (RFC 7858) server will bind to. If the endpoint is disabled
+	// (ports.dns_tls <= 0) the list is empty.
+	//
+	// The ip addresses are taken from 'addresses.dns_tls' or, if it was not
+	// provided, from 'client_addr'. The certificates are those configured
+	// for the HTTPS interface.
+	//
+	// hcl: client_addr = string addresses { dns_tls = string } ports { dns_tls = int }
+	DNSTLSAddrs []net.Addr
+
+	// DNSTLSPort is the port the DNS over TLS server listens on. It is
+	// disabled by default, the standard port is 853.
+	//
+	// hcl: ports { dns_tls = int }
+	DNSTLSPort int
+
+	// DNSHTTPSAddrs contains the list of TCP addresses and UNIX sockets the
+	// DNS over HTTPS (RFC 8484) server will bind to. If the endpoint is
+	// disabled (ports.dns_https <= 0) the list is empty.
+	//
+	// The addresses are taken from 'addresses.dns_https' or, if it was not
+	// provided, from 'client_addr'. The certificates are those configured
+	// for the HTTPS interface.
+	//
+	// hcl: client_addr = string addresses { dns_https = string } ports { dns_https = int }
+	DNSHTTPSAddrs []net.Addr
+
+	// DNSHTTPSPort is the port the DNS over HTTPS server listens on. It is
+	// disabled by default.
+	//
+	// hcl: ports { dns_https = int }
+	DNSHTTPSPort int
+
 	// DNSSOA is the settings applied for DNS SOA
 	// hcl: soa {}
 	DNSSOA RuntimeSOAConfig
@@ -1041,6 +1117,54 @@ type RuntimeConfig struct {
 	// hcl: limits { request_limits { write_rate = (float64|MaxFloat64) } }
 	RequestLimitsWriteRate rate.Limit
 
+	// RequestLimitsIPMode will disable or enable per-source-IP rate limiting.
+	// It enforces the action that will occur when RequestLimitsIPReadRate or
+	// RequestLimitsIPWriteRate is exceeded by a single source address, and
+	// accepts the same values as RequestLimitsMode.
+	//
+	// hcl: limits { request_limits { ip_limits { mode = "permissive" } } }
+	RequestLimitsIPMode consulrate.Mode
+
+	// RequestLimitsIPReadRate controls how frequently each source IP address
+	// is allowed to perform RPC, gRPC, and HTTP queries.
+	//
+	// hcl: limits { request_limits { ip_limits { read_rate = (float64|MaxFloat64) } } }
+	RequestLimitsIPReadRate rate.Limit
+
+	// RequestLimitsIPWriteRate controls how frequently each source IP address
+	// is allowed to perform RPC, gRPC, and HTTP writes.
+	//
+	// hcl: limits { request_limits { ip_limits { write_rate = (float64|MaxFloat64) } } }
+	RequestLimitsIPWriteRate rate.Limit
+
+	// RequestLimitsIPAllowlist contains the CIDR blocks whose addresses are
+	// exempt from per-source-IP rate limiting, such as the other servers in
+	// the cluster.
+	//
+	// hcl: limits { request_limits { ip_limits { allowlist = []string } } }
+	RequestLimitsIPAllowlist []net.IPNet
+
+	// RequestLimitsIPPrefixes configures read and write rate limits that are
+	// shared by all source addresses within a CIDR block. Addresses matching a
+	// prefix are not subject to RequestLimitsIPReadRate and
+	// RequestLimitsIPWriteRate.
+	//
+	// hcl: limits { request_limits { ip_limits { prefixes = [{ cidr = string read_rate = float64 write_rate = float64 }] } } }
+	RequestLimitsIPPrefixes []consul.IPPrefixRequestLimits
+
+	// RequestLimitsEndpoints configures the read and write rate limits shared
+	// by all requests against an RPC endpoint, keyed by the endpoint's name
+	// (e.g. "Health.ServiceNodes").
+	//
+	// hcl: limits { request_limits { endpoints = [{ name = string mode = string read_rate = float64 write_rate = float64 }] } }
+	RequestLimitsEndpoints map[string]consul.RequestLimitsPolicy
+
+	// RequestLimitsTokens configures the read and write rate limits shared by
+	// all requests made with an ACL token, keyed by the token's accessor ID.
+	//
+	// hcl: limits { request_limits { tokens = [{ accessor_id = string mode = string read_rate = float64 write_rate = float64 }] } }
+	RequestLimitsTokens map[string]consul.RequestLimitsPolicy
+
 	// RetryJoinIntervalLAN specifies the amount of time to wait in between join
 	// attempts on agent start. The minimum allowed value is 1 second and
 	// the default is 30s.
@@ -2021,6 +2021,26 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl:         []string{`addresses = { dns = "unix:///foo" }`},
 		expectedErr: "DNS address cannot be a unix socket",
 	})
+	run(t, testCase{
+		desc: "dns over tls requires a certificate",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "ports": {"dns_tls": 8853 } }`},
+		hcl:         []string{`ports = { dns_tls = 8853 }`},
+		expectedErr: "ports.dns_tls and ports.dns_https require a TLS certificate",
+	})
+	run(t, testCase{
+		desc: "dns over https requires a certificate",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "ports": {"dns_https": 8443 } }`},
+		hcl:         []string{`ports = { dns_https = 8443 }`},
+		expectedErr: "ports.dns_tls and ports.dns_https require a TLS certificate",
+	})
 	run(t, testCase{
 		desc: "ui enabled and dir specified",
 		args: []string{
@@ -2072,6 +2092,33 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl:         []string{`dns_config = { udp_answer_limit = -1 }`},
 		expectedErr: "dns_config.udp_answer_limit cannot be -1. Must be greater than or equal to zero",
 	})
+	run(t, testCase{
+		desc: "dns_config.zone_transfer_keys invalid algorithm",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "zone_transfer_keys": [{ "name": "k1", "algorithm": "hmac-md5", "secret": "c2VjcmV0" }] } }`},
+		hcl:         []string{`dns_config = { zone_transfer_keys = [{ name = "k1" algorithm = "hmac-md5" secret = "c2VjcmV0" }] }`},
+		expectedErr: `dns_config.zone_transfer_keys[0].algorithm: invalid algorithm: "hmac-md5"`,
+	})
+	run(t, testCase{
+		desc: "dns_config.zone_transfer_keys invalid secret",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "zone_transfer_keys": [{ "name": "k1", "secret": "not base64" }] } }`},
+		hcl:         []string{`dns_config = { zone_transfer_keys = [{ name = "k1" secret = "not base64" }] }`},
+		expectedErr: "dns_config.zone_transfer_keys[0].secret: must be base64 encoded",
+	})
+	run(t, testCase{
+		desc: "dns_config.zone_transfer_keys duplicate name",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "dns_config": { "zone_transfer_keys": [{ "name": "k1", "secret": "c2VjcmV0" }, { "name": "K1.", "secret": "c2VjcmV0" }] } }`},
+		hcl:         []string{`dns_config = { zone_transfer_keys = [{ name = "k1" secret = "c2VjcmV0" }, { name = "K1." secret = "c2VjcmV0" }] }`},
+		expectedErr: `dns_config.zone_transfer_keys[1].name: duplicate key name "k1."`,
+	})
 	run(t, testCase{
 		desc: "dns_config.a_record_limit invalid",
 		args: []string{
@@ -2497,6 +2544,31 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.DataDir = dataDir
 		},
 	})
+	run(t, testCase{
+		desc: "aggregate check",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{
+			`{ "check": { "name": "a", "aggregate_check_ids": ["db", "cache"], "aggregate_service_tag": "web", "aggregate_rule": "critical-if-more-than", "aggregate_threshold": 1 } }`,
+		},
+		hcl: []string{
+			`check = { name = "a", aggregate_check_ids = ["db", "cache"], aggregate_service_tag = "web", aggregate_rule = "critical-if-more-than", aggregate_threshold = 1 }`,
+		},
+		expected: func(rt *RuntimeConfig) {
+			rt.Checks = []*structs.CheckDefinition{
+				{
+					Name:                "a",
+					AggregateCheckIDs:   []string{"db", "cache"},
+					AggregateServiceTag: "web",
+					AggregateRule:       "critical-if-more-than",
+					AggregateThreshold:  1,
+					OutputMaxSize:       checks.DefaultBufSize,
+				},
+			}
+			rt.DataDir = dataDir
+		},
+	})
 	run(t, testCase{
 		desc: "os_service check no interval",
 		args: []string{
@@ -2508,7 +2580,7 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl: []string{
 			`check = { name = "a", os_service = "foo" }`,
 		},
-		expectedErr: `Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP or OSService checks`,
+		expectedErr: `Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP, OSService, DNS or TLS checks`,
 	})
 	run(t, testCase{
 		desc: "os_service check",
@@ -4644,6 +4716,9 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.RequestLimitsMode = consulrate.ModeDisabled
 			rt.RequestLimitsReadRate = rate.Inf
 			rt.RequestLimitsWriteRate = rate.Inf
+			rt.RequestLimitsIPMode = consulrate.ModeDisabled
+			rt.RequestLimitsIPReadRate = rate.Inf
+			rt.RequestLimitsIPWriteRate = rate.Inf
 			rt.SegmentLimit = 64
 			rt.XDSUpdateRateLimit = 250
 		},
@@ -5949,6 +6024,9 @@ func TestLoad_FullConfig(t *testing.T) {
 					"ZBfTin3L": {"1sDbEqYG", "lJGASsWK"},
 					"Ui0nU99X": {"LMccm3Qe", "k5H5RggQ"},
 				},
+				ResponseHeaders: map[string][]string{
+					"Ki4YpSXl": {"ZCW7cWlB", "uMLt5zdI"},
+				},
 				Method:                         "aldrIQ4l",
 				Body:                           "wSjTy7dg",
 				DisableRedirects:               true,
@@ -5956,6 +6034,25 @@ func TestLoad_FullConfig(t *testing.T) {
 				H2PING:                         "9N1cSb5B",
 				H2PingUseTLS:                   false,
 				OSService:                      "aAjE6m9Z",
+				DNS:                            "pMbXDuCL",
+				DNSQuery:                       "1mHoOsFa",
+				DNSRecordType:                  "QfDPrAJ7",
+				DNSRcode:                       "1fTquWoG",
+				DNSMinAnswers:                  5,
+				DNSExpectedValues:              []string{"beKXgzg2", "sye9b2Ra"},
+				DNSUseTCP:                      true,
+				TLS:                            "RzZ1fb6d",
+				TLSStartTLS:                    "smtp",
+				TLSCAFile:                      "fB8ChQBi",
+				TLSExpectedSANs:                []string{"Iu4NJkS4", "dJkG0fzM"},
+				TLSExpiryWarning:               9413 * time.Second,
+				FlapThreshold:                  5,
+				FlapWindow:                     6225 * time.Second,
+				TLSExpiryCritical:              5621 * time.Second,
+				ResponseBodyRegex:              "JFMWTfDj",
+				ResponseJSONPath:               "$.0ylRJUp4",
+				ResponseJSONValue:              "XYNapCeI",
+				ResponseTimeWarning:            519 * time.Second,
 				Interval:                       22164 * time.Second,
 				OutputMaxSize:                  checks.DefaultBufSize,
 				DockerContainerID:              "ipgdFtjd",
@@ -5978,6 +6075,9 @@ func TestLoad_FullConfig(t *testing.T) {
 					"zcqwA8dO": {"qb1zx0DL", "sXCxPFsD"},
 					"qxvdnSE9": {"6wBPUYdF", "YYh8wtSZ"},
 				},
+				ResponseHeaders: map[string][]string{
+					"mftxCc3o": {"AUfhjLhk", "IE29SKSp"},
+				},
 				Method:                         "gLrztrNw",
 				Body:                           "0jkKgGUC",
 				DisableRedirects:               false,
@@ -5986,6 +6086,25 @@ func TestLoad_FullConfig(t *testing.T) {
 				H2PING:                         "HCHU7gEb",
 				H2PingUseTLS:                   false,
 				OSService:                      "aqq95BhP",
+				DNS:                            "nn76dEyT",
+				DNSQuery:                       "zAeKOmXR",
+				DNSRecordType:                  "rvftva9A",
+				DNSRcode:                       "W7hipTga",
+				DNSMinAnswers:                  1,
+				DNSExpectedValues:              []string{"DZFlRJmC", "GmUXiAPy"},
+				DNSUseTCP:                      true,
+				TLS:                            "MEEMyIbP",
+				TLSStartTLS:                    "smtp",
+				TLSCAFile:                      "9mYQqw8x",
+				TLSExpectedSANs:                []string{"3SyRthqp", "vxxGKZWG"},
+				TLSExpiryWarning:               7862 * time.Second,
+				FlapThreshold:                  4,
+				FlapWindow:                     2837 * time.Second,
+				TLSExpiryCritical:              5573 * time.Second,
+				ResponseBodyRegex:              "Rll6vg3q",
+				ResponseJSONPath:               "$.1B9K1S21",
+				ResponseJSONValue:              "it8upzVM",
+				ResponseTimeWarning:            9034 * time.Second,
 				Interval:                       28767 * time.Second,
 				DockerContainerID:              "THW6u7rL",
 				Shell:                          "C1Zt3Zwh",
@@ -6007,6 +6126,9 @@ func TestLoad_FullConfig(t *testing.T) {
 					"hBq0zn1q": {"2a9o9ZKP", "vKwA5lR6"},
 					"f3r6xFtM": {"RyuIdDWv", "QbxEcIUM"},
 				},
+				ResponseHeaders: map[string][]string{
+					"PdVdLuP7": {"pyYWzTGe", "mndbHMMu"},
+				},
 				Method:                         "Dou0nGT5",
 				Body:                           "5PBQd2OT",
 				DisableRedirects:               true,
@@ -6015,6 +6137,25 @@ func TestLoad_FullConfig(t *testing.T) {
 				H2PING:                         "rQ8eyCSF",
 				H2PingUseTLS:                   false,
 				OSService:                      "aZaCAXww",
+				DNS:                            "C3J27XDC",
+				DNSQuery:                       "G2LmlZGE",
+				DNSRecordType:                  "ONYlgCtj",
+				DNSRcode:                       "fIZ4SOcM",
+				DNSMinAnswers:                  7,
+				DNSExpectedValues:              []string{"9CPVNPkN", "a1Hedcm4"},
+				DNSUseTCP:                      true,
+				TLS:                            "QHQwjyax",
+				TLSStartTLS:                    "postgres",
+				TLSCAFile:                      "rPZDS3Mo",
+				TLSExpectedSANs:                []string{"JaQNjCxk", "v5ndK0me"},
+				TLSExpiryWarning:               8775 * time.Second,
+				FlapThreshold:                  3,
+				FlapWindow:                     1146 * time.Second,
+				TLSExpiryCritical:              4507 * time.Second,
+				ResponseBodyRegex:              "4W5fUv7j",
+				ResponseJSONPath:               "$.X5G2Nm28",
+				ResponseJSONValue:              "QONdhz0B",
+				ResponseTimeWarning:            5311 * time.Second,
 				Interval:                       18714 * time.Second,
 				DockerContainerID:              "qF66POS9",
 				Shell:                          "sOnDy228",
@@ -6112,6 +6253,10 @@ func TestLoad_FullConfig(t *testing.T) {
 		DNSNodeTTL:                       7084 * time.Second,
 		DNSOnlyPassing:                   true,
 		DNSPort:                          7001,
+		DNSTLSAddrs:                      []net.Addr{tcpAddr("61.27.83.14:7853")},
+		DNSTLSPort:                       7853,
+		DNSHTTPSAddrs:                    []net.Addr{tcpAddr("48.92.12.66:7443")},
+		DNSHTTPSPort:                     7443,
 		DNSRecursorStrategy:              "sequential",
 		DNSRecursorTimeout:               4427 * time.Second,
 		DNSRecursors:                     []string{"63.38.39.58", "92.49.18.18"},
@@ -6121,6 +6266,7 @@ func TestLoad_FullConfig(t *testing.T) {
 		DNSNodeMetaTXT:                   true,
 		DNSUseCache:                      true,
 		DNSCacheMaxAge:                   5 * time.Minute,
+		DNSZoneTransferKeys:              []RuntimeDNSTSIGKey{{Name: "secondary1.", Algorithm: "hmac-sha512.", Secret: "bWlXNHVMaG9vbmdvYmVpcA==", Token: "Ka9sQdkv"}},
 		DataDir:                          dataDir,
 		Datacenter:                       "rzo029wg",
 		DefaultQueryTime:                 16743 * time.Second,
@@ -6168,33 +6314,46 @@ func TestLoad_FullConfig(t *testing.T) {
 			EnableSyslog:   true,
 			SyslogFacility: "hHv79Uia",
 		},
-		MaxQueryTime:            18237 * time.Second,
-		NodeID:                  types.NodeID("AsUIlw99"),
-		NodeMeta:                map[string]string{"5mgGQMBk": "mJLtVMSG", "A7ynFMJB": "0Nx6RGab"},
-		NodeName:                "otlLxGaI",
-		ReadReplica:             true,
-		PeeringEnabled:          true,
-		PidFile:                 "43xN80Km",
-		PrimaryGateways:         []string{"aej8eeZo", "roh2KahS"},
-		PrimaryGatewaysInterval: 18866 * time.Second,
-		RPCAdvertiseAddr:        tcpAddr("17.99.29.16:3757"),
-		RPCBindAddr:             tcpAddr("16.99.34.17:3757"),
-		RPCHandshakeTimeout:     1932 * time.Millisecond,
-		RPCClientTimeout:        62 * time.Second,
-		RPCHoldTimeout:          15707 * time.Second,
-		RPCProtocol:             30793,
-		RPCRateLimit:            12029.43,
-		RPCMaxBurst:             44848,
-		RPCMaxConnsPerClient:    2954,
-		RaftProtocol:            3,
-		RaftSnapshotThreshold:   16384,
-		RaftSnapshotInterval:    30 * time.Second,
-		RaftTrailingLogs:        83749,
-		ReconnectTimeoutLAN:     23739 * time.Second,
-		ReconnectTimeoutWAN:     26694 * time.Second,
-		RequestLimitsMode:       consulrate.ModePermissive,
-		RequestLimitsReadRate:   99.0,
-		RequestLimitsWriteRate:  101.0,
+		MaxQueryTime:             18237 * time.Second,
+		NodeID:                   types.NodeID("AsUIlw99"),
+		NodeMeta:                 map[string]string{"5mgGQMBk": "mJLtVMSG", "A7ynFMJB": "0Nx6RGab"},
+		NodeName:                 "otlLxGaI",
+		ReadReplica:              true,
+		PeeringEnabled:           true,
+		PidFile:                  "43xN80Km",
+		PrimaryGateways:          []string{"aej8eeZo", "roh2KahS"},
+		PrimaryGatewaysInterval:  18866 * time.Second,
+		RPCAdvertiseAddr:         tcpAddr("17.99.29.16:3757"),
+		RPCBindAddr:              tcpAddr("16.99.34.17:3757"),
+		RPCHandshakeTimeout:      1932 * time.Millisecond,
+		RPCClientTimeout:         62 * time.Second,
+		RPCHoldTimeout:           15707 * time.Second,
+		RPCProtocol:              30793,
+		RPCRateLimit:             12029.43,
+		RPCMaxBurst:              44848,
+		RPCMaxConnsPerClient:     2954,
+		RaftProtocol:             3,
+		RaftSnapshotThreshold:    16384,
+		RaftSnapshotInterval:     30 * time.Second,
+		RaftTrailingLogs:         83749,
+		ReconnectTimeoutLAN:      23739 * time.Second,
+		ReconnectTimeoutWAN:      26694 * time.Second,
+		RequestLimitsMode:        consulrate.ModePermissive,
+		RequestLimitsReadRate:    99.0,
+		RequestLimitsWriteRate:   101.0,
+		RequestLimitsIPMode:      consulrate.ModeEnforcing,
+		RequestLimitsIPReadRate:  17.0,
+		RequestLimitsIPWriteRate: 7.0,
+		RequestLimitsIPAllowlist: []net.IPNet{*parseCIDR(t, "10.23.0.0/16")},
+		RequestLimitsIPPrefixes: []consul.IPPrefixRequestLimits{
+			{CIDR: *parseCIDR(t, "10.42.0.0/24"), ReadRate: 13.0, WriteRate: 3.0},
+		},
+		RequestLimitsEndpoints: map[string]consul.RequestLimitsPolicy{
+			"KVS.Apply": {Mode: consulrate.ModeEnforcing, ReadRate: 31.0, WriteRate: 11.0},
+		},
+		RequestLimitsTokens: map[string]consul.RequestLimitsPolicy{
+			"a0c4ab50-5a11-4f0e-8b39-1b6b5e3c2d19": {Mode: consulrate.ModePermissive, ReadRate: 29.0, WriteRate: 19.0},
+		},
 		RejoinAfterLeave:        true,
 		RetryJoinIntervalLAN:    8067 * time.Second,
 		RetryJoinIntervalWAN:    28866 * time.Second,
@@ -6234,6 +6393,9 @@ func TestLoad_FullConfig(t *testing.T) {
 							"UkpmZ3a3": {"2dfzXuxZ"},
 							"cVFpko4u": {"gGqdEB6k", "9LsRo22u"},
 						},
+						ResponseHeaders: map[string][]string{
+							"XgITTLbS": {"h3e06mB3", "z3YSNAyB"},
+						},
 						Method:                         "X5DrovFc",
 						Body:                           "WeikigLh",
 						DisableRedirects:               true,
@@ -6242,6 +6404,25 @@ func TestLoad_FullConfig(t *testing.T) {
 						H2PING:                         "7s7BbMyb",
 						H2PingUseTLS:                   false,
 						OSService:                      "amfeO5if",
+						DNS:                            "bE6wSt9c",
+						DNSQuery:                       "bMOeEeUt",
+						DNSRecordType:                  "uieeCIxV",
+						DNSRcode:                       "c57VVTiY",
+						DNSMinAnswers:                  6,
+						DNSExpectedValues:              []string{"wfRE5e32", "A8Yb3FKa"},
+						DNSUseTCP:                      true,
+						TLS:                            "v83GOSCT",
+						TLSStartTLS:                    "smtp",
+						TLSCAFile:                      "UsHqbRaS",
+						TLSExpectedSANs:                []string{"biJ4rAip", "mBEdJKNU"},
+						TLSExpiryWarning:               1753 * time.Second,
+						FlapThreshold:                  8,
+						FlapWindow:                     5941 * time.Second,
+						TLSExpiryCritical:              669 * time.Second,
+						ResponseBodyRegex:              "nz61kK0M",
+						ResponseJSONPath:               "$.Glp8Mb9d",
+						ResponseJSONValue:              "mvEaMdDx",
+						ResponseTimeWarning:            9197 * time.Second,
 						Interval:                       24392 * time.Second,
 						DockerContainerID:              "ZKXr68Yb",
 						Shell:                          "CEfzx0Fo",
@@ -6288,6 +6469,9 @@ func TestLoad_FullConfig(t *testing.T) {
 							"MUlReo8L": {"AUZG7wHG", "gsN0Dc2N"},
 							"1UJXjVrT": {"OJgxzTfk", "xZZrFsq7"},
 						},
+						ResponseHeaders: map[string][]string{
+							"tsV3MiZN": {"Q1u3HPuw", "4mx81T4i"},
+						},
 						Method:                         "5wkAxCUE",
 						Body:                           "7CRjCJyz",
 						OutputMaxSize:                  checks.DefaultBufSize,
@@ -6295,6 +6479,25 @@ func TestLoad_FullConfig(t *testing.T) {
 						H2PING:                         "OV6Q2XEg",
 						H2PingUseTLS:                   false,
 						OSService:                      "GTti9hCA",
+						DNS:                            "NQyyLaMe",
+						DNSQuery:                       "ffOhq4AU",
+						DNSRecordType:                  "vy7VSLDC",
+						DNSRcode:                       "D1IfHWGb",
+						DNSMinAnswers:                  5,
+						DNSExpectedValues:              []string{"MfEbo9Sh", "FXNQ6Fq5"},
+						DNSUseTCP:                      true,
+						TLS:                            "ABLf5zYm",
+						TLSStartTLS:                    "postgres",
+						TLSCAFile:                      "xOVyKkbS",
+						TLSExpectedSANs:                []string{"Tj5bZc5e", "6AImY7yY"},
+						TLSExpiryWarning:               5225 * time.Second,
+						FlapThreshold:                  9,
+						FlapWindow:                     7319 * time.Second,
+						TLSExpiryCritical:              421 * time.Second,
+						ResponseBodyRegex:              "aizSIeqF",
+						ResponseJSONPath:               "$.oHCDBIXN",
+						ResponseJSONValue:              "fg5uiGgD",
+						ResponseTimeWarning:            8390 * time.Second,
 						Interval:                       32718 * time.Second,
 						DockerContainerID:              "cU15LMet",
 						Shell:                          "nEz9qz2l",
@@ -6433,6 +6636,9 @@ func TestLoad_FullConfig(t *testing.T) {
 							"gv5qefTz": {"5Olo2pMG", "PvvKWQU5"},
 							"SHOVq1Vv": {"jntFhyym", "GYJh32pp"},
 						},
+						ResponseHeaders: map[string][]string{
+							"SfG22FpH": {"liVsIW34", "1eL6cmTH"},
+						},
 						Method:                         "T66MFBfR",
 						Body:                           "OwGjTFQi",
 						DisableRedirects:               true,
@@ -6441,6 +6647,25 @@ func TestLoad_FullConfig(t *testing.T) {
 						H2PING:                         "qC1pidiW",
 						H2PingUseTLS:                   false,
 						OSService:                      "ZA99e9Ka",
+						DNS:                            "1Ki3ylOj",
+						DNSQuery:                       "t6o0NpUm",
+						DNSRecordType:                  "kVO8JmR8",
+						DNSRcode:                       "y4EMfAdg",
+						DNSMinAnswers:                  2,
+						DNSExpectedValues:              []string{"cG9qpVTz", "qA05MFsH"},
+						DNSUseTCP:                      true,
+						TLS:                            "vsDES92E",
+						TLSStartTLS:                    "smtp",
+						TLSCAFile:                      "9kD7Jxlm",
+						TLSExpectedSANs:                []string{"XVo6Ma8r", "6XZvlpYa"},
+						TLSExpiryWarning:               7034 * time.Second,
+						FlapThreshold:                  2,
+						FlapWindow:                     9872 * time.Second,
+						TLSExpiryCritical:              1810 * time.Second,
+						ResponseBodyRegex:              "79zX04tS",
+						ResponseJSONPath:               "$.qQfEff19",
+						ResponseJSONValue:              "FbbyemcC",
+						ResponseTimeWarning:            5608 * time.Second,
 						Interval:                       22224 * time.Second,
 						DockerContainerID:              "ipgdFtjd",
 						Shell:                          "omVZq7Sz",
@@ -6460,6 +6685,9 @@ func TestLoad_FullConfig(t *testing.T) {
 							"4ebP5vL4": {"G20SrL5Q", "DwPKlMbo"},
 							"p2UI34Qz": {"UsG1D0Qh", "NHhRiB6s"},
 						},
+						ResponseHeaders: map[string][]string{
+							"GG320PKH": {"qyfK6ARA", "1sti1vUg"},
+						},
 						Method:                         "ciYHWors",
 						Body:                           "lUVLGYU7",
 						DisableRedirects:               false,
@@ -6468,6 +6696,25 @@ func TestLoad_FullConfig(t *testing.T) {
 						H2PING:                         "spI3muI3",
 						H2PingUseTLS:                   false,
 						OSService:                      "GAaO6Mpr",
+						DNS:                            "l7UeioEJ",
+						DNSQuery:                       "P2NNern6",
+						DNSRecordType:                  "6nVberAC",
+						DNSRcode:                       "pdclsxHK",
+						DNSMinAnswers:                  3,
+						DNSExpectedValues:              []string{"fxi5CvQU", "SHL8iLc7"},
+						DNSUseTCP:                      true,
+						TLS:                            "pNhxIGjb",
+						TLSStartTLS:                    "postgres",
+						TLSCAFile:                      "4YQ1rOho",
+						TLSExpectedSANs:                []string{"YHGEFOwq", "R8C5VkCs"},
+						TLSExpiryWarning:               9215 * time.Second,
+						FlapThreshold:                  6,
+						FlapWindow:                     3382 * time.Second,
+						TLSExpiryCritical:              755 * time.Second,
+						ResponseBodyRegex:              "xjjTZYDA",
+						ResponseJSONPath:               "$.BYDkzW1n",
+						ResponseJSONValue:              "nVa2iJDf",
+						ResponseTimeWarning:            4274 * time.Second,
 						Interval:                       12356 * time.Second,
 						DockerContainerID:              "HBndBU6R",
 						Shell:                          "hVI33JjA",
@@ -6487,6 +6734,9 @@ func TestLoad_FullConfig(t *testing.T) {
 							"rjm4DEd3": {"2m3m2Fls"},
 							"l4HwQ112": {"fk56MNlo", "dhLK56aZ"},
 						},
+						ResponseHeaders: map[string][]string{
+							"rdPHFEex": {"gQuLOeFG", "t7S9OgQs"},
+						},
 						Method:                         "9afLm3Mj",
 						Body:                           "wVVL2V6f",
 						DisableRedirects:               true,
@@ -6495,6 +6745,25 @@ func TestLoad_FullConfig(t *testing.T) {
 						H2PING:                         "5NbNWhan",
 						H2PingUseTLS:                   false,
 						OSService:                      "RAa85Dv8",
+						DNS:                            "hzAnar3Z",
+						DNSQuery:                       "Lt4bnlz2",
+						DNSRecordType:                  "MPKgcjnC",
+						DNSRcode:                       "qaXNv1sy",
+						DNSMinAnswers:                  2,
+						DNSExpectedValues:              []string{"efnLOpaM", "xxNDi9LE"},
+						DNSUseTCP:                      true,
+						TLS:                            "y02BcHbo",
+						TLSStartTLS:                    "postgres",
+						TLSCAFile:                      "cynXMgWJ",
+						TLSExpectedSANs:                []string{"oleSrcBr", "FwMOUdGD"},
+						TLSExpiryWarning:               3939 * time.Second,
+						FlapThreshold:                  7,
+						FlapWindow:                     4513 * time.Second,
+						TLSExpiryCritical:              215 * time.Second,
+						ResponseBodyRegex:              "37UYZ2ub",
+						ResponseJSONPath:               "$.kbtkBQDe",
+						ResponseJSONValue:              "opfPO4JF",
+						ResponseTimeWarning:            3274 * time.Second,
 						Interval:                       23926 * time.Second,
 						DockerContainerID:              "dO5TtRHk",
 						Shell:                          "e6q2ttES",
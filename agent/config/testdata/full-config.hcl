@@ -41,6 +41,8 @@ acl = {
 }
 addresses = {
     dns = "93.95.95.81"
+    dns_tls = "61.27.83.14"
+    dns_https = "48.92.12.66"
     http = "83.39.91.39"
     https = "95.17.17.19"
     grpc = "32.31.61.91"
@@ -120,6 +122,28 @@ check = {
     docker_container_id = "qF66POS9"
     shell = "sOnDy228"
     os_service = "aZaCAXww"
+    dns = "C3J27XDC"
+    dns_query = "G2LmlZGE"
+    dns_record_type = "ONYlgCtj"
+    dns_rcode = "fIZ4SOcM"
+    dns_min_answers = 7
+    dns_expected_values = ["9CPVNPkN", "a1Hedcm4"]
+    dns_use_tcp = true
+    tls = "QHQwjyax"
+    tls_starttls = "postgres"
+    tls_ca_file = "rPZDS3Mo"
+    tls_expected_sans = ["JaQNjCxk", "v5ndK0me"]
+    tls_expiry_warning = "8775s"
+    flap_threshold = 3
+    flap_window = "1146s"
+    tls_expiry_critical = "4507s"
+    response_body_regex = "4W5fUv7j"
+    response_json_path = "$.X5G2Nm28"
+    response_json_value = "QONdhz0B"
+    response_headers = {
+        PdVdLuP7 = [ "pyYWzTGe", "mndbHMMu" ]
+    }
+    response_time_warning = "5311s"
     tls_server_name = "7BdnzBYk"
     tls_skip_verify = true
     timeout = "5954s"
@@ -150,6 +174,28 @@ checks = [
         docker_container_id = "ipgdFtjd"
         shell = "qAeOYy0M"
         os_service = "aAjE6m9Z"
+        dns = "pMbXDuCL"
+        dns_query = "1mHoOsFa"
+        dns_record_type = "QfDPrAJ7"
+        dns_rcode = "1fTquWoG"
+        dns_min_answers = 5
+        dns_expected_values = ["beKXgzg2", "sye9b2Ra"]
+        dns_use_tcp = true
+        tls = "RzZ1fb6d"
+        tls_starttls = "smtp"
+        tls_ca_file = "fB8ChQBi"
+        tls_expected_sans = ["Iu4NJkS4", "dJkG0fzM"]
+        tls_expiry_warning = "9413s"
+        flap_threshold = 5
+        flap_window = "6225s"
+        tls_expiry_critical = "5621s"
+        response_body_regex = "JFMWTfDj"
+        response_json_path = "$.0ylRJUp4"
+        response_json_value = "XYNapCeI"
+        response_headers = {
+            Ki4YpSXl = [ "ZCW7cWlB", "uMLt5zdI" ]
+        }
+        response_time_warning = "519s"
         tls_server_name = "bdeb5f6a"
         tls_skip_verify = true
         timeout = "1813s"
@@ -179,6 +225,28 @@ checks = [
         docker_container_id = "THW6u7rL"
         shell = "C1Zt3Zwh"
         os_service = "aqq95BhP"
+        dns = "nn76dEyT"
+        dns_query = "zAeKOmXR"
+        dns_record_type = "rvftva9A"
+        dns_rcode = "W7hipTga"
+        dns_min_answers = 1
+        dns_expected_values = ["DZFlRJmC", "GmUXiAPy"]
+        dns_use_tcp = true
+        tls = "MEEMyIbP"
+        tls_starttls = "smtp"
+        tls_ca_file = "9mYQqw8x"
+        tls_expected_sans = ["3SyRthqp", "vxxGKZWG"]
+        tls_expiry_warning = "7862s"
+        flap_threshold = 4
+        flap_window = "2837s"
+        tls_expiry_critical = "5573s"
+        response_body_regex = "Rll6vg3q"
+        response_json_path = "$.1B9K1S21"
+        response_json_value = "it8upzVM"
+        response_headers = {
+            mftxCc3o = [ "AUfhjLhk", "IE29SKSp" ]
+        }
+        response_time_warning = "9034s"
         tls_server_name = "6adc3bfb"
         tls_skip_verify = true
         timeout = "18506s"
@@ -271,6 +339,14 @@ dns_config {
     use_cache = true
     cache_max_age = "5m"
     prefer_namespace = true
+    zone_transfer_keys = [
+        {
+            name = "Secondary1"
+            algorithm = "hmac-sha512"
+            secret = "bWlXNHVMaG9vbmdvYmVpcA=="
+            token = "Ka9sQdkv"
+        }
+    ]
 }
 enable_acl_replication = true
 enable_agent_tls_for_checks = true
@@ -309,6 +385,35 @@ limits {
         mode = "permissive"
         read_rate = 99.0
         write_rate = 101.0
+        ip_limits {
+            mode = "enforcing"
+            read_rate = 17.0
+            write_rate = 7.0
+            allowlist = ["10.23.0.0/16"]
+            prefixes = [
+                {
+                    cidr = "10.42.0.0/24"
+                    read_rate = 13.0
+                    write_rate = 3.0
+                }
+            ]
+        }
+        endpoints = [
+            {
+                name = "KVS.Apply"
+                mode = "enforcing"
+                read_rate = 31.0
+                write_rate = 11.0
+            }
+        ]
+        tokens = [
+            {
+                accessor_id = "a0c4ab50-5a11-4f0e-8b39-1b6b5e3c2d19"
+                mode = "permissive"
+                read_rate = 29.0
+                write_rate = 19.0
+            }
+        ]
     }
 }
 log_level = "k1zo9Spt"
@@ -333,6 +438,8 @@ performance {
 pid_file = "43xN80Km"
 ports {
     dns = 7001
+    dns_tls = 7853
+    dns_https = 7443
     http = 7999
     https = 15127
     server = 3757
@@ -421,6 +528,28 @@ service = {
         docker_container_id = "dO5TtRHk"
         shell = "e6q2ttES"
         os_service = "RAa85Dv8"
+        dns = "hzAnar3Z"
+        dns_query = "Lt4bnlz2"
+        dns_record_type = "MPKgcjnC"
+        dns_rcode = "qaXNv1sy"
+        dns_min_answers = 2
+        dns_expected_values = ["efnLOpaM", "xxNDi9LE"]
+        dns_use_tcp = true
+        tls = "y02BcHbo"
+        tls_starttls = "postgres"
+        tls_ca_file = "cynXMgWJ"
+        tls_expected_sans = ["oleSrcBr", "FwMOUdGD"]
+        tls_expiry_warning = "3939s"
+        flap_threshold = 7
+        flap_window = "4513s"
+        tls_expiry_critical = "215s"
+        response_body_regex = "37UYZ2ub"
+        response_json_path = "$.kbtkBQDe"
+        response_json_value = "opfPO4JF"
+        response_headers = {
+            rdPHFEex = [ "gQuLOeFG", "t7S9OgQs" ]
+        }
+        response_time_warning = "3274s"
         tls_server_name = "ECSHk8WF"
         tls_skip_verify = true
         timeout = "38483s"
@@ -449,6 +578,28 @@ service = {
             docker_container_id = "ipgdFtjd"
             shell = "omVZq7Sz"
             os_service = "ZA99e9Ka"
+            dns = "1Ki3ylOj"
+            dns_query = "t6o0NpUm"
+            dns_record_type = "kVO8JmR8"
+            dns_rcode = "y4EMfAdg"
+            dns_min_answers = 2
+            dns_expected_values = ["cG9qpVTz", "qA05MFsH"]
+            dns_use_tcp = true
+            tls = "vsDES92E"
+            tls_starttls = "smtp"
+            tls_ca_file = "9kD7Jxlm"
+            tls_expected_sans = ["XVo6Ma8r", "6XZvlpYa"]
+            tls_expiry_warning = "7034s"
+            flap_threshold = 2
+            flap_window = "9872s"
+            tls_expiry_critical = "1810s"
+            response_body_regex = "79zX04tS"
+            response_json_path = "$.qQfEff19"
+            response_json_value = "FbbyemcC"
+            response_headers = {
+                SfG22FpH = [ "liVsIW34", "1eL6cmTH" ]
+            }
+            response_time_warning = "5608s"
             tls_server_name = "axw5QPL5"
             tls_skip_verify = true
             timeout = "18913s"
@@ -476,6 +627,28 @@ service = {
             docker_container_id = "HBndBU6R"
             shell = "hVI33JjA"
             os_service = "GAaO6Mpr"
+            dns = "l7UeioEJ"
+            dns_query = "P2NNern6"
+            dns_record_type = "6nVberAC"
+            dns_rcode = "pdclsxHK"
+            dns_min_answers = 3
+            dns_expected_values = ["fxi5CvQU", "SHL8iLc7"]
+            dns_use_tcp = true
+            tls = "pNhxIGjb"
+            tls_starttls = "postgres"
+            tls_ca_file = "4YQ1rOho"
+            tls_expected_sans = ["YHGEFOwq", "R8C5VkCs"]
+            tls_expiry_warning = "9215s"
+            flap_threshold = 6
+            flap_window = "3382s"
+            tls_expiry_critical = "755s"
+            response_body_regex = "xjjTZYDA"
+            response_json_path = "$.BYDkzW1n"
+            response_json_value = "nVa2iJDf"
+            response_headers = {
+                GG320PKH = [ "qyfK6ARA", "1sti1vUg" ]
+            }
+            response_time_warning = "4274s"
             tls_server_name = "7uwWOnUS"
             tls_skip_verify = true
             timeout = "38282s"
@@ -517,6 +690,28 @@ services = [
             docker_container_id = "ZKXr68Yb"
             shell = "CEfzx0Fo"
             os_service = "amfeO5if"
+            dns = "bE6wSt9c"
+            dns_query = "bMOeEeUt"
+            dns_record_type = "uieeCIxV"
+            dns_rcode = "c57VVTiY"
+            dns_min_answers = 6
+            dns_expected_values = ["wfRE5e32", "A8Yb3FKa"]
+            dns_use_tcp = true
+            tls = "v83GOSCT"
+            tls_starttls = "smtp"
+            tls_ca_file = "UsHqbRaS"
+            tls_expected_sans = ["biJ4rAip", "mBEdJKNU"]
+            tls_expiry_warning = "1753s"
+            flap_threshold = 8
+            flap_window = "5941s"
+            tls_expiry_critical = "669s"
+            response_body_regex = "nz61kK0M"
+            response_json_path = "$.Glp8Mb9d"
+            response_json_value = "mvEaMdDx"
+            response_headers = {
+                XgITTLbS = [ "h3e06mB3", "z3YSNAyB" ]
+            }
+            response_time_warning = "9197s"
             tls_server_name = "4f191d4F"
             tls_skip_verify = true
             timeout = "38333s"
@@ -561,6 +756,28 @@ services = [
                 docker_container_id = "cU15LMet"
                 shell = "nEz9qz2l"
                 os_service = "GTti9hCA"
+                dns = "NQyyLaMe"
+                dns_query = "ffOhq4AU"
+                dns_record_type = "vy7VSLDC"
+                dns_rcode = "D1IfHWGb"
+                dns_min_answers = 5
+                dns_expected_values = ["MfEbo9Sh", "FXNQ6Fq5"]
+                dns_use_tcp = true
+                tls = "ABLf5zYm"
+                tls_starttls = "postgres"
+                tls_ca_file = "xOVyKkbS"
+                tls_expected_sans = ["Tj5bZc5e", "6AImY7yY"]
+                tls_expiry_warning = "5225s"
+                flap_threshold = 9
+                flap_window = "7319s"
+                tls_expiry_critical = "421s"
+                response_body_regex = "aizSIeqF"
+                response_json_path = "$.oHCDBIXN"
+                response_json_value = "fg5uiGgD"
+                response_headers = {
+                    tsV3MiZN = [ "Q1u3HPuw", "4mx81T4i" ]
+                }
+                response_time_warning = "8390s"
                 tls_server_name = "f43ouY7a"
                 tls_skip_verify = true
                 timeout = "34738s"
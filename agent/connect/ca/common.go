@@ -2,6 +2,7 @@ package ca
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/x509"
 	"fmt"
 
@@ -51,12 +52,18 @@ func validateIntermediateSignedByPrivateKey(intermediatePEM string, privateKey s
 		return err
 	}
 
+	return validateIntermediateMatchesPublicKey(intermediate, privKey.Public())
+}
+
+// validateIntermediateMatchesPublicKey returns an error if the intermediate
+// certificate was not issued for the given public key.
+func validateIntermediateMatchesPublicKey(intermediate *x509.Certificate, pub crypto.PublicKey) error {
 	// Compare the two keys to make sure they match.
 	b1, err := x509.MarshalPKIXPublicKey(intermediate.PublicKey)
 	if err != nil {
 		return err
 	}
-	b2, err := x509.MarshalPKIXPublicKey(privKey.Public())
+	b2, err := x509.MarshalPKIXPublicKey(pub)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package ca
 
 import (
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 )
 
@@ -22,6 +23,16 @@ type PrimaryUsesIntermediate interface {
 	PrimaryUsesIntermediate()
 }
 
+// LeafCRLSigner is an optional interface that CA providers may implement to
+// sign a certificate revocation list for the leaf certificates they signed.
+// The CRL is handed to the proxies so that they reject revoked leaf
+// certificates.
+type LeafCRLSigner interface {
+	// SignLeafCRL returns a PEM encoded CRL revoking the given certificates,
+	// signed by the CA certificate returned by ActiveIntermediate.
+	SignLeafCRL(revoked []pkix.RevokedCertificate) (string, error)
+}
+
 // ProviderConfig encapsulates all the data Consul passes to `Configure` on a
 // new provider instance. The provider must treat this as read-only and make
 // copies of any map or slice if it might modify them internally.
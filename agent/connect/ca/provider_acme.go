@@ -0,0 +1,760 @@
+package ca
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/crypto/acme"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/lib"
+)
+
+const (
+	// ACMEOrderTimeout is the maximum time we will spend ordering an
+	// intermediate, including solving the challenges of the ACME server.
+	ACMEOrderTimeout = 2 * time.Minute
+
+	// acmeAccountKeyType and acmeAccountKeyBits define the key generated for
+	// the ACME account. It is only used to sign requests to the ACME server.
+	acmeAccountKeyType = "ec"
+	acmeAccountKeyBits = 256
+)
+
+// errACMESecondary is returned when the ACME provider is used outside of the
+// primary datacenter.
+var errACMESecondary = errors.New("the ACME CA provider is only supported in the primary datacenter")
+
+// ACMEProvider implements Provider by ordering the intermediate used to sign
+// leaf certificates from an ACME server (RFC 8555), such as step-ca. The
+// private key of the intermediate is generated by Consul and stored in the
+// state store like with the built-in provider, only its certificate is issued
+// by the ACME server. The intermediate is ordered again by the renewal routine
+// of the leader before it expires.
+type ACMEProvider struct {
+	Delegate ConsulProviderStateDelegate
+
+	config     *structs.ACMECAProviderConfig
+	id         string
+	accountID  string
+	spiffeID   *connect.SpiffeIDSigning
+	httpClient *http.Client
+	logger     hclog.Logger
+
+	// client is the ACME client of the registered account. It is created on
+	// the first order and reused afterwards.
+	client     *acme.Client
+	clientLock sync.Mutex
+
+	// rootIntermediate is the intermediate ordered by GenerateRoot to learn
+	// the root. It is returned by the next GenerateIntermediate instead of
+	// ordering another one.
+	rootIntermediate string
+
+	sync.Mutex
+}
+
+var _ Provider = (*ACMEProvider)(nil)
+
+// NewACMEProvider returns a new ACMEProvider
+func NewACMEProvider(delegate ConsulProviderStateDelegate, logger hclog.Logger) *ACMEProvider {
+	return &ACMEProvider{Delegate: delegate, logger: logger}
+}
+
+// Configure implements Provider
+func (a *ACMEProvider) Configure(cfg ProviderConfig) error {
+	if !cfg.IsPrimary {
+		return errACMESecondary
+	}
+
+	config, err := ParseACMECAConfig(cfg.RawConfig)
+	if err != nil {
+		return err
+	}
+	httpClient, err := acmeHTTPClient(config)
+	if err != nil {
+		return err
+	}
+
+	a.config = config
+	a.id = acmeProviderID(config)
+	a.accountID = acmeAccountID(config)
+	a.spiffeID = connect.SpiffeIDSigningForCluster(cfg.ClusterID)
+	a.httpClient = httpClient
+
+	a.clientLock.Lock()
+	a.client = nil
+	a.clientLock.Unlock()
+
+	a.Lock()
+	a.rootIntermediate = ""
+	a.Unlock()
+
+	// Exit early if the state store has an entry for this provider's config.
+	providerState, err := a.Delegate.ProviderState(a.id)
+	if err != nil {
+		return err
+	}
+	if providerState != nil {
+		return nil
+	}
+
+	args := &structs.CARequest{
+		Op:            structs.CAOpSetProviderState,
+		ProviderState: &structs.CAConsulProviderState{ID: a.id},
+	}
+	if _, err := a.Delegate.ApplyCARequest(args); err != nil {
+		return err
+	}
+
+	a.logger.Debug("ACME CA provider configured", "id", a.id, "directory_url", config.DirectoryURL)
+
+	return nil
+}
+
+// State implements Provider. The keys and certificates are stored in the
+// state store through the delegate, like for the built-in provider.
+func (a *ACMEProvider) State() (map[string]string, error) {
+	return nil, nil
+}
+
+// GenerateRoot implements Provider. The root is the trust anchor of the
+// intermediates issued by the ACME server. When it is not configured it is
+// taken from the chain returned with the first intermediate.
+func (a *ACMEProvider) GenerateRoot() (RootResult, error) {
+	providerState, err := a.getState()
+	if err != nil {
+		return RootResult{}, err
+	}
+	if providerState.RootCert != "" {
+		return RootResult{PEM: providerState.RootCert}, nil
+	}
+
+	if a.config.RootCert != "" {
+		newState := *providerState
+		newState.RootCert = lib.EnsureTrailingNewline(a.config.RootCert)
+		args := &structs.CARequest{
+			Op:            structs.CAOpSetProviderState,
+			ProviderState: &newState,
+		}
+		if _, err := a.Delegate.ApplyCARequest(args); err != nil {
+			return RootResult{}, err
+		}
+		return RootResult{PEM: newState.RootCert}, nil
+	}
+
+	newState, err := a.renewIntermediate()
+	if err != nil {
+		return RootResult{}, err
+	}
+	a.Lock()
+	a.rootIntermediate = newState.IntermediateCert
+	a.Unlock()
+	return RootResult{PEM: newState.RootCert}, nil
+}
+
+// GenerateIntermediateCSR implements Provider
+func (a *ACMEProvider) GenerateIntermediateCSR() (string, string, error) {
+	return "", "", errACMESecondary
+}
+
+// SetIntermediate implements Provider
+func (a *ACMEProvider) SetIntermediate(_, _, _ string) error {
+	return errACMESecondary
+}
+
+// ActiveIntermediate implements Provider. It returns the intermediate issued
+// by the ACME server followed by the rest of its chain, if any.
+func (a *ACMEProvider) ActiveIntermediate() (string, error) {
+	providerState, err := a.getState()
+	if err != nil {
+		return "", err
+	}
+	return providerState.IntermediateCert, nil
+}
+
+// GenerateIntermediate implements Provider. It generates a new private key
+// and orders a certificate for it from the ACME server, unless GenerateRoot
+// just ordered one which is still the active intermediate.
+func (a *ACMEProvider) GenerateIntermediate() (string, error) {
+	a.Lock()
+	rootIntermediate := a.rootIntermediate
+	a.rootIntermediate = ""
+	a.Unlock()
+	if rootIntermediate != "" {
+		providerState, err := a.getState()
+		if err != nil {
+			return "", err
+		}
+		if providerState.IntermediateCert == rootIntermediate {
+			return rootIntermediate, nil
+		}
+	}
+
+	newState, err := a.renewIntermediate()
+	if err != nil {
+		return "", err
+	}
+	return newState.IntermediateCert, nil
+}
+
+// renewIntermediate orders a new intermediate and writes it to the provider
+// state along with its private key and the root, if it wasn't known yet.
+func (a *ACMEProvider) renewIntermediate() (*structs.CAConsulProviderState, error) {
+	providerState, err := a.getState()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ACMEOrderTimeout)
+	defer cancel()
+
+	signer, pk, chain, err := a.orderIntermediate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rootPEM := providerState.RootCert
+	if rootPEM == "" {
+		rootPEM = a.config.RootCert
+	}
+	if rootPEM == "" {
+		// Use the last certificate of the chain as trust anchor when the root
+		// was not configured.
+		if len(chain) < 2 {
+			return nil, fmt.Errorf("the ACME server did not return the issuer of the intermediate, the root certificate must be configured")
+		}
+		rootPEM = certPEM(chain[len(chain)-1])
+	}
+
+	intermediatePEM, err := validateACMEChain(chain, rootPEM, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	newState := *providerState
+	newState.PrivateKey = pk
+	newState.IntermediateCert = intermediatePEM
+	newState.RootCert = lib.EnsureTrailingNewline(rootPEM)
+	args := &structs.CARequest{
+		Op:            structs.CAOpSetProviderState,
+		ProviderState: &newState,
+	}
+	if _, err := a.Delegate.ApplyCARequest(args); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("obtained new intermediate from ACME server",
+		"serial", connect.HexString(chain[0].SerialNumber.Bytes()),
+		"expiration", chain[0].NotAfter)
+
+	return &newState, nil
+}
+
+// orderIntermediate generates a private key and orders a CA certificate for
+// it from the ACME server. It returns the key and the chain of certificates
+// returned by the server, starting with the intermediate.
+func (a *ACMEProvider) orderIntermediate(ctx context.Context) (crypto.Signer, string, []*x509.Certificate, error) {
+	client, err := a.acmeClient(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	signer, pk, err := connect.GeneratePrivateKeyWithConfig(a.config.PrivateKeyType, a.config.PrivateKeyBits)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	ext, err := connect.CreateCAExtension()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	csrPEM, err := connect.CreateCSR(a.spiffeID, signer, []string{a.config.Domain}, nil, ext)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	csr, err := connect.ParseCSR(csrPEM)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(a.config.Domain))
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("error creating ACME order: %w", err)
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err := a.authorize(ctx, client, authzURL); err != nil {
+			return nil, "", nil, err
+		}
+	}
+	order, err = client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("error waiting for ACME order: %w", err)
+	}
+
+	der, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr.Raw, true)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("error finalizing ACME order: %w", err)
+	}
+	chain := make([]*x509.Certificate, 0, len(der))
+	for _, b := range der {
+		cert, err := x509.ParseCertificate(b)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("error parsing certificate issued by the ACME server: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+
+	return signer, pk, chain, nil
+}
+
+// authorize completes the authorization at the given URL by solving its
+// http-01 challenge, unless it is already valid.
+func (a *ACMEProvider) authorize(ctx context.Context, client *acme.Client, authzURL string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("error getting ACME authorization: %w", err)
+	}
+	switch authz.Status {
+	case acme.StatusValid:
+		return nil
+	case acme.StatusPending:
+	default:
+		return fmt.Errorf("ACME authorization for %q is %s", authz.Identifier.Value, authz.Status)
+	}
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "http-01" {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("ACME authorization for %q has no http-01 challenge", authz.Identifier.Value)
+	}
+	if a.config.HTTPChallengeAddr == "" {
+		return fmt.Errorf("ACME authorization for %q is pending and no http challenge address is configured", authz.Identifier.Value)
+	}
+
+	path := client.HTTP01ChallengePath(challenge.Token)
+	response, err := client.HTTP01ChallengeResponse(challenge.Token)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", a.config.HTTPChallengeAddr)
+	if err != nil {
+		return fmt.Errorf("error listening for http-01 challenge: %w", err)
+	}
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != path {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(response))
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("error accepting ACME challenge: %w", err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("error waiting for ACME authorization: %w", err)
+	}
+	return nil
+}
+
+// acmeClient returns the client of the ACME account, registering it on first
+// use. The account key is kept in the state store so the same account is used
+// by every server.
+func (a *ACMEProvider) acmeClient(ctx context.Context) (*acme.Client, error) {
+	a.clientLock.Lock()
+	defer a.clientLock.Unlock()
+
+	if a.client != nil {
+		return a.client, nil
+	}
+
+	accountState, err := a.Delegate.ProviderState(a.accountID)
+	if err != nil {
+		return nil, err
+	}
+	var key crypto.Signer
+	if accountState != nil && accountState.PrivateKey != "" {
+		key, err = connect.ParseSigner(accountState.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var pk string
+		key, pk, err = connect.GeneratePrivateKeyWithConfig(acmeAccountKeyType, acmeAccountKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		args := &structs.CARequest{
+			Op: structs.CAOpSetProviderState,
+			ProviderState: &structs.CAConsulProviderState{
+				ID:         a.accountID,
+				PrivateKey: pk,
+			},
+		}
+		if _, err := a.Delegate.ApplyCARequest(args); err != nil {
+			return nil, err
+		}
+	}
+
+	client := &acme.Client{
+		Key:          key,
+		DirectoryURL: a.config.DirectoryURL,
+		HTTPClient:   a.httpClient,
+		UserAgent:    "consul",
+	}
+	account := &acme.Account{}
+	if a.config.Email != "" {
+		account.Contact = []string{"mailto:" + a.config.Email}
+	}
+	if a.config.EABKeyID != "" {
+		hmacKey, err := decodeEABHMACKey(a.config.EABHMACKey)
+		if err != nil {
+			return nil, err
+		}
+		account.ExternalAccountBinding = &acme.ExternalAccountBinding{
+			KID: a.config.EABKeyID,
+			Key: hmacKey,
+		}
+	}
+	_, err = client.Register(ctx, account, acme.AcceptTOS)
+	if err != nil && err != acme.ErrAccountAlreadyExists {
+		return nil, fmt.Errorf("error registering ACME account: %w", err)
+	}
+
+	a.client = client
+	return client, nil
+}
+
+// Sign implements Provider. Leaf certificates are signed locally with the
+// private key of the intermediate, they never outlive the intermediate.
+func (a *ACMEProvider) Sign(csr *x509.CertificateRequest) (string, error) {
+	connect.HackSANExtensionForCSR(csr)
+
+	// Lock during the signing so we don't use the same index twice
+	// for different cert serial numbers.
+	a.Lock()
+	defer a.Unlock()
+
+	providerState, err := a.getState()
+	if err != nil {
+		return "", err
+	}
+	if providerState.PrivateKey == "" || providerState.IntermediateCert == "" {
+		return "", ErrNotInitialized
+	}
+
+	signer, err := connect.ParseSigner(providerState.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	keyId, err := connect.KeyId(signer.Public())
+	if err != nil {
+		return "", err
+	}
+	subjectKeyID, err := connect.KeyId(csr.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	caCert, err := connect.ParseCert(providerState.IntermediateCert)
+	if err != nil {
+		return "", fmt.Errorf("error parsing CA cert: %s", err)
+	}
+
+	args := &structs.CARequest{
+		Op: structs.CAOpIncrementProviderSerialNumber,
+	}
+	raw, err := a.Delegate.ApplyCARequest(args)
+	if err != nil {
+		return "", fmt.Errorf("error computing next serial number: %v", err)
+	}
+	sn := &big.Int{}
+	sn.SetUint64(raw.(uint64))
+
+	// Sign the certificate valid from 1 minute in the past, this helps it be
+	// accepted right away even when nodes are not in close time sync across the
+	// cluster. A minute is more than enough for typical DC clock drift.
+	effectiveNow := time.Now().Add(-1 * CertificateTimeDriftBuffer)
+	notAfter := effectiveNow.Add(a.config.LeafCertTTL)
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+	template := x509.Certificate{
+		SerialNumber:          sn,
+		URIs:                  csr.URIs,
+		Signature:             csr.Signature,
+		SignatureAlgorithm:    connect.SigAlgoForKey(signer),
+		PublicKeyAlgorithm:    csr.PublicKeyAlgorithm,
+		PublicKey:             csr.PublicKey,
+		BasicConstraintsValid: true,
+		KeyUsage: x509.KeyUsageDataEncipherment |
+			x509.KeyUsageKeyAgreement |
+			x509.KeyUsageDigitalSignature |
+			x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageClientAuth,
+			x509.ExtKeyUsageServerAuth,
+		},
+		NotAfter:       notAfter,
+		NotBefore:      effectiveNow,
+		AuthorityKeyId: keyId,
+		SubjectKeyId:   subjectKeyID,
+		DNSNames:       csr.DNSNames,
+		IPAddresses:    csr.IPAddresses,
+	}
+
+	bs, err := x509.CreateCertificate(rand.Reader, &template, caCert, csr.PublicKey, signer)
+	if err != nil {
+		return "", fmt.Errorf("error generating certificate: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: bs}); err != nil {
+		return "", fmt.Errorf("error encoding certificate: %s", err)
+	}
+
+	return buf.String(), nil
+}
+
+// SignIntermediate implements Provider. Intermediates of secondary
+// datacenters are not supported by the ACME provider.
+func (a *ACMEProvider) SignIntermediate(*x509.CertificateRequest) (string, error) {
+	return "", errACMESecondary
+}
+
+// CrossSignCA implements Provider. The root belongs to the ACME server so
+// Consul cannot cross-sign with it.
+func (a *ACMEProvider) CrossSignCA(*x509.Certificate) (string, error) {
+	return "", errors.New("the ACME CA provider does not support cross-signing")
+}
+
+// SupportsCrossSigning implements Provider
+func (a *ACMEProvider) SupportsCrossSigning() (bool, error) {
+	return false, nil
+}
+
+// Cleanup implements Provider. It removes the state store entries of the
+// provider, the ACME account is kept when the next configuration uses it.
+func (a *ACMEProvider) Cleanup(providerTypeChange bool, otherConfig map[string]interface{}) error {
+	var otherID, otherAccountID string
+	if !providerTypeChange {
+		if other, err := ParseACMECAConfig(otherConfig); err == nil {
+			otherID = acmeProviderID(other)
+			otherAccountID = acmeAccountID(other)
+		}
+	}
+
+	if otherID != a.id {
+		args := &structs.CARequest{
+			Op:            structs.CAOpDeleteProviderState,
+			ProviderState: &structs.CAConsulProviderState{ID: a.id},
+		}
+		if _, err := a.Delegate.ApplyCARequest(args); err != nil {
+			return err
+		}
+	}
+	if otherAccountID != a.accountID {
+		args := &structs.CARequest{
+			Op:            structs.CAOpDeleteProviderState,
+			ProviderState: &structs.CAConsulProviderState{ID: a.accountID},
+		}
+		if _, err := a.Delegate.ApplyCARequest(args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrimaryUsesIntermediate implements PrimaryUsesIntermediate so the
+// intermediate is renewed by the leader.
+func (a *ACMEProvider) PrimaryUsesIntermediate() {}
+
+// getState returns the current provider state from the state delegate, and returns
+// ErrNotInitialized if no entry is found.
+func (a *ACMEProvider) getState() (*structs.CAConsulProviderState, error) {
+	providerState, err := a.Delegate.ProviderState(a.id)
+	if err != nil {
+		return nil, err
+	}
+	if providerState == nil {
+		return nil, ErrNotInitialized
+	}
+	return providerState, nil
+}
+
+// validateACMEChain verifies that the intermediate issued by the ACME server
+// is a CA for the given public key which chains up to the root. It returns the
+// PEM bundle of the intermediate followed by the certificates of the chain
+// other than the root.
+func validateACMEChain(chain []*x509.Certificate, rootPEM string, pub crypto.PublicKey) (string, error) {
+	if len(chain) == 0 {
+		return "", errors.New("the ACME server returned an empty certificate chain")
+	}
+	intermediate := chain[0]
+	if !intermediate.IsCA {
+		return "", errors.New("the certificate issued by the ACME server is not a CA certificate")
+	}
+	if err := validateIntermediateMatchesPublicKey(intermediate, pub); err != nil {
+		return "", err
+	}
+
+	root, err := connect.ParseCert(rootPEM)
+	if err != nil {
+		return "", fmt.Errorf("error parsing root cert: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	var buf bytes.Buffer
+	intermediates := x509.NewCertPool()
+	for i, cert := range chain {
+		if cert.Equal(root) {
+			continue
+		}
+		if i > 0 {
+			intermediates.AddCert(cert)
+		}
+		buf.WriteString(certPEM(cert))
+	}
+
+	_, err = intermediate.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return "", fmt.Errorf("the certificate issued by the ACME server does not chain to the root: %v", err)
+	}
+
+	return buf.String(), nil
+}
+
+func certPEM(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+// acmeHTTPClient returns the client used to reach the ACME server, trusting
+// the certificates of the CA file in addition to the system roots.
+func acmeHTTPClient(config *structs.ACMECAProviderConfig) (*http.Client, error) {
+	if config.CAFile == "" {
+		return http.DefaultClient, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	pemBytes, err := os.ReadFile(config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CA file: %v", err)
+	}
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("no certificates found in CA file %q", config.CAFile)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return &http.Client{Transport: transport}, nil
+}
+
+// decodeEABHMACKey decodes the base64url encoded HMAC key of the external
+// account binding, with or without padding.
+func decodeEABHMACKey(key string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(key, "="))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding EAB HMAC key: %v", err)
+	}
+	return b, nil
+}
+
+// acmeProviderID returns the ID of the provider state entry holding the
+// intermediate and its private key.
+func acmeProviderID(config *structs.ACMECAProviderConfig) string {
+	return hexStringHash(fmt.Sprintf("acme,%s,%s,%s,%s,%d", config.DirectoryURL, config.Domain,
+		config.RootCert, config.PrivateKeyType, config.PrivateKeyBits))
+}
+
+// acmeAccountID returns the ID of the provider state entry holding the key of
+// the ACME account.
+func acmeAccountID(config *structs.ACMECAProviderConfig) string {
+	return hexStringHash(fmt.Sprintf("acme-account,%s,%s", config.DirectoryURL, config.EABKeyID))
+}
+
+// ParseACMECAConfig parses and validates ACME CA Provider configuration.
+func ParseACMECAConfig(raw map[string]interface{}) (*structs.ACMECAProviderConfig, error) {
+	config := structs.ACMECAProviderConfig{
+		CommonCAProviderConfig: defaultCommonConfig(),
+	}
+
+	decodeConf := &mapstructure.DecoderConfig{
+		DecodeHook:       structs.ParseDurationFunc(),
+		Result:           &config,
+		WeaklyTypedInput: true,
+	}
+
+	decoder, err := mapstructure.NewDecoder(decodeConf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decoder.Decode(raw); err != nil {
+		return nil, fmt.Errorf("error decoding config: %s", err)
+	}
+
+	if config.DirectoryURL == "" {
+		return nil, fmt.Errorf("must provide the ACME directory URL")
+	}
+
+	if config.Domain == "" {
+		return nil, fmt.Errorf("must provide the domain to order the intermediate for")
+	}
+
+	if (config.EABKeyID == "") != (config.EABHMACKey == "") {
+		return nil, fmt.Errorf("EAB key ID and HMAC key must be provided together")
+	}
+	if config.EABHMACKey != "" {
+		if _, err := decodeEABHMACKey(config.EABHMACKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.RootCert != "" {
+		if _, err := connect.ParseCert(config.RootCert); err != nil {
+			return nil, fmt.Errorf("error parsing root cert: %v", err)
+		}
+	}
+
+	if err := config.CommonCAProviderConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
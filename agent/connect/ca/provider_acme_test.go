@@ -0,0 +1,572 @@
+package ca
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/sdk/freeport"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+// testACMEServer is a minimal ACME server issuing CA certificates from a
+// two level test PKI. It validates http-01 challenges against the address
+// configured in the provider unless authorizations are pre-validated.
+type testACMEServer struct {
+	*httptest.Server
+
+	rootPEM   string
+	issuer    *x509.Certificate
+	issuerKey crypto.Signer
+
+	// validAuthz makes authorizations valid without solving the challenge.
+	validAuthz bool
+	// ttl is the lifetime of the issued intermediates.
+	ttl time.Duration
+	// challengeURL is the base URL http-01 challenges are validated against.
+	challengeURL string
+
+	lock        sync.Mutex
+	orders      int
+	accounts    int
+	eabKeyIDs   []string
+	identifiers []string
+	challenged  map[string]bool
+	certs       map[string]string
+}
+
+func newTestACMEServer(t *testing.T) *testACMEServer {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	root := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test ACME Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, root, root, rootKey.Public(), rootKey)
+	require.NoError(t, err)
+	root, err = x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issuer := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "Test ACME Issuer"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	issuerDER, err := x509.CreateCertificate(rand.Reader, issuer, root, issuerKey.Public(), rootKey)
+	require.NoError(t, err)
+	issuer, err = x509.ParseCertificate(issuerDER)
+	require.NoError(t, err)
+
+	s := &testACMEServer{
+		rootPEM:    certPEM(root),
+		issuer:     issuer,
+		issuerKey:  issuerKey,
+		ttl:        24 * time.Hour,
+		challenged: make(map[string]bool),
+		certs:      make(map[string]string),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testACMEServer) directoryURL() string {
+	return s.URL + "/directory"
+}
+
+func (s *testACMEServer) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", time.Now().UnixNano()))
+
+	if r.URL.Path == "/directory" {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"newNonce":   s.URL + "/new-nonce",
+			"newAccount": s.URL + "/new-account",
+			"newOrder":   s.URL + "/new-order",
+			"revokeCert": s.URL + "/revoke-cert",
+			"keyChange":  s.URL + "/key-change",
+		})
+		return
+	}
+	if r.URL.Path == "/new-nonce" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch parts[0] {
+	case "new-account":
+		var req struct {
+			EAB *struct {
+				Protected string `json:"protected"`
+			} `json:"externalAccountBinding"`
+		}
+		json.Unmarshal(payload, &req)
+		if req.EAB != nil {
+			protected, _ := base64.RawURLEncoding.DecodeString(req.EAB.Protected)
+			var header struct {
+				KID string `json:"kid"`
+			}
+			json.Unmarshal(protected, &header)
+			s.eabKeyIDs = append(s.eabKeyIDs, header.KID)
+		}
+		s.accounts++
+		w.Header().Set("Location", s.URL+"/account/1")
+		writeJSON(w, http.StatusCreated, map[string]string{"status": "valid"})
+
+	case "new-order":
+		var req struct {
+			Identifiers []struct {
+				Value string `json:"value"`
+			} `json:"identifiers"`
+		}
+		json.Unmarshal(payload, &req)
+		for _, id := range req.Identifiers {
+			s.identifiers = append(s.identifiers, id.Value)
+		}
+		s.orders++
+		w.Header().Set("Location", fmt.Sprintf("%s/order/%d", s.URL, s.orders))
+		writeJSON(w, http.StatusCreated, s.order(fmt.Sprint(s.orders), "pending"))
+
+	case "authz":
+		status := "pending"
+		if s.validAuthz || s.challenged[parts[1]] {
+			status = "valid"
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status":     status,
+			"identifier": map[string]string{"type": "dns", "value": "consul.example.com"},
+			"challenges": []map[string]string{{
+				"type":   "http-01",
+				"url":    s.URL + "/chal/" + parts[1],
+				"token":  "token" + parts[1],
+				"status": status,
+			}},
+		})
+
+	case "chal":
+		// Validate the challenge right away so the authorization is valid when
+		// it is polled.
+		resp, err := http.Get(s.challengeURL + "/.well-known/acme-challenge/token" + parts[1])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.HasPrefix(string(body), "token"+parts[1]+".") {
+			http.Error(w, "invalid key authorization", http.StatusBadRequest)
+			return
+		}
+		s.challenged[parts[1]] = true
+		writeJSON(w, http.StatusOK, map[string]string{"type": "http-01", "status": "valid"})
+
+	case "order":
+		writeJSON(w, http.StatusOK, s.order(parts[1], "ready"))
+
+	case "finalize":
+		var req struct {
+			CSR string `json:"csr"`
+		}
+		json.Unmarshal(payload, &req)
+		der, _ := base64.RawURLEncoding.DecodeString(req.CSR)
+		csr, err := x509.ParseCertificateRequest(der)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(time.Now().UnixNano()),
+			Subject:               pkix.Name{CommonName: csr.DNSNames[0]},
+			DNSNames:              csr.DNSNames,
+			URIs:                  csr.URIs,
+			NotBefore:             time.Now().Add(-time.Minute),
+			NotAfter:              time.Now().Add(s.ttl),
+			IsCA:                  true,
+			MaxPathLenZero:        true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		certDER, err := x509.CreateCertificate(rand.Reader, template, s.issuer, csr.PublicKey, s.issuerKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		cert, _ := x509.ParseCertificate(certDER)
+		s.certs[parts[1]] = certPEM(cert) + certPEM(s.issuer)
+		w.Header().Set("Location", fmt.Sprintf("%s/order/%s", s.URL, parts[1]))
+		writeJSON(w, http.StatusOK, s.order(parts[1], "valid"))
+
+	case "cert":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(s.certs[parts[1]]))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *testACMEServer) order(n string, status string) map[string]interface{} {
+	if _, ok := s.certs[n]; ok {
+		status = "valid"
+	}
+	return map[string]interface{}{
+		"status":         status,
+		"identifiers":    []map[string]string{{"type": "dns", "value": "consul.example.com"}},
+		"authorizations": []string{fmt.Sprintf("%s/authz/%s", s.URL, n)},
+		"finalize":       fmt.Sprintf("%s/finalize/%s", s.URL, n),
+		"certificate":    fmt.Sprintf("%s/cert/%s", s.URL, n),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func testACMEProviderConfig(t *testing.T, s *testACMEServer, cfg map[string]interface{}) ProviderConfig {
+	addr := fmt.Sprintf("127.0.0.1:%d", freeport.GetOne(t))
+	s.challengeURL = "http://" + addr
+
+	rawCfg := map[string]interface{}{
+		"DirectoryURL":      s.directoryURL(),
+		"Domain":            "consul.example.com",
+		"HTTPChallengeAddr": addr,
+	}
+	for k, v := range cfg {
+		rawCfg[k] = v
+	}
+	return ProviderConfig{
+		ClusterID:  connect.TestClusterID,
+		Datacenter: "dc1",
+		IsPrimary:  true,
+		RawConfig:  rawCfg,
+	}
+}
+
+func testACMEProvider(t *testing.T, delegate ConsulProviderStateDelegate, cfg ProviderConfig) *ACMEProvider {
+	p := NewACMEProvider(delegate, testutil.Logger(t))
+	require.NoError(t, p.Configure(cfg))
+	return p
+}
+
+func TestParseACMECAConfig(t *testing.T) {
+	cases := map[string]struct {
+		rawConfig map[string]interface{}
+		expectErr string
+	}{
+		"valid": {
+			rawConfig: map[string]interface{}{"DirectoryURL": "https://ca/acme/directory", "Domain": "consul.example.com"},
+		},
+		"valid with EAB": {
+			rawConfig: map[string]interface{}{
+				"DirectoryURL": "https://ca/acme/directory",
+				"Domain":       "consul.example.com",
+				"EABKeyID":     "kid",
+				"EABHMACKey":   "c2VjcmV0",
+			},
+		},
+		"missing directory URL": {
+			rawConfig: map[string]interface{}{"Domain": "consul.example.com"},
+			expectErr: "must provide the ACME directory URL",
+		},
+		"missing domain": {
+			rawConfig: map[string]interface{}{"DirectoryURL": "https://ca/acme/directory"},
+			expectErr: "must provide the domain to order the intermediate for",
+		},
+		"EAB key ID without HMAC key": {
+			rawConfig: map[string]interface{}{
+				"DirectoryURL": "https://ca/acme/directory",
+				"Domain":       "consul.example.com",
+				"EABKeyID":     "kid",
+			},
+			expectErr: "EAB key ID and HMAC key must be provided together",
+		},
+		"invalid EAB HMAC key": {
+			rawConfig: map[string]interface{}{
+				"DirectoryURL": "https://ca/acme/directory",
+				"Domain":       "consul.example.com",
+				"EABKeyID":     "kid",
+				"EABHMACKey":   "not base64!",
+			},
+			expectErr: "error decoding EAB HMAC key: illegal base64 data at input byte 3",
+		},
+		"invalid root cert": {
+			rawConfig: map[string]interface{}{
+				"DirectoryURL": "https://ca/acme/directory",
+				"Domain":       "consul.example.com",
+				"RootCert":     "not a cert",
+			},
+			expectErr: "error parsing root cert: no PEM-encoded data found",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseACMECAConfig(tc.rawConfig)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestACMECAProvider_SignLeaf(t *testing.T) {
+	for _, tc := range KeyTestCases {
+		tc := tc
+		t.Run(tc.Desc, func(t *testing.T) {
+			s := newTestACMEServer(t)
+			delegate := newMockDelegate(t, testConsulCAConfig())
+			provider := testACMEProvider(t, delegate, testACMEProviderConfig(t, s, map[string]interface{}{
+				"PrivateKeyType": tc.KeyType,
+				"PrivateKeyBits": tc.KeyBits,
+			}))
+
+			root, err := provider.GenerateRoot()
+			require.NoError(t, err)
+			// The last certificate of the chain is used as root when none is
+			// configured.
+			require.Equal(t, certPEM(s.issuer), root.PEM)
+
+			interPEM, err := provider.GenerateIntermediate()
+			require.NoError(t, err)
+			active, err := provider.ActiveIntermediate()
+			require.NoError(t, err)
+			require.Equal(t, interPEM, active)
+
+			inter, err := connect.ParseCert(interPEM)
+			require.NoError(t, err)
+			require.True(t, inter.IsCA)
+			require.Equal(t, []string{"consul.example.com"}, inter.DNSNames)
+			keyType, keyBits, err := connect.KeyInfoFromCert(inter)
+			require.NoError(t, err)
+			require.Equal(t, tc.KeyType, keyType)
+			require.Equal(t, tc.KeyBits, keyBits)
+
+			testSignAndValidate(t, provider, root.PEM, []string{interPEM})
+
+			// The intermediate ordered to learn the root is the one used, so
+			// initializing the provider places a single order.
+			s.lock.Lock()
+			defer s.lock.Unlock()
+			require.Equal(t, 1, s.accounts)
+			require.Equal(t, 1, s.orders)
+			require.Equal(t, []string{"consul.example.com"}, s.identifiers)
+			require.Len(t, s.challenged, 1)
+		})
+	}
+}
+
+func TestACMECAProvider_RootCert(t *testing.T) {
+	s := newTestACMEServer(t)
+	s.validAuthz = true
+	delegate := newMockDelegate(t, testConsulCAConfig())
+	provider := testACMEProvider(t, delegate, testACMEProviderConfig(t, s, map[string]interface{}{
+		"RootCert": s.rootPEM,
+	}))
+
+	root, err := provider.GenerateRoot()
+	require.NoError(t, err)
+	require.Equal(t, s.rootPEM, root.PEM)
+
+	// The root is configured so it doesn't need to be ordered.
+	s.lock.Lock()
+	require.Equal(t, 0, s.orders)
+	s.lock.Unlock()
+
+	// The intermediate of the ACME server is part of the bundle.
+	interPEM, err := provider.GenerateIntermediate()
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(interPEM, certPEM(s.issuer)))
+
+	testSignAndValidate(t, provider, root.PEM, []string{interPEM})
+
+	t.Run("chain to another root", func(t *testing.T) {
+		other := newTestACMEServer(t)
+		other.validAuthz = true
+		delegate := newMockDelegate(t, testConsulCAConfig())
+		provider := testACMEProvider(t, delegate, testACMEProviderConfig(t, other, map[string]interface{}{
+			"RootCert": s.rootPEM,
+		}))
+
+		_, err := provider.GenerateRoot()
+		require.NoError(t, err)
+		_, err = provider.GenerateIntermediate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the certificate issued by the ACME server does not chain to the root")
+	})
+}
+
+func TestACMECAProvider_RenewIntermediate(t *testing.T) {
+	s := newTestACMEServer(t)
+	// Intermediates shorter lived than leaf certificates are fine, the leaf
+	// certificates are capped to the lifetime of the intermediate.
+	s.ttl = 4 * time.Hour
+	delegate := newMockDelegate(t, testConsulCAConfig())
+	cfg := testACMEProviderConfig(t, s, nil)
+	provider := testACMEProvider(t, delegate, cfg)
+
+	root, err := provider.GenerateRoot()
+	require.NoError(t, err)
+	interPEM, err := provider.GenerateIntermediate()
+	require.NoError(t, err)
+	state, err := delegate.ProviderState(provider.id)
+	require.NoError(t, err)
+	oldKey := state.PrivateKey
+
+	// A provider configured on another server uses the same state and account.
+	provider = testACMEProvider(t, delegate, cfg)
+	newRoot, err := provider.GenerateRoot()
+	require.NoError(t, err)
+	require.Equal(t, root.PEM, newRoot.PEM)
+
+	newInterPEM, err := provider.GenerateIntermediate()
+	require.NoError(t, err)
+	require.NotEqual(t, interPEM, newInterPEM)
+	state, err = delegate.ProviderState(provider.id)
+	require.NoError(t, err)
+	require.NotEqual(t, oldKey, state.PrivateKey)
+	require.Equal(t, newInterPEM, state.IntermediateCert)
+
+	csrPEM, _ := connect.TestCSR(t, connect.TestSpiffeIDService(t, "testsvc"))
+	csr, err := connect.ParseCSR(csrPEM)
+	require.NoError(t, err)
+	leafPEM, err := provider.Sign(csr)
+	require.NoError(t, err)
+	require.NoError(t, connect.ValidateLeaf(root.PEM, leafPEM, []string{newInterPEM}))
+
+	leaf, err := connect.ParseCert(leafPEM)
+	require.NoError(t, err)
+	inter, err := connect.ParseCert(newInterPEM)
+	require.NoError(t, err)
+	require.Equal(t, inter.NotAfter, leaf.NotAfter)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	require.Equal(t, 2, s.accounts)
+	require.Equal(t, 2, s.orders)
+}
+
+func TestACMECAProvider_Authorization(t *testing.T) {
+	t.Run("external account binding", func(t *testing.T) {
+		s := newTestACMEServer(t)
+		s.validAuthz = true
+		delegate := newMockDelegate(t, testConsulCAConfig())
+		provider := testACMEProvider(t, delegate, testACMEProviderConfig(t, s, map[string]interface{}{
+			"EABKeyID":   "kid-1",
+			"EABHMACKey": "c2VjcmV0",
+		}))
+
+		_, err := provider.GenerateRoot()
+		require.NoError(t, err)
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		require.Equal(t, []string{"kid-1"}, s.eabKeyIDs)
+		require.Empty(t, s.challenged)
+	})
+
+	t.Run("pending without challenge address", func(t *testing.T) {
+		s := newTestACMEServer(t)
+		delegate := newMockDelegate(t, testConsulCAConfig())
+		provider := testACMEProvider(t, delegate, testACMEProviderConfig(t, s, map[string]interface{}{
+			"HTTPChallengeAddr": "",
+		}))
+
+		_, err := provider.GenerateRoot()
+		require.EqualError(t, err, `ACME authorization for "consul.example.com" is pending and no http challenge address is configured`)
+	})
+}
+
+func TestACMECAProvider_Secondary(t *testing.T) {
+	s := newTestACMEServer(t)
+	cfg := testACMEProviderConfig(t, s, nil)
+	cfg.IsPrimary = false
+	cfg.Datacenter = "dc2"
+
+	provider := NewACMEProvider(newMockDelegate(t, testConsulCAConfig()), testutil.Logger(t))
+	require.Equal(t, errACMESecondary, provider.Configure(cfg))
+}
+
+func TestACMECAProvider_Cleanup(t *testing.T) {
+	s := newTestACMEServer(t)
+	s.validAuthz = true
+	delegate := newMockDelegate(t, testConsulCAConfig())
+	cfg := testACMEProviderConfig(t, s, nil)
+	provider := testACMEProvider(t, delegate, cfg)
+
+	_, err := provider.GenerateRoot()
+	require.NoError(t, err)
+
+	requireState := func(id string, exists bool) {
+		t.Helper()
+		state, err := delegate.ProviderState(id)
+		require.NoError(t, err)
+		if exists {
+			require.NotNil(t, state)
+		} else {
+			require.Nil(t, state)
+		}
+	}
+
+	// Another domain with the same ACME account keeps the account.
+	otherConfig := map[string]interface{}{}
+	for k, v := range cfg.RawConfig {
+		otherConfig[k] = v
+	}
+	otherConfig["Domain"] = "other.example.com"
+	require.NoError(t, provider.Cleanup(false, otherConfig))
+	requireState(provider.id, false)
+	requireState(provider.accountID, true)
+
+	require.NoError(t, provider.Cleanup(true, nil))
+	requireState(provider.accountID, false)
+}
@@ -415,6 +415,56 @@ func (c *ConsulProvider) Sign(csr *x509.CertificateRequest) (string, error) {
 	return buf.String(), nil
 }
 
+// SignLeafCRL implements LeafCRLSigner.
+func (c *ConsulProvider) SignLeafCRL(revoked []pkix.RevokedCertificate) (string, error) {
+	providerState, err := c.getState()
+	if err != nil {
+		return "", err
+	}
+	if providerState.PrivateKey == "" {
+		return "", ErrNotInitialized
+	}
+
+	signer, err := connect.ParseSigner(providerState.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	if signer == nil {
+		return "", ErrNotInitialized
+	}
+
+	certPEM, err := c.ActiveIntermediate()
+	if err != nil {
+		return "", err
+	}
+	caCert, err := connect.ParseCert(certPEM)
+	if err != nil {
+		return "", fmt.Errorf("error parsing CA cert: %s", err)
+	}
+
+	// The CRL is signed again whenever the revoked certificates change, so
+	// it stays valid for as long as the CA cert that issued it.
+	now := time.Now()
+	template := x509.RevocationList{
+		SignatureAlgorithm:  connect.SigAlgoForKey(signer),
+		RevokedCertificates: revoked,
+		Number:              big.NewInt(now.UnixNano()),
+		ThisUpdate:          now.Add(-1 * time.Minute),
+		NextUpdate:          caCert.NotAfter,
+	}
+
+	var buf bytes.Buffer
+	bs, err := x509.CreateRevocationList(rand.Reader, &template, caCert, signer)
+	if err != nil {
+		return "", fmt.Errorf("error generating CRL: %s", err)
+	}
+	err = pem.Encode(&buf, &pem.Block{Type: "X509 CRL", Bytes: bs})
+	if err != nil {
+		return "", fmt.Errorf("error encoding CRL: %s", err)
+	}
+	return buf.String(), nil
+}
+
 // SignIntermediate will validate the CSR to ensure the trust domain in the
 // URI SAN matches the local one and that basic constraints for a CA certificate
 // are met. It should return a signed CA certificate with a path length constraint
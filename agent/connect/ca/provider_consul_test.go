@@ -2,6 +2,8 @@ package ca
 
 import (
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"testing"
 	"time"
@@ -252,6 +254,90 @@ func TestConsulCAProvider_SignLeaf(t *testing.T) {
 	}
 }
 
+func TestConsulCAProvider_SignLeafCRL(t *testing.T) {
+	t.Parallel()
+
+	spiffeService := &connect.SpiffeIDService{
+		Host:       connect.TestClusterID + ".consul",
+		Namespace:  "default",
+		Datacenter: "dc1",
+		Service:    "foo",
+	}
+
+	requireCRL := func(t *testing.T, provider Provider) {
+		raw, _ := connect.TestCSR(t, spiffeService)
+		csr, err := connect.ParseCSR(raw)
+		require.NoError(t, err)
+		leafPEM, err := provider.Sign(csr)
+		require.NoError(t, err)
+		leaf, err := connect.ParseCert(leafPEM)
+		require.NoError(t, err)
+
+		revokedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+		crlPEM, err := provider.(LeafCRLSigner).SignLeafCRL([]pkix.RevokedCertificate{
+			{SerialNumber: leaf.SerialNumber, RevocationTime: revokedAt},
+		})
+		require.NoError(t, err)
+
+		block, _ := pem.Decode([]byte(crlPEM))
+		require.NotNil(t, block)
+		require.Equal(t, "X509 CRL", block.Type)
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		require.NoError(t, err)
+
+		// The CRL must be signed by the issuer of the leaf certificates.
+		caPEM, err := provider.ActiveIntermediate()
+		require.NoError(t, err)
+		caCert, err := connect.ParseCert(caPEM)
+		require.NoError(t, err)
+		require.NoError(t, crl.CheckSignatureFrom(caCert))
+		require.NoError(t, leaf.CheckSignatureFrom(caCert))
+
+		require.Len(t, crl.RevokedCertificates, 1)
+		require.Equal(t, leaf.SerialNumber, crl.RevokedCertificates[0].SerialNumber)
+		require.Equal(t, revokedAt, crl.RevokedCertificates[0].RevocationTime)
+		require.Equal(t, caCert.NotAfter, crl.NextUpdate)
+	}
+
+	conf1 := testConsulCAConfig()
+	delegate1 := newMockDelegate(t, conf1)
+	provider1 := TestConsulProvider(t, delegate1)
+	require.NoError(t, provider1.Configure(testProviderConfig(conf1)))
+	_, err := provider1.GenerateRoot()
+	require.NoError(t, err)
+
+	t.Run("primary", func(t *testing.T) {
+		requireCRL(t, provider1)
+	})
+
+	t.Run("secondary", func(t *testing.T) {
+		conf2 := testConsulCAConfig()
+		conf2.CreateIndex = 10
+		delegate2 := newMockDelegate(t, conf2)
+		provider2 := TestConsulProvider(t, delegate2)
+		cfg := testProviderConfig(conf2)
+		cfg.IsPrimary = false
+		cfg.Datacenter = "dc2"
+		require.NoError(t, provider2.Configure(cfg))
+
+		// The CRL can't be signed until the intermediate is set.
+		_, err := provider2.SignLeafCRL(nil)
+		require.Error(t, err)
+
+		csrPEM, opaque, err := provider2.GenerateIntermediateCSR()
+		require.NoError(t, err)
+		csr, err := connect.ParseCSR(csrPEM)
+		require.NoError(t, err)
+		intermediatePEM, err := provider1.SignIntermediate(csr)
+		require.NoError(t, err)
+		root, err := provider1.GenerateRoot()
+		require.NoError(t, err)
+		require.NoError(t, provider2.SetIntermediate(intermediatePEM, root.PEM, opaque))
+
+		requireCRL(t, provider2)
+	})
+}
+
 func TestConsulCAProvider_CrossSignCA(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
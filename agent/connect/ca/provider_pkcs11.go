@@ -0,0 +1,673 @@
+//go:build cgo
+
+package ca
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ThalesIgnite/crypto11"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// PKCS11Provider implements Provider using keys generated in and held by a
+// PKCS#11 token such as a hardware security module. The private keys never
+// leave the token, only their CKA_IDs and the CA certificates are persisted
+// through the provider State.
+type PKCS11Provider struct {
+	config    *structs.PKCS11CAProviderConfig
+	configID  string
+	ctx       *crypto11.Context
+	clusterID string
+	isPrimary bool
+	spiffeID  *connect.SpiffeIDSigning
+	logger    hclog.Logger
+
+	rootKeyID         string
+	rootKey           crypto11.Signer
+	rootPEM           string
+	intermediateKeyID string
+	intermediateKey   crypto11.Signer
+	intermediatePEM   string
+
+	// pendingKeys are the intermediate keys generated for CSRs which haven't
+	// been passed to SetIntermediate yet, by hex encoded CKA_ID.
+	pendingKeys map[string]crypto11.Signer
+
+	sync.RWMutex
+}
+
+var _ Provider = (*PKCS11Provider)(nil)
+
+// NewPKCS11Provider returns a new PKCS11Provider
+func NewPKCS11Provider(logger hclog.Logger) *PKCS11Provider {
+	return &PKCS11Provider{logger: logger}
+}
+
+// Configure implements Provider
+func (p *PKCS11Provider) Configure(cfg ProviderConfig) error {
+	config, err := ParsePKCS11CAConfig(cfg.RawConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := crypto11.Configure(&crypto11.Config{
+		Path:        config.Lib,
+		TokenLabel:  config.TokenLabel,
+		TokenSerial: config.TokenSerial,
+		Pin:         config.PIN,
+	})
+	if err != nil {
+		return fmt.Errorf("error opening PKCS#11 token: %v", err)
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.config = config
+	p.configID = pkcs11ConfigID(config, cfg.IsPrimary)
+	p.ctx = ctx
+	p.clusterID = cfg.ClusterID
+	p.isPrimary = cfg.IsPrimary
+	p.spiffeID = connect.SpiffeIDSigningForCluster(p.clusterID)
+	p.pendingKeys = make(map[string]crypto11.Signer)
+
+	if err := p.loadState(cfg.State); err != nil {
+		p.close()
+		return err
+	}
+
+	p.logger.Debug("pkcs11 CA provider configured",
+		"root_key_id", p.rootKeyID,
+		"intermediate_key_id", p.intermediateKeyID,
+		"is_primary", p.isPrimary,
+	)
+	return nil
+}
+
+// loadState finds the keys persisted in the provider state in the token.
+// Keys generated for a different configuration, such as another key type, are
+// not reused.
+func (p *PKCS11Provider) loadState(state map[string]string) error {
+	if state[PKCS11StateConfigIDKey] != p.configID {
+		return nil
+	}
+
+	if id := state[PKCS11StateRootKeyIDKey]; id != "" {
+		key, err := p.findKey(id)
+		if err != nil {
+			return err
+		}
+		// The root can't be replaced without a rotation, so refuse to continue
+		// rather than silently generating a new one.
+		if key == nil {
+			return fmt.Errorf("root key %s not found in PKCS#11 token", id)
+		}
+		p.rootKeyID = id
+		p.rootKey = key
+		p.rootPEM = state[PKCS11StateRootCertKey]
+	}
+
+	if id := state[PKCS11StateIntermediateKeyIDKey]; id != "" {
+		key, err := p.findKey(id)
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			p.logger.Warn("intermediate key not found in PKCS#11 token, a new intermediate will be generated", "key_id", id)
+			return nil
+		}
+		p.intermediateKeyID = id
+		p.intermediateKey = key
+		p.intermediatePEM = state[PKCS11StateIntermediateCertKey]
+	}
+	return nil
+}
+
+// State implements Provider. The state holds the IDs of the keys in the token
+// along with the certificates issued for them.
+func (p *PKCS11Provider) State() (map[string]string, error) {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.rootKeyID == "" && p.intermediateKeyID == "" {
+		return nil, nil
+	}
+
+	state := map[string]string{
+		PKCS11StateConfigIDKey: p.configID,
+	}
+	if p.rootKeyID != "" {
+		state[PKCS11StateRootKeyIDKey] = p.rootKeyID
+		state[PKCS11StateRootCertKey] = p.rootPEM
+	}
+	if p.intermediateKeyID != "" {
+		state[PKCS11StateIntermediateKeyIDKey] = p.intermediateKeyID
+		state[PKCS11StateIntermediateCertKey] = p.intermediatePEM
+	}
+	return state, nil
+}
+
+// GenerateRoot implements Provider
+func (p *PKCS11Provider) GenerateRoot() (RootResult, error) {
+	if !p.isPrimary {
+		return RootResult{}, fmt.Errorf("provider is not the root certificate authority")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.rootPEM != "" {
+		return RootResult{PEM: p.rootPEM}, nil
+	}
+
+	id, key, err := p.generateKey("root")
+	if err != nil {
+		return RootResult{}, err
+	}
+	rootPEM, err := p.generateRootCert(key)
+	if err != nil {
+		p.deleteKey(id, key)
+		return RootResult{}, fmt.Errorf("error generating CA: %v", err)
+	}
+
+	p.rootKeyID = id
+	p.rootKey = key
+	p.rootPEM = rootPEM
+	return RootResult{PEM: rootPEM}, nil
+}
+
+// generateRootCert returns a self-signed root certificate for the key.
+func (p *PKCS11Provider) generateRootCert(key crypto.Signer) (string, error) {
+	keyID, err := connect.KeyId(key.Public())
+	if err != nil {
+		return "", err
+	}
+	sn, err := pkcs11SerialNumber()
+	if err != nil {
+		return "", err
+	}
+	uid, err := connect.CompactUID()
+	if err != nil {
+		return "", err
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          sn,
+		Subject:               pkix.Name{CommonName: connect.CACN("pkcs11", uid, p.clusterID, p.isPrimary)},
+		URIs:                  []*url.URL{p.spiffeID.URI()},
+		SignatureAlgorithm:    connect.SigAlgoForKey(key),
+		BasicConstraintsValid: true,
+		KeyUsage: x509.KeyUsageCertSign |
+			x509.KeyUsageCRLSign |
+			x509.KeyUsageDigitalSignature,
+		IsCA:           true,
+		NotAfter:       time.Now().Add(p.config.RootCertTTL),
+		NotBefore:      time.Now(),
+		AuthorityKeyId: keyID,
+		SubjectKeyId:   keyID,
+	}
+	return createCertificatePEM(&template, &template, key.Public(), key)
+}
+
+// GenerateIntermediateCSR implements Provider. A new key is generated in the
+// token for every CSR and its ID is returned as the opaque value, so
+// SetIntermediate can find the key matching the signed certificate.
+func (p *PKCS11Provider) GenerateIntermediateCSR() (string, string, error) {
+	if p.isPrimary {
+		return "", "", fmt.Errorf("provider is the root certificate authority, " +
+			"cannot generate an intermediate CSR")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	id, key, err := p.generateKey("intermediate")
+	if err != nil {
+		return "", "", err
+	}
+	csr, err := connect.CreateCACSR(p.spiffeID, key)
+	if err != nil {
+		p.deleteKey(id, key)
+		return "", "", err
+	}
+
+	p.pendingKeys[id] = key
+	return csr, id, nil
+}
+
+// SetIntermediate implements Provider
+func (p *PKCS11Provider) SetIntermediate(intermediatePEM, rootPEM, opaque string) error {
+	if p.isPrimary {
+		return fmt.Errorf("cannot set an intermediate using another root in the primary datacenter")
+	}
+
+	if err := validateSetIntermediate(intermediatePEM, rootPEM, p.spiffeID); err != nil {
+		return err
+	}
+	intermediate, err := connect.ParseCert(intermediatePEM)
+	if err != nil {
+		return fmt.Errorf("error parsing intermediate PEM: %v", err)
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	// The key is only pending in this instance if the CSR was generated by it.
+	// Look it up in the token otherwise.
+	key, ok := p.pendingKeys[opaque]
+	if !ok {
+		if opaque == "" {
+			return fmt.Errorf("missing intermediate key ID")
+		}
+		key, err = p.findKey(opaque)
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return fmt.Errorf("intermediate key %s not found in PKCS#11 token", opaque)
+		}
+	}
+	if err := validateIntermediateMatchesPublicKey(intermediate, key.Public()); err != nil {
+		return err
+	}
+
+	delete(p.pendingKeys, opaque)
+	p.setIntermediate(opaque, key, intermediatePEM)
+
+	// Keys of CSRs which were never signed won't be used anymore.
+	for id, key := range p.pendingKeys {
+		p.deleteKey(id, key)
+		delete(p.pendingKeys, id)
+	}
+	return nil
+}
+
+// ActiveIntermediate implements Provider
+func (p *PKCS11Provider) ActiveIntermediate() (string, error) {
+	p.RLock()
+	defer p.RUnlock()
+	return p.intermediatePEM, nil
+}
+
+// GenerateIntermediate implements Provider. It generates a new intermediate
+// key in the token and signs it with the root key.
+func (p *PKCS11Provider) GenerateIntermediate() (string, error) {
+	if !p.isPrimary {
+		return "", fmt.Errorf("provider is not the root certificate authority")
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.rootKey == nil {
+		return "", ErrNotInitialized
+	}
+
+	id, key, err := p.generateKey("intermediate")
+	if err != nil {
+		return "", err
+	}
+	intermediatePEM, err := p.signKeyWithRoot(key)
+	if err != nil {
+		p.deleteKey(id, key)
+		return "", err
+	}
+
+	p.setIntermediate(id, key, intermediatePEM)
+	return intermediatePEM, nil
+}
+
+// signKeyWithRoot returns an intermediate certificate for the key signed by
+// the root key.
+func (p *PKCS11Provider) signKeyWithRoot(key crypto.Signer) (string, error) {
+	csrPEM, err := connect.CreateCACSR(p.spiffeID, key)
+	if err != nil {
+		return "", err
+	}
+	csr, err := connect.ParseCSR(csrPEM)
+	if err != nil {
+		return "", err
+	}
+	return p.signIntermediate(csr)
+}
+
+// setIntermediate makes the key and certificate the active intermediate and
+// deletes the key of the intermediate it replaces from the token. Must be
+// called with the lock held.
+func (p *PKCS11Provider) setIntermediate(id string, key crypto11.Signer, intermediatePEM string) {
+	oldID, oldKey := p.intermediateKeyID, p.intermediateKey
+
+	p.intermediateKeyID = id
+	p.intermediateKey = key
+	p.intermediatePEM = intermediatePEM
+
+	// Certificates issued by the previous intermediate remain valid without
+	// its key, which is never needed again.
+	if oldKey != nil && oldID != id {
+		p.deleteKey(oldID, oldKey)
+	}
+}
+
+// Sign implements Provider
+func (p *PKCS11Provider) Sign(csr *x509.CertificateRequest) (string, error) {
+	connect.HackSANExtensionForCSR(csr)
+
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.intermediateKey == nil {
+		return "", ErrNotInitialized
+	}
+
+	caCert, err := connect.ParseCert(p.intermediatePEM)
+	if err != nil {
+		return "", fmt.Errorf("error parsing CA cert: %s", err)
+	}
+	keyID, err := connect.KeyId(p.intermediateKey.Public())
+	if err != nil {
+		return "", err
+	}
+	subjectKeyID, err := connect.KeyId(csr.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	sn, err := pkcs11SerialNumber()
+	if err != nil {
+		return "", err
+	}
+
+	// Sign the certificate valid from 1 minute in the past, this helps it be
+	// accepted right away even when nodes are not in close time sync across the
+	// cluster. A minute is more than enough for typical DC clock drift.
+	effectiveNow := time.Now().Add(-1 * CertificateTimeDriftBuffer)
+	template := x509.Certificate{
+		SerialNumber: sn,
+		URIs:         csr.URIs,
+		// We use the correct signature algorithm for the CA key we are signing with
+		// regardless of the algorithm used to sign the CSR signature above since
+		// the leaf might use a different key type.
+		SignatureAlgorithm:    connect.SigAlgoForKey(p.intermediateKey),
+		PublicKeyAlgorithm:    csr.PublicKeyAlgorithm,
+		PublicKey:             csr.PublicKey,
+		BasicConstraintsValid: true,
+		KeyUsage: x509.KeyUsageDataEncipherment |
+			x509.KeyUsageKeyAgreement |
+			x509.KeyUsageDigitalSignature |
+			x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageClientAuth,
+			x509.ExtKeyUsageServerAuth,
+		},
+		NotAfter:       effectiveNow.Add(p.config.LeafCertTTL),
+		NotBefore:      effectiveNow,
+		AuthorityKeyId: keyID,
+		SubjectKeyId:   subjectKeyID,
+		DNSNames:       csr.DNSNames,
+		IPAddresses:    csr.IPAddresses,
+	}
+	return createCertificatePEM(&template, caCert, csr.PublicKey, p.intermediateKey)
+}
+
+// SignIntermediate implements Provider
+func (p *PKCS11Provider) SignIntermediate(csr *x509.CertificateRequest) (string, error) {
+	p.RLock()
+	defer p.RUnlock()
+	return p.signIntermediate(csr)
+}
+
+// signIntermediate signs the intermediate CSR with the root key. Must be
+// called with the lock held.
+func (p *PKCS11Provider) signIntermediate(csr *x509.CertificateRequest) (string, error) {
+	if p.rootKey == nil {
+		return "", ErrNotInitialized
+	}
+
+	if err := validateSignIntermediate(csr, p.spiffeID); err != nil {
+		return "", err
+	}
+
+	caCert, err := connect.ParseCert(p.rootPEM)
+	if err != nil {
+		return "", fmt.Errorf("error parsing CA cert: %s", err)
+	}
+	subjectKeyID, err := connect.KeyId(csr.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	sn, err := pkcs11SerialNumber()
+	if err != nil {
+		return "", err
+	}
+
+	effectiveNow := time.Now().Add(-1 * CertificateTimeDriftBuffer)
+	template := x509.Certificate{
+		SerialNumber:          sn,
+		DNSNames:              csr.DNSNames,
+		EmailAddresses:        csr.EmailAddresses,
+		IPAddresses:           csr.IPAddresses,
+		URIs:                  csr.URIs,
+		ExtraExtensions:       csr.ExtraExtensions,
+		Subject:               csr.Subject,
+		SignatureAlgorithm:    connect.SigAlgoForKey(p.rootKey),
+		PublicKeyAlgorithm:    csr.PublicKeyAlgorithm,
+		PublicKey:             csr.PublicKey,
+		BasicConstraintsValid: true,
+		KeyUsage: x509.KeyUsageCertSign |
+			x509.KeyUsageCRLSign |
+			x509.KeyUsageDigitalSignature,
+		IsCA:           true,
+		MaxPathLenZero: true,
+		NotAfter:       effectiveNow.Add(p.config.IntermediateCertTTL),
+		NotBefore:      effectiveNow,
+		SubjectKeyId:   subjectKeyID,
+	}
+	return createCertificatePEM(&template, caCert, csr.PublicKey, p.rootKey)
+}
+
+// CrossSignCA implements Provider
+func (p *PKCS11Provider) CrossSignCA(cert *x509.Certificate) (string, error) {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.rootKey == nil {
+		return "", ErrNotInitialized
+	}
+
+	rootCA, err := connect.ParseCert(p.rootPEM)
+	if err != nil {
+		return "", err
+	}
+	keyID, err := connect.KeyId(p.rootKey.Public())
+	if err != nil {
+		return "", err
+	}
+	sn, err := pkcs11SerialNumber()
+	if err != nil {
+		return "", err
+	}
+
+	// Create the cross-signing template from the existing root CA
+	template := *cert
+	template.SerialNumber = sn
+	template.SignatureAlgorithm = rootCA.SignatureAlgorithm
+	template.AuthorityKeyId = keyID
+
+	// This cross-signed cert is only needed during rotation, and only while old
+	// leaf certs are still in use. They expire within 3 days currently so 7 is
+	// safe.
+	effectiveNow := time.Now().Add(-1 * CertificateTimeDriftBuffer)
+	template.NotBefore = effectiveNow
+	template.NotAfter = effectiveNow.AddDate(0, 0, 7)
+
+	return createCertificatePEM(&template, rootCA, cert.PublicKey, p.rootKey)
+}
+
+// SupportsCrossSigning implements Provider
+func (p *PKCS11Provider) SupportsCrossSigning() (bool, error) {
+	return true, nil
+}
+
+// PrimaryUsesIntermediate implements PrimaryUsesIntermediate so leaf
+// certificates in the primary datacenter are signed by an intermediate which
+// is renewed periodically, keeping the root key use to a minimum.
+func (p *PKCS11Provider) PrimaryUsesIntermediate() {}
+
+// Cleanup implements Provider. The intermediate keys are deleted from the
+// token unless the provider replacing this one keeps using them. The root key
+// is never deleted since destroying a CA key is usually subject to its own
+// procedure, it is logged so it can be removed from the token by an operator.
+func (p *PKCS11Provider) Cleanup(providerTypeChange bool, otherConfig map[string]interface{}) error {
+	p.Lock()
+	defer p.Unlock()
+	defer p.close()
+
+	if !providerTypeChange {
+		other, err := ParsePKCS11CAConfig(otherConfig)
+		if err == nil && pkcs11ConfigID(other, p.isPrimary) == p.configID {
+			return nil
+		}
+	}
+
+	var result error
+	for id, key := range p.pendingKeys {
+		if err := key.Delete(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("error deleting key %s: %v", id, err))
+		}
+		delete(p.pendingKeys, id)
+	}
+	if p.intermediateKey != nil {
+		if err := p.intermediateKey.Delete(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("error deleting key %s: %v", p.intermediateKeyID, err))
+		}
+		p.intermediateKey = nil
+	}
+	if p.rootKey != nil {
+		p.logger.Info("the root key is no longer used and can be deleted from the PKCS#11 token", "key_id", p.rootKeyID)
+	}
+	return result
+}
+
+// Stop implements NeedsStop and releases the session with the token.
+func (p *PKCS11Provider) Stop() {
+	p.Lock()
+	defer p.Unlock()
+	p.close()
+}
+
+// close releases the session with the token. Must be called with the lock
+// held.
+func (p *PKCS11Provider) close() {
+	if p.ctx == nil {
+		return
+	}
+	if err := p.ctx.Close(); err != nil {
+		p.logger.Warn("failed to close PKCS#11 token", "error", err)
+	}
+	p.ctx = nil
+}
+
+// findKey returns the key pair with the hex encoded CKA_ID from the token, or
+// nil if it doesn't exist.
+func (p *PKCS11Provider) findKey(id string) (crypto11.Signer, error) {
+	rawID, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key ID %q: %v", id, err)
+	}
+	key, err := p.ctx.FindKeyPair(rawID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error finding key %s in PKCS#11 token: %v", id, err)
+	}
+	return key, nil
+}
+
+// generateKey generates a new key pair of the configured type in the token. It
+// returns the hex encoded CKA_ID of the key along with the key.
+func (p *PKCS11Provider) generateKey(kind string) (string, crypto11.Signer, error) {
+	rawID := make([]byte, 16)
+	if _, err := rand.Read(rawID); err != nil {
+		return "", nil, err
+	}
+	uid, err := connect.CompactUID()
+	if err != nil {
+		return "", nil, err
+	}
+	label := []byte(fmt.Sprintf("%s-%s-%s", p.config.KeyLabel, kind, uid))
+
+	var key crypto11.Signer
+	switch p.config.PrivateKeyType {
+	case "rsa":
+		key, err = p.ctx.GenerateRSAKeyPairWithLabel(rawID, label, p.config.PrivateKeyBits)
+	case "ec":
+		var curve elliptic.Curve
+		curve, err = pkcs11Curve(p.config.PrivateKeyBits)
+		if err == nil {
+			key, err = p.ctx.GenerateECDSAKeyPairWithLabel(rawID, label, curve)
+		}
+	default:
+		err = fmt.Errorf("unknown private key type requested: %s", p.config.PrivateKeyType)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("error generating %s key in PKCS#11 token: %v", kind, err)
+	}
+
+	id := hex.EncodeToString(rawID)
+	p.logger.Info("generated key in PKCS#11 token", "kind", kind, "key_id", id, "label", string(label))
+	return id, key, nil
+}
+
+// deleteKey deletes a key pair which is no longer used from the token.
+func (p *PKCS11Provider) deleteKey(id string, key crypto11.Signer) {
+	if err := key.Delete(); err != nil {
+		p.logger.Warn("failed to delete key from PKCS#11 token", "key_id", id, "error", err)
+	}
+}
+
+func pkcs11Curve(keyBits int) (elliptic.Curve, error) {
+	switch keyBits {
+	case 224:
+		return elliptic.P224(), nil
+	case 256:
+		return elliptic.P256(), nil
+	case 384:
+		return elliptic.P384(), nil
+	case 521:
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("error generating EC private key: unknown key length %d", keyBits)
+	}
+}
+
+// pkcs11SerialNumber returns a random 128 bit certificate serial number since
+// unlike the built-in provider there's no counter in raft to use.
+func pkcs11SerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// createCertificatePEM creates the certificate and returns it PEM encoded.
+func createCertificatePEM(template, parent *x509.Certificate, pub crypto.PublicKey, signer crypto.Signer) (string, error) {
+	bs, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	if err != nil {
+		return "", fmt.Errorf("error generating certificate: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: bs}); err != nil {
+		return "", fmt.Errorf("error encoding certificate: %s", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,83 @@
+package ca
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const (
+	// PKCS11StateConfigIDKey is the key in the provider State we store the ID
+	// of the configuration the keys were generated for.
+	PKCS11StateConfigIDKey = "CONFIG_ID"
+
+	// PKCS11StateRootKeyIDKey is the key in the provider State we store the
+	// hex encoded CKA_ID of the root key in the token.
+	PKCS11StateRootKeyIDKey = "ROOT_KEY_ID"
+
+	// PKCS11StateRootCertKey is the key in the provider State we store the
+	// root certificate.
+	PKCS11StateRootCertKey = "ROOT_CERT"
+
+	// PKCS11StateIntermediateKeyIDKey is the key in the provider State we store
+	// the hex encoded CKA_ID of the intermediate key in the token.
+	PKCS11StateIntermediateKeyIDKey = "INTERMEDIATE_KEY_ID"
+
+	// PKCS11StateIntermediateCertKey is the key in the provider State we store
+	// the active intermediate certificate.
+	PKCS11StateIntermediateCertKey = "INTERMEDIATE_CERT"
+
+	// PKCS11DefaultKeyLabel is the default prefix of the labels of the keys
+	// generated in the token.
+	PKCS11DefaultKeyLabel = "consul-ca"
+)
+
+func ParsePKCS11CAConfig(raw map[string]interface{}) (*structs.PKCS11CAProviderConfig, error) {
+	config := structs.PKCS11CAProviderConfig{
+		CommonCAProviderConfig: defaultCommonConfig(),
+		KeyLabel:               PKCS11DefaultKeyLabel,
+	}
+
+	decodeConf := &mapstructure.DecoderConfig{
+		DecodeHook:       structs.ParseDurationFunc(),
+		Result:           &config,
+		WeaklyTypedInput: true,
+	}
+
+	decoder, err := mapstructure.NewDecoder(decodeConf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decoder.Decode(raw); err != nil {
+		return nil, fmt.Errorf("error decoding config: %s", err)
+	}
+
+	if config.Lib == "" {
+		return nil, fmt.Errorf("must provide the path to a PKCS#11 library")
+	}
+
+	if (config.TokenLabel == "") == (config.TokenSerial == "") {
+		return nil, fmt.Errorf("must provide exactly one of token label or token serial")
+	}
+
+	if config.KeyLabel == "" {
+		return nil, fmt.Errorf("key label cannot be empty")
+	}
+
+	if err := config.CommonCAProviderConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// pkcs11ConfigID returns an ID for the parts of the configuration which
+// determine the keys of the CA. Keys persisted in the provider State are only
+// reused while the ID stays the same, otherwise new keys are generated.
+func pkcs11ConfigID(config *structs.PKCS11CAProviderConfig, isPrimary bool) string {
+	return hexStringHash(fmt.Sprintf("%s,%s,%s,%d,%v", config.TokenLabel, config.TokenSerial,
+		config.PrivateKeyType, config.PrivateKeyBits, isPrimary))
+}
@@ -0,0 +1,75 @@
+//go:build !cgo
+
+package ca
+
+import (
+	"crypto/x509"
+	"errors"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// errPKCS11Unsupported is returned by the PKCS11Provider of binaries built
+// without cgo, which is required to load PKCS#11 libraries.
+var errPKCS11Unsupported = errors.New("the pkcs11 CA provider requires Consul to be built with cgo enabled")
+
+// PKCS11Provider is a placeholder for the PKCS#11 provider in binaries built
+// without cgo. It fails to configure so the CA config is rejected.
+type PKCS11Provider struct {
+	logger hclog.Logger
+}
+
+var _ Provider = (*PKCS11Provider)(nil)
+
+// NewPKCS11Provider returns a new PKCS11Provider
+func NewPKCS11Provider(logger hclog.Logger) *PKCS11Provider {
+	return &PKCS11Provider{logger: logger}
+}
+
+func (p *PKCS11Provider) Configure(ProviderConfig) error {
+	return errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) State() (map[string]string, error) {
+	return nil, nil
+}
+
+func (p *PKCS11Provider) GenerateRoot() (RootResult, error) {
+	return RootResult{}, errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) GenerateIntermediateCSR() (string, string, error) {
+	return "", "", errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) SetIntermediate(string, string, string) error {
+	return errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) ActiveIntermediate() (string, error) {
+	return "", errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) GenerateIntermediate() (string, error) {
+	return "", errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) Sign(*x509.CertificateRequest) (string, error) {
+	return "", errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) SignIntermediate(*x509.CertificateRequest) (string, error) {
+	return "", errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) CrossSignCA(*x509.Certificate) (string, error) {
+	return "", errPKCS11Unsupported
+}
+
+func (p *PKCS11Provider) SupportsCrossSigning() (bool, error) {
+	return false, nil
+}
+
+func (p *PKCS11Provider) Cleanup(bool, map[string]interface{}) error {
+	return nil
+}
@@ -0,0 +1,318 @@
+//go:build cgo
+
+package ca
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+const (
+	testSoftHSMTokenLabel = "consul-test"
+	testSoftHSMPIN        = "1234"
+)
+
+var (
+	testSoftHSMOnce sync.Once
+	testSoftHSMLib  string
+	testSoftHSMErr  string
+)
+
+// skipIfSoftHSMNotPresent skips the test unless SoftHSM is installed. The
+// library is looked up in the usual install locations, SOFTHSM2_LIB can be set
+// to the path of libsofthsm2.so otherwise. A token shared by all the tests is
+// initialized in a temporary directory on first use and the path to the
+// library is returned.
+func skipIfSoftHSMNotPresent(t *testing.T) string {
+	testSoftHSMOnce.Do(func() {
+		candidates := []string{
+			os.Getenv("SOFTHSM2_LIB"),
+			"/usr/lib/softhsm/libsofthsm2.so",
+			"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+			"/usr/local/lib/softhsm/libsofthsm2.so",
+			"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+		}
+		for _, path := range candidates {
+			if path == "" {
+				continue
+			}
+			if _, err := os.Stat(path); err == nil {
+				testSoftHSMLib = path
+				break
+			}
+		}
+		if testSoftHSMLib == "" {
+			testSoftHSMErr = "libsofthsm2.so not found"
+			return
+		}
+		util, err := exec.LookPath("softhsm2-util")
+		if err != nil {
+			testSoftHSMErr = "softhsm2-util not found"
+			return
+		}
+
+		// The configuration is read when the library is initialized so it must
+		// be set before any provider is configured.
+		dir, err := os.MkdirTemp("", "consul-softhsm")
+		if err != nil {
+			testSoftHSMErr = err.Error()
+			return
+		}
+		conf := filepath.Join(dir, "softhsm2.conf")
+		content := "directories.tokendir = " + dir + "\nobjectstore.backend = file\n"
+		if err := os.WriteFile(conf, []byte(content), 0600); err != nil {
+			testSoftHSMErr = err.Error()
+			return
+		}
+		os.Setenv("SOFTHSM2_CONF", conf)
+
+		out, err := exec.Command(util, "--init-token", "--free",
+			"--label", testSoftHSMTokenLabel,
+			"--pin", testSoftHSMPIN, "--so-pin", testSoftHSMPIN).CombinedOutput()
+		if err != nil {
+			testSoftHSMErr = "failed to initialize token: " + string(out)
+		}
+	})
+
+	if testSoftHSMErr != "" {
+		t.Skipf("Skipping because SoftHSM is not available: %s", testSoftHSMErr)
+	}
+	return testSoftHSMLib
+}
+
+func testPKCS11Provider(t *testing.T, cfg ProviderConfig) *PKCS11Provider {
+	p := NewPKCS11Provider(testutil.Logger(t))
+	require.NoError(t, p.Configure(cfg))
+	t.Cleanup(func() {
+		p.Cleanup(true, nil)
+	})
+	return p
+}
+
+func testPKCS11ProviderConfig(t *testing.T, isPrimary bool, cfg map[string]interface{}) ProviderConfig {
+	lib := skipIfSoftHSMNotPresent(t)
+
+	rawCfg := map[string]interface{}{
+		"Lib":        lib,
+		"TokenLabel": testSoftHSMTokenLabel,
+		"PIN":        testSoftHSMPIN,
+	}
+	for k, v := range cfg {
+		rawCfg[k] = v
+	}
+	pCfg := ProviderConfig{
+		ClusterID:  connect.TestClusterID,
+		Datacenter: "dc1",
+		IsPrimary:  isPrimary,
+		RawConfig:  rawCfg,
+	}
+	if !isPrimary {
+		pCfg.Datacenter = "dc2"
+	}
+	return pCfg
+}
+
+func TestParsePKCS11CAConfig(t *testing.T) {
+	cases := map[string]struct {
+		rawConfig map[string]interface{}
+		expectErr string
+	}{
+		"valid": {
+			rawConfig: map[string]interface{}{"Lib": "/lib.so", "TokenLabel": "ca"},
+		},
+		"missing lib": {
+			rawConfig: map[string]interface{}{"TokenLabel": "ca"},
+			expectErr: "must provide the path to a PKCS#11 library",
+		},
+		"missing token": {
+			rawConfig: map[string]interface{}{"Lib": "/lib.so"},
+			expectErr: "must provide exactly one of token label or token serial",
+		},
+		"both token label and serial": {
+			rawConfig: map[string]interface{}{"Lib": "/lib.so", "TokenLabel": "ca", "TokenSerial": "1a2b"},
+			expectErr: "must provide exactly one of token label or token serial",
+		},
+		"empty key label": {
+			rawConfig: map[string]interface{}{"Lib": "/lib.so", "TokenSerial": "1a2b", "KeyLabel": ""},
+			expectErr: "key label cannot be empty",
+		},
+		"invalid key type": {
+			rawConfig: map[string]interface{}{"Lib": "/lib.so", "TokenLabel": "ca", "PrivateKeyType": "dsa"},
+			expectErr: "private key type must be either 'ec' or 'rsa'",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config, err := ParsePKCS11CAConfig(tc.rawConfig)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, PKCS11DefaultKeyLabel, config.KeyLabel)
+		})
+	}
+}
+
+func TestPKCS11CAProvider_SignLeaf(t *testing.T) {
+	for _, tc := range KeyTestCases {
+		tc := tc
+		t.Run(tc.Desc, func(t *testing.T) {
+			provider := testPKCS11Provider(t, testPKCS11ProviderConfig(t, true, map[string]interface{}{
+				"PrivateKeyType": tc.KeyType,
+				"PrivateKeyBits": tc.KeyBits,
+			}))
+
+			root, err := provider.GenerateRoot()
+			require.NoError(t, err)
+			interPEM, err := provider.GenerateIntermediate()
+			require.NoError(t, err)
+			require.NotEqual(t, root.PEM, interPEM)
+
+			rootCert, err := connect.ParseCert(root.PEM)
+			require.NoError(t, err)
+			keyType, keyBits, err := connect.KeyInfoFromCert(rootCert)
+			require.NoError(t, err)
+			require.Equal(t, tc.KeyType, keyType)
+			require.Equal(t, tc.KeyBits, keyBits)
+
+			testSignAndValidate(t, provider, root.PEM, []string{interPEM})
+		})
+	}
+}
+
+func TestPKCS11CAProvider_State(t *testing.T) {
+	cfg := testPKCS11ProviderConfig(t, true, nil)
+	provider := testPKCS11Provider(t, cfg)
+
+	root, err := provider.GenerateRoot()
+	require.NoError(t, err)
+	interPEM, err := provider.GenerateIntermediate()
+	require.NoError(t, err)
+
+	state, err := provider.State()
+	require.NoError(t, err)
+	require.Equal(t, root.PEM, state[PKCS11StateRootCertKey])
+	require.Equal(t, interPEM, state[PKCS11StateIntermediateCertKey])
+	require.NotEmpty(t, state[PKCS11StateRootKeyIDKey])
+	require.NotEmpty(t, state[PKCS11StateIntermediateKeyIDKey])
+
+	t.Run("keys are found again from state", func(t *testing.T) {
+		cfg := cfg
+		cfg.State = state
+		provider := NewPKCS11Provider(testutil.Logger(t))
+		require.NoError(t, provider.Configure(cfg))
+		defer provider.Stop()
+
+		newRoot, err := provider.GenerateRoot()
+		require.NoError(t, err)
+		require.Equal(t, root.PEM, newRoot.PEM)
+		active, err := provider.ActiveIntermediate()
+		require.NoError(t, err)
+		require.Equal(t, interPEM, active)
+
+		testSignAndValidate(t, provider, root.PEM, []string{interPEM})
+	})
+
+	t.Run("keys are not reused for another key type", func(t *testing.T) {
+		cfg := testPKCS11ProviderConfig(t, true, map[string]interface{}{
+			"PrivateKeyType": "rsa",
+			"PrivateKeyBits": 2048,
+		})
+		cfg.State = state
+		provider := testPKCS11Provider(t, cfg)
+
+		newRoot, err := provider.GenerateRoot()
+		require.NoError(t, err)
+		require.NotEqual(t, root.PEM, newRoot.PEM)
+	})
+
+	t.Run("missing root key", func(t *testing.T) {
+		cfg := cfg
+		cfg.State = map[string]string{
+			PKCS11StateConfigIDKey:  state[PKCS11StateConfigIDKey],
+			PKCS11StateRootKeyIDKey: "0123456789abcdef",
+			PKCS11StateRootCertKey:  root.PEM,
+		}
+		provider := NewPKCS11Provider(testutil.Logger(t))
+		require.EqualError(t, provider.Configure(cfg), "root key 0123456789abcdef not found in PKCS#11 token")
+	})
+}
+
+func TestPKCS11CAProvider_GenerateIntermediateDeletesPreviousKey(t *testing.T) {
+	provider := testPKCS11Provider(t, testPKCS11ProviderConfig(t, true, nil))
+
+	_, err := provider.GenerateRoot()
+	require.NoError(t, err)
+	_, err = provider.GenerateIntermediate()
+	require.NoError(t, err)
+	state, err := provider.State()
+	require.NoError(t, err)
+	oldKeyID := state[PKCS11StateIntermediateKeyIDKey]
+
+	_, err = provider.GenerateIntermediate()
+	require.NoError(t, err)
+
+	key, err := provider.findKey(oldKeyID)
+	require.NoError(t, err)
+	require.Nil(t, key)
+}
+
+func TestPKCS11CAProvider_CrossSignCA(t *testing.T) {
+	tests := CASigningKeyTypeCases()
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Desc, func(t *testing.T) {
+			provider1 := testPKCS11Provider(t, testPKCS11ProviderConfig(t, true, map[string]interface{}{
+				"PrivateKeyType": tc.SigningKeyType,
+				"PrivateKeyBits": tc.SigningKeyBits,
+			}))
+			_, err := provider1.GenerateRoot()
+			require.NoError(t, err)
+
+			provider2 := testPKCS11Provider(t, testPKCS11ProviderConfig(t, true, map[string]interface{}{
+				"PrivateKeyType": tc.CSRKeyType,
+				"PrivateKeyBits": tc.CSRKeyBits,
+			}))
+			_, err = provider2.GenerateRoot()
+			require.NoError(t, err)
+			_, err = provider2.GenerateIntermediate()
+			require.NoError(t, err)
+
+			testCrossSignProviders(t, provider1, provider2)
+		})
+	}
+}
+
+func TestPKCS11CAProvider_SignIntermediate(t *testing.T) {
+	tests := CASigningKeyTypeCases()
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Desc, func(t *testing.T) {
+			provider1 := testPKCS11Provider(t, testPKCS11ProviderConfig(t, true, map[string]interface{}{
+				"PrivateKeyType": tc.SigningKeyType,
+				"PrivateKeyBits": tc.SigningKeyBits,
+			}))
+			_, err := provider1.GenerateRoot()
+			require.NoError(t, err)
+
+			provider2 := testPKCS11Provider(t, testPKCS11ProviderConfig(t, false, map[string]interface{}{
+				"PrivateKeyType": tc.CSRKeyType,
+				"PrivateKeyBits": tc.CSRKeyBits,
+			}))
+
+			testSignIntermediateCrossDC(t, provider1, provider2)
+		})
+	}
+}
@@ -80,7 +80,22 @@ func (a *Agent) ConnectAuthorize(token string,
 	// Note that we DON'T explicitly validate the trust-domain matches ours. See
 	// the PR for this change for details.
 
-	// TODO(banks): Implement revocation list checking here.
+	// Deny revoked certificates before looking at intentions.
+	if a.config.ConnectEnabled {
+		rootsArgs := &structs.DCSpecificRequest{Datacenter: a.config.Datacenter}
+		raw, _, err := a.cache.Get(context.TODO(), cachetype.ConnectCARootName, rootsArgs)
+		if err != nil {
+			return returnErr(err)
+		}
+		roots, ok := raw.(*structs.IndexedCARoots)
+		if !ok {
+			return returnErr(fmt.Errorf("internal error: roots response type not correct"))
+		}
+		if revoked := roots.LeafRevoked(req.ClientCertSerial, uri.URI().String()); revoked != nil {
+			reason = fmt.Sprintf("Client certificate has been revoked: %s", revoked.ID)
+			return false, reason, nil, nil
+		}
+	}
 
 	// Get the intentions for this target service.
 	args := &structs.IntentionQueryRequest{
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/structs"
@@ -93,14 +94,114 @@ func (s *HTTPHandlers) ConnectCAConfigurationSet(req *http.Request) (interface{}
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
 	}
 
+	if _, ok := req.URL.Query()["dry-run"]; ok {
+		var plan structs.CARotationPlan
+		err := s.agent.RPC(req.Context(), "ConnectCA.ConfigurationDryRun", &args, &plan)
+		if err != nil {
+			return nil, caConfigurationError(err)
+		}
+		return plan, nil
+	}
+
 	var reply interface{}
 	err := s.agent.RPC(req.Context(), "ConnectCA.ConfigurationSet", &args, &reply)
+	return nil, caConfigurationError(err)
+}
+
+// caConfigurationError converts the errors of the CA configuration updates
+// caused by the request.
+func caConfigurationError(err error) error {
 	if err != nil && err.Error() == consul.ErrStateReadOnly.Error() {
-		return nil, HTTPError{
+		return HTTPError{
 			StatusCode: http.StatusBadRequest,
 			Reason: "Provider State is read-only. It must be omitted" +
 				" or identical to the current value",
 		}
 	}
-	return nil, err
+	return err
+}
+
+// GET /v1/connect/ca/rotation
+func (s *HTTPHandlers) ConnectCARotationStatus(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.DCSpecificRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.CARotationStatus
+	defer setMeta(resp, &reply.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "ConnectCA.RotationStatus", &args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Roots == nil {
+		reply.Roots = make([]*structs.CARootRotationStatus, 0)
+	}
+	if reply.PendingInstances == nil {
+		reply.PendingInstances = make([]*structs.CARotationInstance, 0)
+	}
+	return reply, nil
+}
+
+// /v1/connect/ca/revocations
+func (s *HTTPHandlers) ConnectCARevocations(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.ConnectCARevocationsList(resp, req)
+
+	case "PUT":
+		return s.ConnectCARevokeLeaf(req, structs.CAOpRevokeLeaf)
+
+	case "DELETE":
+		return s.ConnectCARevokeLeaf(req, structs.CAOpDeleteRevokedLeaf)
+
+	default:
+		return nil, MethodNotAllowedError{req.Method, []string{"GET", "PUT", "DELETE"}}
+	}
+}
+
+// GET /v1/connect/ca/revocations
+func (s *HTTPHandlers) ConnectCARevocationsList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Method is tested in ConnectCARevocations
+	var args structs.DCSpecificRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.IndexedCARevokedLeafs
+	defer setMeta(resp, &reply.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "ConnectCA.RevokedLeafs", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	if reply.RevokedLeafs == nil {
+		return []*structs.CARevokedLeaf{}, nil
+	}
+	return reply.RevokedLeafs, nil
+}
+
+// PUT and DELETE /v1/connect/ca/revocations
+func (s *HTTPHandlers) ConnectCARevokeLeaf(req *http.Request, op structs.CAOp) (interface{}, error) {
+	// Method is tested in ConnectCARevocations
+	args := structs.CARevokeLeafRequest{Op: op}
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	if op == structs.CAOpRevokeLeaf {
+		if err := decodeBody(req.Body, &args.RevokedLeaf); err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+		}
+	} else {
+		query := req.URL.Query()
+		args.RevokedLeaf.SerialNumber = query.Get("serial_number")
+		args.RevokedLeaf.SpiffeID = query.Get("spiffe_id")
+	}
+
+	var reply interface{}
+	if err := s.agent.RPC(req.Context(), "ConnectCA.RevokeLeaf", &args, &reply); err != nil {
+		if strings.Contains(err.Error(), "must be provided") || strings.Contains(err.Error(), "invalid ") {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: err.Error()}
+		}
+		return nil, err
+	}
+	return true, nil
 }
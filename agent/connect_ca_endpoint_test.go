@@ -69,6 +69,63 @@ func TestConnectCARoots_list(t *testing.T) {
 	}
 }
 
+func TestConnectCARevocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	list := func(t *testing.T) []*structs.CARevokedLeaf {
+		req, _ := http.NewRequest("GET", "/v1/connect/ca/revocations", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.ConnectCARevocations(resp, req)
+		require.NoError(t, err)
+		return obj.([]*structs.CARevokedLeaf)
+	}
+
+	// Empty list
+	require.Empty(t, list(t))
+
+	// Revoke by serial number
+	body := bytes.NewBufferString(`{"SerialNumber": "0A:0B", "Reason": "key compromise"}`)
+	req, _ := http.NewRequest("PUT", "/v1/connect/ca/revocations", body)
+	resp := httptest.NewRecorder()
+	_, err := a.srv.ConnectCARevocations(resp, req)
+	require.NoError(t, err)
+
+	revoked := list(t)
+	require.Len(t, revoked, 1)
+	require.Equal(t, "0a:0b", revoked[0].SerialNumber)
+	require.Equal(t, "key compromise", revoked[0].Reason)
+
+	// The revocations are not exposed by the roots endpoint.
+	req, _ = http.NewRequest("GET", "/v1/connect/ca/roots", nil)
+	resp = httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NotContains(t, resp.Body.String(), "0a:0b")
+
+	// Invalid requests
+	body = bytes.NewBufferString(`{"Reason": "missing"}`)
+	req, _ = http.NewRequest("PUT", "/v1/connect/ca/revocations", body)
+	resp = httptest.NewRecorder()
+	_, err = a.srv.ConnectCARevocations(resp, req)
+	require.Error(t, err)
+	require.True(t, isHTTPBadRequest(err), "expected bad request, got %v", err)
+
+	// Remove the revocation
+	req, _ = http.NewRequest("DELETE", "/v1/connect/ca/revocations?serial_number=0a:0b", nil)
+	resp = httptest.NewRecorder()
+	_, err = a.srv.ConnectCARevocations(resp, req)
+	require.NoError(t, err)
+	require.Empty(t, list(t))
+}
+
 func TestConnectCAConfig(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -262,6 +319,56 @@ func TestConnectCAConfig(t *testing.T) {
 	}
 }
 
+func TestConnectCAConfig_DryRunAndRotationStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	rotationStatus := func(t *testing.T) structs.CARotationStatus {
+		req, _ := http.NewRequest("GET", "/v1/connect/ca/rotation", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.ConnectCARotationStatus(resp, req)
+		require.NoError(t, err)
+		return obj.(structs.CARotationStatus)
+	}
+
+	before := rotationStatus(t)
+	require.Len(t, before.Roots, 1)
+	require.NotEmpty(t, before.ActiveRootID)
+	require.NotNil(t, before.PendingInstances)
+
+	body := bytes.NewBufferString(`{"Provider": "consul", "Config": {"PrivateKeyType": "rsa", "PrivateKeyBits": 2048}}`)
+	req, _ := http.NewRequest("PUT", "/v1/connect/ca/configuration?dry-run", body)
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.ConnectCAConfiguration(resp, req)
+	require.NoError(t, err)
+
+	plan := obj.(structs.CARotationPlan)
+	require.Equal(t, before.ActiveRootID, plan.CurrentRootID)
+	require.True(t, plan.RootRotation)
+	require.True(t, plan.CrossSign)
+	require.NotEqual(t, before.ActiveRootID, plan.NewRootID)
+
+	// Nothing changed.
+	after := rotationStatus(t)
+	require.Len(t, after.Roots, 1)
+	require.Equal(t, before.ActiveRootID, after.ActiveRootID)
+
+	// The provider state is read-only.
+	body = bytes.NewBufferString(`{"Provider": "consul", "State": {"foo": "bar"}}`)
+	req, _ = http.NewRequest("PUT", "/v1/connect/ca/configuration?dry-run", body)
+	resp = httptest.NewRecorder()
+	_, err = a.srv.ConnectCAConfiguration(resp, req)
+	require.Error(t, err)
+	require.True(t, isHTTPBadRequest(err), "expected bad request, got %v", err)
+}
+
 func TestConnectCARoots_PEMEncoding(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,497 @@
+package ldapauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const (
+	authMethodType string = "ldap"
+
+	defaultUserAttr    = "cn"
+	defaultUserFilter  = "({{.UserAttr}}={{.Username}})"
+	defaultGroupAttr   = "cn"
+	defaultGroupFilter = "(|(memberUid={{.Username}})(member={{.UserDN}})(uniqueMember={{.UserDN}}))"
+
+	// requestTimeout bounds the connection to the LDAP server and each of the
+	// requests made during a login.
+	requestTimeout = 10 * time.Second
+)
+
+// errInvalidCredentials is returned for unknown users and wrong passwords
+// alike so that a login does not reveal which users exist.
+var errInvalidCredentials = errors.New("invalid username or password")
+
+func init() {
+	// register this as an available auth method type
+	authmethod.Register(authMethodType, func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+		v, err := NewValidator(logger, method)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+// Credentials is the login token expected by the ldap auth method, JSON
+// encoded.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Config is the configuration of an ldap auth method.
+type Config struct {
+	// URL is the address of the LDAP server, using the ldap:// or ldaps://
+	// scheme.
+	URL string `json:",omitempty"`
+
+	// StartTLS upgrades ldap:// connections to TLS.
+	StartTLS bool `json:",omitempty"`
+
+	// CACert is the PEM encoded CA certificate used to verify the certificate
+	// of the LDAP server. The system roots are used when empty.
+	CACert string `json:",omitempty"`
+
+	// InsecureSkipVerify disables the verification of the certificate of the
+	// LDAP server.
+	InsecureSkipVerify bool `json:",omitempty"`
+
+	// BindDN and BindPassword are the credentials used to search for the
+	// users and their groups. When empty, the users are bound to directly
+	// and the searches are made with their own credentials.
+	BindDN       string `json:",omitempty"`
+	BindPassword string `json:",omitempty"`
+
+	// UserDN is the base DN under which the users are searched.
+	UserDN string `json:",omitempty"`
+
+	// UserAttr is the attribute of the user entries matching the username.
+	// Defaults to "cn", Active Directory uses "sAMAccountName".
+	UserAttr string `json:",omitempty"`
+
+	// UserFilter is the template of the filter used to search for a user.
+	// It can reference {{.UserAttr}} and {{.Username}}.
+	UserFilter string `json:",omitempty"`
+
+	// UPNDomain binds the users as username@UPNDomain instead of using their
+	// DN, as is usual with Active Directory.
+	UPNDomain string `json:",omitempty"`
+
+	// GroupDN is the base DN under which the groups of a user are searched.
+	// When empty, the groups are read from the memberOf attribute of the user.
+	GroupDN string `json:",omitempty"`
+
+	// GroupFilter is the template of the filter used to search for the
+	// groups of a user. It can reference {{.Username}} and {{.UserDN}}.
+	GroupFilter string `json:",omitempty"`
+
+	// GroupAttr is the attribute of the group entries holding their name.
+	// Defaults to "cn".
+	GroupAttr string `json:",omitempty"`
+
+	// UserAttributes are the attributes of the user entry made available to
+	// the binding rules as attributes.<name>.
+	UserAttributes []string `json:",omitempty"`
+}
+
+func (c *Config) validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("URL is required")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %v", err)
+	}
+	switch u.Scheme {
+	case "ldap":
+	case "ldaps":
+		if c.StartTLS {
+			return fmt.Errorf("StartTLS cannot be used with an ldaps:// URL")
+		}
+	default:
+		return fmt.Errorf("invalid URL: scheme must be ldap or ldaps")
+	}
+	if c.UserDN == "" {
+		return fmt.Errorf("UserDN is required")
+	}
+	if (c.BindDN == "") != (c.BindPassword == "") {
+		return fmt.Errorf("BindDN and BindPassword must be set together")
+	}
+	if c.BindDN != "" && c.UPNDomain != "" {
+		return fmt.Errorf("BindDN and UPNDomain cannot be set together")
+	}
+	if c.GroupDN == "" && c.GroupFilter != "" {
+		return fmt.Errorf("GroupFilter requires GroupDN")
+	}
+	return nil
+}
+
+// Validator is the authmethod.Validator of the ldap auth method.
+type Validator struct {
+	name   string
+	config *Config
+	logger hclog.Logger
+
+	tlsConfig   *tls.Config
+	userFilter  *template.Template
+	groupFilter *template.Template
+}
+
+var _ authmethod.Validator = (*Validator)(nil)
+
+func NewValidator(logger hclog.Logger, method *structs.ACLAuthMethod) (*Validator, error) {
+	if method.Type != authMethodType {
+		return nil, fmt.Errorf("%q is not an LDAP auth method", method.Name)
+	}
+
+	var config Config
+	if err := authmethod.ParseConfig(method.Config, &config); err != nil {
+		return nil, err
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if config.UserAttr == "" {
+		config.UserAttr = defaultUserAttr
+	}
+	if config.UserFilter == "" {
+		config.UserFilter = defaultUserFilter
+	}
+	if config.GroupAttr == "" {
+		config.GroupAttr = defaultGroupAttr
+	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = defaultGroupFilter
+	}
+
+	userFilter, err := template.New("UserFilter").Option("missingkey=error").Parse(config.UserFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UserFilter: %v", err)
+	}
+	groupFilter, err := template.New("GroupFilter").Option("missingkey=error").Parse(config.GroupFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GroupFilter: %v", err)
+	}
+
+	u, _ := url.Parse(config.URL)
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+	if config.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CACert)) {
+			return nil, fmt.Errorf("invalid CACert: no PEM encoded certificate found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &Validator{
+		name:        method.Name,
+		config:      &config,
+		logger:      logger,
+		tlsConfig:   tlsConfig,
+		userFilter:  userFilter,
+		groupFilter: groupFilter,
+	}, nil
+}
+
+// Name implements authmethod.Validator.
+func (v *Validator) Name() string { return v.name }
+
+// Stop implements authmethod.Validator.
+func (v *Validator) Stop() {}
+
+// ValidateLogin implements authmethod.Validator.
+func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*authmethod.Identity, error) {
+	var creds Credentials
+	if err := json.Unmarshal([]byte(loginToken), &creds); err != nil {
+		return nil, fmt.Errorf("invalid login token: %v", err)
+	}
+	// An empty password would make an unauthenticated bind, which most
+	// servers accept.
+	if creds.Username == "" || creds.Password == "" {
+		return nil, errInvalidCredentials
+	}
+
+	conn, err := v.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	user, err := v.authenticate(conn, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := v.groups(conn, creds.Username, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.identity(creds.Username, user, groups), nil
+}
+
+func (v *Validator) dial(ctx context.Context) (*ldap.Conn, error) {
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+	conn, err := ldap.DialURL(v.config.URL,
+		ldap.DialWithDialer(dialer),
+		ldap.DialWithTLSConfig(v.tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to LDAP server: %v", err)
+	}
+	conn.SetTimeout(requestTimeout)
+
+	if v.config.StartTLS {
+		if err := conn.StartTLS(v.tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error starting TLS with LDAP server: %v", err)
+		}
+	}
+	return conn, nil
+}
+
+// authenticate binds as the user and returns its entry. The connection is
+// left bound with the credentials to use for the group search.
+func (v *Validator) authenticate(conn *ldap.Conn, creds Credentials) (*ldap.Entry, error) {
+	var (
+		user     *ldap.Entry
+		bindName string
+		filter   string
+		err      error
+	)
+
+	switch {
+	case v.config.BindDN != "":
+		if err := conn.Bind(v.config.BindDN, v.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("error binding to LDAP server with BindDN: %v", err)
+		}
+		user, err = v.searchUser(conn, creds.Username)
+		if err != nil {
+			return nil, err
+		}
+		bindName = user.DN
+
+	case v.config.UPNDomain != "":
+		bindName = creds.Username + "@" + v.config.UPNDomain
+		filter = fmt.Sprintf("(userPrincipalName=%s)", ldap.EscapeFilter(bindName))
+
+	default:
+		// Without search credentials the DN of the user is derived from its
+		// username and the user entry is read once bound.
+		bindName = fmt.Sprintf("%s=%s,%s", v.config.UserAttr, escapeDNValue(creds.Username), v.config.UserDN)
+	}
+
+	if err := conn.Bind(bindName, creds.Password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			v.logger.Debug("LDAP bind failed", "username", creds.Username, "error", err)
+			return nil, errInvalidCredentials
+		}
+		return nil, fmt.Errorf("error binding to LDAP server: %v", err)
+	}
+
+	switch {
+	case v.config.BindDN != "":
+		// Rebind with BindDN so that the groups are searched with the same
+		// credentials as the user was.
+		if err := conn.Bind(v.config.BindDN, v.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("error binding to LDAP server with BindDN: %v", err)
+		}
+		return user, nil
+
+	case filter != "":
+		return v.searchOne(conn, v.config.UserDN, ldap.ScopeWholeSubtree, filter)
+
+	default:
+		return v.searchOne(conn, bindName, ldap.ScopeBaseObject, "(objectClass=*)")
+	}
+}
+
+// searchUser searches the entry of a user with UserFilter.
+func (v *Validator) searchUser(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	var buf bytes.Buffer
+	err := v.userFilter.Execute(&buf, struct {
+		UserAttr string
+		Username string
+	}{
+		UserAttr: v.config.UserAttr,
+		Username: ldap.EscapeFilter(username),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error rendering UserFilter: %v", err)
+	}
+	return v.searchOne(conn, v.config.UserDN, ldap.ScopeWholeSubtree, buf.String())
+}
+
+func (v *Validator) searchOne(conn *ldap.Conn, baseDN string, scope int, filter string) (*ldap.Entry, error) {
+	res, err := conn.Search(ldap.NewSearchRequest(
+		baseDN, scope, ldap.NeverDerefAliases, 2, int(requestTimeout.Seconds()), false,
+		filter, v.userAttributes(), nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, errInvalidCredentials
+		}
+		return nil, fmt.Errorf("error searching LDAP user: %v", err)
+	}
+
+	switch len(res.Entries) {
+	case 0:
+		return nil, errInvalidCredentials
+	case 1:
+		return res.Entries[0], nil
+	default:
+		return nil, fmt.Errorf("LDAP user search returned more than one entry")
+	}
+}
+
+// userAttributes are the attributes of the user entry used by the login.
+func (v *Validator) userAttributes() []string {
+	attrs := append([]string{}, v.config.UserAttributes...)
+	if v.config.GroupDN == "" {
+		attrs = append(attrs, "memberOf")
+	}
+	if len(attrs) == 0 {
+		// No attributes are needed, "1.1" requests none.
+		attrs = []string{"1.1"}
+	}
+	return attrs
+}
+
+// groups returns the sorted names of the groups of the user.
+func (v *Validator) groups(conn *ldap.Conn, username string, user *ldap.Entry) ([]string, error) {
+	names := make(map[string]struct{})
+
+	if v.config.GroupDN == "" {
+		for _, groupDN := range user.GetEqualFoldAttributeValues("memberOf") {
+			dn, err := ldap.ParseDN(groupDN)
+			if err != nil || len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) == 0 {
+				v.logger.Warn("ignoring invalid group DN", "dn", groupDN)
+				continue
+			}
+			names[dn.RDNs[0].Attributes[0].Value] = struct{}{}
+		}
+	} else {
+		var buf bytes.Buffer
+		err := v.groupFilter.Execute(&buf, struct {
+			Username string
+			UserDN   string
+		}{
+			Username: ldap.EscapeFilter(username),
+			UserDN:   ldap.EscapeFilter(user.DN),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error rendering GroupFilter: %v", err)
+		}
+
+		res, err := conn.Search(ldap.NewSearchRequest(
+			v.config.GroupDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(requestTimeout.Seconds()), false,
+			buf.String(), []string{v.config.GroupAttr}, nil,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("error searching LDAP groups: %v", err)
+		}
+		for _, e := range res.Entries {
+			if name := e.GetEqualFoldAttributeValue(v.config.GroupAttr); name != "" {
+				names[name] = struct{}{}
+			}
+		}
+	}
+
+	groups := make([]string, 0, len(names))
+	for name := range names {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+func (v *Validator) identity(username string, user *ldap.Entry, groups []string) *authmethod.Identity {
+	id := v.NewIdentity()
+	fields := id.SelectableFields.(*ldapFieldDetails)
+
+	fields.Username = username
+	fields.UserDN = user.DN
+	fields.Groups = groups
+	id.ProjectedVars["username"] = username
+	id.ProjectedVars["user_dn"] = user.DN
+
+	for _, attr := range v.config.UserAttributes {
+		value := user.GetEqualFoldAttributeValue(attr)
+		fields.Attributes[attr] = value
+		id.ProjectedVars["attributes."+attr] = value
+	}
+	return id
+}
+
+// NewIdentity implements authmethod.Validator.
+func (v *Validator) NewIdentity() *authmethod.Identity {
+	// Populate selectable fields with empty values so emptystring filters
+	// works. Populate projectable vars with empty values so HIL works.
+	fields := &ldapFieldDetails{
+		Groups:     []string{},
+		Attributes: make(map[string]string),
+	}
+	vars := map[string]string{
+		"username": "",
+		"user_dn":  "",
+	}
+	for _, attr := range v.config.UserAttributes {
+		fields.Attributes[attr] = ""
+		vars["attributes."+attr] = ""
+	}
+	return &authmethod.Identity{
+		SelectableFields: fields,
+		ProjectedVars:    vars,
+	}
+}
+
+type ldapFieldDetails struct {
+	Username   string            `bexpr:"username"`
+	UserDN     string            `bexpr:"user_dn"`
+	Groups     []string          `bexpr:"groups"`
+	Attributes map[string]string `bexpr:"attributes"`
+}
+
+// escapeDNValue escapes the special characters of an attribute value of a DN
+// as described in RFC 4514.
+func escapeDNValue(value string) string {
+	var b strings.Builder
+	for i, r := range value {
+		switch {
+		case strings.ContainsRune(`,+"\<>;=`, r),
+			i == 0 && (r == ' ' || r == '#'),
+			i == len(value)-1 && r == ' ':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == 0:
+			b.WriteString(`\00`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,284 @@
+package ldapauth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestNewValidator(t *testing.T) {
+	nullLogger := hclog.NewNullLogger()
+	type AM = *structs.ACLAuthMethod
+
+	makeAuthMethod := func(f func(method AM)) *structs.ACLAuthMethod {
+		method := &structs.ACLAuthMethod{
+			Name:        "test-ldap",
+			Description: "ldap test",
+			Type:        "ldap",
+			Config: map[string]interface{}{
+				"URL":    "ldap://127.0.0.1:389",
+				"UserDN": "ou=users,dc=example,dc=org",
+			},
+		}
+		if f != nil {
+			f(method)
+		}
+		return method
+	}
+
+	for name, tc := range map[string]struct {
+		method    *structs.ACLAuthMethod
+		expectErr string
+	}{
+		"wrong type": {makeAuthMethod(func(method AM) {
+			method.Type = "invalid"
+		}), "is not an LDAP auth method"},
+		"extra config": {makeAuthMethod(func(method AM) {
+			method.Config["extra"] = "config"
+		}), "has invalid keys"},
+		"missing URL": {makeAuthMethod(func(method AM) {
+			delete(method.Config, "URL")
+		}), "URL is required"},
+		"invalid scheme": {makeAuthMethod(func(method AM) {
+			method.Config["URL"] = "https://127.0.0.1"
+		}), "scheme must be ldap or ldaps"},
+		"start tls with ldaps": {makeAuthMethod(func(method AM) {
+			method.Config["URL"] = "ldaps://127.0.0.1"
+			method.Config["StartTLS"] = true
+		}), "StartTLS cannot be used"},
+		"missing UserDN": {makeAuthMethod(func(method AM) {
+			delete(method.Config, "UserDN")
+		}), "UserDN is required"},
+		"BindDN without password": {makeAuthMethod(func(method AM) {
+			method.Config["BindDN"] = "cn=admin,dc=example,dc=org"
+		}), "BindDN and BindPassword must be set together"},
+		"BindDN with UPNDomain": {makeAuthMethod(func(method AM) {
+			method.Config["BindDN"] = "cn=admin,dc=example,dc=org"
+			method.Config["BindPassword"] = "admin"
+			method.Config["UPNDomain"] = "example.org"
+		}), "BindDN and UPNDomain cannot be set together"},
+		"GroupFilter without GroupDN": {makeAuthMethod(func(method AM) {
+			method.Config["GroupFilter"] = "(member={{.UserDN}})"
+		}), "GroupFilter requires GroupDN"},
+		"invalid UserFilter": {makeAuthMethod(func(method AM) {
+			method.Config["UserFilter"] = "(uid={{.Username)"
+		}), "invalid UserFilter"},
+		"invalid CACert": {makeAuthMethod(func(method AM) {
+			method.Config["CACert"] = "not a cert"
+		}), "invalid CACert"},
+		"valid": {makeAuthMethod(nil), ""},
+		"valid with search": {makeAuthMethod(func(method AM) {
+			method.Config["BindDN"] = "cn=admin,dc=example,dc=org"
+			method.Config["BindPassword"] = "admin"
+			method.Config["GroupDN"] = "ou=groups,dc=example,dc=org"
+			method.Config["UserAttributes"] = []string{"mail"}
+		}), ""},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			v, err := NewValidator(nullLogger, tc.method)
+			if tc.expectErr != "" {
+				testutil.RequireErrorContains(t, err, tc.expectErr)
+				require.Nil(t, v)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, v)
+			}
+		})
+	}
+}
+
+func TestValidateLogin(t *testing.T) {
+	srv := StartTestServer(t)
+	srv.AddEntry(t, "cn=admin,dc=example,dc=org", nil)
+	srv.SetPassword("cn=admin,dc=example,dc=org", "admin")
+
+	aliceDN := "cn=alice,ou=users,dc=example,dc=org"
+	srv.AddEntry(t, aliceDN, map[string][]string{
+		"uid":               {"alice"},
+		"mail":              {"alice@example.org"},
+		"userPrincipalName": {"alice@example.org"},
+		"memberOf": {
+			"cn=ops,ou=groups,dc=example,dc=org",
+			"cn=dev,ou=groups,dc=example,dc=org",
+		},
+	})
+	srv.SetPassword(aliceDN, "s3cret")
+	srv.SetPassword("alice@example.org", "s3cret")
+	srv.AddEntry(t, "cn=bob,ou=users,dc=example,dc=org", map[string][]string{"uid": {"bob"}})
+
+	srv.AddEntry(t, "cn=ops,ou=groups,dc=example,dc=org", map[string][]string{
+		"member": {aliceDN},
+	})
+	srv.AddEntry(t, "cn=web,ou=groups,dc=example,dc=org", map[string][]string{
+		"memberUid": {"alice"},
+	})
+	srv.AddEntry(t, "cn=db,ou=groups,dc=example,dc=org", map[string][]string{
+		"member": {"cn=bob,ou=users,dc=example,dc=org"},
+	})
+
+	makeValidator := func(t *testing.T, config map[string]interface{}) *Validator {
+		config["URL"] = srv.URL()
+		config["UserDN"] = "ou=users,dc=example,dc=org"
+		v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+			Name:   "test-ldap",
+			Type:   "ldap",
+			Config: config,
+		})
+		require.NoError(t, err)
+		return v
+	}
+	login := func(v *Validator, username, password string) (*authmethod.Identity, error) {
+		token, err := json.Marshal(Credentials{Username: username, Password: password})
+		require.NoError(t, err)
+		return v.ValidateLogin(context.Background(), string(token))
+	}
+
+	t.Run("direct bind with memberOf", func(t *testing.T) {
+		v := makeValidator(t, map[string]interface{}{
+			"UserAttributes": []string{"mail", "title"},
+		})
+
+		id, err := login(v, "alice", "s3cret")
+		require.NoError(t, err)
+		require.Equal(t, &ldapFieldDetails{
+			Username: "alice",
+			UserDN:   aliceDN,
+			Groups:   []string{"dev", "ops"},
+			Attributes: map[string]string{
+				"mail":  "alice@example.org",
+				"title": "",
+			},
+		}, id.SelectableFields)
+		require.Equal(t, map[string]string{
+			"username":         "alice",
+			"user_dn":          aliceDN,
+			"attributes.mail":  "alice@example.org",
+			"attributes.title": "",
+		}, id.ProjectedVars)
+	})
+
+	t.Run("search with BindDN and group search", func(t *testing.T) {
+		v := makeValidator(t, map[string]interface{}{
+			"BindDN":       "cn=admin,dc=example,dc=org",
+			"BindPassword": "admin",
+			"UserAttr":     "uid",
+			"GroupDN":      "ou=groups,dc=example,dc=org",
+		})
+
+		id, err := login(v, "alice", "s3cret")
+		require.NoError(t, err)
+		fields := id.SelectableFields.(*ldapFieldDetails)
+		require.Equal(t, aliceDN, fields.UserDN)
+		require.Equal(t, []string{"ops", "web"}, fields.Groups)
+	})
+
+	t.Run("UPN bind", func(t *testing.T) {
+		v := makeValidator(t, map[string]interface{}{
+			"UPNDomain": "example.org",
+		})
+
+		id, err := login(v, "alice", "s3cret")
+		require.NoError(t, err)
+		require.Equal(t, aliceDN, id.ProjectedVars["user_dn"])
+	})
+
+	t.Run("custom group filter", func(t *testing.T) {
+		v := makeValidator(t, map[string]interface{}{
+			"GroupDN":     "ou=groups,dc=example,dc=org",
+			"GroupFilter": "(memberUid={{.Username}})",
+		})
+
+		id, err := login(v, "alice", "s3cret")
+		require.NoError(t, err)
+		require.Equal(t, []string{"web"}, id.SelectableFields.(*ldapFieldDetails).Groups)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		v := makeValidator(t, map[string]interface{}{
+			"BindDN":       "cn=admin,dc=example,dc=org",
+			"BindPassword": "admin",
+		})
+
+		_, err := login(v, "alice", "wrong")
+		require.Equal(t, errInvalidCredentials, err)
+
+		// Unknown users and users without a password look the same.
+		_, err = login(v, "carol", "s3cret")
+		require.Equal(t, errInvalidCredentials, err)
+		_, err = login(v, "bob", "s3cret")
+		require.Equal(t, errInvalidCredentials, err)
+
+		// Empty passwords would make an unauthenticated bind.
+		_, err = login(v, "alice", "")
+		require.Equal(t, errInvalidCredentials, err)
+
+		_, err = v.ValidateLogin(context.Background(), "not json")
+		testutil.RequireErrorContains(t, err, "invalid login token")
+	})
+
+	t.Run("filter injection", func(t *testing.T) {
+		v := makeValidator(t, map[string]interface{}{
+			"BindDN":       "cn=admin,dc=example,dc=org",
+			"BindPassword": "admin",
+		})
+
+		_, err := login(v, "*", "s3cret")
+		require.Equal(t, errInvalidCredentials, err)
+	})
+
+	t.Run("unreachable server", func(t *testing.T) {
+		v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+			Name: "test-ldap",
+			Type: "ldap",
+			Config: map[string]interface{}{
+				"URL":    "ldap://127.0.0.1:1",
+				"UserDN": "ou=users,dc=example,dc=org",
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = login(v, "alice", "s3cret")
+		testutil.RequireErrorContains(t, err, "error connecting to LDAP server")
+	})
+}
+
+func TestNewIdentity(t *testing.T) {
+	v, err := NewValidator(hclog.NewNullLogger(), &structs.ACLAuthMethod{
+		Name: "test-ldap",
+		Type: "ldap",
+		Config: map[string]interface{}{
+			"URL":            "ldap://127.0.0.1",
+			"UserDN":         "ou=users,dc=example,dc=org",
+			"UserAttributes": []string{"mail"},
+		},
+	})
+	require.NoError(t, err)
+
+	id := v.NewIdentity()
+	require.ElementsMatch(t, []string{"username", "user_dn", "attributes.mail"}, id.ProjectedVarNames())
+	require.Equal(t, &ldapFieldDetails{
+		Groups:     []string{},
+		Attributes: map[string]string{"mail": ""},
+	}, id.SelectableFields)
+}
+
+func TestEscapeDNValue(t *testing.T) {
+	for in, expected := range map[string]string{
+		"alice":     "alice",
+		"a,b":       `a\,b`,
+		"a=b+c":     `a\=b\+c`,
+		" alice ":   `\ alice\ `,
+		"#alice":    `\#alice`,
+		`a"b<c>;\d`: `a\"b\<c\>\;\\d`,
+	} {
+		require.Equal(t, expected, escapeDNValue(in), in)
+	}
+}
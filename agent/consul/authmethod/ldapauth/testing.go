@@ -0,0 +1,279 @@
+package ldapauth
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/mitchellh/go-testing-interface"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServer is a minimal in-memory LDAP server supporting the operations
+// used by the ldap auth method: simple binds and searches with equality,
+// presence, and, or and not filters.
+type TestServer struct {
+	listener net.Listener
+
+	mu        sync.Mutex
+	entries   map[string]*testEntry // keyed by lower-cased DN
+	passwords map[string]string     // keyed by lower-cased DN
+}
+
+type testEntry struct {
+	dn    *ldap.DN
+	name  string
+	attrs map[string][]string
+}
+
+// StartTestServer creates a disposable TestServer listening on a random free
+// port of the loopback interface.
+func StartTestServer(t testing.T) *TestServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &TestServer{
+		listener:  l,
+		entries:   make(map[string]*testEntry),
+		passwords: make(map[string]string),
+	}
+	t.Cleanup(s.Stop)
+	go s.serve()
+	return s
+}
+
+// URL returns the ldap:// URL of the server.
+func (s *TestServer) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+// Stop stops the server.
+func (s *TestServer) Stop() {
+	s.listener.Close()
+}
+
+// AddEntry adds an entry to the directory. The values of the RDN of the DN do
+// not need to be repeated in attrs.
+func (s *TestServer) AddEntry(t testing.T, dn string, attrs map[string][]string) {
+	parsed, err := ldap.ParseDN(dn)
+	require.NoError(t, err)
+
+	e := &testEntry{dn: parsed, name: dn, attrs: make(map[string][]string)}
+	for k, values := range attrs {
+		e.attrs[strings.ToLower(k)] = values
+	}
+	for _, rdn := range parsed.RDNs {
+		for _, a := range rdn.Attributes {
+			k := strings.ToLower(a.Type)
+			if _, ok := e.attrs[k]; !ok {
+				e.attrs[k] = []string{a.Value}
+			}
+		}
+		break
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[strings.ToLower(dn)] = e
+}
+
+// SetPassword sets the password used to bind as dn.
+func (s *TestServer) SetPassword(dn, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.passwords[strings.ToLower(dn)] = password
+}
+
+func (s *TestServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *TestServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	bound := false
+	for {
+		packet, err := ber.ReadPacket(r)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		var responses []*ber.Packet
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			code, msg := s.bind(op)
+			bound = code == ldap.LDAPResultSuccess
+			responses = append(responses, testResult(ldap.ApplicationBindResponse, code, msg))
+		case ldap.ApplicationSearchRequest:
+			if !bound {
+				responses = append(responses, testResult(ldap.ApplicationSearchResultDone,
+					ldap.LDAPResultInsufficientAccessRights, "bind required"))
+				break
+			}
+			responses = s.search(op)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = append(responses, testResult(ldap.ApplicationExtendedResponse,
+				ldap.LDAPResultUnwillingToPerform, "unsupported operation"))
+		}
+
+		for _, resp := range responses {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+			envelope.AppendChild(resp)
+			if _, err := conn.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *TestServer) bind(op *ber.Packet) (uint16, string) {
+	if len(op.Children) < 3 {
+		return ldap.LDAPResultProtocolError, "invalid bind request"
+	}
+	name := strings.ToLower(op.Children[1].Data.String())
+	password := op.Children[2].Data.String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if expected, ok := s.passwords[name]; !ok || password == "" || expected != password {
+		return ldap.LDAPResultInvalidCredentials, "invalid credentials"
+	}
+	return ldap.LDAPResultSuccess, ""
+}
+
+func (s *TestServer) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return []*ber.Packet{testResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "invalid search request")}
+	}
+	baseDN, err := ldap.ParseDN(op.Children[0].Data.String())
+	if err != nil {
+		return []*ber.Packet{testResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultInvalidDNSyntax, err.Error())}
+	}
+	scope, _ := op.Children[1].Value.(int64)
+	filter := op.Children[6]
+	var attrs []string
+	for _, a := range op.Children[7].Children {
+		attrs = append(attrs, a.Data.String())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if scope == ldap.ScopeBaseObject {
+		if _, ok := s.entries[strings.ToLower(op.Children[0].Data.String())]; !ok {
+			return []*ber.Packet{testResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultNoSuchObject, "no such object")}
+		}
+	}
+
+	var responses []*ber.Packet
+	for _, e := range s.entries {
+		switch scope {
+		case ldap.ScopeBaseObject:
+			if !baseDN.EqualFold(e.dn) {
+				continue
+			}
+		case ldap.ScopeSingleLevel:
+			if !baseDN.AncestorOfFold(e.dn) || len(e.dn.RDNs) != len(baseDN.RDNs)+1 {
+				continue
+			}
+		default:
+			if !baseDN.EqualFold(e.dn) && !baseDN.AncestorOfFold(e.dn) {
+				continue
+			}
+		}
+		if !e.matches(filter) {
+			continue
+		}
+		responses = append(responses, e.packet(attrs))
+	}
+	return append(responses, testResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+}
+
+func (e *testEntry) matches(filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, c := range filter.Children {
+			if !e.matches(c) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, c := range filter.Children {
+			if e.matches(c) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !e.matches(filter.Children[0])
+	case ldap.FilterPresent:
+		attr := strings.ToLower(filter.Data.String())
+		if attr == "objectclass" {
+			return true
+		}
+		_, ok := e.attrs[attr]
+		return ok
+	case ldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		attr := strings.ToLower(filter.Children[0].Data.String())
+		value := filter.Children[1].Data.String()
+		for _, v := range e.attrs[attr] {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func (e *testEntry) packet(attrs []string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.name, "Object Name"))
+
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, name := range attrs {
+		values, ok := e.attrs[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(set)
+		list.AppendChild(attr)
+	}
+	p.AppendChild(list)
+	return p
+}
+
+func testResult(tag ber.Tag, code uint16, msg string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, fmt.Sprintf("Result %d", code))
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, msg, "Diagnostic Message"))
+	return p
+}
@@ -338,6 +338,34 @@ type Config struct {
 	// limiter limits the rate to RequestLimitsWriteRate tokens per second.
 	RequestLimitsWriteRate rate.Limit
 
+	// RequestLimitsIPMode will disable or enable per-source-IP rate limiting,
+	// and has the same semantics as RequestLimitsMode.
+	RequestLimitsIPMode string
+
+	// RequestLimitsIPReadRate controls how frequently each source IP address
+	// is allowed to perform queries.
+	RequestLimitsIPReadRate rate.Limit
+
+	// RequestLimitsIPWriteRate controls how frequently each source IP address
+	// is allowed to perform writes.
+	RequestLimitsIPWriteRate rate.Limit
+
+	// RequestLimitsIPAllowlist contains CIDR blocks whose addresses are exempt
+	// from per-source-IP rate limiting (e.g. the other servers).
+	RequestLimitsIPAllowlist []net.IPNet
+
+	// RequestLimitsIPPrefixes configures rate limits that are shared by all
+	// addresses within a CIDR block, instead of being applied to each of them.
+	RequestLimitsIPPrefixes []IPPrefixRequestLimits
+
+	// RequestLimitsEndpoints configures rate limits for individual RPC
+	// endpoints, keyed by endpoint name (e.g. "KVS.Apply").
+	RequestLimitsEndpoints map[string]RequestLimitsPolicy
+
+	// RequestLimitsTokens configures rate limits for individual ACL tokens,
+	// keyed by accessor ID.
+	RequestLimitsTokens map[string]RequestLimitsPolicy
+
 	// RPCHandshakeTimeout limits how long we will wait for the initial magic byte
 	// on an RPC client connection. It also governs how long we will wait for a
 	// TLS handshake when TLS is configured however the timout applies separately
@@ -525,6 +553,10 @@ func DefaultConfig() *Config {
 		RequestLimitsReadRate:  rate.Inf, // ops / sec
 		RequestLimitsWriteRate: rate.Inf, // ops / sec
 
+		RequestLimitsIPMode:      "disabled",
+		RequestLimitsIPReadRate:  rate.Inf, // ops / sec
+		RequestLimitsIPWriteRate: rate.Inf, // ops / sec
+
 		RPCRateLimit: rate.Inf,
 		RPCMaxBurst:  1000,
 
@@ -650,6 +682,31 @@ type RequestLimits struct {
 	Mode      consulrate.Mode
 	ReadRate  rate.Limit
 	WriteRate rate.Limit
+
+	IPMode      consulrate.Mode
+	IPReadRate  rate.Limit
+	IPWriteRate rate.Limit
+	IPAllowlist []net.IPNet
+	IPPrefixes  []IPPrefixRequestLimits
+
+	Endpoints map[string]RequestLimitsPolicy
+	Tokens    map[string]RequestLimitsPolicy
+}
+
+// RequestLimitsPolicy is configuration for the rate limits applied to requests
+// against a specific endpoint, or made with a specific ACL token.
+type RequestLimitsPolicy struct {
+	Mode      consulrate.Mode
+	ReadRate  rate.Limit
+	WriteRate rate.Limit
+}
+
+// IPPrefixRequestLimits is configuration for the rate limits shared by all
+// source addresses within a CIDR block.
+type IPPrefixRequestLimits struct {
+	CIDR      net.IPNet
+	ReadRate  rate.Limit
+	WriteRate rate.Limit
 }
 
 // ReloadableConfig is the configuration that is passed to ReloadConfig when
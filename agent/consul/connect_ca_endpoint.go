@@ -1,8 +1,11 @@
 package consul
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -103,6 +106,78 @@ func (s *ConnectCA) ConfigurationSet(
 	return s.srv.caManager.UpdateConfiguration(args)
 }
 
+// ConfigurationDryRun validates a new configuration for the CA and returns
+// the changes applying it would make to the roots, without applying it.
+func (s *ConnectCA) ConfigurationDryRun(
+	args *structs.CARequest,
+	reply *structs.CARotationPlan) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	if done, err := s.srv.ForwardRPC("ConnectCA.ConfigurationDryRun", args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	authz, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorWriteAllowed(nil); err != nil {
+		return err
+	}
+
+	if args.Config == nil {
+		return fmt.Errorf("missing CA configuration")
+	}
+
+	plan, err := s.srv.caManager.DryRunConfiguration(args.Config)
+	if err != nil {
+		return err
+	}
+	*reply = *plan
+
+	return nil
+}
+
+// RotationStatus returns how many identities use a leaf certificate signed by
+// each of the trusted roots.
+func (s *ConnectCA) RotationStatus(
+	args *structs.DCSpecificRequest,
+	reply *structs.CARotationStatus) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	// The leaf certificates are only tracked by the leader, which signs
+	// them, so this cannot be served by a follower.
+	args.AllowStale = false
+	if done, err := s.srv.ForwardRPC("ConnectCA.RotationStatus", args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	authz, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorReadAllowed(nil); err != nil {
+		return err
+	}
+
+	status, err := s.srv.caManager.RotationStatus()
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	s.srv.setQueryMeta(&reply.QueryMeta, args.Token)
+
+	return nil
+}
+
 // Roots returns the currently trusted root certificates.
 func (s *ConnectCA) Roots(
 	args *structs.DCSpecificRequest,
@@ -131,6 +206,117 @@ func (s *ConnectCA) Roots(
 	)
 }
 
+// RevokedLeafs returns the revoked leaf certificates and identities along with
+// the reasons they were revoked.
+func (s *ConnectCA) RevokedLeafs(
+	args *structs.DCSpecificRequest,
+	reply *structs.IndexedCARevokedLeafs) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	if done, err := s.srv.ForwardRPC("ConnectCA.RevokedLeafs", args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	authz, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorReadAllowed(nil); err != nil {
+		return err
+	}
+
+	return s.srv.blockingQuery(
+		&args.QueryOptions, &reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, revoked, err := state.CARevokedLeafs(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.Index, reply.RevokedLeafs = index, revoked
+			return nil
+		},
+	)
+}
+
+// RevokeLeaf revokes a leaf certificate, or all the leaf certificates of an
+// identity, or removes a revocation. Revocations are written in the primary
+// datacenter and replicated to the secondary datacenters along with the roots.
+func (s *ConnectCA) RevokeLeaf(
+	args *structs.CARevokeLeafRequest,
+	reply *interface{}) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	// Ensure that all revocations go to the primary datacenter.
+	args.Datacenter = s.srv.config.PrimaryDatacenter
+	if done, err := s.srv.ForwardRPC("ConnectCA.RevokeLeaf", args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	authz, err := s.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorWriteAllowed(nil); err != nil {
+		return err
+	}
+
+	revoked, err := normalizeRevokedLeaf(args.RevokedLeaf)
+	if err != nil {
+		return err
+	}
+	switch args.Op {
+	case structs.CAOpRevokeLeaf:
+		revoked.RevokedAt = time.Now().UTC()
+	case structs.CAOpDeleteRevokedLeaf:
+	default:
+		return fmt.Errorf("Invalid revoke leaf operation %q", args.Op)
+	}
+
+	req := &structs.CARequest{
+		Op:           args.Op,
+		RevokedLeafs: []*structs.CARevokedLeaf{revoked},
+	}
+	_, err = s.srv.raftApplyMsgpack(structs.ConnectCARequestType, req)
+	return err
+}
+
+// normalizeRevokedLeaf validates that the revoked leaf is identified by
+// exactly one of its serial number and SPIFFE ID, and returns a copy using
+// their canonical encoding as ID.
+func normalizeRevokedLeaf(r structs.CARevokedLeaf) (*structs.CARevokedLeaf, error) {
+	if (r.SerialNumber == "") == (r.SpiffeID == "") {
+		return nil, fmt.Errorf("exactly one of SerialNumber and SpiffeID must be provided")
+	}
+
+	revoked := &structs.CARevokedLeaf{Reason: r.Reason}
+	if r.SerialNumber != "" {
+		b, err := hex.DecodeString(strings.ReplaceAll(r.SerialNumber, ":", ""))
+		serial := new(big.Int).SetBytes(b)
+		if err != nil || serial.Sign() == 0 {
+			return nil, fmt.Errorf("invalid serial number %q", r.SerialNumber)
+		}
+		revoked.SerialNumber = connect.EncodeSerialNumber(serial)
+		revoked.ID = revoked.SerialNumber
+	} else {
+		uri, err := connect.ParseCertURIFromString(r.SpiffeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPIFFE ID %q: %v", r.SpiffeID, err)
+		}
+		revoked.SpiffeID = uri.URI().String()
+		revoked.ID = revoked.SpiffeID
+	}
+	return revoked, nil
+}
+
 // Sign signs a certificate for a service.
 func (s *ConnectCA) Sign(
 	args *structs.CASignRequest,
@@ -154,7 +340,7 @@ func (s *ConnectCA) Sign(
 		return err
 	}
 
-	cert, err := s.srv.caManager.AuthorizeAndSignCertificate(csr, authz)
+	cert, err := s.srv.caManager.authorizeAndSignCertificate(csr, authz, args.Node)
 	if err != nil {
 		return err
 	}
@@ -77,6 +77,134 @@ func TestConnectCARoots(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.consul", caCfg.ClusterID), reply.TrustDomain)
 }
 
+func TestConnectCARevokeLeaf(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	revoke := func(op structs.CAOp, leaf structs.CARevokedLeaf) error {
+		args := &structs.CARevokeLeafRequest{
+			Datacenter:  "dc1",
+			Op:          op,
+			RevokedLeaf: leaf,
+		}
+		var reply interface{}
+		return msgpackrpc.CallWithCodec(codec, "ConnectCA.RevokeLeaf", args, &reply)
+	}
+	roots := func() structs.IndexedCARoots {
+		var reply structs.IndexedCARoots
+		args := &structs.DCSpecificRequest{Datacenter: "dc1"}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Roots", args, &reply))
+		return reply
+	}
+
+	// Exactly one identifier is required.
+	err := revoke(structs.CAOpRevokeLeaf, structs.CARevokedLeaf{})
+	testutil.RequireErrorContains(t, err, "exactly one of SerialNumber and SpiffeID must be provided")
+	err = revoke(structs.CAOpRevokeLeaf, structs.CARevokedLeaf{SerialNumber: "0a", SpiffeID: "spiffe://foo"})
+	testutil.RequireErrorContains(t, err, "exactly one of SerialNumber and SpiffeID must be provided")
+	err = revoke(structs.CAOpRevokeLeaf, structs.CARevokedLeaf{SerialNumber: "zz"})
+	testutil.RequireErrorContains(t, err, "invalid serial number")
+	err = revoke(structs.CAOpRevokeLeaf, structs.CARevokedLeaf{SpiffeID: "https://example.com"})
+	testutil.RequireErrorContains(t, err, "invalid SPIFFE ID")
+
+	// Serial numbers are stored in their canonical encoding.
+	require.NoError(t, revoke(structs.CAOpRevokeLeaf, structs.CARevokedLeaf{SerialNumber: "0A0B", Reason: "key compromise"}))
+
+	spiffeID := connect.TestSpiffeIDService(t, "web")
+	require.NoError(t, revoke(structs.CAOpRevokeLeaf, structs.CARevokedLeaf{SpiffeID: spiffeID.URI().String()}))
+
+	reply := roots()
+	require.Len(t, reply.RevokedLeafs, 2)
+	bySerial := reply.LeafRevoked("0a:0b", "")
+	require.NotNil(t, bySerial)
+	require.Equal(t, "0a:0b", bySerial.ID)
+	require.Equal(t, "key compromise", bySerial.Reason)
+	require.False(t, bySerial.RevokedAt.IsZero())
+	require.NotNil(t, reply.LeafRevoked("", spiffeID.URI().String()))
+
+	// Signing is refused for revoked identities.
+	csr, _ := connect.TestCSR(t, spiffeID)
+	signArgs := &structs.CASignRequest{Datacenter: "dc1", CSR: csr}
+	var cert structs.IssuedCert
+	err = msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", signArgs, &cert)
+	testutil.RequireErrorContains(t, err, "SPIFFE ID in CSR has been revoked")
+
+	// Removing the revocation allows signing again.
+	require.NoError(t, revoke(structs.CAOpDeleteRevokedLeaf, structs.CARevokedLeaf{SpiffeID: spiffeID.URI().String()}))
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", signArgs, &cert))
+
+	reply = roots()
+	require.Len(t, reply.RevokedLeafs, 1)
+	require.Equal(t, "0a:0b", reply.RevokedLeafs[0].ID)
+}
+
+func TestConnectCARevokedLeafs_ACLDeny(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = TestDefaultInitialManagementToken
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	opReadToken, err := upsertTestTokenWithPolicyRules(
+		codec, TestDefaultInitialManagementToken, "dc1", `operator = "read"`)
+	require.NoError(t, err)
+
+	revokeArgs := &structs.CARevokeLeafRequest{
+		Datacenter:   "dc1",
+		Op:           structs.CAOpRevokeLeaf,
+		RevokedLeaf:  structs.CARevokedLeaf{SerialNumber: "0a:0b", Reason: "key compromise"},
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+	}
+	var ignored interface{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.RevokeLeaf", revokeArgs, &ignored))
+
+	t.Run("deny", func(t *testing.T) {
+		args := &structs.DCSpecificRequest{Datacenter: "dc1"}
+		var reply structs.IndexedCARevokedLeafs
+		err := msgpackrpc.CallWithCodec(codec, "ConnectCA.RevokedLeafs", args, &reply)
+		if !acl.IsErrPermissionDenied(err) {
+			t.Fatalf("expected permission denied, got %v", err)
+		}
+	})
+
+	t.Run("operator read", func(t *testing.T) {
+		args := &structs.DCSpecificRequest{
+			Datacenter:   "dc1",
+			QueryOptions: structs.QueryOptions{Token: opReadToken.SecretID},
+		}
+		var reply structs.IndexedCARevokedLeafs
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.RevokedLeafs", args, &reply))
+		require.Len(t, reply.RevokedLeafs, 1)
+		require.Equal(t, "0a:0b", reply.RevokedLeafs[0].SerialNumber)
+		require.Equal(t, "key compromise", reply.RevokedLeafs[0].Reason)
+	})
+}
+
 func TestConnectCAConfig_GetSet(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -818,6 +946,279 @@ func TestConnectCAConfig_UpdateSecondary(t *testing.T) {
 }
 
 // Test CA signing
+func TestConnectCAConfig_DryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	_, oldRoot, err := state.CARootActive(nil)
+	require.NoError(t, err)
+	_, oldConfig, err := state.CAConfig(nil)
+	require.NoError(t, err)
+	oldSerial, err := state.CAProviderSerialNumber()
+	require.NoError(t, err)
+
+	dryRun := func(conf *structs.CAConfiguration) (*structs.CARotationPlan, error) {
+		args := &structs.CARequest{
+			Datacenter: "dc1",
+			Config:     conf,
+		}
+		var reply structs.CARotationPlan
+		err := msgpackrpc.CallWithCodec(codec, "ConnectCA.ConfigurationDryRun", args, &reply)
+		return &reply, err
+	}
+
+	testutil.RunStep(t, "unchanged configuration", func(t *testing.T) {
+		plan, err := dryRun(&structs.CAConfiguration{
+			Provider: oldConfig.Provider,
+			Config:   oldConfig.Config,
+		})
+		require.NoError(t, err)
+		require.Equal(t, oldRoot.ID, plan.CurrentRootID)
+		require.False(t, plan.RootRotation)
+		require.Empty(t, plan.NewRootID)
+	})
+
+	_, newKey, err := connect.GeneratePrivateKey()
+	require.NoError(t, err)
+	newConfig := &structs.CAConfiguration{
+		Provider: "consul",
+		Config: map[string]interface{}{
+			"PrivateKey": newKey,
+		},
+	}
+
+	testutil.RunStep(t, "new private key", func(t *testing.T) {
+		plan, err := dryRun(newConfig)
+		require.NoError(t, err)
+		require.Equal(t, "consul", plan.Provider)
+		require.Equal(t, oldRoot.ID, plan.CurrentRootID)
+		require.True(t, plan.RootRotation)
+		require.True(t, plan.CrossSign)
+		require.NotEmpty(t, plan.NewRootID)
+		require.NotEqual(t, oldRoot.ID, plan.NewRootID)
+
+		// The new root uses the provided key.
+		newRoot, err := connect.ParseCert(plan.NewRootCert)
+		require.NoError(t, err)
+		signer, err := connect.ParseSigner(newKey)
+		require.NoError(t, err)
+		require.Equal(t, signer.Public(), newRoot.PublicKey)
+	})
+
+	testutil.RunStep(t, "without cross-signing", func(t *testing.T) {
+		conf := *newConfig
+		conf.ForceWithoutCrossSigning = true
+		plan, err := dryRun(&conf)
+		require.NoError(t, err)
+		require.True(t, plan.RootRotation)
+		require.False(t, plan.CrossSign)
+		require.Contains(t, plan.Warnings[0], "not cross-signed")
+	})
+
+	testutil.RunStep(t, "invalid configuration", func(t *testing.T) {
+		_, err := dryRun(&structs.CAConfiguration{
+			Provider: "vault",
+			Config:   map[string]interface{}{},
+		})
+		testutil.RequireErrorContains(t, err, "error configuring provider")
+
+		_, err = dryRun(&structs.CAConfiguration{
+			Provider: "consul",
+			Config:   oldConfig.Config,
+			State:    map[string]string{"foo": "bar"},
+		})
+		testutil.RequireErrorContains(t, err, ErrStateReadOnly.Error())
+	})
+
+	testutil.RunStep(t, "nothing is applied", func(t *testing.T) {
+		_, roots, err := state.CARoots(nil)
+		require.NoError(t, err)
+		require.Len(t, roots, 1)
+		require.Equal(t, oldRoot.ID, roots[0].ID)
+
+		_, config, err := state.CAConfig(nil)
+		require.NoError(t, err)
+		require.Equal(t, oldConfig, config)
+
+		serial, err := state.CAProviderSerialNumber()
+		require.NoError(t, err)
+		require.Equal(t, oldSerial, serial)
+	})
+}
+
+func TestConnectCARotationStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	status := func() structs.CARotationStatus {
+		args := &structs.DCSpecificRequest{Datacenter: "dc1"}
+		var reply structs.CARotationStatus
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.RotationStatus", args, &reply))
+		return reply
+	}
+	sign := func(node string, spiffeID connect.CertURI) {
+		csr, _ := connect.TestCSR(t, spiffeID)
+		args := &structs.CASignRequest{Datacenter: "dc1", CSR: csr, Node: node}
+		var reply structs.IssuedCert
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.Sign", args, &reply))
+	}
+	register := func(node string, svc *structs.NodeService) {
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Address:    "127.0.0.1",
+			Service:    svc,
+		}
+		var out struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", args, &out))
+	}
+	pendingIDs := func(status structs.CARotationStatus) []string {
+		var ids []string
+		for _, instance := range status.PendingInstances {
+			ids = append(ids, instance.Node+"/"+instance.ServiceID)
+		}
+		return ids
+	}
+
+	// Two instances of web on different nodes and a natively integrated api.
+	register("node1", &structs.NodeService{
+		Kind:    structs.ServiceKindConnectProxy,
+		ID:      "web-sidecar-proxy",
+		Service: "web-sidecar-proxy",
+		Port:    21000,
+		Proxy:   structs.ConnectProxyConfig{DestinationServiceName: "web"},
+	})
+	register("node2", &structs.NodeService{
+		Kind:    structs.ServiceKindConnectProxy,
+		ID:      "web-sidecar-proxy",
+		Service: "web-sidecar-proxy",
+		Port:    21000,
+		Proxy:   structs.ConnectProxyConfig{DestinationServiceName: "web"},
+	})
+	register("node1", &structs.NodeService{
+		ID:      "api",
+		Service: "api",
+		Port:    8080,
+		Connect: structs.ServiceConnect{Native: true},
+	})
+
+	web := connect.TestSpiffeIDService(t, "web")
+	api := connect.TestSpiffeIDService(t, "api")
+
+	// Only the instance of web on node1 has a leaf certificate yet.
+	sign("node1", web)
+	sign("node1", web)
+	sign("", &connect.SpiffeIDAgent{Host: "11111111-2222-3333-4444-555555555555.consul", Datacenter: "dc1", Agent: "node1"})
+
+	reply := status()
+	require.False(t, reply.TrackingSince.IsZero())
+	require.Len(t, reply.Roots, 1)
+	oldRoot := reply.Roots[0]
+	require.True(t, oldRoot.Active)
+	require.Equal(t, oldRoot.ID, reply.ActiveRootID)
+	require.Equal(t, 1, oldRoot.Services)
+	require.Equal(t, 1, oldRoot.Agents)
+	require.Equal(t, 3, reply.Instances)
+	require.Equal(t, 1, reply.UpdatedInstances)
+	require.ElementsMatch(t, []string{"node2/web-sidecar-proxy", "node1/api"}, pendingIDs(reply))
+	require.False(t, reply.Complete)
+
+	sign("node2", web)
+	sign("node1", api)
+
+	// Every instance has a leaf certificate signed by the active root, but
+	// the leaf certificates signed before tracking started may still be in
+	// use.
+	reply = status()
+	require.Equal(t, 3, reply.Roots[0].Services)
+	require.Equal(t, 3, reply.UpdatedInstances)
+	require.Empty(t, reply.PendingInstances)
+	require.False(t, reply.Complete)
+
+	s1.caManager.leafTracker.Lock()
+	s1.caManager.leafTracker.since = s1.caManager.leafTracker.since.Add(-73 * time.Hour)
+	s1.caManager.leafTracker.Unlock()
+
+	reply = status()
+	require.True(t, reply.Complete)
+
+	// Rotate the root.
+	_, newKey, err := connect.GeneratePrivateKey()
+	require.NoError(t, err)
+	args := &structs.CARequest{
+		Datacenter: "dc1",
+		Config: &structs.CAConfiguration{
+			Provider: "consul",
+			Config: map[string]interface{}{
+				"PrivateKey":  newKey,
+				"LeafCertTTL": "72h",
+			},
+		},
+	}
+	var setReply interface{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.ConfigurationSet", args, &setReply))
+
+	reply = status()
+	require.Len(t, reply.Roots, 2)
+	require.NotEqual(t, oldRoot.ID, reply.ActiveRootID)
+	for _, r := range reply.Roots {
+		if r.ID == oldRoot.ID {
+			require.False(t, r.Active)
+			require.False(t, r.RotatedOutAt.IsZero())
+			require.Equal(t, r.RotatedOutAt.Add(144*time.Hour), r.PruneAfter)
+			require.Equal(t, 3, r.Services)
+		} else {
+			require.True(t, r.Active)
+			require.Zero(t, r.Services)
+		}
+	}
+	require.Zero(t, reply.UpdatedInstances)
+	require.Len(t, reply.PendingInstances, 3)
+	for _, instance := range reply.PendingInstances {
+		require.Equal(t, oldRoot.ID, instance.RootID)
+	}
+	require.False(t, reply.Complete)
+
+	// Renewing a leaf moves the instances of the identity on that node to the
+	// new root.
+	sign("node1", web)
+
+	reply = status()
+	for _, r := range reply.Roots {
+		if r.ID == oldRoot.ID {
+			require.Equal(t, 2, r.Services)
+		} else {
+			require.Equal(t, 1, r.Services)
+		}
+	}
+	require.Equal(t, 1, reply.UpdatedInstances)
+	require.ElementsMatch(t, []string{"node2/web-sidecar-proxy", "node1/api"}, pendingIDs(reply))
+	require.False(t, reply.Complete)
+}
+
 func TestConnectCASign(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -451,6 +451,28 @@ func ApplyConnectCAOperationFromRequest(state *state.Store, req *structs.CAReque
 		}
 
 		return sn
+	case structs.CAOpRevokeLeaf:
+		for _, r := range req.RevokedLeafs {
+			if err := state.CARevokeLeaf(index, r); err != nil {
+				return err
+			}
+		}
+
+		return true
+	case structs.CAOpDeleteRevokedLeaf:
+		for _, r := range req.RevokedLeafs {
+			if err := state.CADeleteRevokedLeaf(index, r.ID); err != nil {
+				return err
+			}
+		}
+
+		return true
+	case structs.CAOpSetRevokedLeafs:
+		if err := state.CASetRevokedLeafs(index, req.RevokedLeafs); err != nil {
+			return err
+		}
+
+		return true
 	default:
 		return fmt.Errorf("Invalid CA operation '%s'", req.Op)
 	}
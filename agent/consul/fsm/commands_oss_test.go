@@ -1267,6 +1267,49 @@ func TestFSM_CARoots(t *testing.T) {
 	}
 }
 
+func TestFSM_CARevokedLeafs(t *testing.T) {
+	t.Parallel()
+
+	logger := testutil.Logger(t)
+	fsm, err := New(nil, logger)
+	require.NoError(t, err)
+
+	apply := func(t *testing.T, req structs.CARequest) {
+		buf, err := structs.Encode(structs.ConnectCARequestType, req)
+		require.NoError(t, err)
+		require.Equal(t, true, fsm.Apply(makeLog(buf)))
+	}
+
+	apply(t, structs.CARequest{
+		Op: structs.CAOpRevokeLeaf,
+		RevokedLeafs: []*structs.CARevokedLeaf{
+			{ID: "01", SerialNumber: "01"},
+			{ID: "02", SerialNumber: "02"},
+		},
+	})
+	_, revoked, err := fsm.state.CARevokedLeafs(nil)
+	require.NoError(t, err)
+	require.Len(t, revoked, 2)
+
+	apply(t, structs.CARequest{
+		Op:           structs.CAOpDeleteRevokedLeaf,
+		RevokedLeafs: []*structs.CARevokedLeaf{{ID: "01"}},
+	})
+	_, revoked, err = fsm.state.CARevokedLeafs(nil)
+	require.NoError(t, err)
+	require.Len(t, revoked, 1)
+	require.Equal(t, "02", revoked[0].ID)
+
+	apply(t, structs.CARequest{
+		Op:           structs.CAOpSetRevokedLeafs,
+		RevokedLeafs: []*structs.CARevokedLeaf{{ID: "03", SerialNumber: "03"}},
+	})
+	_, revoked, err = fsm.state.CARevokedLeafs(nil)
+	require.NoError(t, err)
+	require.Len(t, revoked, 1)
+	require.Equal(t, "03", revoked[0].ID)
+}
+
 func TestFSM_CABuiltinProvider(t *testing.T) {
 	t.Parallel()
 
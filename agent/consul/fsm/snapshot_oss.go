@@ -27,6 +27,7 @@ func init() {
 	registerRestorer(structs.ConnectCARequestType, restoreConnectCA)
 	registerRestorer(structs.ConnectCAProviderStateType, restoreConnectCAProviderState)
 	registerRestorer(structs.ConnectCAConfigType, restoreConnectCAConfig)
+	registerRestorer(structs.ConnectCARevokedLeafType, restoreConnectCARevokedLeaf)
 	registerRestorer(structs.IndexRequestType, restoreIndex)
 	registerRestorer(structs.ACLTokenSetRequestType, restoreToken)
 	registerRestorer(structs.ACLPolicySetRequestType, restorePolicy)
@@ -80,6 +81,9 @@ func persistOSS(s *snapshot, sink raft.SnapshotSink, encoder *codec.Encoder) err
 	if err := s.persistConnectCAConfig(sink, encoder); err != nil {
 		return err
 	}
+	if err := s.persistConnectCARevokedLeafs(sink, encoder); err != nil {
+		return err
+	}
 	if err := s.persistConfigEntries(sink, encoder); err != nil {
 		return err
 	}
@@ -416,6 +420,24 @@ func (s *snapshot) persistConnectCAProviderState(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *snapshot) persistConnectCARevokedLeafs(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	revoked, err := s.state.CARevokedLeafs()
+	if err != nil {
+		return err
+	}
+
+	for _, r := range revoked {
+		if _, err := sink.Write([]byte{byte(structs.ConnectCARevokedLeafType)}); err != nil {
+			return err
+		}
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *snapshot) persistLegacyIntentions(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	//nolint:staticcheck
@@ -736,6 +758,17 @@ func restoreConnectCAConfig(header *SnapshotHeader, restore *state.Restore, deco
 	return nil
 }
 
+func restoreConnectCARevokedLeaf(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
+	var req structs.CARevokedLeaf
+	if err := decoder.Decode(&req); err != nil {
+		return err
+	}
+	if err := restore.CARevokedLeaf(&req); err != nil {
+		return err
+	}
+	return nil
+}
+
 func restoreIndex(header *SnapshotHeader, restore *state.Restore, decoder *codec.Decoder) error {
 	var req state.IndexEntry
 	if err := decoder.Decode(&req); err != nil {
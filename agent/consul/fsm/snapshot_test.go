@@ -226,6 +226,14 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 	err = fsm.state.CASetConfig(17, caConfig)
 	require.NoError(t, err)
 
+	// CA revoked leafs
+	revokedLeaf := &structs.CARevokedLeaf{
+		ID:           "0a:0b",
+		SerialNumber: "0a:0b",
+		Reason:       "key compromise",
+	}
+	require.NoError(t, fsm.state.CARevokeLeaf(17, revokedLeaf))
+
 	// Config entries
 	serviceConfig := &structs.ServiceConfigEntry{
 		Kind:     structs.ServiceDefaults,
@@ -737,6 +745,12 @@ func TestFSM_SnapshotRestore_OSS(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, caConfig, caConf)
 
+	// Verify CA revoked leafs are restored.
+	_, revokedLeafs, err := fsm2.state.CARevokedLeafs(nil)
+	require.NoError(t, err)
+	require.Len(t, revokedLeafs, 1)
+	require.Equal(t, revokedLeaf.Reason, revokedLeafs[0].Reason)
+
 	// Verify config entries are restored
 	_, serviceConfEntry, err := fsm2.state.ConfigEntry(nil, structs.ServiceDefaults, "foo", structs.DefaultEnterpriseMetaInDefaultPartition())
 	require.NoError(t, err)
@@ -81,6 +81,10 @@ type CAManager struct {
 
 	// shim time.Now for testing
 	timeNow func() time.Time
+
+	// leafTracker records the root used to sign the leaf certificates, to
+	// report the progress of a root rotation.
+	leafTracker caLeafTracker
 }
 
 type caDelegateWithState struct {
@@ -312,6 +316,8 @@ func (c *CAManager) setCAProvider(newProvider ca.Provider, root *structs.CARoot)
 }
 
 func (c *CAManager) Start(ctx context.Context) {
+	c.leafTracker.reset(c.timeNow())
+
 	// Attempt to initialize the Connect CA now. This will
 	// happen during leader establishment and it would be great
 	// if the CA was ready to go once that process was finished.
@@ -458,6 +464,10 @@ func (c *CAManager) newProvider(conf *structs.CAConfiguration) (ca.Provider, err
 		return ca.NewVaultProvider(logger), nil
 	case structs.AWSCAProvider:
 		return ca.NewAWSProvider(logger), nil
+	case structs.PKCS11CAProvider:
+		return ca.NewPKCS11Provider(logger), nil
+	case structs.ACMECAProvider:
+		return ca.NewACMEProvider(c.delegate, logger), nil
 	default:
 		if c.providerShim != nil {
 			return c.providerShim, nil
@@ -986,6 +996,12 @@ func (c *CAManager) primaryUpdateRootCA(newProvider ca.Provider, args *structs.C
 		}
 	}
 
+	// Generating the intermediate may have changed the provider state.
+	args.Config.State, err = newProvider.State()
+	if err != nil {
+		return fmt.Errorf("error getting provider state: %v", err)
+	}
+
 	// Update the roots and CA config in the state store at the same time
 	idx, roots, err := state.CARoots(nil)
 	if err != nil {
@@ -1260,6 +1276,10 @@ func (c *CAManager) secondaryCARootWatch(ctx context.Context) error {
 		default:
 		}
 
+		if err := c.secondaryReplicateRevokedLeafs(roots); err != nil {
+			return err
+		}
+
 		// Attempt to update the roots using the returned data.
 		if err := c.secondaryUpdateRoots(roots); err != nil {
 			return err
@@ -1276,6 +1296,47 @@ func (c *CAManager) secondaryCARootWatch(ctx context.Context) error {
 	return nil
 }
 
+// secondaryReplicateRevokedLeafs stores the revoked leafs of the primary
+// datacenter, returned along with its roots, when they differ from the local
+// ones.
+func (c *CAManager) secondaryReplicateRevokedLeafs(roots structs.IndexedCARoots) error {
+	_, local, err := c.delegate.State().CARevokedLeafs(nil)
+	if err != nil {
+		return err
+	}
+	if revokedLeafsEqual(local, roots.RevokedLeafs) {
+		return nil
+	}
+
+	req := structs.CARequest{
+		Op:           structs.CAOpSetRevokedLeafs,
+		RevokedLeafs: roots.RevokedLeafs,
+	}
+	if _, err := c.delegate.ApplyCARequest(&req); err != nil {
+		return fmt.Errorf("failed to replicate revoked leafs: %w", err)
+	}
+	return nil
+}
+
+// revokedLeafsEqual compares the revoked leafs, ignoring their raft indexes.
+func revokedLeafsEqual(a, b []*structs.CARevokedLeaf) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := *a[i], *b[i]
+		x.RaftIndex, y.RaftIndex = structs.RaftIndex{}, structs.RaftIndex{}
+		if !x.RevokedAt.Equal(y.RevokedAt) {
+			return false
+		}
+		x.RevokedAt, y.RevokedAt = time.Time{}, time.Time{}
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
 // secondaryUpdateRoots updates the cached roots from the primary and regenerates the intermediate
 // certificate if necessary.
 func (c *CAManager) secondaryUpdateRoots(roots structs.IndexedCARoots) error {
@@ -1410,6 +1471,12 @@ func (l *connectSignRateLimiter) getCSRRateLimiterWithLimit(limit rate.Limit) *r
 // identified by the SPIFFE ID in the given CSR's SAN. It performs authorization
 // using the given acl.Authorizer.
 func (c *CAManager) AuthorizeAndSignCertificate(csr *x509.CertificateRequest, authz acl.Authorizer) (*structs.IssuedCert, error) {
+	return c.authorizeAndSignCertificate(csr, authz, "")
+}
+
+// authorizeAndSignCertificate is AuthorizeAndSignCertificate for a
+// certificate requested by the given node, if known.
+func (c *CAManager) authorizeAndSignCertificate(csr *x509.CertificateRequest, authz acl.Authorizer, node string) (*structs.IssuedCert, error) {
 	// Note that only one spiffe id is allowed currently. If more than one is desired
 	// in future implmentations, then each ID should have authorization checks.
 	if len(csr.URIs) != 1 {
@@ -1477,10 +1544,14 @@ func (c *CAManager) AuthorizeAndSignCertificate(csr *x509.CertificateRequest, au
 		return nil, connect.InvalidCSRError("SPIFFE ID in CSR must be a service, mesh-gateway, or agent ID")
 	}
 
-	return c.SignCertificate(csr, spiffeID)
+	return c.signCertificate(csr, spiffeID, node)
 }
 
 func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID connect.CertURI) (*structs.IssuedCert, error) {
+	return c.signCertificate(csr, spiffeID, "")
+}
+
+func (c *CAManager) signCertificate(csr *x509.CertificateRequest, spiffeID connect.CertURI, node string) (*structs.IssuedCert, error) {
 	provider, caRoot := c.getCAProvider()
 	if provider == nil {
 		return nil, fmt.Errorf("CA is uninitialized and unable to sign certificates yet: provider is nil")
@@ -1552,6 +1623,18 @@ func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID conne
 		return nil, connect.InvalidCSRError("SPIFFE ID in CSR must be a service, agent, server, or mesh gateway ID")
 	}
 
+	// Refuse to sign certificates for revoked identities.
+	_, revokedLeafs, err := state.CARevokedLeafs(nil)
+	if err != nil {
+		return nil, err
+	}
+	uri := spiffeID.URI().String()
+	for _, revoked := range revokedLeafs {
+		if revoked.SpiffeID == uri {
+			return nil, connect.InvalidCSRError("SPIFFE ID in CSR has been revoked: %s", uri)
+		}
+	}
+
 	commonCfg, err := config.GetCommonConfig()
 	if err != nil {
 		return nil, err
@@ -1644,6 +1727,8 @@ func (c *CAManager) SignCertificate(csr *x509.CertificateRequest, spiffeID conne
 		return nil, errors.New("not possible")
 	}
 
+	c.leafTracker.record(node, spiffeID, caRoot.ID, cert.NotAfter)
+
 	return &reply, nil
 }
 
@@ -1679,6 +1764,10 @@ func providerPrettyName(provider string) string {
 		return "Vault"
 	case "aws-pca":
 		return "Aws-Pca"
+	case "pkcs11":
+		return "PKCS11"
+	case "acme":
+		return "ACME"
 	case "provider-name":
 		return "Provider-Name"
 	default:
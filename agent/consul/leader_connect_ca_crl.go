@@ -0,0 +1,138 @@
+package consul
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/connect/ca"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/lib/stringslice"
+)
+
+// leafCRLs returns the CRLs the local datacenter publishes in its federation
+// state. The CRL of the active leaf signing certificate revokes every leaf
+// certificate revoked by serial number and is only signed again when they or
+// the signing certificate change. The CRLs of the previous signing
+// certificates are kept, unchanged, until the leaf certificates they signed
+// have expired.
+//
+// No CRL is published when the provider can't sign them, in which case the
+// proxies don't check CRLs at all.
+func (c *CAManager) leafCRLs(ws memdb.WatchSet, state *state.Store, prev []*structs.CALeafCRL) (uint64, []*structs.CALeafCRL, error) {
+	idx, activeRoot, err := state.CARootActive(ws)
+	if err != nil {
+		return 0, nil, err
+	}
+	revokedIdx, revokedLeafs, err := state.CARevokedLeafs(ws)
+	if err != nil {
+		return 0, nil, err
+	}
+	configIdx, config, err := state.CAConfig(ws)
+	if err != nil {
+		return 0, nil, err
+	}
+	idx = lib.MaxUint64(idx, lib.MaxUint64(revokedIdx, configIdx))
+	if activeRoot == nil || config == nil {
+		return idx, nil, nil
+	}
+
+	provider, _ := c.getCAProvider()
+	signer, ok := provider.(ca.LeafCRLSigner)
+	if !ok {
+		return idx, nil, nil
+	}
+	common, err := config.GetCommonConfig()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		revoked []pkix.RevokedCertificate
+		serials []string
+	)
+	for _, r := range revokedLeafs {
+		if r.SerialNumber == "" {
+			continue
+		}
+		b, err := hex.DecodeString(strings.ReplaceAll(r.SerialNumber, ":", ""))
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid revoked serial number %q: %v", r.SerialNumber, err)
+		}
+		revoked = append(revoked, pkix.RevokedCertificate{
+			SerialNumber:   new(big.Int).SetBytes(b),
+			RevocationTime: r.RevokedAt,
+		})
+		serials = append(serials, r.SerialNumber)
+	}
+
+	var current *structs.CALeafCRL
+	for _, crl := range prev {
+		if crl.RetiredAt.IsZero() &&
+			crl.SigningKeyID == activeRoot.SigningKeyID &&
+			stringslice.Equal(crl.SerialNumbers, serials) {
+			current = crl
+		}
+	}
+	if current == nil {
+		crlPEM, err := signer.SignLeafCRL(revoked)
+		if err != nil {
+			c.logger.Warn("failed to sign the leaf certificate revocation list; proxies won't reject the leaf certificates revoked by serial number",
+				"error", err)
+			return idx, nil, nil
+		}
+		keyID, err := crlSigningKeyID(crlPEM)
+		if err != nil {
+			return 0, nil, err
+		}
+		if keyID != activeRoot.SigningKeyID {
+			// The provider hasn't switched to the new signing certificate yet.
+			return 0, nil, fmt.Errorf("CA provider signed the leaf certificate revocation list with %s instead of the active signing key %s",
+				keyID, activeRoot.SigningKeyID)
+		}
+		current = &structs.CALeafCRL{
+			SigningKeyID:  keyID,
+			SerialNumbers: serials,
+			CRL:           crlPEM,
+		}
+	}
+
+	crls := []*structs.CALeafCRL{current}
+	now := c.timeNow()
+	for _, crl := range prev {
+		if crl.SigningKeyID == current.SigningKeyID {
+			continue
+		}
+		// Copy so the structure in the memdb store is never modified.
+		retired := *crl
+		if retired.RetiredAt.IsZero() {
+			retired.RetiredAt = now
+		}
+		if now.Sub(retired.RetiredAt) < leafCertTTL(common) {
+			crls = append(crls, &retired)
+		}
+	}
+	return idx, crls, nil
+}
+
+// crlSigningKeyID returns the id of the key that signed the PEM encoded CRL.
+func crlSigningKeyID(crlPEM string) (string, error) {
+	block, _ := pem.Decode([]byte(crlPEM))
+	if block == nil {
+		return "", fmt.Errorf("no PEM-encoded data found in the leaf certificate revocation list")
+	}
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("error parsing the leaf certificate revocation list: %v", err)
+	}
+	return connect.EncodeSigningKeyID(crl.AuthorityKeyId), nil
+}
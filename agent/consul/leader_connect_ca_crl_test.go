@@ -0,0 +1,140 @@
+package consul
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/consul-net-rpc/net-rpc-msgpackrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestLeader_FederationStateAntiEntropy_LeafCRLs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc2"
+		c.PrimaryDatacenter = "dc1"
+		c.FederationStateReplicationRate = 100
+		c.FederationStateReplicationBurst = 100
+		c.FederationStateReplicationApplyLimit = 1000000
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	joinWAN(t, s2, s1)
+	waitForLeaderEstablishment(t, s1)
+	waitForLeaderEstablishment(t, s2)
+	waitForFederationStateFeature(t, s1)
+	waitForFederationStateFeature(t, s2)
+	testrpc.WaitForActiveCARoot(t, s1.RPC, "dc1", nil)
+	testrpc.WaitForActiveCARoot(t, s2.RPC, "dc2", nil)
+
+	parseCRL := func(t require.TestingT, crlPEM string) *x509.RevocationList {
+		block, _ := pem.Decode([]byte(crlPEM))
+		require.NotNil(t, block)
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		require.NoError(t, err)
+		return crl
+	}
+
+	// Every datacenter publishes the CRL of its leaf signing certificate.
+	retry.Run(t, func(r *retry.R) {
+		_, fedStates, err := s1.fsm.State().FederationStateList(nil)
+		require.NoError(r, err)
+		require.Len(r, fedStates, 2)
+		for _, fedState := range fedStates {
+			require.Len(r, fedState.LeafCRLs, 1)
+			require.Empty(r, fedState.LeafCRLs[0].SerialNumbers)
+		}
+	})
+
+	// The proxies don't check CRLs until a serial number is revoked.
+	roots, err := s1.getCARoots(nil, s1.fsm.State())
+	require.NoError(t, err)
+	require.Empty(t, roots.LeafCRLs)
+
+	revokeArgs := &structs.CARevokeLeafRequest{
+		Datacenter:  "dc1",
+		Op:          structs.CAOpRevokeLeaf,
+		RevokedLeaf: structs.CARevokedLeaf{SerialNumber: "0a:0b"},
+	}
+	var ignored interface{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.RevokeLeaf", revokeArgs, &ignored))
+
+	for _, srv := range []*Server{s1, s2} {
+		retry.Run(t, func(r *retry.R) {
+			roots, err := srv.getCARoots(nil, srv.fsm.State())
+			require.NoError(r, err)
+			require.Len(r, roots.LeafCRLs, 2)
+			for _, crlPEM := range roots.LeafCRLs {
+				crl := parseCRL(r, crlPEM)
+				require.Len(r, crl.RevokedCertificates, 1)
+				require.Equal(r, big.NewInt(0x0a0b), crl.RevokedCertificates[0].SerialNumber)
+			}
+		})
+	}
+
+	// The CRL of the secondary is signed by its intermediate, which signs
+	// its leaf certificates.
+	_, fedState, err := s1.fsm.State().FederationStateGet(nil, "dc2")
+	require.NoError(t, err)
+	provider, _ := s2.caManager.getCAProvider()
+	intermediatePEM, err := provider.ActiveIntermediate()
+	require.NoError(t, err)
+	intermediate, err := connect.ParseCert(intermediatePEM)
+	require.NoError(t, err)
+	require.NoError(t, parseCRL(t, fedState.LeafCRLs[0].CRL).CheckSignatureFrom(intermediate))
+
+	// Rotate the primary CA, the CRL of the previous root is kept for the
+	// leaf certificates it signed.
+	_, oldRoot, err := s1.fsm.State().CARootActive(nil)
+	require.NoError(t, err)
+	configArgs := &structs.CARequest{
+		Datacenter: "dc1",
+		Config: &structs.CAConfiguration{
+			Provider: "consul",
+			Config: map[string]interface{}{
+				"PrivateKeyType": "rsa",
+				"PrivateKeyBits": 2048,
+			},
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.ConfigurationSet", configArgs, &ignored))
+
+	_, newRoot, err := s1.fsm.State().CARootActive(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, oldRoot.SigningKeyID, newRoot.SigningKeyID)
+
+	retry.Run(t, func(r *retry.R) {
+		_, fedState, err := s1.fsm.State().FederationStateGet(nil, "dc1")
+		require.NoError(r, err)
+		require.Len(r, fedState.LeafCRLs, 2)
+
+		require.Equal(r, newRoot.SigningKeyID, fedState.LeafCRLs[0].SigningKeyID)
+		require.True(r, fedState.LeafCRLs[0].RetiredAt.IsZero())
+		require.Equal(r, []string{"0a:0b"}, fedState.LeafCRLs[0].SerialNumbers)
+
+		require.Equal(r, oldRoot.SigningKeyID, fedState.LeafCRLs[1].SigningKeyID)
+		require.False(r, fedState.LeafCRLs[1].RetiredAt.IsZero())
+	})
+}
@@ -0,0 +1,474 @@
+package consul
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/agent/connect"
+	"github.com/hashicorp/consul/agent/connect/ca"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// DryRunConfiguration validates a CA configuration change and returns what
+// applying it with UpdateConfiguration would do, without changing anything.
+//
+// The new root and its intermediate are only generated for the built-in
+// provider, whose state is kept in memory for the dry-run. The other providers
+// create resources outside of Consul when they are configured, so only their
+// configuration is validated.
+func (c *CAManager) DryRunConfiguration(newConfig *structs.CAConfiguration) (*structs.CARotationPlan, error) {
+	state := c.delegate.State()
+	_, config, err := state.CAConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("CA is uninitialized: no configuration")
+	}
+
+	// Same restrictions as UpdateConfiguration.
+	if len(newConfig.State) > 0 &&
+		!reflect.DeepEqual(newConfig.State, config.State) {
+		return nil, ErrStateReadOnly
+	}
+	conf := *newConfig
+	conf.ClusterID = config.ClusterID
+
+	plan := &structs.CARotationPlan{Provider: conf.Provider}
+	_, root, err := state.CARootActive(nil)
+	if err != nil {
+		return nil, err
+	}
+	if root != nil {
+		plan.CurrentRootID = root.ID
+	}
+
+	if conf.Provider == config.Provider && reflect.DeepEqual(conf.Config, config.Config) {
+		return plan, nil
+	}
+
+	if conf.Provider == config.Provider {
+		conf.State = config.State
+
+		newProvider, err := c.newProvider(&conf)
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize provider: %v", err)
+		}
+		if validator, ok := newProvider.(ValidateConfigUpdater); ok {
+			if err := validator.ValidateConfigUpdate(config.Config, conf.Config); err != nil {
+				return nil, fmt.Errorf("new configuration is incompatible with previous configuration: %w", err)
+			}
+		}
+	}
+
+	isPrimary := c.serverConf.Datacenter == c.serverConf.PrimaryDatacenter
+	if !isPrimary || conf.Provider != structs.ConsulCAProvider {
+		if err := validateCAProviderConfig(conf.Provider, conf.Config); err != nil {
+			return nil, fmt.Errorf("error configuring provider: %v", err)
+		}
+	}
+
+	switch {
+	case !isPrimary:
+		plan.Warnings = append(plan.Warnings, "The root CA is managed by the primary datacenter, "+
+			"the intermediate of this datacenter is regenerated if the provider changes.")
+		return plan, nil
+
+	case conf.Provider == structs.ConsulCAProvider:
+		if err := c.dryRunConsulProvider(&conf, root, plan); err != nil {
+			return nil, err
+		}
+
+	default:
+		// A different provider always comes with a different root.
+		plan.RootRotation = conf.Provider != config.Provider
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("The %s provider only generates its root "+
+			"when the configuration is applied, the new root is not shown.", providerPrettyName(conf.Provider)))
+		if !plan.RootRotation {
+			plan.Warnings = append(plan.Warnings, "The root CA is rotated if the provider returns a different root.")
+		}
+	}
+
+	if !plan.RootRotation || root == nil {
+		return plan, nil
+	}
+
+	oldProvider, _ := c.getCAProvider()
+	if oldProvider == nil {
+		return nil, fmt.Errorf("internal error: CA provider is nil")
+	}
+	canXSign, err := oldProvider.SupportsCrossSigning()
+	if err != nil {
+		return nil, fmt.Errorf("CA provider error: %s", err)
+	}
+	if !canXSign && !conf.ForceWithoutCrossSigning {
+		return nil, errors.New("The current CA Provider does not support cross-signing. " +
+			"You can try again with ForceWithoutCrossSigningSet but this may cause " +
+			"disruption - see documentation for more.")
+	}
+	plan.CrossSign = canXSign && !conf.ForceWithoutCrossSigning
+	if !plan.CrossSign {
+		plan.Warnings = append(plan.Warnings, "The new root is not cross-signed, connections fail "+
+			"until every proxy has observed the new root.")
+	}
+
+	if common, err := conf.GetCommonConfig(); err == nil {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("The current root is pruned %s after the rotation, "+
+			"leaf certificates still signed by it are rejected after that.", common.LeafCertTTL*2))
+	}
+	return plan, nil
+}
+
+// dryRunConsulProvider generates the root and intermediate of the built-in
+// provider for conf without persisting its state.
+func (c *CAManager) dryRunConsulProvider(conf *structs.CAConfiguration, root *structs.CARoot, plan *structs.CARotationPlan) error {
+	delegate := &dryRunProviderStateDelegate{state: c.delegate.State()}
+	provider := ca.NewConsulProvider(delegate, c.logger.Named(conf.Provider))
+	pCfg := ca.ProviderConfig{
+		ClusterID:  conf.ClusterID,
+		Datacenter: c.serverConf.Datacenter,
+		IsPrimary:  true,
+		RawConfig:  conf.Config,
+		State:      conf.State,
+	}
+	if err := provider.Configure(pCfg); err != nil {
+		return fmt.Errorf("error configuring provider: %v", err)
+	}
+
+	providerRoot, err := provider.GenerateRoot()
+	if err != nil {
+		return fmt.Errorf("error generating CA root certificate: %v", err)
+	}
+	newRoot, err := newCARoot(providerRoot.PEM, conf.Provider, conf.ClusterID)
+	if err != nil {
+		return err
+	}
+	intermediate, err := provider.GenerateIntermediate()
+	if err != nil {
+		return err
+	}
+
+	plan.NewRootID = newRoot.ID
+	plan.NewRootCert = newRoot.RootCert
+	if intermediate != providerRoot.PEM {
+		plan.NewIntermediateCerts = []string{intermediate}
+	}
+	plan.RootRotation = root == nil || root.ID != newRoot.ID
+	return nil
+}
+
+// validateCAProviderConfig validates the configuration of a provider without
+// configuring it.
+func validateCAProviderConfig(provider string, raw map[string]interface{}) error {
+	var err error
+	switch provider {
+	case structs.ConsulCAProvider:
+		_, err = ca.ParseConsulCAConfig(raw)
+	case structs.VaultCAProvider:
+		_, err = ca.ParseVaultCAConfig(raw)
+	case structs.AWSCAProvider:
+		_, err = ca.ParseAWSCAConfig(raw)
+	case structs.PKCS11CAProvider:
+		_, err = ca.ParsePKCS11CAConfig(raw)
+	case structs.ACMECAProvider:
+		_, err = ca.ParseACMECAConfig(raw)
+	default:
+		err = fmt.Errorf("unknown CA provider %q", provider)
+	}
+	return err
+}
+
+// dryRunProviderStateDelegate is a ca.ConsulProviderStateDelegate reading the
+// provider state from the state store and keeping its changes in memory.
+type dryRunProviderStateDelegate struct {
+	state *state.Store
+
+	// providerStates holds the states written during the dry-run, a nil
+	// value marks a deleted state.
+	providerStates map[string]*structs.CAConsulProviderState
+	serial         uint64
+}
+
+func (d *dryRunProviderStateDelegate) ProviderState(id string) (*structs.CAConsulProviderState, error) {
+	if providerState, ok := d.providerStates[id]; ok {
+		return providerState, nil
+	}
+	_, providerState, err := d.state.CAProviderState(id)
+	return providerState, err
+}
+
+func (d *dryRunProviderStateDelegate) ApplyCARequest(req *structs.CARequest) (interface{}, error) {
+	if d.providerStates == nil {
+		d.providerStates = make(map[string]*structs.CAConsulProviderState)
+	}
+
+	switch req.Op {
+	case structs.CAOpSetProviderState:
+		providerState := *req.ProviderState
+		d.providerStates[providerState.ID] = &providerState
+		return true, nil
+	case structs.CAOpDeleteProviderState:
+		d.providerStates[req.ProviderState.ID] = nil
+		return true, nil
+	case structs.CAOpIncrementProviderSerialNumber:
+		if d.serial == 0 {
+			last, err := d.state.CAProviderSerialNumber()
+			if err != nil {
+				return nil, err
+			}
+			d.serial = last
+		}
+		d.serial++
+		return d.serial, nil
+	default:
+		return nil, fmt.Errorf("unexpected CA operation %q during dry-run", req.Op)
+	}
+}
+
+// caLeafTracker records which root signed the most recent leaf certificate of
+// each identity on each node, so the progress of a root rotation can be
+// reported. It is only populated on the leader, which signs all the leaf
+// certificates of the datacenter, and is kept in memory only: it starts empty
+// whenever leadership is acquired.
+type caLeafTracker struct {
+	sync.Mutex
+
+	since  time.Time
+	leaves map[trackedLeafKey]trackedLeaf
+}
+
+// trackedLeafKey identifies the leaf certificate of an identity on a node.
+// The node is empty when the caller didn't report it.
+type trackedLeafKey struct {
+	node     string
+	identity string
+}
+
+type trackedLeaf struct {
+	rootID   string
+	kind     string
+	notAfter time.Time
+}
+
+const (
+	trackedLeafService     = "service"
+	trackedLeafMeshGateway = "mesh-gateway"
+	trackedLeafAgent       = "agent"
+	trackedLeafServer      = "server"
+)
+
+// reset forgets all the recorded leafs.
+func (t *caLeafTracker) reset(now time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.since = now
+	t.leaves = make(map[trackedLeafKey]trackedLeaf)
+}
+
+// record stores the root which signed the latest leaf of the identity on the
+// node.
+func (t *caLeafTracker) record(node string, spiffeID connect.CertURI, rootID string, notAfter time.Time) {
+	var kind string
+	switch id := spiffeID.(type) {
+	case *connect.SpiffeIDService:
+		kind = trackedLeafService
+	case *connect.SpiffeIDMeshGateway:
+		kind = trackedLeafMeshGateway
+	case *connect.SpiffeIDAgent:
+		kind = trackedLeafAgent
+		node = id.Agent
+	case *connect.SpiffeIDServer:
+		kind = trackedLeafServer
+	default:
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.leaves == nil {
+		t.leaves = make(map[trackedLeafKey]trackedLeaf)
+	}
+	key := trackedLeafKey{node: node, identity: spiffeID.URI().String()}
+	t.leaves[key] = trackedLeaf{
+		rootID:   rootID,
+		kind:     kind,
+		notAfter: notAfter,
+	}
+}
+
+// status fills the counts of each root of status from the recorded leafs,
+// ignoring and removing the expired ones, and the instances still pending.
+// It returns whether an unexpired leaf signed by another root than the active
+// one was recorded.
+func (t *caLeafTracker) status(now time.Time, status *structs.CARotationStatus, instances []*structs.CARotationInstance) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	status.TrackingSince = t.since
+
+	byID := make(map[string]*structs.CARootRotationStatus, len(status.Roots))
+	for _, r := range status.Roots {
+		byID[r.ID] = r
+	}
+
+	inactive := false
+	for key, leaf := range t.leaves {
+		if now.After(leaf.notAfter) {
+			delete(t.leaves, key)
+			continue
+		}
+		if leaf.rootID != status.ActiveRootID {
+			inactive = true
+		}
+
+		r, ok := byID[leaf.rootID]
+		if !ok {
+			// The root has been pruned already.
+			continue
+		}
+		switch leaf.kind {
+		case trackedLeafService:
+			r.Services++
+		case trackedLeafMeshGateway:
+			r.MeshGateways++
+		case trackedLeafAgent:
+			r.Agents++
+		case trackedLeafServer:
+			r.Servers++
+		}
+	}
+
+	status.Instances = len(instances)
+	for _, instance := range instances {
+		leaf, ok := t.leaves[trackedLeafKey{node: instance.Node, identity: instance.Identity}]
+		if ok && leaf.rootID == status.ActiveRootID {
+			status.UpdatedInstances++
+			continue
+		}
+		if ok {
+			instance.RootID = leaf.rootID
+		}
+		status.PendingInstances = append(status.PendingInstances, instance)
+	}
+	return inactive
+}
+
+// RotationStatus returns how many leaf certificates signed by each of the
+// trusted roots are in use, and which instances of the catalog are not known
+// to use a leaf certificate signed by the active root.
+func (c *CAManager) RotationStatus() (*structs.CARotationStatus, error) {
+	state := c.delegate.State()
+	_, roots, err := state.CARoots(nil)
+	if err != nil {
+		return nil, err
+	}
+	_, config, err := state.CAConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	common, err := config.GetCommonConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Secondary datacenters sign with an intermediate of the root of the
+	// primary datacenter, so the roots are those of the primary.
+	var status structs.CARotationStatus
+	for _, r := range roots {
+		rootStatus := &structs.CARootRotationStatus{
+			ID:     r.ID,
+			Name:   r.Name,
+			Active: r.Active,
+		}
+		if r.Active {
+			status.ActiveRootID = r.ID
+		} else if !r.RotatedOutAt.IsZero() {
+			rootStatus.RotatedOutAt = r.RotatedOutAt
+			rootStatus.PruneAfter = r.RotatedOutAt.Add(common.LeafCertTTL * 2)
+		}
+		status.Roots = append(status.Roots, rootStatus)
+	}
+
+	instances, err := c.rotationInstances(state, config)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.timeNow()
+	inactive := c.leafTracker.status(now, &status, instances)
+
+	// The leaf certificates signed before tracking started are unknown until
+	// they have expired.
+	status.Complete = !inactive &&
+		len(status.PendingInstances) == 0 &&
+		!now.Before(status.TrackingSince.Add(leafCertTTL(common)))
+	return &status, nil
+}
+
+// rotationInstances returns the service instances of the catalog which use a
+// leaf certificate, along with the SPIFFE ID of their certificate.
+// Terminating gateways are not included since they use the certificates of
+// the services they are linked to.
+func (c *CAManager) rotationInstances(state *state.Store, config *structs.CAConfiguration) ([]*structs.CARotationInstance, error) {
+	signingID := connect.SpiffeIDSigningForCluster(config.ClusterID)
+	entMeta := structs.WildcardEnterpriseMetaInDefaultPartition()
+	_, nodes, err := state.ServiceDump(nil, "", false, entMeta, structs.DefaultPeerKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []*structs.CARotationInstance
+	for _, n := range nodes {
+		svc := n.Service
+		var id connect.CertURI
+		switch {
+		case svc.Kind == structs.ServiceKindConnectProxy:
+			id = &connect.SpiffeIDService{
+				Host:       signingID.Host(),
+				Partition:  svc.PartitionOrDefault(),
+				Namespace:  svc.NamespaceOrDefault(),
+				Datacenter: c.serverConf.Datacenter,
+				Service:    svc.Proxy.DestinationServiceName,
+			}
+		case svc.Kind == structs.ServiceKindIngressGateway,
+			svc.Kind == structs.ServiceKindTypical && svc.Connect.Native:
+			id = &connect.SpiffeIDService{
+				Host:       signingID.Host(),
+				Partition:  svc.PartitionOrDefault(),
+				Namespace:  svc.NamespaceOrDefault(),
+				Datacenter: c.serverConf.Datacenter,
+				Service:    svc.Service,
+			}
+		case svc.Kind == structs.ServiceKindMeshGateway:
+			id = &connect.SpiffeIDMeshGateway{
+				Host:       signingID.Host(),
+				Partition:  svc.PartitionOrDefault(),
+				Datacenter: c.serverConf.Datacenter,
+			}
+		default:
+			continue
+		}
+		instances = append(instances, &structs.CARotationInstance{
+			Node:      n.Node.Node,
+			ServiceID: svc.ID,
+			Identity:  id.URI().String(),
+		})
+	}
+	return instances, nil
+}
+
+// leafCertTTL returns the TTL of the leaf certificates of the configuration.
+func leafCertTTL(common *structs.CommonCAProviderConfig) time.Duration {
+	if common.LeafCertTTL == 0 {
+		// The provider signs the leaf certificates for the default TTL.
+		ttl, _ := time.ParseDuration(structs.DefaultLeafCertTTL)
+		return ttl
+	}
+	return common.LeafCertTTL
+}
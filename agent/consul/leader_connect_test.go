@@ -1066,6 +1066,71 @@ func TestCAManager_Initialize_TransitionFromPrimaryToSecondary(t *testing.T) {
 	})
 }
 
+func TestCAManager_Secondary_ReplicatesRevokedLeafs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Build = "1.6.0"
+		c.PrimaryDatacenter = "dc1"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// dc2 as a secondary DC
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc2"
+		c.PrimaryDatacenter = "dc1"
+		c.Build = "1.6.0"
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	// Create the WAN link
+	joinWAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s2.RPC, "dc2")
+
+	// Revoke a leaf through the secondary, the write is forwarded to the
+	// primary.
+	codec := rpcClient(t, s2)
+	defer codec.Close()
+	args := &structs.CARevokeLeafRequest{
+		Datacenter:  "dc2",
+		Op:          structs.CAOpRevokeLeaf,
+		RevokedLeaf: structs.CARevokedLeaf{SerialNumber: "0a:0b"},
+	}
+	var reply interface{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.RevokeLeaf", args, &reply))
+
+	_, primary, err := s1.fsm.State().CARevokedLeafs(nil)
+	require.NoError(t, err)
+	require.Len(t, primary, 1)
+
+	// The secondary replicates it from the primary roots.
+	retry.Run(t, func(r *retry.R) {
+		_, secondary, err := s2.fsm.State().CARevokedLeafs(nil)
+		require.NoError(r, err)
+		require.Len(r, secondary, 1)
+		require.Equal(r, "0a:0b", secondary[0].ID)
+		require.True(r, primary[0].RevokedAt.Equal(secondary[0].RevokedAt))
+	})
+
+	// Removing the revocation is replicated as well.
+	args.Op = structs.CAOpDeleteRevokedLeaf
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConnectCA.RevokeLeaf", args, &reply))
+
+	retry.Run(t, func(r *retry.R) {
+		_, secondary, err := s2.fsm.State().CARevokedLeafs(nil)
+		require.NoError(r, err)
+		require.Empty(r, secondary)
+	})
+}
+
 func TestCAManager_Initialize_SecondaryBeforePrimary(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
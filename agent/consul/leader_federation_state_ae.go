@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/consul/agent/consul/state"
 	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/lib"
 )
 
 const (
@@ -162,17 +163,24 @@ func (s *Server) fetchFederationStateAntiEntropyDetails(
 				return err
 			}
 
+			// Sign the CRLs of our leaf signing certificates.
+			var prevCRLs []*structs.CALeafCRL
+			if prev != nil {
+				prevCRLs = prev.LeafCRLs
+			}
+			idx3, crls, err := s.caManager.leafCRLs(ws, state, prevCRLs)
+			if err != nil {
+				return err
+			}
+
 			curr := &structs.FederationState{
 				Datacenter:   s.config.Datacenter,
 				MeshGateways: raw,
+				LeafCRLs:     crls,
 			}
 
 			// Compute the maximum index seen.
-			if idx2 > idx1 {
-				queryMeta.Index = idx2
-			} else {
-				queryMeta.Index = idx1
-			}
+			queryMeta.Index = lib.MaxUint64(idx1, lib.MaxUint64(idx2, idx3))
 
 			prevFedState = prev
 			currFedState = curr
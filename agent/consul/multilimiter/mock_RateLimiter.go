@@ -27,6 +27,11 @@ func (_m *MockRateLimiter) Allow(entity LimitedEntity) bool {
 	return r0
 }
 
+// RemoveConfig provides a mock function with given fields: prefix
+func (_m *MockRateLimiter) RemoveConfig(prefix []byte) {
+	_m.Called(prefix)
+}
+
 // Run provides a mock function with given fields: ctx
 func (_m *MockRateLimiter) Run(ctx context.Context) {
 	_m.Called(ctx)
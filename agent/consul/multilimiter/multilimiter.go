@@ -30,6 +30,7 @@ type RateLimiter interface {
 	Run(ctx context.Context)
 	Allow(entity LimitedEntity) bool
 	UpdateConfig(c LimiterConfig, prefix []byte)
+	RemoveConfig(prefix []byte)
 }
 
 type limiterWithKey struct {
@@ -89,6 +90,19 @@ func (m *MultiLimiter) UpdateConfig(c LimiterConfig, prefix []byte) {
 	m.limitersConfigs.Store(newConfigs)
 }
 
+// RemoveConfig removes the LimiterConfig of a prefix, the Limiter(s) under
+// that prefix fall back to the MultiLimiter Config.
+func (m *MultiLimiter) RemoveConfig(prefix []byte) {
+	m.configsLock.Lock()
+	defer m.configsLock.Unlock()
+	if prefix == nil {
+		prefix = []byte("")
+	}
+	configs := m.limitersConfigs.Load()
+	newConfigs, _, _ := configs.Delete(prefix)
+	m.limitersConfigs.Store(newConfigs)
+}
+
 // NewMultiLimiter create a new MultiLimiter
 func NewMultiLimiter(c Config) *MultiLimiter {
 	limiters := atomic.Pointer[radix.Tree]{}
@@ -222,15 +236,14 @@ func (m *MultiLimiter) reconcileConfig(txn *radix.Txn) {
 		}
 
 		// find the prefix for the leaf and check if the defaultConfig is up-to-date
-		// it's possible that the prefix is equal to the key
+		// it's possible that the prefix is equal to the key, and limiters of
+		// a prefix without config use the defaultConfig
 		prefix, _ := splitKey(k)
-		v, ok := m.limitersConfigs.Load().Get(prefix)
-		if v == nil || !ok {
-			continue
-		}
-		cl, ok := v.(*LimiterConfig)
-		if cl == nil || !ok {
-			continue
+		cl := &m.defaultConfig.Load().LimiterConfig
+		if v, ok := m.limitersConfigs.Load().Get(prefix); ok && v != nil {
+			if prefixConfig, ok := v.(*LimiterConfig); ok && prefixConfig != nil {
+				cl = prefixConfig
+			}
 		}
 		if cl.isApplied(pl.limiter) {
 			continue
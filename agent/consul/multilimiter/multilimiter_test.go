@@ -298,6 +298,29 @@ func TestRateLimiterUpdateConfig(t *testing.T) {
 		limiter := l.(*Limiter)
 		require.True(t, c1.isApplied(limiter.limiter))
 	})
+
+	t.Run("Remove a prefix config and check defaultConfig is applied to existing keys under that prefix", func(t *testing.T) {
+		c := Config{LimiterConfig: LimiterConfig{Rate: 0.1}, ReconcileCheckLimit: 100 * time.Millisecond, ReconcileCheckInterval: 10 * time.Millisecond}
+		m := NewMultiLimiter(c)
+		require.Equal(t, *m.defaultConfig.Load(), c)
+		prefix := []byte("namespace.read")
+		ip := Key(prefix, []byte("127.0.0.1"))
+		c1 := LimiterConfig{Rate: 1}
+		m.UpdateConfig(c1, prefix)
+		m.Allow(ipLimited{key: ip})
+		storeLimiter(m)
+		m.RemoveConfig(prefix)
+		_, ok := m.limitersConfigs.Load().Get(prefix)
+		require.False(t, ok)
+		// call reconcileConfig to make sure the removal is applied
+		txn := m.limiters.Load().Txn()
+		m.reconcileConfig(txn)
+		l, ok := txn.Get(ip)
+		require.True(t, ok)
+		require.NotNil(t, l)
+		limiter := l.(*Limiter)
+		require.True(t, c.LimiterConfig.isApplied(limiter.limiter))
+	})
 }
 
 func FuzzSingleConfig(f *testing.F) {
@@ -24,6 +24,7 @@ func TestWalk_ServiceQuery(t *testing.T) {
 			Datacenters: []string{"dc1", "dc2"},
 		},
 		Near:           "_agent",
+		Locality:       structs.QueryLocalityOptions{NodeMetaKeys: []string{"zone"}},
 		Tags:           []string{"tag1", "tag2", "tag3"},
 		NodeMeta:       map[string]string{"foo": "bar", "role": "server"},
 		EnterpriseMeta: *structs.DefaultEnterpriseMetaInDefaultPartition(),
@@ -35,9 +36,12 @@ func TestWalk_ServiceQuery(t *testing.T) {
 	expected := []string{
 		".Failover.Datacenters[0]:dc1",
 		".Failover.Datacenters[1]:dc2",
+		".Failover.Policy:",
+		".Locality.NodeMetaKeys[0]:zone",
 		".Near:_agent",
 		".NodeMeta[foo]:bar",
 		".NodeMeta[role]:server",
+		".Ordering:",
 		".Service:the-service",
 		".Tags[0]:tag1",
 		".Tags[1]:tag2",
@@ -2,6 +2,8 @@ package consul
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -197,6 +199,32 @@ func parseService(svc *structs.ServiceQuery) error {
 		return fmt.Errorf("Targets cannot be populated with NearestN or Datacenters")
 	}
 
+	switch failover.Policy {
+	case "", structs.QueryFailoverPolicyOrdered, structs.QueryFailoverPolicyHealthWeighted:
+	default:
+		return fmt.Errorf("Bad failover Policy '%s', must be %s or %s", failover.Policy,
+			structs.QueryFailoverPolicyOrdered, structs.QueryFailoverPolicyHealthWeighted)
+	}
+
+	for _, target := range failover.Targets {
+		if target.Weight < 0 {
+			return fmt.Errorf("Bad Weight '%d' for failover target, must be >= 0", target.Weight)
+		}
+	}
+
+	switch svc.Ordering {
+	case "", structs.QueryOrderingShuffle, structs.QueryOrderingWeighted:
+	default:
+		return fmt.Errorf("Bad Ordering '%s', must be %s or %s", svc.Ordering,
+			structs.QueryOrderingShuffle, structs.QueryOrderingWeighted)
+	}
+
+	for _, key := range svc.Locality.NodeMetaKeys {
+		if key == "" {
+			return fmt.Errorf("Locality NodeMetaKeys cannot contain an empty key")
+		}
+	}
+
 	// Make sure the metadata filters are valid
 	if err := structs.ValidateNodeMetadata(svc.NodeMeta, true); err != nil {
 		return err
@@ -368,8 +396,25 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 		return structs.ErrQueryNotFound
 	}
 
+	// The trace is checked against the token of the request rather than the
+	// one of the query, which may have more privileges.
+	if args.Trace {
+		if err := p.allowTrace(args.QueryOptions.Token); err != nil {
+			return err
+		}
+	}
+
+	trace := newQueryTrace(args.Trace, p.srv.config.Datacenter)
+	if query.Template.Type != "" {
+		trace.add("resolve", 0, "Rendered %s template of query %q for %q, querying service %q",
+			query.Template.Type, query.ID, args.QueryIDOrName, query.Service.Service)
+	} else {
+		trace.add("resolve", 0, "Resolved %q to query %q, querying service %q",
+			args.QueryIDOrName, query.ID, query.Service.Service)
+	}
+
 	// Execute the query for the local DC.
-	if err := p.execute(query, reply, args.Connect); err != nil {
+	if err := p.execute(query, reply, args.Connect, trace); err != nil {
 		return err
 	}
 
@@ -382,6 +427,7 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 	if err := p.srv.filterACL(token, reply); err != nil {
 		return err
 	}
+	trace.add("filter-acl", len(reply.Nodes), "Applied the ACL filter")
 
 	// TODO (slackpad) We could add a special case here that will avoid the
 	// fail over if we filtered everything due to ACLs. This seems like it
@@ -393,7 +439,8 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 
 	// Shuffle the results in case coordinates are not available if they
 	// requested an RTT sort.
-	reply.Nodes.Shuffle()
+	shuffleNodes(query, reply.Nodes)
+	trace.add("order", len(reply.Nodes), "Shuffled the results using the %s ordering", orderingName(query))
 
 	// Build the query source. This can be provided by the client, or by
 	// the prepared query. Client-specified takes priority.
@@ -440,6 +487,33 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 	if err != nil {
 		return err
 	}
+	if qs.Node != "" {
+		trace.add("sort-distance", len(reply.Nodes), "Sorted by distance from node %q in datacenter %q, if it has coordinates",
+			qs.Node, qs.Datacenter)
+	}
+
+	// Group the results by locality, provided they are from the same
+	// datacenter as the source node.
+	if keys := query.Service.Locality.NodeMetaKeys; len(keys) > 0 && reply.Datacenter == qs.Datacenter {
+		source := qs.Node
+		if source == "" {
+			source = args.Agent.Node
+		}
+		if source != "" {
+			entMeta := structs.NodeEnterpriseMetaInPartition(query.Service.PartitionOrDefault())
+			_, node, err := state.GetNode(source, entMeta, structs.DefaultPeerKeyword)
+			if err != nil {
+				return err
+			}
+			if node != nil {
+				sortNodesByLocality(node.Meta, keys, reply.Nodes)
+				trace.add("sort-locality", len(reply.Nodes), "Grouped by the locality of node %q using %s",
+					source, strings.Join(keys, ", "))
+			} else {
+				trace.add("sort-locality", len(reply.Nodes), "Skipped locality ordering, node %q is unknown", source)
+			}
+		}
+	}
 
 	// If we applied a distance sort, make sure that the node queried for is in
 	// position 0, provided the results are from the same datacenter.
@@ -461,7 +535,9 @@ func (p *PreparedQuery) Execute(args *structs.PreparedQueryExecuteRequest,
 	// Apply the limit if given.
 	if args.Limit > 0 && len(reply.Nodes) > args.Limit {
 		reply.Nodes = reply.Nodes[:args.Limit]
+		trace.add("limit", len(reply.Nodes), "Limited the results to %d nodes", args.Limit)
 	}
+	reply.Trace = trace.steps
 
 	// In the happy path where we found some healthy nodes we go with that
 	// and bail out. Otherwise, we fail over and try remote DCs, as allowed
@@ -495,8 +571,14 @@ func (p *PreparedQuery) ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRe
 		}
 	}
 
+	// Tokens can be local to the datacenter which checked the trace was
+	// allowed, so rather than failing over to the next datacenter, leave the
+	// steps of this one out of the trace.
+	traceEnabled := args.Trace && p.allowTrace(args.QueryOptions.Token) == nil
+
 	// Run the query locally to see what we can find.
-	if err := p.execute(&args.Query, reply, args.Connect); err != nil {
+	trace := newQueryTrace(traceEnabled, p.srv.config.Datacenter)
+	if err := p.execute(&args.Query, reply, args.Connect, trace); err != nil {
 		return err
 	}
 
@@ -509,6 +591,7 @@ func (p *PreparedQuery) ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRe
 	if err := p.srv.filterACL(token, reply); err != nil {
 		return err
 	}
+	trace.add("filter-acl", len(reply.Nodes), "Applied the ACL filter")
 
 	// We have to do this ourselves since we are not doing a blocking RPC.
 	p.srv.setQueryMeta(&reply.QueryMeta, token)
@@ -516,12 +599,15 @@ func (p *PreparedQuery) ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRe
 	// We don't bother trying to do an RTT sort here since we are by
 	// definition in another DC. We just shuffle to make sure that we
 	// balance the load across the results.
-	reply.Nodes.Shuffle()
+	shuffleNodes(&args.Query, reply.Nodes)
+	trace.add("order", len(reply.Nodes), "Shuffled the results using the %s ordering", orderingName(&args.Query))
 
 	// Apply the limit if given.
 	if args.Limit > 0 && len(reply.Nodes) > args.Limit {
 		reply.Nodes = reply.Nodes[:args.Limit]
+		trace.add("limit", len(reply.Nodes), "Limited the results to %d nodes", args.Limit)
 	}
+	reply.Trace = trace.steps
 
 	return nil
 }
@@ -530,8 +616,9 @@ func (p *PreparedQuery) ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRe
 // apply any sorting options or ACL checks at this level - it should be done up above.
 func (p *PreparedQuery) execute(query *structs.PreparedQuery,
 	reply *structs.PreparedQueryExecuteResponse,
-	forceConnect bool) error {
+	forceConnect bool, trace *queryTrace) error {
 	state := p.srv.fsm.State()
+	trace.setPeer(query.Service.Peer)
 
 	// If we're requesting Connect-capable services, then switch the
 	// lookup to be the Connect function.
@@ -544,24 +631,43 @@ func (p *PreparedQuery) execute(query *structs.PreparedQuery,
 	if err != nil {
 		return err
 	}
-
-	// Filter out any unhealthy nodes.
-	nodes = nodes.FilterIgnore(query.Service.OnlyPassing,
-		query.Service.IgnoreCheckIDs)
+	if query.Service.Connect || forceConnect {
+		trace.add("catalog", len(nodes), "Found Connect-capable instances of service %q", query.Service.Service)
+	} else {
+		trace.add("catalog", len(nodes), "Found instances of service %q", query.Service.Service)
+	}
 
 	// Apply the node metadata filters, if any.
 	if len(query.Service.NodeMeta) > 0 {
 		nodes = nodeMetaFilter(query.Service.NodeMeta, nodes)
+		trace.add("filter-node-meta", len(nodes), "Applied the node metadata filter %v", query.Service.NodeMeta)
 	}
 
 	// Apply the service metadata filters, if any.
 	if len(query.Service.ServiceMeta) > 0 {
 		nodes = serviceMetaFilter(query.Service.ServiceMeta, nodes)
+		trace.add("filter-service-meta", len(nodes), "Applied the service metadata filter %v", query.Service.ServiceMeta)
 	}
 
 	// Apply the tag filters, if any.
 	if len(query.Service.Tags) > 0 {
 		nodes = tagFilter(query.Service.Tags, nodes)
+		trace.add("filter-tags", len(nodes), "Applied the tag filter %v", query.Service.Tags)
+	}
+
+	// Filter out any unhealthy nodes. This is done last so that we know
+	// which fraction of the matching nodes is healthy.
+	matching := len(nodes)
+	nodes = nodes.FilterIgnore(query.Service.OnlyPassing,
+		query.Service.IgnoreCheckIDs)
+	reply.HealthyFraction = 0
+	if matching > 0 {
+		reply.HealthyFraction = float64(len(nodes)) / float64(matching)
+	}
+	if query.Service.OnlyPassing {
+		trace.add("filter-health", len(nodes), "Removed instances which are not passing, ignoring checks %v", query.Service.IgnoreCheckIDs)
+	} else {
+		trace.add("filter-health", len(nodes), "Removed critical instances, ignoring checks %v", query.Service.IgnoreCheckIDs)
 	}
 
 	// Capture the nodes and pass the DNS information through to the reply.
@@ -581,6 +687,41 @@ func (p *PreparedQuery) execute(query *structs.PreparedQuery,
 	return nil
 }
 
+// shuffleNodes randomizes the order of the nodes, honoring the service
+// weights if the query asks for a weighted ordering.
+func shuffleNodes(query *structs.PreparedQuery, nodes structs.CheckServiceNodes) {
+	if query.Service.Ordering == structs.QueryOrderingWeighted {
+		nodes.WeightedShuffle()
+		return
+	}
+	nodes.Shuffle()
+}
+
+// orderingName returns the name of the ordering used by the query.
+func orderingName(query *structs.PreparedQuery) string {
+	if query.Service.Ordering == "" {
+		return structs.QueryOrderingShuffle
+	}
+	return query.Service.Ordering
+}
+
+// sortNodesByLocality does a stable sort of the nodes by how closely their
+// metadata matches the source metadata. Nodes sharing the value of the first
+// key come first, then those sharing the value of the second key and so on.
+func sortNodesByLocality(source map[string]string, keys []string, nodes structs.CheckServiceNodes) {
+	tier := func(node *structs.Node) int {
+		for i, key := range keys {
+			if v, ok := source[key]; ok && v != "" && node.Meta[key] == v {
+				return i
+			}
+		}
+		return len(keys)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return tier(nodes[i].Node) < tier(nodes[j].Node)
+	})
+}
+
 // tagFilter returns a list of nodes who satisfy the given tags. Nodes must have
 // ALL the given tags, and NONE of the forbidden tags (prefixed with !). Note
 // for performance this modifies the original slice.
@@ -711,6 +852,15 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 	args *structs.PreparedQueryExecuteRequest,
 	reply *structs.PreparedQueryExecuteResponse) error {
 
+	// Keep the trace of the local execution around, since every remote
+	// execution overwrites the reply.
+	trace := newQueryTrace(args.Trace, q.GetLocalDC())
+	trace.steps = reply.Trace
+	defer func() {
+		reply.Trace = trace.steps
+	}()
+	trace.add("failover", 0, "No healthy nodes in the local datacenter, failing over")
+
 	// Pull the list of other DCs. This is sorted by RTT in case the user
 	// has selected that.
 	nearest, err := q.GetOtherDatacentersByDistance()
@@ -736,6 +886,8 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 
 			targets = append(targets, structs.QueryFailoverTarget{Datacenter: dc})
 			index[dc] = struct{}{}
+			trace.add("failover-select", 0, "Selected datacenter %q as number %d of the NearestN %d by network distance",
+				dc, i+1, query.Service.Failover.NearestN)
 		}
 	}
 
@@ -746,6 +898,7 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 		if dc := target.Datacenter; dc != "" {
 			if _, ok := known[dc]; !ok {
 				q.GetLogger().Debug("Skipping unknown datacenter in prepared query", "datacenter", dc)
+				trace.add("failover-select", 0, "Skipped unknown datacenter %q", dc)
 				continue
 			}
 
@@ -753,14 +906,20 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 			// from the NearestN list.
 			if _, ok := index[dc]; !ok {
 				targets = append(targets, target)
+				trace.add("failover-select", 0, "Selected datacenter %q from the failover list", dc)
 			}
 		}
 
 		if target.Peer != "" {
 			targets = append(targets, target)
+			trace.add("failover-select", 0, "Selected peer %q from the failover list", target.Peer)
 		}
 	}
 
+	if query.Service.Failover.Policy == structs.QueryFailoverPolicyHealthWeighted {
+		return queryFailoverHealthWeighted(q, query, args, reply, targets, trace)
+	}
+
 	// Now try the selected DCs in priority order.
 	failovers := 0
 	for _, target := range targets {
@@ -775,33 +934,7 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 		// through this slice across successive RPC calls.
 		reply.Nodes = nil
 
-		// Reset PeerName because it may have been set by a previous failover
-		// target.
-		query.Service.Peer = target.Peer
-		dc := target.Datacenter
-		if target.Peer != "" {
-			dc = q.GetLocalDC()
-		}
-
-		// Note that we pass along the limit since may be applied
-		// remotely to save bandwidth. We also pass along the consistency
-		// mode information and token we were given, so that applies to
-		// the remote query as well.
-		remote := &structs.PreparedQueryExecuteRemoteRequest{
-			Datacenter:   dc,
-			Query:        *query,
-			Limit:        args.Limit,
-			QueryOptions: args.QueryOptions,
-			Connect:      args.Connect,
-		}
-
-		if err = q.ExecuteRemote(remote, reply); err != nil {
-			q.GetLogger().Warn("Failed querying for service in datacenter",
-				"service", query.Service.Service,
-				"peerName", query.Service.Peer,
-				"datacenter", dc,
-				"error", err,
-			)
+		if err := queryFailoverTarget(q, query, args, target, reply, trace); err != nil {
 			continue
 		}
 
@@ -817,3 +950,244 @@ func queryFailover(q queryServer, query *structs.PreparedQuery,
 
 	return nil
 }
+
+// allowTrace returns an error unless the token may see the trace of a query.
+// The trace has the number of instances found before the ACL filter is
+// applied, and the errors of other datacenters, so it requires operator:read.
+func (p *PreparedQuery) allowTrace(token string) error {
+	authz, err := p.srv.ResolveToken(token)
+	if err != nil {
+		return err
+	}
+	return authz.ToAllowAuthorizer().OperatorReadAllowed(nil)
+}
+
+// queryFailoverTarget runs the query against a single failover target. Errors
+// are logged and returned so the caller can move on to the next target.
+func queryFailoverTarget(q queryServer, query *structs.PreparedQuery,
+	args *structs.PreparedQueryExecuteRequest,
+	target structs.QueryFailoverTarget,
+	reply *structs.PreparedQueryExecuteResponse,
+	trace *queryTrace) error {
+
+	// Reset PeerName because it may have been set by a previous failover
+	// target.
+	query.Service.Peer = target.Peer
+	dc := target.Datacenter
+	if target.Peer != "" {
+		dc = q.GetLocalDC()
+	}
+
+	// Note that we pass along the limit since may be applied
+	// remotely to save bandwidth. We also pass along the consistency
+	// mode information and token we were given, so that applies to
+	// the remote query as well.
+	remote := &structs.PreparedQueryExecuteRemoteRequest{
+		Datacenter:   dc,
+		Query:        *query,
+		Limit:        args.Limit,
+		QueryOptions: args.QueryOptions,
+		Connect:      args.Connect,
+		Trace:        args.Trace,
+	}
+
+	// The trace so far is kept by the caller, so don't let the decoder
+	// reuse its slice, see the note on reply.Nodes in queryFailover.
+	reply.Trace = nil
+	if err := q.ExecuteRemote(remote, reply); err != nil {
+		q.GetLogger().Warn("Failed querying for service in datacenter",
+			"service", query.Service.Service,
+			"peerName", query.Service.Peer,
+			"datacenter", dc,
+			"error", err,
+		)
+		trace.setPeer(target.Peer)
+		trace.add("failover-target", 0, "Failed querying datacenter %q: %v", dc, err)
+		trace.setPeer("")
+		return err
+	}
+	trace.append(reply.Trace)
+	return nil
+}
+
+// healthWeightedRankDiscount scales down the score of each failover target by
+// its position in the list of targets. The list is ordered by network distance
+// for NearestN and by preference for Datacenters and Targets, so a nearby
+// target is preferred unless a farther one is significantly healthier.
+const healthWeightedRankDiscount = 0.9
+
+// healthWeightedResult is the outcome of querying a single failover target
+// for the health-weighted policy.
+type healthWeightedResult struct {
+	rank  int
+	reply structs.PreparedQueryExecuteResponse
+	trace *queryTrace
+	err   error
+
+	// fraction is the fraction of healthy instances of the target, and score
+	// the resulting score, when it has any healthy instances.
+	fraction float64
+	score    float64
+}
+
+// queryFailoverHealthWeighted queries all the failover targets concurrently and
+// returns the results of the one with the best score, which is the fraction of
+// its healthy instances multiplied by its weight and discounted by its position
+// in the list. This prefers a healthy target over a nearly-dead one that comes
+// earlier in the list, such as a nearby datacenter that only has a single
+// instance left out of many, but not over one that is only slightly less
+// healthy.
+func queryFailoverHealthWeighted(q queryServer, query *structs.PreparedQuery,
+	args *structs.PreparedQueryExecuteRequest,
+	reply *structs.PreparedQueryExecuteResponse,
+	targets []structs.QueryFailoverTarget,
+	trace *queryTrace) error {
+
+	// The channel is buffered so that targets we stop waiting for don't
+	// block once they're done.
+	resultCh := make(chan *healthWeightedResult, len(targets))
+	for i, target := range targets {
+		go func(rank int, target structs.QueryFailoverTarget) {
+			// queryFailoverTarget sets the peer of the query it's given, so
+			// each target needs its own copy.
+			query := *query
+			res := &healthWeightedResult{
+				rank:  rank,
+				trace: newQueryTrace(trace.enabled, trace.datacenter),
+			}
+			res.err = queryFailoverTarget(q, &query, args, target, &res.reply, res.trace)
+			resultCh <- res
+		}(i, target)
+	}
+
+	// Wait for the results until none of the pending targets could beat the
+	// best one so far, even if all of their instances were healthy.
+	results := make([]*healthWeightedResult, len(targets))
+	var best *healthWeightedResult
+	for pending := len(targets); pending > 0; pending-- {
+		res := <-resultCh
+		results[res.rank] = res
+
+		if res.err == nil && len(res.reply.Nodes) > 0 {
+			// Servers running an older version don't report the fraction,
+			// so treat them as fully healthy rather than skipping them.
+			res.fraction = res.reply.HealthyFraction
+			if res.fraction == 0 {
+				res.fraction = 1
+			}
+			res.score = res.fraction * healthWeightedMaxScore(targets[res.rank], res.rank)
+			if best == nil || healthWeightedBeats(res.score, res.rank, best.score, best.rank) {
+				best = res
+			}
+		}
+
+		if best != nil && !healthWeightedCanBeat(targets, results, best) {
+			break
+		}
+	}
+
+	// Record the traces in the order of the targets, so it doesn't depend on
+	// which one answered first.
+	for i, res := range results {
+		if res == nil {
+			trace.add("failover-score", 0, "Stopped waiting for %s, it can't score higher than %.2f",
+				targetName(targets[i]), best.score)
+			continue
+		}
+		trace.append(res.trace.steps)
+		if res.err == nil && len(res.reply.Nodes) > 0 {
+			trace.add("failover-score", len(res.reply.Nodes), "Scored %s at %.2f, %.0f%% healthy with weight %d at position %d",
+				targetName(targets[i]), res.score, res.fraction*100, targets[i].EffectiveWeight(), i+1)
+		}
+	}
+
+	if best != nil {
+		*reply = best.reply
+		trace.add("failover-pick", len(best.reply.Nodes), "Picked the results from the best scoring target")
+	} else {
+		reply.Nodes = nil
+	}
+
+	// Every target was queried, regardless of which one was picked.
+	reply.Failovers = len(targets)
+
+	return nil
+}
+
+// healthWeightedMaxScore is the score of the target at the given position in
+// the list of targets if all of its instances are healthy.
+func healthWeightedMaxScore(target structs.QueryFailoverTarget, rank int) float64 {
+	return float64(target.EffectiveWeight()) * math.Pow(healthWeightedRankDiscount, float64(rank))
+}
+
+// healthWeightedBeats returns whether a score beats another one. Ties go to the
+// target that comes first.
+func healthWeightedBeats(score float64, rank int, otherScore float64, otherRank int) bool {
+	return score > otherScore || (score == otherScore && rank < otherRank)
+}
+
+// healthWeightedCanBeat returns whether any of the targets that haven't
+// returned yet could beat the best result.
+func healthWeightedCanBeat(targets []structs.QueryFailoverTarget, results []*healthWeightedResult,
+	best *healthWeightedResult) bool {
+	for i, target := range targets {
+		if results[i] != nil {
+			continue
+		}
+		if healthWeightedBeats(healthWeightedMaxScore(target, i), i, best.score, best.rank) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetName describes a failover target for the trace.
+func targetName(target structs.QueryFailoverTarget) string {
+	if target.Peer != "" {
+		return fmt.Sprintf("peer %q", target.Peer)
+	}
+	return fmt.Sprintf("datacenter %q", target.Datacenter)
+}
+
+// queryTrace records the evaluation steps of a prepared query when a trace was
+// requested. A nil or disabled queryTrace records nothing.
+type queryTrace struct {
+	enabled    bool
+	datacenter string
+	peer       string
+	steps      []structs.PreparedQueryTraceStep
+}
+
+func newQueryTrace(enabled bool, datacenter string) *queryTrace {
+	return &queryTrace{enabled: enabled, datacenter: datacenter}
+}
+
+// setPeer sets the peer that the following steps are evaluated against.
+func (t *queryTrace) setPeer(peer string) {
+	if t == nil {
+		return
+	}
+	t.peer = peer
+}
+
+// add records a step with the number of candidate nodes left after it.
+func (t *queryTrace) add(step string, nodes int, format string, args ...interface{}) {
+	if t == nil || !t.enabled {
+		return
+	}
+	t.steps = append(t.steps, structs.PreparedQueryTraceStep{
+		Step:       step,
+		Datacenter: t.datacenter,
+		PeerName:   t.peer,
+		Nodes:      nodes,
+		Detail:     fmt.Sprintf(format, args...),
+	})
+}
+
+// append records the steps of a remote execution.
+func (t *queryTrace) append(steps []structs.PreparedQueryTraceStep) {
+	if t == nil || !t.enabled {
+		return
+	}
+	t.steps = append(t.steps, steps...)
+}
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -105,7 +106,25 @@ func TestPreparedQuery_Apply(t *testing.T) {
 		t.Fatalf("bad: %v", err)
 	}
 
+	// Fix that and ensure an unknown failover policy is rejected.
+	query.Query.Service.Failover.Datacenters = nil
+	query.Query.Service.Failover.Policy = "random"
+	err = msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &reply)
+	if err == nil || !strings.Contains(err.Error(), "Bad failover Policy") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Fix that and ensure negative target weights are rejected.
+	query.Query.Service.Failover.Policy = structs.QueryFailoverPolicyHealthWeighted
+	query.Query.Service.Failover.Targets = []structs.QueryFailoverTarget{{Peer: "peer", Weight: -1}}
+	err = msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &reply)
+	if err == nil || !strings.Contains(err.Error(), "Bad Weight") {
+		t.Fatalf("bad: %v", err)
+	}
+
 	// Fix that and make sure it propagates an error from the Raft apply.
+	query.Query.Service.Failover.Policy = ""
+	query.Query.Service.Failover.Datacenters = []string{"dc2"}
 	query.Query.Service.Failover.Targets = nil
 	query.Query.Session = "nope"
 	err = msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &reply)
@@ -643,6 +662,28 @@ func TestPreparedQuery_parseQuery(t *testing.T) {
 	if err := parseQuery(query); err != nil {
 		t.Fatalf("err: %v", err)
 	}
+
+	query.Service.Ordering = "sorted"
+	err = parseQuery(query)
+	if err == nil || !strings.Contains(err.Error(), "Bad Ordering") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	query.Service.Ordering = structs.QueryOrderingWeighted
+	if err := parseQuery(query); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	query.Service.Locality.NodeMetaKeys = []string{"zone", ""}
+	err = parseQuery(query)
+	if err == nil || !strings.Contains(err.Error(), "cannot contain an empty key") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	query.Service.Locality.NodeMetaKeys = []string{"zone", "region"}
+	if err := parseQuery(query); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 }
 
 func TestPreparedQuery_ACLDeny_Catchall_Template(t *testing.T) {
@@ -2784,6 +2825,428 @@ func TestPreparedQuery_Execute_ConnectExact(t *testing.T) {
 		codec, "PreparedQuery.Apply", &query, &query.Query.ID))
 }
 
+func TestPreparedQuery_Execute_Ordering(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Spread 6 nodes over 3 zones in 2 regions. The service on node6 is
+	// much heavier than the others.
+	zones := []string{"a", "a", "b", "b", "c", "c"}
+	for i, zone := range zones {
+		region := "r1"
+		if zone == "c" {
+			region = "r2"
+		}
+		req := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       fmt.Sprintf("node%d", i+1),
+			Address:    fmt.Sprintf("127.0.0.%d", i+1),
+			NodeMeta:   map[string]string{"zone": zone, "region": region},
+			Service: &structs.NodeService{
+				Service: "foo",
+				Port:    8000,
+				Weights: &structs.Weights{Passing: 1, Warning: 1},
+			},
+		}
+		if i == 5 {
+			req.Service.Weights.Passing = 1000
+		}
+
+		var reply struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &req, &reply))
+	}
+
+	query := structs.PreparedQueryRequest{
+		Datacenter: "dc1",
+		Op:         structs.PreparedQueryCreate,
+		Query: &structs.PreparedQuery{
+			Name: "test",
+			Service: structs.ServiceQuery{
+				Service:  "foo",
+				Ordering: structs.QueryOrderingWeighted,
+			},
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &query.Query.ID))
+
+	execute := func(t *testing.T, agentNode string) []string {
+		req := structs.PreparedQueryExecuteRequest{
+			Agent: structs.QuerySource{
+				Datacenter: "dc1",
+				Node:       agentNode,
+			},
+			Datacenter:    "dc1",
+			QueryIDOrName: query.Query.ID,
+		}
+
+		var reply structs.PreparedQueryExecuteResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Execute", &req, &reply))
+		require.Len(t, reply.Nodes, len(zones))
+
+		var names []string
+		for _, node := range reply.Nodes {
+			names = append(names, node.Node.Node)
+		}
+		return names
+	}
+
+	t.Run("weighted", func(t *testing.T) {
+		// The odds of node6 not being first are about 1 in 200 per run.
+		first := 0
+		for i := 0; i < 20; i++ {
+			if execute(t, "node1")[0] == "node6" {
+				first++
+			}
+		}
+		require.GreaterOrEqual(t, first, 15)
+	})
+
+	query.Op = structs.PreparedQueryUpdate
+	query.Query.Service.Locality.NodeMetaKeys = []string{"zone", "region"}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &query.Query.ID))
+
+	t.Run("locality", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			names := execute(t, "node1")
+			require.ElementsMatch(t, []string{"node1", "node2"}, names[0:2])
+			require.ElementsMatch(t, []string{"node3", "node4"}, names[2:4])
+			require.ElementsMatch(t, []string{"node5", "node6"}, names[4:6])
+		}
+	})
+
+	t.Run("locality falls back to the next tier", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			names := execute(t, "node5")
+			require.ElementsMatch(t, []string{"node5", "node6"}, names[0:2])
+		}
+	})
+
+	t.Run("locality with unknown source node", func(t *testing.T) {
+		// The weighted ordering is still applied.
+		first := 0
+		for i := 0; i < 20; i++ {
+			if execute(t, "nope")[0] == "node6" {
+				first++
+			}
+		}
+		require.GreaterOrEqual(t, first, 15)
+	})
+}
+
+func TestPreparedQuery_Execute_Trace(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Register 4 instances: two are tagged, and one of those is critical.
+	for i := 0; i < 4; i++ {
+		req := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       fmt.Sprintf("node%d", i+1),
+			Address:    fmt.Sprintf("127.0.0.%d", i+1),
+			Service: &structs.NodeService{
+				Service: "foo",
+				Port:    8000,
+			},
+		}
+		if i < 2 {
+			req.Service.Tags = []string{"primary"}
+		}
+		if i == 0 {
+			req.Check = &structs.HealthCheck{
+				Name:      "failing",
+				Status:    api.HealthCritical,
+				ServiceID: "foo",
+			}
+		}
+
+		var reply struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &req, &reply))
+	}
+
+	query := structs.PreparedQueryRequest{
+		Datacenter: "dc1",
+		Op:         structs.PreparedQueryCreate,
+		Query: &structs.PreparedQuery{
+			Name: "test",
+			Service: structs.ServiceQuery{
+				Service: "foo",
+				Tags:    []string{"primary"},
+			},
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &query.Query.ID))
+
+	steps := func(trace []structs.PreparedQueryTraceStep) []string {
+		var steps []string
+		for _, step := range trace {
+			require.Equal(t, "dc1", step.Datacenter)
+			steps = append(steps, fmt.Sprintf("%s:%d", step.Step, step.Nodes))
+		}
+		return steps
+	}
+
+	t.Run("no trace by default", func(t *testing.T) {
+		req := structs.PreparedQueryExecuteRequest{
+			Datacenter:    "dc1",
+			QueryIDOrName: "test",
+		}
+
+		var reply structs.PreparedQueryExecuteResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Execute", &req, &reply))
+		require.Len(t, reply.Nodes, 1)
+		require.Empty(t, reply.Trace)
+	})
+
+	t.Run("trace", func(t *testing.T) {
+		req := structs.PreparedQueryExecuteRequest{
+			Datacenter:    "dc1",
+			QueryIDOrName: "test",
+			Trace:         true,
+		}
+
+		var reply structs.PreparedQueryExecuteResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Execute", &req, &reply))
+		require.Len(t, reply.Nodes, 1)
+		require.Equal(t, []string{
+			"resolve:0",
+			"catalog:4",
+			"filter-tags:2",
+			"filter-health:1",
+			"filter-acl:1",
+			"order:1",
+		}, steps(reply.Trace))
+		require.Contains(t, reply.Trace[0].Detail, `Resolved "test" to query`)
+	})
+
+	t.Run("trace with failover", func(t *testing.T) {
+		query.Op = structs.PreparedQueryUpdate
+		query.Query.Service.Tags = []string{"missing"}
+		query.Query.Service.Failover.Datacenters = []string{"dc2"}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &query.Query.ID))
+
+		req := structs.PreparedQueryExecuteRequest{
+			Datacenter:    "dc1",
+			QueryIDOrName: "test",
+			Trace:         true,
+		}
+
+		var reply structs.PreparedQueryExecuteResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Execute", &req, &reply))
+		require.Empty(t, reply.Nodes)
+		require.Equal(t, []string{
+			"resolve:0",
+			"catalog:4",
+			"filter-tags:0",
+			"filter-health:0",
+			"filter-acl:0",
+			"order:0",
+			"failover:0",
+			"failover-select:0",
+		}, steps(reply.Trace))
+		require.Equal(t, `Skipped unknown datacenter "dc2"`, reply.Trace[7].Detail)
+	})
+}
+
+func TestPreparedQuery_Execute_TraceACL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1", testrpc.WithToken("root"))
+
+	readToken := createTokenWithPolicyName(t, codec, "read", `
+		node_prefix "" { policy = "read" }
+		service "foo" { policy = "read" }
+	`, "root")
+	operatorToken := createTokenWithPolicyName(t, codec, "operator", `
+		node_prefix "" { policy = "read" }
+		service "foo" { policy = "read" }
+		operator = "read"
+	`, "root")
+
+	reg := structs.RegisterRequest{
+		Datacenter:   "dc1",
+		Node:         "node1",
+		Address:      "127.0.0.1",
+		Service:      &structs.NodeService{Service: "foo", Port: 8000},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var out struct{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &reg, &out))
+
+	query := structs.PreparedQueryRequest{
+		Datacenter: "dc1",
+		Op:         structs.PreparedQueryCreate,
+		Query: &structs.PreparedQuery{
+			Name:    "test",
+			Service: structs.ServiceQuery{Service: "foo"},
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.Apply", &query, &query.Query.ID))
+
+	execute := func(token string, trace bool) (*structs.PreparedQueryExecuteResponse, error) {
+		req := structs.PreparedQueryExecuteRequest{
+			Datacenter:    "dc1",
+			QueryIDOrName: "test",
+			QueryOptions:  structs.QueryOptions{Token: token},
+			Trace:         trace,
+		}
+		var reply structs.PreparedQueryExecuteResponse
+		err := msgpackrpc.CallWithCodec(codec, "PreparedQuery.Execute", &req, &reply)
+		return &reply, err
+	}
+
+	t.Run("trace requires operator read", func(t *testing.T) {
+		reply, err := execute(readToken, false)
+		require.NoError(t, err)
+		require.Len(t, reply.Nodes, 1)
+
+		_, err = execute(readToken, true)
+		require.True(t, acl.IsErrPermissionDenied(err), "bad: %v", err)
+
+		reply, err = execute(operatorToken, true)
+		require.NoError(t, err)
+		require.Len(t, reply.Nodes, 1)
+		require.NotEmpty(t, reply.Trace)
+	})
+
+	t.Run("remote trace requires operator read", func(t *testing.T) {
+		executeRemote := func(token string) *structs.PreparedQueryExecuteResponse {
+			req := structs.PreparedQueryExecuteRemoteRequest{
+				Datacenter:   "dc1",
+				Query:        *query.Query,
+				QueryOptions: structs.QueryOptions{Token: token},
+				Trace:        true,
+			}
+			var reply structs.PreparedQueryExecuteResponse
+			require.NoError(t, msgpackrpc.CallWithCodec(codec, "PreparedQuery.ExecuteRemote", &req, &reply))
+			require.Len(t, reply.Nodes, 1)
+			return &reply
+		}
+
+		require.Empty(t, executeRemote(readToken).Trace)
+		require.NotEmpty(t, executeRemote(operatorToken).Trace)
+	})
+}
+
+func TestPreparedQuery_queryFailover_Trace(t *testing.T) {
+	t.Parallel()
+
+	query := &structs.PreparedQuery{
+		Name: "test",
+		Service: structs.ServiceQuery{
+			Failover: structs.QueryFailoverOptions{
+				NearestN:    1,
+				Datacenters: []string{"dc3", "dc4"},
+			},
+		},
+	}
+	mock := &mockQueryServer{
+		Datacenters: []string{"dc2", "dc3"},
+		QueryFn: func(req *structs.PreparedQueryExecuteRemoteRequest, reply *structs.PreparedQueryExecuteResponse) error {
+			require.True(t, req.Trace)
+			require.Nil(t, reply.Trace)
+			if req.Datacenter == "dc2" {
+				return fmt.Errorf("XXX")
+			}
+			reply.Nodes = structs.CheckServiceNodes{{Node: &structs.Node{Node: "node1"}}}
+			reply.Trace = []structs.PreparedQueryTraceStep{
+				{Step: "catalog", Datacenter: req.Datacenter, Nodes: 1},
+			}
+			return nil
+		},
+	}
+
+	reply := structs.PreparedQueryExecuteResponse{
+		Trace: []structs.PreparedQueryTraceStep{{Step: "resolve", Datacenter: "dc1"}},
+	}
+	require.NoError(t, queryFailover(mock, query, &structs.PreparedQueryExecuteRequest{Trace: true}, &reply))
+	require.Len(t, reply.Nodes, 1)
+	require.Equal(t, "dc3", reply.Datacenter)
+
+	var details []string
+	for _, step := range reply.Trace {
+		details = append(details, fmt.Sprintf("%s %s %s", step.Datacenter, step.Step, step.Detail))
+	}
+	require.Equal(t, []string{
+		"dc1 resolve ",
+		"dc1 failover No healthy nodes in the local datacenter, failing over",
+		`dc1 failover-select Selected datacenter "dc2" as number 1 of the NearestN 1 by network distance`,
+		`dc1 failover-select Selected datacenter "dc3" from the failover list`,
+		`dc1 failover-select Skipped unknown datacenter "dc4"`,
+		`dc1 failover-target Failed querying datacenter "dc2": XXX`,
+		"dc3 catalog ",
+	}, details)
+}
+
+func TestPreparedQuery_sortNodesByLocality(t *testing.T) {
+	t.Parallel()
+
+	var nodes structs.CheckServiceNodes
+	for i, meta := range []map[string]string{
+		{"zone": "b", "region": "r1"},
+		{"zone": "c", "region": "r2"},
+		{"zone": "a", "region": "r1"},
+		{"region": "r1"},
+		{"zone": "a"},
+		{},
+	} {
+		nodes = append(nodes, structs.CheckServiceNode{
+			Node: &structs.Node{Node: fmt.Sprintf("node%d", i), Meta: meta},
+		})
+	}
+
+	names := func() []string {
+		var names []string
+		for _, node := range nodes {
+			names = append(names, node.Node.Node)
+		}
+		return names
+	}
+
+	sortNodesByLocality(map[string]string{"zone": "a", "region": "r1"}, []string{"zone", "region"}, nodes)
+	require.Equal(t, []string{"node2", "node4", "node0", "node3", "node1", "node5"}, names())
+
+	// A source without a value for a key doesn't match nodes missing it.
+	sortNodesByLocality(map[string]string{"region": "r2"}, []string{"zone", "region"}, nodes)
+	require.Equal(t, []string{"node1", "node2", "node4", "node0", "node3", "node5"}, names())
+}
+
 func TestPreparedQuery_tagFilter(t *testing.T) {
 	t.Parallel()
 	testNodes := func() structs.CheckServiceNodes {
@@ -2936,13 +3399,21 @@ type mockQueryServer struct {
 	QueryFn          func(args *structs.PreparedQueryExecuteRemoteRequest, reply *structs.PreparedQueryExecuteResponse) error
 	Logger           hclog.Logger
 	LogBuffer        *bytes.Buffer
+
+	// lock protects QueryLog and Logger, as the health-weighted failover
+	// policy queries the targets concurrently.
+	lock sync.Mutex
 }
 
 func (m *mockQueryServer) JoinQueryLog() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	return strings.Join(m.QueryLog, "|")
 }
 
 func (m *mockQueryServer) GetLogger() hclog.Logger {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	if m.Logger == nil {
 		m.LogBuffer = new(bytes.Buffer)
 
@@ -2966,11 +3437,13 @@ func (m *mockQueryServer) GetOtherDatacentersByDistance() ([]string, error) {
 func (m *mockQueryServer) ExecuteRemote(args *structs.PreparedQueryExecuteRemoteRequest, reply *structs.PreparedQueryExecuteResponse) error {
 	peerName := args.Query.Service.Peer
 	dc := args.Datacenter
+	m.lock.Lock()
 	if peerName != "" {
 		m.QueryLog = append(m.QueryLog, fmt.Sprintf("peer:%s", peerName))
 	} else {
 		m.QueryLog = append(m.QueryLog, fmt.Sprintf("%s:%s", dc, "PreparedQuery.ExecuteRemote"))
 	}
+	m.lock.Unlock()
 	reply.PeerName = peerName
 	reply.Datacenter = dc
 
@@ -3341,3 +3814,164 @@ func TestPreparedQuery_queryFailover(t *testing.T) {
 		require.Equal(t, "peer:cluster-01|dc44:PreparedQuery.ExecuteRemote|peer:cluster-02", mock.JoinQueryLog())
 	}
 }
+
+func TestPreparedQuery_queryFailover_HealthWeighted(t *testing.T) {
+	t.Parallel()
+
+	nodes := func(n int) structs.CheckServiceNodes {
+		var nodes structs.CheckServiceNodes
+		for i := 0; i < n; i++ {
+			nodes = append(nodes, structs.CheckServiceNode{
+				Node: &structs.Node{Node: fmt.Sprintf("node%d", i)},
+			})
+		}
+		return nodes
+	}
+
+	// health maps each datacenter to its healthy and matching instances.
+	health := map[string][2]int{
+		"dc2": {1, 10},
+		"dc3": {4, 5},
+		"dc4": {9, 10},
+		"dc6": {99, 100},
+		"dc7": {10, 10},
+	}
+	// Queries to dc8 don't return until unblock is called.
+	var (
+		blockCh   = make(chan struct{})
+		blockOnce sync.Once
+	)
+	unblock := func() { blockOnce.Do(func() { close(blockCh) }) }
+	t.Cleanup(unblock)
+	queryFn := func(req *structs.PreparedQueryExecuteRemoteRequest, reply *structs.PreparedQueryExecuteResponse) error {
+		switch req.Datacenter {
+		case "dc5":
+			return fmt.Errorf("XXX")
+		case "dc8":
+			<-blockCh
+			reply.Nodes = nodes(10)
+			reply.HealthyFraction = 1
+			return nil
+		}
+		h, ok := health[req.Datacenter]
+		if !ok {
+			return nil
+		}
+		reply.Nodes = nodes(h[0])
+		reply.HealthyFraction = float64(h[0]) / float64(h[1])
+		return nil
+	}
+
+	run := func(t *testing.T, failover structs.QueryFailoverOptions) (structs.PreparedQueryExecuteResponse, string) {
+		query := &structs.PreparedQuery{
+			Name: "test",
+			Service: structs.ServiceQuery{
+				Failover: failover,
+			},
+		}
+		mock := &mockQueryServer{
+			Datacenters: []string{"dc2", "dc3", "xxx", "dc4", "dc5", "dc6", "dc7", "dc8"},
+			QueryFn:     queryFn,
+		}
+
+		var reply structs.PreparedQueryExecuteResponse
+		require.NoError(t, queryFailover(mock, query, &structs.PreparedQueryExecuteRequest{Trace: true}, &reply))
+		return reply, mock.JoinQueryLog()
+	}
+
+	t.Run("ordered uses the nearest datacenter with nodes", func(t *testing.T) {
+		reply, queries := run(t, structs.QueryFailoverOptions{NearestN: 3})
+		require.Equal(t, "dc2", reply.Datacenter)
+		require.Equal(t, 1, reply.Failovers)
+		require.Equal(t, "dc2:PreparedQuery.ExecuteRemote", queries)
+	})
+
+	t.Run("health weighted prefers a healthy datacenter over a nearly-dead nearby one", func(t *testing.T) {
+		reply, _ := run(t, structs.QueryFailoverOptions{
+			NearestN: 4,
+			Policy:   structs.QueryFailoverPolicyHealthWeighted,
+		})
+		// dc2 scores 0.1, dc3 0.8*0.9 and dc4 0.9*0.9^3.
+		require.Equal(t, "dc3", reply.Datacenter)
+		require.Len(t, reply.Nodes, 4)
+		require.Equal(t, 4, reply.Failovers)
+	})
+
+	t.Run("health weighted prefers a nearby datacenter over a slightly healthier one", func(t *testing.T) {
+		reply, _ := run(t, structs.QueryFailoverOptions{
+			Policy:      structs.QueryFailoverPolicyHealthWeighted,
+			Datacenters: []string{"dc6", "dc7"},
+		})
+		require.Equal(t, "dc6", reply.Datacenter)
+		require.Len(t, reply.Nodes, 99)
+	})
+
+	t.Run("health weighted applies target weights", func(t *testing.T) {
+		reply, _ := run(t, structs.QueryFailoverOptions{
+			Policy: structs.QueryFailoverPolicyHealthWeighted,
+			Targets: []structs.QueryFailoverTarget{
+				{Datacenter: "dc6"},
+				{Datacenter: "dc3", Weight: 2},
+				{Datacenter: "dc4"},
+			},
+		})
+		require.Equal(t, "dc3", reply.Datacenter)
+		require.Len(t, reply.Nodes, 4)
+	})
+
+	t.Run("health weighted skips failing targets", func(t *testing.T) {
+		reply, _ := run(t, structs.QueryFailoverOptions{
+			Policy:      structs.QueryFailoverPolicyHealthWeighted,
+			Datacenters: []string{"dc5", "dc4", "dc2"},
+		})
+		require.Equal(t, "dc4", reply.Datacenter)
+		require.Equal(t, 3, reply.Failovers)
+	})
+
+	t.Run("health weighted with no nodes anywhere", func(t *testing.T) {
+		reply, _ := run(t, structs.QueryFailoverOptions{
+			Policy:      structs.QueryFailoverPolicyHealthWeighted,
+			Datacenters: []string{"xxx", "dc5"},
+		})
+		require.Empty(t, reply.Nodes)
+		require.Equal(t, 2, reply.Failovers)
+	})
+
+	t.Run("health weighted stops waiting once no target can score higher", func(t *testing.T) {
+		reply, _ := run(t, structs.QueryFailoverOptions{
+			Policy:      structs.QueryFailoverPolicyHealthWeighted,
+			Datacenters: []string{"dc7", "dc8"},
+		})
+		require.Equal(t, "dc7", reply.Datacenter)
+		require.Len(t, reply.Nodes, 10)
+
+		var details []string
+		for _, step := range reply.Trace {
+			details = append(details, step.Detail)
+		}
+		require.Contains(t, details, `Stopped waiting for datacenter "dc8", it can't score higher than 1.00`)
+	})
+
+	t.Run("health weighted waits for targets which can score higher", func(t *testing.T) {
+		// dc8 is fully healthy, so it must be waited for while dc2 isn't.
+		resultCh := make(chan structs.PreparedQueryExecuteResponse, 1)
+		go func() {
+			reply, _ := run(t, structs.QueryFailoverOptions{
+				Policy:      structs.QueryFailoverPolicyHealthWeighted,
+				Datacenters: []string{"dc2", "dc8"},
+			})
+			resultCh <- reply
+		}()
+
+		select {
+		case reply := <-resultCh:
+			t.Fatalf("didn't wait for dc8, picked %q", reply.Datacenter)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		unblock()
+		reply := <-resultCh
+		require.Equal(t, "dc8", reply.Datacenter)
+		require.Equal(t, 2, reply.Failovers)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"reflect"
 	"sync/atomic"
 
@@ -103,6 +104,13 @@ type Operation struct {
 
 	// Type of operation to be performed (e.g. read or write).
 	Type OperationType
+
+	// AccessorID is the accessor ID of the ACL token the operation is being
+	// performed with. Tokens are only resolved once the request body has been
+	// decoded, so operations are first checked without it (against the global,
+	// IP-based and endpoint limits) and then checked again with it, at which
+	// point only the token limits are applied.
+	AccessorID string
 }
 
 //go:generate mockery --name RequestLimitsHandler --inpackage
@@ -111,6 +119,11 @@ type RequestLimitsHandler interface {
 	Allow(op Operation) error
 	UpdateConfig(cfg HandlerConfig)
 	Register(leaderStatusProvider LeaderStatusProvider)
+
+	// TokenLimitsEnabled returns whether any rate limits are configured for
+	// ACL tokens, so callers can avoid resolving the token of a request when
+	// there are none.
+	TokenLimitsEnabled() bool
 }
 
 // Handler enforces rate limits for incoming RPCs.
@@ -128,8 +141,6 @@ type HandlerConfig struct {
 
 	// GlobalMode configures the action that will be taken when a global rate-limit
 	// has been exhausted.
-	//
-	// Note: in the future there'll be a separate Mode for IP-based limits.
 	GlobalMode Mode
 
 	// GlobalWriteConfig configures the global rate limiter for write operations.
@@ -137,6 +148,62 @@ type HandlerConfig struct {
 
 	// GlobalReadConfig configures the global rate limiter for read operations.
 	GlobalReadConfig multilimiter.LimiterConfig
+
+	// IPMode configures the action that will be taken when an IP-based
+	// rate-limit has been exhausted.
+	IPMode Mode
+
+	// IPWriteConfig configures the rate limiter for write operations that is
+	// applied to each source IP address individually.
+	IPWriteConfig multilimiter.LimiterConfig
+
+	// IPReadConfig configures the rate limiter for read operations that is
+	// applied to each source IP address individually.
+	IPReadConfig multilimiter.LimiterConfig
+
+	// IPPrefixLimits configures rate limiters that are shared by all source IP
+	// addresses within a CIDR block. If an address matches multiple prefixes
+	// the most specific one is used, and replaces the per-IP limit.
+	IPPrefixLimits []IPPrefixLimit
+
+	// IPAllowlist contains CIDR blocks whose addresses are exempt from IP-based
+	// rate-limits (e.g. other servers forwarding RPCs on behalf of clients).
+	IPAllowlist []netip.Prefix
+
+	// EndpointPolicies configures rate limits shared by all operations against
+	// an RPC endpoint, keyed by the endpoint's name (e.g. "KVS.Apply").
+	EndpointPolicies map[string]Policy
+
+	// TokenPolicies configures rate limits shared by all operations performed
+	// with an ACL token, keyed by the token's accessor ID.
+	TokenPolicies map[string]Policy
+}
+
+// Policy configures the rate limits applied to a subset of operations, such as
+// those against a specific endpoint or performed with a specific ACL token.
+type Policy struct {
+	// Mode configures the action that will be taken when one of the policy's
+	// rate-limits has been exhausted.
+	Mode Mode
+
+	// WriteConfig configures the rate limiter for write operations.
+	WriteConfig multilimiter.LimiterConfig
+
+	// ReadConfig configures the rate limiter for read operations.
+	ReadConfig multilimiter.LimiterConfig
+}
+
+// IPPrefixLimit configures the rate limiters shared by all source IP addresses
+// within Prefix.
+type IPPrefixLimit struct {
+	// Prefix is the CIDR block the limits apply to.
+	Prefix netip.Prefix
+
+	// WriteConfig configures the rate limiter for write operations.
+	WriteConfig multilimiter.LimiterConfig
+
+	// ReadConfig configures the rate limiter for read operations.
+	ReadConfig multilimiter.LimiterConfig
 }
 
 //go:generate mockery --name LeaderStatusProvider --inpackage --filename mock_LeaderStatusProvider_test.go
@@ -155,6 +222,9 @@ func NewHandlerWithLimiter(
 
 	limiter.UpdateConfig(cfg.GlobalWriteConfig, globalWrite)
 	limiter.UpdateConfig(cfg.GlobalReadConfig, globalRead)
+	updateIPConfig(limiter, &HandlerConfig{}, &cfg)
+	updatePolicyConfig(limiter, endpointKind, nil, cfg.EndpointPolicies)
+	updatePolicyConfig(limiter, tokenKind, nil, cfg.TokenPolicies)
 
 	h := &Handler{
 		cfg:     new(atomic.Pointer[HandlerConfig]),
@@ -190,11 +260,6 @@ func (h *Handler) Allow(op Operation) error {
 		// panic("leaderStatusProvider required to be set via Register(..)")
 	}
 
-	cfg := h.cfg.Load()
-	if cfg.GlobalMode == ModeDisabled {
-		return nil
-	}
-
 	for _, l := range h.limits(op) {
 		if l.mode == ModeDisabled {
 			continue
@@ -207,14 +272,18 @@ func (h *Handler) Allow(op Operation) error {
 		// TODO(NET-1382): is this the correct log-level?
 
 		enforced := l.mode == ModeEnforcing
-		h.logger.Warn("RPC exceeded allowed rate limit",
+		logArgs := []interface{}{
 			"rpc", op.Name,
 			"source_addr", op.SourceAddr,
 			"limit_type", l.desc,
 			"limit_enforced", enforced,
-		)
+		}
+		if op.AccessorID != "" {
+			logArgs = append(logArgs, "accessor_id", op.AccessorID)
+		}
+		h.logger.Warn("RPC exceeded allowed rate limit", logArgs...)
 
-		metrics.IncrCounterWithLabels([]string{"consul", "rate_limit"}, 1, []metrics.Label{
+		labels := []metrics.Label{
 			{
 				Name:  "limit_type",
 				Value: l.desc,
@@ -227,7 +296,9 @@ func (h *Handler) Allow(op Operation) error {
 				Name:  "mode",
 				Value: l.mode.String(),
 			},
-		})
+		}
+		labels = append(labels, l.labels...)
+		metrics.IncrCounterWithLabels([]string{"consul", "rate_limit"}, 1, labels)
 
 		if enforced {
 			if h.leaderStatusProvider.IsLeader() && op.Type == OperationTypeWrite {
@@ -252,6 +323,61 @@ func (h *Handler) UpdateConfig(cfg HandlerConfig) {
 	if !reflect.DeepEqual(existingCfg.GlobalReadConfig, cfg.GlobalReadConfig) {
 		h.limiter.UpdateConfig(cfg.GlobalReadConfig, globalRead)
 	}
+	updateIPConfig(h.limiter, existingCfg, &cfg)
+	updatePolicyConfig(h.limiter, endpointKind, existingCfg.EndpointPolicies, cfg.EndpointPolicies)
+	updatePolicyConfig(h.limiter, tokenKind, existingCfg.TokenPolicies, cfg.TokenPolicies)
+}
+
+// updateIPConfig pushes the IP-based limiter configs that differ between
+// existing and cfg down to the given limiter, and removes the configs of the
+// prefixes no longer in cfg.
+func updateIPConfig(limiter multilimiter.RateLimiter, existing, cfg *HandlerConfig) {
+	if !reflect.DeepEqual(existing.IPWriteConfig, cfg.IPWriteConfig) {
+		limiter.UpdateConfig(cfg.IPWriteConfig, ipWrite)
+	}
+	if !reflect.DeepEqual(existing.IPReadConfig, cfg.IPReadConfig) {
+		limiter.UpdateConfig(cfg.IPReadConfig, ipRead)
+	}
+
+	existingPrefixes := make(map[netip.Prefix]IPPrefixLimit, len(existing.IPPrefixLimits))
+	for _, p := range existing.IPPrefixLimits {
+		existingPrefixes[p.Prefix.Masked()] = p
+	}
+	for _, p := range cfg.IPPrefixLimits {
+		prev, ok := existingPrefixes[p.Prefix.Masked()]
+		if !ok || !reflect.DeepEqual(prev.WriteConfig, p.WriteConfig) {
+			limiter.UpdateConfig(p.WriteConfig, prefixLimit(ipWrite, p.Prefix))
+		}
+		if !ok || !reflect.DeepEqual(prev.ReadConfig, p.ReadConfig) {
+			limiter.UpdateConfig(p.ReadConfig, prefixLimit(ipRead, p.Prefix))
+		}
+		delete(existingPrefixes, p.Prefix.Masked())
+	}
+	for prefix := range existingPrefixes {
+		limiter.RemoveConfig(prefixLimit(ipWrite, prefix))
+		limiter.RemoveConfig(prefixLimit(ipRead, prefix))
+	}
+}
+
+// updatePolicyConfig pushes the configs of the policies that differ between
+// existing and policies down to the given limiter, and removes the configs of
+// the policies no longer in policies.
+func updatePolicyConfig(limiter multilimiter.RateLimiter, kind string, existing, policies map[string]Policy) {
+	for name, p := range policies {
+		prev, ok := existing[name]
+		if !ok || !reflect.DeepEqual(prev.WriteConfig, p.WriteConfig) {
+			limiter.UpdateConfig(p.WriteConfig, policyLimit(kind, OperationTypeWrite, name))
+		}
+		if !ok || !reflect.DeepEqual(prev.ReadConfig, p.ReadConfig) {
+			limiter.UpdateConfig(p.ReadConfig, policyLimit(kind, OperationTypeRead, name))
+		}
+	}
+	for name := range existing {
+		if _, ok := policies[name]; !ok {
+			limiter.RemoveConfig(policyLimit(kind, OperationTypeWrite, name))
+			limiter.RemoveConfig(policyLimit(kind, OperationTypeRead, name))
+		}
+	}
 }
 
 func (h *Handler) Register(leaderStatusProvider LeaderStatusProvider) {
@@ -262,6 +388,9 @@ type limit struct {
 	mode Mode
 	ent  multilimiter.LimitedEntity
 	desc string
+
+	// labels are added to the metric emitted when the limit is exhausted.
+	labels []metrics.Label
 }
 
 // limits returns the limits to check for the given operation (e.g. global +
@@ -269,10 +398,25 @@ type limit struct {
 func (h *Handler) limits(op Operation) []limit {
 	limits := make([]limit, 0)
 
+	if op.AccessorID != "" {
+		if token := h.tokenLimit(op); token != nil {
+			limits = append(limits, *token)
+		}
+		return limits
+	}
+
 	if global := h.globalLimit(op); global != nil {
 		limits = append(limits, *global)
 	}
 
+	if ip := h.ipLimit(op); ip != nil {
+		limits = append(limits, *ip)
+	}
+
+	if endpoint := h.endpointLimit(op); endpoint != nil {
+		limits = append(limits, *endpoint)
+	}
+
 	return limits
 }
 
@@ -296,12 +440,149 @@ func (h *Handler) globalLimit(op Operation) *limit {
 	return lim
 }
 
+func (h *Handler) ipLimit(op Operation) *limit {
+	if op.Type == OperationTypeExempt {
+		return nil
+	}
+	cfg := h.cfg.Load()
+	if cfg.IPMode == ModeDisabled {
+		return nil
+	}
+
+	addr, ok := sourceIP(op.SourceAddr)
+	if !ok {
+		return nil
+	}
+	for _, allowed := range cfg.IPAllowlist {
+		if allowed.Contains(addr) {
+			return nil
+		}
+	}
+
+	var kind []byte
+	lim := &limit{mode: cfg.IPMode}
+	switch op.Type {
+	case OperationTypeRead:
+		lim.desc = "ip/read"
+		kind = ipRead
+	case OperationTypeWrite:
+		lim.desc = "ip/write"
+		kind = ipWrite
+	default:
+		panic(fmt.Sprintf("unknown operation type %d", op.Type))
+	}
+
+	if prefix, ok := matchPrefix(cfg.IPPrefixLimits, addr); ok {
+		lim.ent = prefixLimit(kind, prefix)
+		lim.labels = []metrics.Label{{Name: "source_prefix", Value: prefix.String()}}
+	} else {
+		lim.ent = addrLimit{kind: kind, addr: addr}
+	}
+	return lim
+}
+
+func (h *Handler) endpointLimit(op Operation) *limit {
+	policy, ok := h.cfg.Load().EndpointPolicies[op.Name]
+	if !ok {
+		return nil
+	}
+	return policyLimitFor(op, policy, endpointKind, op.Name, nil)
+}
+
+// TokenLimitsEnabled returns whether any rate limits are configured for ACL
+// tokens.
+func (h *Handler) TokenLimitsEnabled() bool {
+	return len(h.cfg.Load().TokenPolicies) > 0
+}
+
+func (h *Handler) tokenLimit(op Operation) *limit {
+	policy, ok := h.cfg.Load().TokenPolicies[op.AccessorID]
+	if !ok {
+		return nil
+	}
+	labels := []metrics.Label{{Name: "accessor_id", Value: op.AccessorID}}
+	return policyLimitFor(op, policy, tokenKind, op.AccessorID, labels)
+}
+
+func policyLimitFor(op Operation, policy Policy, kind, name string, labels []metrics.Label) *limit {
+	if op.Type == OperationTypeExempt || policy.Mode == ModeDisabled {
+		return nil
+	}
+
+	lim := &limit{
+		mode:   policy.Mode,
+		ent:    policyLimit(kind, op.Type, name),
+		labels: labels,
+	}
+	switch op.Type {
+	case OperationTypeRead:
+		lim.desc = kind + "/read"
+	case OperationTypeWrite:
+		lim.desc = kind + "/write"
+	default:
+		panic(fmt.Sprintf("unknown operation type %d", op.Type))
+	}
+	return lim
+}
+
+// matchPrefix returns the most specific of the given prefixes that contains
+// addr.
+func matchPrefix(limits []IPPrefixLimit, addr netip.Addr) (netip.Prefix, bool) {
+	var (
+		match netip.Prefix
+		found bool
+	)
+	for _, l := range limits {
+		if !l.Prefix.Contains(addr) {
+			continue
+		}
+		if !found || l.Prefix.Bits() > match.Bits() {
+			match, found = l.Prefix, true
+		}
+	}
+	return match, found
+}
+
+// sourceIP extracts the IP address from an operation's source address.
+func sourceIP(addr net.Addr) (netip.Addr, bool) {
+	var ip net.IP
+	switch a := addr.(type) {
+	case nil:
+		return netip.Addr{}, false
+	case *net.TCPAddr:
+		ip = a.IP
+	case *net.UDPAddr:
+		ip = a.IP
+	case *net.IPAddr:
+		ip = a.IP
+	default:
+		addrPort, err := netip.ParseAddrPort(addr.String())
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		return addrPort.Addr().Unmap(), true
+	}
+	parsed, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return netip.Addr{}, false
+	}
+	return parsed.Unmap(), true
+}
+
 var (
 	// globalWrite identifies the global rate limit applied to write operations.
 	globalWrite = globalLimit("global.write")
 
 	// globalRead identifies the global rate limit applied to read operations.
 	globalRead = globalLimit("global.read")
+
+	// ipWrite is the multilimiter prefix of IP-based limits applied to write
+	// operations.
+	ipWrite = []byte("ip.write")
+
+	// ipRead is the multilimiter prefix of IP-based limits applied to read
+	// operations.
+	ipRead = []byte("ip.read")
 )
 
 // globalLimit represents a limit that applies to all writes or reads.
@@ -312,6 +593,54 @@ func (prefix globalLimit) Key() multilimiter.KeyType {
 	return multilimiter.Key(prefix, nil)
 }
 
+// addrLimit represents a limit that applies to the writes or reads of a single
+// source IP address.
+type addrLimit struct {
+	kind []byte
+	addr netip.Addr
+}
+
+// Key satisfies the multilimiter.LimitedEntity interface.
+func (l addrLimit) Key() multilimiter.KeyType {
+	return multilimiter.Key(l.kind, []byte(l.addr.String()))
+}
+
+const (
+	// endpointKind identifies policy limits applied to RPC endpoints.
+	endpointKind = "endpoint"
+
+	// tokenKind identifies policy limits applied to ACL tokens.
+	tokenKind = "token"
+)
+
+// policyLimit returns the limit shared by the writes or reads against the
+// endpoint, or performed with the token, identified by name. It doubles as the
+// multilimiter prefix the policy's limiter config is stored under.
+func policyLimit(kind string, typ OperationType, name string) sharedLimit {
+	op := "read"
+	if typ == OperationTypeWrite {
+		op = "write"
+	}
+	return sharedLimit(kind + "." + op + "." + name)
+}
+
+// sharedLimit represents a limit that is shared by the writes or reads of a
+// group of operations (e.g. those from all source IP addresses within a CIDR
+// block, or those against a specific endpoint).
+type sharedLimit []byte
+
+// Key satisfies the multilimiter.LimitedEntity interface.
+func (prefix sharedLimit) Key() multilimiter.KeyType {
+	return multilimiter.Key(prefix, nil)
+}
+
+// prefixLimit returns the limit shared by all source IP addresses within the
+// given CIDR block. It doubles as the multilimiter prefix the block's limiter
+// config is stored under.
+func prefixLimit(kind []byte, prefix netip.Prefix) sharedLimit {
+	return sharedLimit(string(kind) + "." + prefix.Masked().String())
+}
+
 // NullRequestLimitsHandler returns a RequestLimitsHandler that allows every operation.
 func NullRequestLimitsHandler() RequestLimitsHandler {
 	return nullRequestLimitsHandler{}
@@ -326,3 +655,5 @@ func (nullRequestLimitsHandler) Run(ctx context.Context) {}
 func (nullRequestLimitsHandler) UpdateConfig(cfg HandlerConfig) {}
 
 func (nullRequestLimitsHandler) Register(leaderStatusProvider LeaderStatusProvider) {}
+
+func (nullRequestLimitsHandler) TokenLimitsEnabled() bool { return false }
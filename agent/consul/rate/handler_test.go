@@ -6,14 +6,17 @@ import (
 	"net"
 	"net/netip"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/consul/agent/consul/multilimiter"
 	"github.com/hashicorp/consul/agent/metrics"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 )
 
 //
@@ -260,6 +263,257 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestHandler_IPLimits(t *testing.T) {
+	var (
+		rpcName    = "Foo.Bar"
+		sourceAddr = net.TCPAddrFromAddrPort(netip.MustParseAddrPort("10.0.1.4:5678"))
+		prefix     = netip.MustParsePrefix("10.0.0.0/16")
+	)
+
+	type limitCheck struct {
+		limit multilimiter.LimitedEntity
+		allow bool
+	}
+	testCases := map[string]struct {
+		op               Operation
+		ipMode           Mode
+		prefixes         []IPPrefixLimit
+		allowlist        []netip.Prefix
+		checks           []limitCheck
+		isLeader         bool
+		expectErr        error
+		expectLog        bool
+		expectMetricName string
+	}{
+		"ip limit disabled": {
+			op:     Operation{Type: OperationTypeRead, Name: rpcName, SourceAddr: sourceAddr},
+			ipMode: ModeDisabled,
+			checks: []limitCheck{},
+		},
+		"ip read limit within allowance": {
+			op:     Operation{Type: OperationTypeRead, Name: rpcName, SourceAddr: sourceAddr},
+			ipMode: ModeEnforcing,
+			checks: []limitCheck{
+				{limit: addrLimit{kind: ipRead, addr: netip.MustParseAddr("10.0.1.4")}, allow: true},
+			},
+		},
+		"ip write limit exceeded (permissive)": {
+			op:     Operation{Type: OperationTypeWrite, Name: rpcName, SourceAddr: sourceAddr},
+			ipMode: ModePermissive,
+			checks: []limitCheck{
+				{limit: addrLimit{kind: ipWrite, addr: netip.MustParseAddr("10.0.1.4")}, allow: false},
+			},
+			expectLog:        true,
+			expectMetricName: "consul.rate_limit;limit_type=ip/write;op=Foo.Bar;mode=permissive",
+		},
+		"ip write limit exceeded (enforcing, leader)": {
+			op:     Operation{Type: OperationTypeWrite, Name: rpcName, SourceAddr: sourceAddr},
+			ipMode: ModeEnforcing,
+			checks: []limitCheck{
+				{limit: addrLimit{kind: ipWrite, addr: netip.MustParseAddr("10.0.1.4")}, allow: false},
+			},
+			isLeader:         true,
+			expectErr:        ErrRetryLater,
+			expectLog:        true,
+			expectMetricName: "consul.rate_limit;limit_type=ip/write;op=Foo.Bar;mode=enforcing",
+		},
+		"prefix read limit exceeded (enforcing)": {
+			op:     Operation{Type: OperationTypeRead, Name: rpcName, SourceAddr: sourceAddr},
+			ipMode: ModeEnforcing,
+			prefixes: []IPPrefixLimit{
+				{Prefix: netip.MustParsePrefix("10.0.0.0/8")},
+				{Prefix: prefix},
+				{Prefix: netip.MustParsePrefix("192.168.0.0/16")},
+			},
+			checks: []limitCheck{
+				{limit: prefixLimit(ipRead, prefix), allow: false},
+			},
+			expectErr:        ErrRetryElsewhere,
+			expectLog:        true,
+			expectMetricName: "consul.rate_limit;limit_type=ip/read;op=Foo.Bar;mode=enforcing;source_prefix=10.0.0.0/16",
+		},
+		"source address in allowlist": {
+			op:        Operation{Type: OperationTypeWrite, Name: rpcName, SourceAddr: sourceAddr},
+			ipMode:    ModeEnforcing,
+			allowlist: []netip.Prefix{netip.MustParsePrefix("10.0.1.0/24")},
+			checks:    []limitCheck{},
+		},
+		"operation exempt from limiting": {
+			op:     Operation{Type: OperationTypeExempt, Name: rpcName, SourceAddr: sourceAddr},
+			ipMode: ModeEnforcing,
+			checks: []limitCheck{},
+		},
+		"unknown source address": {
+			op:     Operation{Type: OperationTypeRead, Name: rpcName},
+			ipMode: ModeEnforcing,
+			checks: []limitCheck{},
+		},
+	}
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			sink := metrics.TestSetupMetrics(t, "")
+			limiter := newMockLimiter(t)
+			limiter.On("UpdateConfig", mock.Anything, mock.Anything).Return()
+			for _, c := range tc.checks {
+				limiter.On("Allow", c.limit).Return(c.allow)
+			}
+
+			leaderStatusProvider := NewMockLeaderStatusProvider(t)
+			leaderStatusProvider.On("IsLeader").Return(tc.isLeader).Maybe()
+
+			var output bytes.Buffer
+			logger := hclog.NewInterceptLogger(&hclog.LoggerOptions{
+				Level:  hclog.Trace,
+				Output: &output,
+			})
+
+			handler := NewHandlerWithLimiter(
+				HandlerConfig{
+					IPMode:         tc.ipMode,
+					IPPrefixLimits: tc.prefixes,
+					IPAllowlist:    tc.allowlist,
+				},
+				limiter,
+				logger,
+			)
+			handler.Register(leaderStatusProvider)
+
+			require.Equal(t, tc.expectErr, handler.Allow(tc.op))
+
+			if tc.expectLog {
+				require.Contains(t, output.String(), "RPC exceeded allowed rate limit")
+			} else {
+				require.Zero(t, output.Len(), "expected no logs to be emitted")
+			}
+
+			if tc.expectMetricName != "" {
+				metrics.AssertCounter(t, sink, tc.expectMetricName, 1)
+			}
+		})
+	}
+}
+
+func TestHandler_Policies(t *testing.T) {
+	var (
+		rpcName    = "KVS.Apply"
+		accessorID = "ff2c4c9b-9a4c-4b9e-8d2b-6b1d3a6e1c6d"
+		sourceAddr = net.TCPAddrFromAddrPort(netip.MustParseAddrPort("1.2.3.4:5678"))
+	)
+
+	type limitCheck struct {
+		limit multilimiter.LimitedEntity
+		allow bool
+	}
+	testCases := map[string]struct {
+		op               Operation
+		endpoints        map[string]Policy
+		tokens           map[string]Policy
+		checks           []limitCheck
+		isLeader         bool
+		expectErr        error
+		expectMetricName string
+	}{
+		"no policy for endpoint": {
+			op:        Operation{Type: OperationTypeWrite, Name: "Health.ServiceNodes", SourceAddr: sourceAddr},
+			endpoints: map[string]Policy{rpcName: {Mode: ModeEnforcing}},
+			checks:    []limitCheck{},
+		},
+		"endpoint policy disabled": {
+			op:        Operation{Type: OperationTypeWrite, Name: rpcName, SourceAddr: sourceAddr},
+			endpoints: map[string]Policy{rpcName: {Mode: ModeDisabled}},
+			checks:    []limitCheck{},
+		},
+		"endpoint write limit within allowance": {
+			op:        Operation{Type: OperationTypeWrite, Name: rpcName, SourceAddr: sourceAddr},
+			endpoints: map[string]Policy{rpcName: {Mode: ModeEnforcing}},
+			checks: []limitCheck{
+				{limit: sharedLimit("endpoint.write.KVS.Apply"), allow: true},
+			},
+		},
+		"endpoint write limit exceeded (enforcing, leader)": {
+			op:        Operation{Type: OperationTypeWrite, Name: rpcName, SourceAddr: sourceAddr},
+			endpoints: map[string]Policy{rpcName: {Mode: ModeEnforcing}},
+			checks: []limitCheck{
+				{limit: sharedLimit("endpoint.write.KVS.Apply"), allow: false},
+			},
+			isLeader:         true,
+			expectErr:        ErrRetryLater,
+			expectMetricName: "consul.rate_limit;limit_type=endpoint/write;op=KVS.Apply;mode=enforcing",
+		},
+		"endpoint policy ignored once token is known": {
+			op:        Operation{Type: OperationTypeWrite, Name: rpcName, AccessorID: accessorID},
+			endpoints: map[string]Policy{rpcName: {Mode: ModeEnforcing}},
+			checks:    []limitCheck{},
+		},
+		"token read limit exceeded (permissive)": {
+			op:     Operation{Type: OperationTypeRead, Name: rpcName, AccessorID: accessorID},
+			tokens: map[string]Policy{accessorID: {Mode: ModePermissive}},
+			checks: []limitCheck{
+				{limit: sharedLimit("token.read." + accessorID), allow: false},
+			},
+			expectMetricName: "consul.rate_limit;limit_type=token/read;op=KVS.Apply;mode=permissive;accessor_id=" + accessorID,
+		},
+		"token write limit exceeded (enforcing, follower)": {
+			op:     Operation{Type: OperationTypeWrite, Name: rpcName, AccessorID: accessorID},
+			tokens: map[string]Policy{accessorID: {Mode: ModeEnforcing}},
+			checks: []limitCheck{
+				{limit: sharedLimit("token.write." + accessorID), allow: false},
+			},
+			expectErr:        ErrRetryElsewhere,
+			expectMetricName: "consul.rate_limit;limit_type=token/write;op=KVS.Apply;mode=enforcing;accessor_id=" + accessorID,
+		},
+		"token policy ignored before token is known": {
+			op:     Operation{Type: OperationTypeWrite, Name: rpcName, SourceAddr: sourceAddr},
+			tokens: map[string]Policy{accessorID: {Mode: ModeEnforcing}},
+			checks: []limitCheck{},
+		},
+	}
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			sink := metrics.TestSetupMetrics(t, "")
+			limiter := newMockLimiter(t)
+			limiter.On("UpdateConfig", mock.Anything, mock.Anything).Return()
+			for _, c := range tc.checks {
+				limiter.On("Allow", c.limit).Return(c.allow)
+			}
+
+			leaderStatusProvider := NewMockLeaderStatusProvider(t)
+			leaderStatusProvider.On("IsLeader").Return(tc.isLeader).Maybe()
+
+			handler := NewHandlerWithLimiter(
+				HandlerConfig{
+					EndpointPolicies: tc.endpoints,
+					TokenPolicies:    tc.tokens,
+				},
+				limiter,
+				hclog.NewNullLogger(),
+			)
+			handler.Register(leaderStatusProvider)
+
+			require.Equal(t, tc.expectErr, handler.Allow(tc.op))
+
+			if tc.expectMetricName != "" {
+				metrics.AssertCounter(t, sink, tc.expectMetricName, 1)
+			}
+		})
+	}
+}
+
+func TestHandler_TokenLimitsEnabled(t *testing.T) {
+	handler := NewHandler(HandlerConfig{}, hclog.NewNullLogger())
+	require.False(t, handler.TokenLimitsEnabled())
+
+	handler.UpdateConfig(HandlerConfig{
+		TokenPolicies: map[string]Policy{
+			"token-1": {Mode: ModeEnforcing, ReadConfig: multilimiter.LimiterConfig{Rate: 10, Burst: 10}},
+		},
+	})
+	require.True(t, handler.TokenLimitsEnabled())
+
+	handler.UpdateConfig(HandlerConfig{})
+	require.False(t, handler.TokenLimitsEnabled())
+}
+
 func TestNewHandlerWithLimiter_CallsUpdateConfig(t *testing.T) {
 	mockRateLimiter := multilimiter.NewMockRateLimiter(t)
 	mockRateLimiter.On("UpdateConfig", mock.Anything, mock.Anything).Return()
@@ -309,6 +563,92 @@ func TestUpdateConfig(t *testing.T) {
 				mockRateLimiter.AssertCalled(t, "UpdateConfig", cfg.GlobalWriteConfig, []byte("global.write"))
 			},
 		},
+		{
+			description: "RateLimiter gets updated when IPReadConfig changes.",
+			configModFunc: func(cfg *HandlerConfig) {
+				cfg.IPReadConfig.Burst++
+			},
+			assertFunc: func(mockRateLimiter *multilimiter.MockRateLimiter, cfg *HandlerConfig) {
+				mockRateLimiter.AssertNumberOfCalls(t, "UpdateConfig", 1)
+				mockRateLimiter.AssertCalled(t, "UpdateConfig", cfg.IPReadConfig, []byte("ip.read"))
+			},
+		},
+		{
+			description: "RateLimiter gets updated when an IPPrefixLimit is added.",
+			configModFunc: func(cfg *HandlerConfig) {
+				cfg.IPPrefixLimits = append(cfg.IPPrefixLimits, IPPrefixLimit{
+					Prefix:      netip.MustParsePrefix("10.1.0.0/16"),
+					ReadConfig:  multilimiter.LimiterConfig{Rate: 5, Burst: 5},
+					WriteConfig: multilimiter.LimiterConfig{Rate: 1, Burst: 1},
+				})
+			},
+			assertFunc: func(mockRateLimiter *multilimiter.MockRateLimiter, cfg *HandlerConfig) {
+				mockRateLimiter.AssertNumberOfCalls(t, "UpdateConfig", 2)
+				mockRateLimiter.AssertCalled(t, "UpdateConfig", multilimiter.LimiterConfig{Rate: 5, Burst: 5}, []byte("ip.read.10.1.0.0/16"))
+				mockRateLimiter.AssertCalled(t, "UpdateConfig", multilimiter.LimiterConfig{Rate: 1, Burst: 1}, []byte("ip.write.10.1.0.0/16"))
+			},
+		},
+		{
+			description: "RateLimiter config is removed when an IPPrefixLimit is removed.",
+			configModFunc: func(cfg *HandlerConfig) {
+				cfg.IPPrefixLimits = nil
+			},
+			assertFunc: func(mockRateLimiter *multilimiter.MockRateLimiter, cfg *HandlerConfig) {
+				mockRateLimiter.AssertNumberOfCalls(t, "UpdateConfig", 0)
+				mockRateLimiter.AssertNumberOfCalls(t, "RemoveConfig", 2)
+				mockRateLimiter.AssertCalled(t, "RemoveConfig", []byte("ip.read.10.0.0.0/16"))
+				mockRateLimiter.AssertCalled(t, "RemoveConfig", []byte("ip.write.10.0.0.0/16"))
+			},
+		},
+		{
+			description: "RateLimiter config is removed when a TokenPolicy is removed.",
+			configModFunc: func(cfg *HandlerConfig) {
+				cfg.TokenPolicies = nil
+			},
+			assertFunc: func(mockRateLimiter *multilimiter.MockRateLimiter, cfg *HandlerConfig) {
+				mockRateLimiter.AssertNumberOfCalls(t, "UpdateConfig", 0)
+				mockRateLimiter.AssertNumberOfCalls(t, "RemoveConfig", 2)
+				mockRateLimiter.AssertCalled(t, "RemoveConfig", []byte("token.read.token-1"))
+				mockRateLimiter.AssertCalled(t, "RemoveConfig", []byte("token.write.token-1"))
+			},
+		},
+		{
+			description: "RateLimiter does not get updated when IPMode or IPAllowlist change.",
+			configModFunc: func(cfg *HandlerConfig) {
+				cfg.IPMode = ModePermissive
+				cfg.IPAllowlist = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+			},
+			assertFunc: func(mockRateLimiter *multilimiter.MockRateLimiter, cfg *HandlerConfig) {
+				mockRateLimiter.AssertNumberOfCalls(t, "UpdateConfig", 0)
+			},
+		},
+		{
+			description: "RateLimiter gets updated when an EndpointPolicy changes.",
+			configModFunc: func(cfg *HandlerConfig) {
+				cfg.EndpointPolicies = map[string]Policy{
+					"KVS.Apply": {
+						Mode:        ModeEnforcing,
+						WriteConfig: multilimiter.LimiterConfig{Rate: 3, Burst: 3},
+					},
+				}
+			},
+			assertFunc: func(mockRateLimiter *multilimiter.MockRateLimiter, cfg *HandlerConfig) {
+				mockRateLimiter.AssertNumberOfCalls(t, "UpdateConfig", 2)
+				mockRateLimiter.AssertCalled(t, "UpdateConfig", multilimiter.LimiterConfig{Rate: 3, Burst: 3}, []byte("endpoint.write.KVS.Apply"))
+				mockRateLimiter.AssertCalled(t, "UpdateConfig", multilimiter.LimiterConfig{}, []byte("endpoint.read.KVS.Apply"))
+			},
+		},
+		{
+			description: "RateLimiter does not get updated when only a TokenPolicy's Mode changes.",
+			configModFunc: func(cfg *HandlerConfig) {
+				policy := cfg.TokenPolicies["token-1"]
+				policy.Mode = ModeEnforcing
+				cfg.TokenPolicies = map[string]Policy{"token-1": policy}
+			},
+			assertFunc: func(mockRateLimiter *multilimiter.MockRateLimiter, cfg *HandlerConfig) {
+				mockRateLimiter.AssertNumberOfCalls(t, "UpdateConfig", 0)
+			},
+		},
 		{
 			description: "RateLimiter does not get updated when GlobalMode changes.",
 			configModFunc: func(cfg *HandlerConfig) {
@@ -328,9 +668,22 @@ func TestUpdateConfig(t *testing.T) {
 				GlobalReadConfig:  readCfg,
 				GlobalWriteConfig: writeCfg,
 				GlobalMode:        ModeEnforcing,
+				IPPrefixLimits: []IPPrefixLimit{
+					{
+						Prefix:     netip.MustParsePrefix("10.0.0.0/16"),
+						ReadConfig: multilimiter.LimiterConfig{Rate: 10, Burst: 10},
+					},
+				},
+				TokenPolicies: map[string]Policy{
+					"token-1": {
+						Mode:       ModePermissive,
+						ReadConfig: multilimiter.LimiterConfig{Rate: 1, Burst: 1},
+					},
+				},
 			}
 			mockRateLimiter := multilimiter.NewMockRateLimiter(t)
 			mockRateLimiter.On("UpdateConfig", mock.Anything, mock.Anything).Return()
+			mockRateLimiter.On("RemoveConfig", mock.Anything).Return().Maybe()
 			logger := hclog.NewNullLogger()
 			handler := NewHandlerWithLimiter(*cfg, mockRateLimiter, logger)
 			mockRateLimiter.Calls = nil
@@ -341,6 +694,56 @@ func TestUpdateConfig(t *testing.T) {
 	}
 }
 
+func TestUpdateConfig_RemovedIPPrefix(t *testing.T) {
+	sourceAddr := net.TCPAddrFromAddrPort(netip.MustParseAddrPort("10.0.1.4:5678"))
+	op := Operation{Type: OperationTypeRead, Name: "Foo.Bar", SourceAddr: sourceAddr}
+
+	cfg := HandlerConfig{
+		Config: multilimiter.Config{
+			LimiterConfig:          multilimiter.LimiterConfig{Rate: rate.Inf},
+			ReconcileCheckLimit:    time.Second,
+			ReconcileCheckInterval: 10 * time.Millisecond,
+		},
+		GlobalReadConfig: multilimiter.LimiterConfig{Rate: rate.Inf},
+		IPMode:           ModeEnforcing,
+		IPReadConfig:     multilimiter.LimiterConfig{Rate: rate.Inf},
+		IPPrefixLimits: []IPPrefixLimit{
+			{
+				Prefix:     netip.MustParsePrefix("10.0.0.0/16"),
+				ReadConfig: multilimiter.LimiterConfig{Rate: 0, Burst: 0},
+			},
+		},
+	}
+	handler := NewHandler(cfg, hclog.NewNullLogger())
+	leaderStatusProvider := NewMockLeaderStatusProvider(t)
+	leaderStatusProvider.On("IsLeader").Return(false).Maybe()
+	handler.Register(leaderStatusProvider)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	handler.Run(ctx)
+
+	require.Equal(t, ErrRetryElsewhere, handler.Allow(op))
+
+	// Once the prefix is removed its limit doesn't apply anymore.
+	cfg.IPPrefixLimits = nil
+	handler.UpdateConfig(cfg)
+	require.NoError(t, handler.Allow(op))
+
+	// Re-adding it applies the limit again, even to the limiter created under
+	// the prefix before it was removed.
+	cfg.IPPrefixLimits = []IPPrefixLimit{
+		{
+			Prefix:     netip.MustParsePrefix("10.0.0.0/16"),
+			ReadConfig: multilimiter.LimiterConfig{Rate: 0, Burst: 0},
+		},
+	}
+	handler.UpdateConfig(cfg)
+	retry.Run(t, func(r *retry.R) {
+		require.Equal(r, ErrRetryElsewhere, handler.Allow(op))
+	})
+}
+
 func TestAllow(t *testing.T) {
 	readCfg := multilimiter.LimiterConfig{Rate: 100, Burst: 100}
 	writeCfg := multilimiter.LimiterConfig{Rate: 99, Burst: 99}
@@ -420,3 +823,4 @@ func (m *mockLimiter) Run(ctx context.Context)                 { m.Called(ctx) }
 func (m *mockLimiter) UpdateConfig(cfg multilimiter.LimiterConfig, prefix []byte) {
 	m.Called(cfg, prefix)
 }
+func (m *mockLimiter) RemoveConfig(prefix []byte) { m.Called(prefix) }
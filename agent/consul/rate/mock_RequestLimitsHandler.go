@@ -42,6 +42,20 @@ func (_m *MockRequestLimitsHandler) Register(leaderStatusProvider LeaderStatusPr
 	_m.Called(leaderStatusProvider)
 }
 
+// TokenLimitsEnabled provides a mock function with given fields:
+func (_m *MockRequestLimitsHandler) TokenLimitsEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewMockRequestLimitsHandler interface {
 	mock.TestingT
 	Cleanup(func())
@@ -613,7 +613,46 @@ func (s *Server) ForwardRPC(method string, info structs.RPCInfo, reply interface
 		return s.connPool.RPC(s.config.Datacenter, leader.ShortName, leader.Addr,
 			method, info, reply)
 	}
-	return s.forwardRPC(info, forwardToDC, forwardToLeader)
+	handled, err := s.forwardRPC(info, forwardToDC, forwardToLeader)
+	if handled || err != nil {
+		return handled, err
+	}
+
+	// The request will be handled by this server, so apply the rate limits of
+	// the token it's being made with.
+	if err := s.allowTokenRateLimit(method, info); err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
+// allowTokenRateLimit applies the rate limits configured for the ACL token the
+// given request is being made with. The token isn't known until the request
+// body has been decoded, so these limits can't be applied alongside the others
+// in the net/rpc pre-body interceptor.
+//
+// Only net/rpc requests are subject to these limits, gRPC requests forwarded
+// with ForwardGRPC are not.
+func (s *Server) allowTokenRateLimit(method string, info structs.RPCInfo) error {
+	if !s.ACLResolver.ACLsEnabled() || !s.incomingRPCLimiter.TokenLimitsEnabled() {
+		return nil
+	}
+
+	token := info.TokenSecret()
+	if token == "" {
+		token = anonymousToken
+	}
+	identity, err := s.ACLResolver.resolveIdentityFromToken(token)
+	if err != nil || identity == nil {
+		// Leave it to the endpoint to reject invalid tokens.
+		return nil
+	}
+
+	return s.incomingRPCLimiter.Allow(rate.Operation{
+		Name:       method,
+		Type:       middleware.NetRPCOperationType(method),
+		AccessorID: identity.ID(),
+	})
 }
 
 // ForwardGRPC is used to potentially forward an RPC request to a remote DC or
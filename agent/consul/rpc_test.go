@@ -949,6 +949,68 @@ func TestRPC_readUint32(t *testing.T) {
 	}
 }
 
+func TestRPC_TokenRateLimit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1, codec := testACLServerWithConfig(t, nil, false)
+	defer codec.Close()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	token, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `
+	key_prefix "" { policy = "write" }
+	`)
+	require.NoError(t, err)
+
+	req := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVSet,
+		DirEnt: structs.DirEntry{
+			Key:   "foo",
+			Value: []byte("bar"),
+		},
+		WriteRequest: structs.WriteRequest{Token: token.SecretID},
+	}
+
+	t.Run("no token limits", func(t *testing.T) {
+		// Allow must not be called when there are no token limits, as the
+		// token doesn't need to be resolved.
+		limiter := rate.NewMockRequestLimitsHandler(t)
+		limiter.On("TokenLimitsEnabled").Return(false)
+		s1.incomingRPCLimiter = limiter
+
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &req, &out))
+
+		_, entry, err := s1.fsm.State().KVSGet(nil, "foo", nil)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+	})
+
+	t.Run("token limit exhausted", func(t *testing.T) {
+		limiter := rate.NewMockRequestLimitsHandler(t)
+		limiter.On("TokenLimitsEnabled").Return(true)
+		limiter.On("Allow", rate.Operation{
+			Name:       "KVS.Apply",
+			Type:       rate.OperationTypeWrite,
+			AccessorID: token.AccessorID,
+		}).Return(rate.ErrRetryLater)
+		s1.incomingRPCLimiter = limiter
+
+		req := req
+		req.DirEnt.Key = "bar"
+		var out bool
+		err := msgpackrpc.CallWithCodec(codec, "KVS.Apply", &req, &out)
+		require.ErrorContains(t, err, rate.ErrRetryLater.Error())
+
+		_, entry, err := s1.fsm.State().KVSGet(nil, "bar", nil)
+		require.NoError(t, err)
+		require.Nil(t, entry)
+	})
+}
+
 func TestRPC_LocalTokenStrippedOnForward(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
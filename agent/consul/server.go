@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -846,9 +847,11 @@ func newGRPCHandlerFromConfig(deps Deps, config *Config, s *Server) connHandler
 			}
 			return s.ForwardGRPC(s.grpcConnPool, info, fn)
 		},
-		Datacenter:     config.Datacenter,
-		ConnectEnabled: config.ConnectEnabled,
-		PeeringEnabled: config.PeeringEnabled,
+		Datacenter:       config.Datacenter,
+		ConnectEnabled:   config.ConnectEnabled,
+		PeeringEnabled:   config.PeeringEnabled,
+		MaxQueryTime:     config.MaxQueryTime,
+		DefaultQueryTime: config.DefaultQueryTime,
 	})
 	s.peeringServer = p
 	o := operator.NewServer(operator.Config{
@@ -1850,9 +1853,16 @@ func (s *Server) hcpServerStatus(deps Deps) hcp.StatusCallback {
 func ConfiguredIncomingRPCLimiter(ctx context.Context, serverLogger hclog.InterceptLogger, consulCfg *Config) *rpcRate.Handler {
 	mlCfg := &multilimiter.Config{ReconcileCheckLimit: 30 * time.Second, ReconcileCheckInterval: time.Second}
 	limitsConfig := &RequestLimits{
-		Mode:      rpcRate.RequestLimitsModeFromNameWithDefault(consulCfg.RequestLimitsMode),
-		ReadRate:  consulCfg.RequestLimitsReadRate,
-		WriteRate: consulCfg.RequestLimitsWriteRate,
+		Mode:        rpcRate.RequestLimitsModeFromNameWithDefault(consulCfg.RequestLimitsMode),
+		ReadRate:    consulCfg.RequestLimitsReadRate,
+		WriteRate:   consulCfg.RequestLimitsWriteRate,
+		IPMode:      rpcRate.RequestLimitsModeFromNameWithDefault(consulCfg.RequestLimitsIPMode),
+		IPReadRate:  consulCfg.RequestLimitsIPReadRate,
+		IPWriteRate: consulCfg.RequestLimitsIPWriteRate,
+		IPAllowlist: consulCfg.RequestLimitsIPAllowlist,
+		IPPrefixes:  consulCfg.RequestLimitsIPPrefixes,
+		Endpoints:   consulCfg.RequestLimitsEndpoints,
+		Tokens:      consulCfg.RequestLimitsTokens,
 	}
 
 	sink := logdrop.NewLogDropSink(ctx, 100, serverLogger.Named("rpc-rate-limit"), func(l logdrop.Log) {
@@ -1877,7 +1887,28 @@ func convertConsulConfigToRateLimitHandlerConfig(limitsConfig RequestLimits, mul
 			Rate:  limitsConfig.WriteRate,
 			Burst: int(limitsConfig.WriteRate) * requestLimitsBurstMultiplier,
 		},
+		IPMode:        limitsConfig.IPMode,
+		IPReadConfig:  requestLimitsLimiterConfig(limitsConfig.IPReadRate),
+		IPWriteConfig: requestLimitsLimiterConfig(limitsConfig.IPWriteRate),
 	}
+	for _, cidr := range limitsConfig.IPAllowlist {
+		if prefix, ok := ipNetToPrefix(cidr); ok {
+			hc.IPAllowlist = append(hc.IPAllowlist, prefix)
+		}
+	}
+	for _, p := range limitsConfig.IPPrefixes {
+		prefix, ok := ipNetToPrefix(p.CIDR)
+		if !ok {
+			continue
+		}
+		hc.IPPrefixLimits = append(hc.IPPrefixLimits, rpcRate.IPPrefixLimit{
+			Prefix:      prefix,
+			ReadConfig:  requestLimitsLimiterConfig(p.ReadRate),
+			WriteConfig: requestLimitsLimiterConfig(p.WriteRate),
+		})
+	}
+	hc.EndpointPolicies = requestLimitsPolicies(limitsConfig.Endpoints)
+	hc.TokenPolicies = requestLimitsPolicies(limitsConfig.Tokens)
 	if multilimiterConfig != nil {
 		hc.Config = *multilimiterConfig
 	}
@@ -1885,6 +1916,41 @@ func convertConsulConfigToRateLimitHandlerConfig(limitsConfig RequestLimits, mul
 	return hc
 }
 
+func requestLimitsLimiterConfig(limit rate.Limit) multilimiter.LimiterConfig {
+	return multilimiter.LimiterConfig{
+		Rate:  limit,
+		Burst: int(limit) * requestLimitsBurstMultiplier,
+	}
+}
+
+func requestLimitsPolicies(policies map[string]RequestLimitsPolicy) map[string]rpcRate.Policy {
+	if len(policies) == 0 {
+		return nil
+	}
+	out := make(map[string]rpcRate.Policy, len(policies))
+	for name, p := range policies {
+		out[name] = rpcRate.Policy{
+			Mode:        p.Mode,
+			ReadConfig:  requestLimitsLimiterConfig(p.ReadRate),
+			WriteConfig: requestLimitsLimiterConfig(p.WriteRate),
+		}
+	}
+	return out
+}
+
+func ipNetToPrefix(cidr net.IPNet) (netip.Prefix, bool) {
+	ip := cidr.IP
+	if len(cidr.Mask) == net.IPv4len {
+		ip = ip.To4()
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	ones, _ := cidr.Mask.Size()
+	return netip.PrefixFrom(addr, ones).Masked(), true
+}
+
 // peersInfoContent is used to help operators understand what happened to the
 // peers.json file. This is written to a file called peers.info in the same
 // location.
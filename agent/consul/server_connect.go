@@ -32,6 +32,28 @@ func (s *Server) getCARoots(ws memdb.WatchSet, state *state.Store) (*structs.Ind
 
 	indexedRoots.TrustDomain = signingID.Host()
 
+	revokedIndex, revokedLeafs, err := state.CARevokedLeafs(ws)
+	if err != nil {
+		return nil, err
+	}
+	if revokedIndex > index {
+		index = revokedIndex
+	}
+	for _, r := range revokedLeafs {
+		// Copy so the structure in the memdb store is never modified.
+		revoked := *r
+		indexedRoots.RevokedLeafs = append(indexedRoots.RevokedLeafs, &revoked)
+	}
+
+	crlIndex, crls, err := s.leafCRLs(ws, state, revokedLeafs)
+	if err != nil {
+		return nil, err
+	}
+	if crlIndex > index {
+		index = crlIndex
+	}
+	indexedRoots.LeafCRLs = crls
+
 	indexedRoots.Index, indexedRoots.Roots = index, roots
 	if indexedRoots.Roots == nil {
 		indexedRoots.Roots = make(structs.CARoots, 0)
@@ -74,3 +96,39 @@ func (s *Server) getCARoots(ws memdb.WatchSet, state *state.Store) (*structs.Ind
 
 	return indexedRoots, nil
 }
+
+// leafCRLs returns the CRLs published by every datacenter in its federation
+// state while leaf certificates are revoked by serial number. Proxies reject
+// the leaf certificates of any CA they don't have a CRL for, so none are
+// returned unless every datacenter publishes them.
+func (s *Server) leafCRLs(ws memdb.WatchSet, state *state.Store, revokedLeafs []*structs.CARevokedLeaf) (uint64, []string, error) {
+	serialRevoked := false
+	for _, r := range revokedLeafs {
+		if r.SerialNumber != "" {
+			serialRevoked = true
+			break
+		}
+	}
+	if !serialRevoked {
+		return 0, nil, nil
+	}
+
+	index, fedStates, err := state.FederationStateList(ws)
+	if err != nil {
+		return 0, nil, err
+	}
+	published := make(map[string]bool, len(fedStates))
+	var crls []string
+	for _, fedState := range fedStates {
+		for _, crl := range fedState.LeafCRLs {
+			published[fedState.Datacenter] = true
+			crls = append(crls, crl.CRL)
+		}
+	}
+	for _, dc := range s.router.GetDatacenters() {
+		if !published[dc] {
+			return index, nil, nil
+		}
+	}
+	return index, crls, nil
+}
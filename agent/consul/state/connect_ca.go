@@ -15,6 +15,7 @@ const (
 	tableConnectCAConfig        = "connect-ca-config"
 	tableConnectCARoots         = "connect-ca-roots"
 	tableConnectCALeafCerts     = "connect-ca-leaf-certs"
+	tableConnectCARevokedLeafs  = "connect-ca-revoked-leafs"
 )
 
 // caBuiltinProviderTableSchema returns a new table schema used for storing
@@ -74,6 +75,24 @@ func caRootTableSchema() *memdb.TableSchema {
 	}
 }
 
+// caRevokedLeafTableSchema returns a new table schema used for storing
+// revoked leaf certificates for Connect.
+func caRevokedLeafTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: tableConnectCARevokedLeafs,
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+		},
+	}
+}
+
 // CAConfig is used to pull the CA config from the snapshot.
 func (s *Snapshot) CAConfig() (*structs.CAConfiguration, error) {
 	c, err := s.tx.First(tableConnectCAConfig, "id")
@@ -469,6 +488,22 @@ func (s *Store) CARootsAndConfig(ws memdb.WatchSet) (uint64, structs.CARoots, *s
 	return idx, roots, config, nil
 }
 
+// CAProviderSerialNumber returns the last serial number used by the built-in
+// CA provider, without incrementing it.
+func (s *Store) CAProviderSerialNumber() (uint64, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	existing, err := tx.First(tableIndex, "id", tableConnectCABuiltinSerial)
+	if err != nil {
+		return 0, fmt.Errorf("failed built-in CA serial number lookup: %s", err)
+	}
+	if existing != nil {
+		return existing.(*IndexEntry).Value, nil
+	}
+	return maxIndexTxn(tx, tableConnectCABuiltin), nil
+}
+
 func (s *Store) CAIncrementProviderSerialNumber(idx uint64) (uint64, error) {
 	tx := s.db.WriteTxn(idx)
 	defer tx.Abort()
@@ -495,3 +530,140 @@ func (s *Store) CAIncrementProviderSerialNumber(idx uint64) (uint64, error) {
 	err = tx.Commit()
 	return next, err
 }
+
+// CARevokedLeafs is used to pull the revoked leafs from the snapshot.
+func (s *Snapshot) CARevokedLeafs() ([]*structs.CARevokedLeaf, error) {
+	iter, err := s.tx.Get(tableConnectCARevokedLeafs, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*structs.CARevokedLeaf
+	for wrapped := iter.Next(); wrapped != nil; wrapped = iter.Next() {
+		ret = append(ret, wrapped.(*structs.CARevokedLeaf))
+	}
+
+	return ret, nil
+}
+
+// CARevokedLeaf is used when restoring from a snapshot.
+func (s *Restore) CARevokedLeaf(r *structs.CARevokedLeaf) error {
+	if err := s.tx.Insert(tableConnectCARevokedLeafs, r); err != nil {
+		return fmt.Errorf("failed restoring revoked leaf: %s", err)
+	}
+	if err := indexUpdateMaxTxn(s.tx, r.ModifyIndex, tableConnectCARevokedLeafs); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	return nil
+}
+
+// CARevokedLeafs returns the list of revoked leaf certificates.
+func (s *Store) CARevokedLeafs(ws memdb.WatchSet) (uint64, []*structs.CARevokedLeaf, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	return caRevokedLeafsTxn(tx, ws)
+}
+
+func caRevokedLeafsTxn(tx ReadTxn, ws memdb.WatchSet) (uint64, []*structs.CARevokedLeaf, error) {
+	// Get the index
+	idx := maxIndexTxn(tx, tableConnectCARevokedLeafs)
+
+	iter, err := tx.Get(tableConnectCARevokedLeafs, "id")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed revoked leaf lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var results []*structs.CARevokedLeaf
+	for v := iter.Next(); v != nil; v = iter.Next() {
+		results = append(results, v.(*structs.CARevokedLeaf))
+	}
+	return idx, results, nil
+}
+
+// CARevokeLeaf is used to add a revocation, or update the reason of an
+// existing one.
+func (s *Store) CARevokeLeaf(idx uint64, r *structs.CARevokedLeaf) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	if err := caRevokeLeafTxn(tx, idx, r); err != nil {
+		return err
+	}
+	if err := tx.Insert(tableIndex, &IndexEntry{tableConnectCARevokedLeafs, idx}); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	return tx.Commit()
+}
+
+func caRevokeLeafTxn(tx WriteTxn, idx uint64, r *structs.CARevokedLeaf) error {
+	if r.ID == "" {
+		return ErrMissingCARevokedLeafID
+	}
+
+	existing, err := tx.First(tableConnectCARevokedLeafs, "id", r.ID)
+	if err != nil {
+		return fmt.Errorf("failed revoked leaf lookup: %s", err)
+	}
+
+	// Copy so the caller's struct isn't modified, and set the indexes.
+	revoked := *r
+	if existing != nil {
+		revoked.CreateIndex = existing.(*structs.CARevokedLeaf).CreateIndex
+	} else {
+		revoked.CreateIndex = idx
+	}
+	revoked.ModifyIndex = idx
+
+	if err := tx.Insert(tableConnectCARevokedLeafs, &revoked); err != nil {
+		return fmt.Errorf("failed updating revoked leaf: %s", err)
+	}
+	return nil
+}
+
+// CADeleteRevokedLeaf is used to remove the revocation with the given ID.
+func (s *Store) CADeleteRevokedLeaf(idx uint64, id string) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	existing, err := tx.First(tableConnectCARevokedLeafs, "id", id)
+	if err != nil {
+		return fmt.Errorf("failed revoked leaf lookup: %s", err)
+	}
+	if existing == nil {
+		return nil
+	}
+
+	if err := tx.Delete(tableConnectCARevokedLeafs, existing); err != nil {
+		return err
+	}
+	if err := tx.Insert(tableIndex, &IndexEntry{tableConnectCARevokedLeafs, idx}); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	return tx.Commit()
+}
+
+// CASetRevokedLeafs replaces all the revocations with the given ones. It is
+// used by secondary datacenters to replicate the revocations of the primary.
+func (s *Store) CASetRevokedLeafs(idx uint64, rs []*structs.CARevokedLeaf) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	if _, err := tx.DeleteAll(tableConnectCARevokedLeafs, "id"); err != nil {
+		return err
+	}
+	for _, r := range rs {
+		if err := caRevokeLeafTxn(tx, idx, r); err != nil {
+			return err
+		}
+	}
+	if err := tx.Insert(tableIndex, &IndexEntry{tableConnectCARevokedLeafs, idx}); err != nil {
+		return fmt.Errorf("failed updating index: %s", err)
+	}
+
+	return tx.Commit()
+}
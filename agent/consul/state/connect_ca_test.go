@@ -492,3 +492,101 @@ func TestStore_CABuiltinProvider_Snapshot_Restore(t *testing.T) {
 		assert.Equal(t, state, res)
 	}
 }
+
+func TestStore_CARevokedLeafs(t *testing.T) {
+	s := testStateStore(t)
+
+	// Call list to populate the watch set
+	ws := memdb.NewWatchSet()
+	idx, revoked, err := s.CARevokedLeafs(ws)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), idx)
+	require.Empty(t, revoked)
+
+	// An ID is required.
+	err = s.CARevokeLeaf(1, &structs.CARevokedLeaf{SerialNumber: "01"})
+	require.Equal(t, ErrMissingCARevokedLeafID, err)
+
+	bySerial := &structs.CARevokedLeaf{ID: "0a:0b", SerialNumber: "0a:0b", Reason: "key compromise"}
+	require.NoError(t, s.CARevokeLeaf(2, bySerial))
+	require.True(t, watchFired(ws))
+	require.Equal(t, structs.RaftIndex{}, bySerial.RaftIndex, "input should not be modified")
+
+	spiffeID := "spiffe://11111111-2222-3333-4444-555555555555.consul/ns/default/dc/dc1/svc/web"
+	require.NoError(t, s.CARevokeLeaf(3, &structs.CARevokedLeaf{ID: spiffeID, SpiffeID: spiffeID}))
+
+	// Updating keeps the create index.
+	bySerial.Reason = "superseded"
+	require.NoError(t, s.CARevokeLeaf(4, bySerial))
+
+	ws = memdb.NewWatchSet()
+	idx, revoked, err = s.CARevokedLeafs(ws)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), idx)
+	require.Len(t, revoked, 2)
+	require.Equal(t, "superseded", revoked[0].Reason)
+	require.Equal(t, structs.RaftIndex{CreateIndex: 2, ModifyIndex: 4}, revoked[0].RaftIndex)
+	require.Equal(t, spiffeID, revoked[1].SpiffeID)
+
+	// Deleting a missing revocation is a no-op.
+	require.NoError(t, s.CADeleteRevokedLeaf(5, "ff"))
+	require.False(t, watchFired(ws))
+
+	require.NoError(t, s.CADeleteRevokedLeaf(6, "0a:0b"))
+	require.True(t, watchFired(ws))
+
+	idx, revoked, err = s.CARevokedLeafs(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), idx)
+	require.Len(t, revoked, 1)
+	require.Equal(t, spiffeID, revoked[0].ID)
+
+	// Setting replaces all the revocations.
+	require.NoError(t, s.CASetRevokedLeafs(7, []*structs.CARevokedLeaf{
+		{ID: "01", SerialNumber: "01"},
+		{ID: "02", SerialNumber: "02"},
+	}))
+	idx, revoked, err = s.CARevokedLeafs(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), idx)
+	require.Len(t, revoked, 2)
+	require.Equal(t, "01", revoked[0].ID)
+	require.Equal(t, "02", revoked[1].ID)
+}
+
+func TestStore_CARevokedLeafs_Snapshot_Restore(t *testing.T) {
+	s := testStateStore(t)
+
+	before := []*structs.CARevokedLeaf{
+		{ID: "01", SerialNumber: "01", Reason: "a"},
+		{ID: "02", SerialNumber: "02", Reason: "b"},
+	}
+	for i, r := range before {
+		require.NoError(t, s.CARevokeLeaf(uint64(98+i), r))
+	}
+
+	// Take a snapshot.
+	snap := s.Snapshot()
+	defer snap.Close()
+
+	// Modify the state store.
+	require.NoError(t, s.CADeleteRevokedLeaf(100, "01"))
+
+	snapped, err := snap.CARevokedLeafs()
+	require.NoError(t, err)
+	require.Len(t, snapped, 2)
+
+	// Restore onto a new state store.
+	s2 := testStateStore(t)
+	restore := s2.Restore()
+	for _, entry := range snapped {
+		require.NoError(t, restore.CARevokedLeaf(entry))
+	}
+	restore.Commit()
+
+	// Verify the restored values match those from before the snapshot.
+	idx, res, err := s2.CARevokedLeafs(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), idx)
+	require.Equal(t, snapped, res)
+}
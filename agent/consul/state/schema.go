@@ -17,6 +17,7 @@ func newDBSchema() *memdb.DBSchema {
 		bindingRulesTableSchema,
 		caBuiltinProviderTableSchema,
 		caConfigTableSchema,
+		caRevokedLeafTableSchema,
 		caRootTableSchema,
 		checksTableSchema,
 		configTableSchema,
@@ -77,6 +77,10 @@ var (
 	// with an CARoot with an empty ID.
 	ErrMissingCARootID = errors.New("Missing CA Root ID")
 
+	// ErrMissingCARevokedLeafID is returned when a revoked leaf is set
+	// with an empty ID.
+	ErrMissingCARevokedLeafID = errors.New("Missing CA Revoked Leaf ID")
+
 	// ErrMissingIntentionID is returned when an Intention set is called
 	// with an Intention with an empty ID.
 	ErrMissingIntentionID = errors.New("Missing Intention ID")
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"net"
 	"regexp"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -100,6 +102,9 @@ type dnsConfig struct {
 	// TTLStict sets TTLs to service by full name match. It Has higher priority than TTLRadix
 	TTLStrict          map[string]time.Duration
 	DisableCompression bool
+	// ZoneTransferKeys are the TSIG keys allowed to transfer the zone,
+	// indexed by their name.
+	ZoneTransferKeys map[string]config.RuntimeDNSTSIGKey
 
 	enterpriseDNSConfig
 }
@@ -112,6 +117,8 @@ type serviceLookup struct {
 	MaxRecursionLevel int
 	Connect           bool
 	Ingress           bool
+	// Token overrides the agent's default token for the lookup.
+	Token string
 	acl.EnterpriseMeta
 }
 
@@ -141,6 +148,17 @@ type DNSServer struct {
 	// the recursor handler is only enabled if recursors are configured. This flag is used during config hot-reloading
 	recursorEnabled uint32
 
+	// xfrRequests holds the raw bytes of the zone transfer requests read
+	// over TCP, keyed by the remote address of the connection, so that their
+	// TSIG signature can be verified against the current config.
+	xfrRequests sync.Map
+
+	// zoneSerialLock guards the zone serial cached for SOA queries.
+	zoneSerialLock    sync.Mutex
+	zoneSerialValue   uint32
+	zoneSerialErr     error
+	zoneSerialFetched time.Time
+
 	defaultEnterpriseMeta acl.EnterpriseMeta
 }
 
@@ -219,6 +237,12 @@ func GetDNSConfig(conf *config.RuntimeConfig) (*dnsConfig, error) {
 			}
 		}
 	}
+	if len(conf.DNSZoneTransferKeys) > 0 {
+		cfg.ZoneTransferKeys = make(map[string]config.RuntimeDNSTSIGKey, len(conf.DNSZoneTransferKeys))
+		for _, k := range conf.DNSZoneTransferKeys {
+			cfg.ZoneTransferKeys[k.Name] = k
+		}
+	}
 	for _, r := range conf.DNSRecursors {
 		ra, err := recursorAddr(r)
 		if err != nil {
@@ -252,11 +276,28 @@ func (d *DNSServer) ListenAndServe(network, addr string, notif func()) error {
 	d.Server = &dns.Server{
 		Addr:              addr,
 		Net:               network,
-		Handler:           d.mux,
+		Handler:           dns.HandlerFunc(d.serveDNS),
 		NotifyStartedFunc: notif,
 	}
 	if network == "udp" {
 		d.UDPSize = 65535
+	} else {
+		d.DecorateReader = d.decorateXFRReader
+	}
+	return d.Server.ListenAndServe()
+}
+
+// ListenAndServeTLS serves DNS over TLS (RFC 7858) on the given TCP address.
+// The tls.Config is consulted on every handshake so certificate rotations
+// take effect without restarting the listener.
+func (d *DNSServer) ListenAndServeTLS(addr string, tlsConfig *tls.Config, notif func()) error {
+	d.Server = &dns.Server{
+		Addr:              addr,
+		Net:               "tcp-tls",
+		TLSConfig:         tlsConfig,
+		Handler:           dns.HandlerFunc(d.serveDNS),
+		NotifyStartedFunc: notif,
+		DecorateReader:    d.decorateXFRReader,
 	}
 	return d.Server.ListenAndServe()
 }
@@ -522,7 +563,7 @@ func (d *DNSServer) handleQuery(resp dns.ResponseWriter, req *dns.Msg) {
 	switch req.Question[0].Qtype {
 	case dns.TypeSOA:
 		ns, glue := d.nameservers(req.Question[0].Name, cfg, maxRecursionLevelDefault)
-		m.Answer = append(m.Answer, d.soa(cfg, q.Name))
+		m.Answer = append(m.Answer, d.zoneSOA(cfg, q.Name))
 		m.Ns = append(m.Ns, ns...)
 		m.Extra = append(m.Extra, glue...)
 		m.SetRcode(req, dns.RcodeSuccess)
@@ -533,8 +574,13 @@ func (d *DNSServer) handleQuery(resp dns.ResponseWriter, req *dns.Msg) {
 		m.Extra = glue
 		m.SetRcode(req, dns.RcodeSuccess)
 
-	case dns.TypeAXFR:
-		m.SetRcode(req, dns.RcodeNotImplemented)
+	case dns.TypeAXFR, dns.TypeIXFR:
+		if len(cfg.ZoneTransferKeys) == 0 {
+			m.SetRcode(req, dns.RcodeNotImplemented)
+			break
+		}
+		d.handleZoneTransfer(cfg, network, resp, req)
+		return
 
 	default:
 		err = d.dispatch(resp.RemoteAddr(), req, m, maxRecursionLevelDefault)
@@ -1404,6 +1450,10 @@ func (d *DNSServer) lookupServiceNodes(cfg *dnsConfig, lookup serviceLookup) (st
 	if lookup.Tag != "" {
 		serviceTags = []string{lookup.Tag}
 	}
+	token := lookup.Token
+	if token == "" {
+		token = d.agent.tokens.UserToken()
+	}
 	args := structs.ServiceSpecificRequest{
 		PeerName:    lookup.PeerName,
 		Connect:     lookup.Connect,
@@ -1413,7 +1463,7 @@ func (d *DNSServer) lookupServiceNodes(cfg *dnsConfig, lookup serviceLookup) (st
 		ServiceTags: serviceTags,
 		TagFilter:   lookup.Tag != "",
 		QueryOptions: structs.QueryOptions{
-			Token:            d.agent.tokens.UserToken(),
+			Token:            token,
 			AllowStale:       cfg.AllowStale,
 			MaxAge:           cfg.CacheMaxAge,
 			UseCache:         cfg.UseCache,
@@ -1499,7 +1549,7 @@ func (d *DNSServer) preparedQueryLookup(cfg *dnsConfig, datacenter, query string
 		// Always pass the local agent through. In the DNS interface, there
 		// is no provision for passing additional query parameters, so we
 		// send the local agent's data through to allow distance sorting
-		// relative to ourself on the server side.
+		// and locality ordering relative to ourself on the server side.
 		Agent: structs.QuerySource{
 			Datacenter:    d.agent.config.Datacenter,
 			Segment:       d.agent.config.SegmentName,
@@ -0,0 +1,195 @@
+package agent
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// dnsOverHTTPSPath is the path DNS over HTTPS queries are served on.
+	dnsOverHTTPSPath = "/dns-query"
+
+	// dnsMessageContentType is the media type of DNS over HTTPS requests and
+	// responses.
+	dnsMessageContentType = "application/dns-message"
+
+	// maxDNSOverHTTPSMessageSize is the largest DNS message accepted in a
+	// DNS over HTTPS request.
+	maxDNSOverHTTPSMessageSize = dns.MaxMsgSize
+)
+
+// dnsOverHTTPSHandler returns the http.Handler serving DNS over HTTPS
+// (RFC 8484) queries with the handlers of the DNS server.
+func (d *DNSServer) dnsOverHTTPSHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(dnsOverHTTPSPath, d.serveDNSOverHTTPS)
+	return mux
+}
+
+// serveDNSOverHTTPS decodes a DNS query sent with either the GET or POST
+// method, dispatches it to the DNS handlers and writes the DNS response.
+func (d *DNSServer) serveDNSOverHTTPS(w http.ResponseWriter, r *http.Request) {
+	var buf []byte
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query().Get("dns")
+		if q == "" {
+			http.Error(w, "Missing dns query parameter", http.StatusBadRequest)
+			return
+		}
+		var err error
+		buf, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(q, "="))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid dns query parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+
+	case http.MethodPost:
+		if ct := r.Header.Get("Content-Type"); ct != dnsMessageContentType {
+			http.Error(w, fmt.Sprintf("Unsupported Content-Type %q", ct), http.StatusUnsupportedMediaType)
+			return
+		}
+		var err error
+		buf, err = io.ReadAll(io.LimitReader(r.Body, maxDNSOverHTTPSMessageSize+1))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed reading request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(buf) > maxDNSOverHTTPSMessageSize {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := new(dns.Msg)
+	if err := req.Unpack(buf); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid DNS message: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// The handlers only truncate responses sent over UDP, so both addresses
+	// are reported as TCP since HTTPS runs over a stream.
+	resp := &dohResponseWriter{
+		localAddr:  dohAddr(r.Context().Value(http.LocalAddrContextKey)),
+		remoteAddr: dohAddr(r.RemoteAddr),
+	}
+	d.mux.ServeDNS(resp, req)
+	if resp.msg == nil {
+		http.Error(w, "No DNS response", http.StatusInternalServerError)
+		return
+	}
+
+	out, err := resp.msg.Pack()
+	if err != nil {
+		d.logger.Error("failed to pack DNS over HTTPS response", "error", err)
+		http.Error(w, "Failed packing DNS response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", dnsMessageContentType)
+	if ttl, ok := minTTL(resp.msg); ok {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", ttl))
+	}
+	w.Write(out)
+}
+
+// minTTL returns the smallest TTL of the records in the response, which
+// RFC 8484 recommends as the freshness lifetime of the HTTP response.
+func minTTL(m *dns.Msg) (uint32, bool) {
+	ttl := uint32(math.MaxUint32)
+	found := false
+	for _, sec := range [][]dns.RR{m.Answer, m.Ns, m.Extra} {
+		for _, rr := range sec {
+			if rr.Header().Rrtype == dns.TypeOPT {
+				continue
+			}
+			found = true
+			if t := rr.Header().Ttl; t < ttl {
+				ttl = t
+			}
+		}
+	}
+	return ttl, found
+}
+
+// dohAddr converts the address of an HTTP connection to a *net.TCPAddr.
+// Addresses which are not IP based, such as UNIX sockets, result in an empty
+// *net.TCPAddr.
+func dohAddr(v interface{}) net.Addr {
+	var s string
+	switch addr := v.(type) {
+	case *net.TCPAddr:
+		return addr
+	case net.Addr:
+		s = addr.String()
+	case string:
+		s = addr
+	}
+	if tcpAddr, err := net.ResolveTCPAddr("tcp", s); err == nil {
+		return tcpAddr
+	}
+	return &net.TCPAddr{}
+}
+
+// dohResponseWriter is a dns.ResponseWriter which captures the response to a
+// DNS over HTTPS query so it can be written to the HTTP response.
+type dohResponseWriter struct {
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	msg        *dns.Msg
+}
+
+// LocalAddr returns the net.Addr of the server.
+func (w *dohResponseWriter) LocalAddr() net.Addr {
+	return w.localAddr
+}
+
+// RemoteAddr returns the net.Addr of the client that sent the current request.
+func (w *dohResponseWriter) RemoteAddr() net.Addr {
+	return w.remoteAddr
+}
+
+// WriteMsg captures the response.
+func (w *dohResponseWriter) WriteMsg(m *dns.Msg) error {
+	w.msg = m
+	return nil
+}
+
+// Write captures a packed response.
+func (w *dohResponseWriter) Write(b []byte) (int, error) {
+	m := new(dns.Msg)
+	if err := m.Unpack(b); err != nil {
+		return 0, err
+	}
+	w.msg = m
+	return len(b), nil
+}
+
+// Close is a no-op since the HTTP server owns the connection.
+func (w *dohResponseWriter) Close() error {
+	return nil
+}
+
+// TsigStatus reports that TSIG signatures can't be verified since the raw
+// request is not available to the DNS handlers. This prevents zone transfers
+// over HTTPS.
+func (w *dohResponseWriter) TsigStatus() error {
+	return dns.ErrSecret
+}
+
+// TsigTimersOnly sets the tsig timers only boolean.
+func (w *dohResponseWriter) TsigTimersOnly(bool) {}
+
+// Hijack is a no-op since the HTTP server owns the connection.
+func (w *dohResponseWriter) Hijack() {}
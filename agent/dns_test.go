@@ -1,14 +1,20 @@
 package agent
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
 	"net"
+	"net/http"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -23,6 +29,7 @@ import (
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/sdk/freeport"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 )
@@ -3852,6 +3859,85 @@ func TestDNS_PreparedQueryNearIP(t *testing.T) {
 	})
 }
 
+func TestDNS_PreparedQueryLocality(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, `
+		node_meta {
+			zone = "a"
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// Register nodes with a service, only one of them in the agent's zone
+	for i, zone := range []string{"b", "a", "c"} {
+		args := &structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       fmt.Sprintf("foo%d", i+1),
+			Address:    fmt.Sprintf("198.18.0.%d", i+1),
+			NodeMeta:   map[string]string{"zone": zone},
+			Service: &structs.NodeService{
+				Service: "db",
+				Port:    12345,
+			},
+		}
+
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+	}
+
+	// Register a prepared query ordering by zone
+	{
+		args := &structs.PreparedQueryRequest{
+			Datacenter: "dc1",
+			Op:         structs.PreparedQueryCreate,
+			Query: &structs.PreparedQuery{
+				Name: "some.query.we.like",
+				Service: structs.ServiceQuery{
+					Service: "db",
+					Locality: structs.QueryLocalityOptions{
+						NodeMetaKeys: []string{"zone"},
+					},
+				},
+			},
+		}
+
+		var id string
+		require.NoError(t, a.RPC(context.Background(), "PreparedQuery.Apply", args, &id))
+	}
+
+	// The agent's node metadata reaches the catalog with anti-entropy, so
+	// retry until the node in the same zone is always first.
+	retry.Run(t, func(r *retry.R) {
+		for i := 0; i < 10; i++ {
+			m := new(dns.Msg)
+			m.SetQuestion("some.query.we.like.query.consul.", dns.TypeA)
+
+			c := new(dns.Client)
+			in, _, err := c.Exchange(m, a.DNSAddr())
+			if err != nil {
+				r.Fatalf("Error with call to dns.Client.Exchange: %s", err)
+			}
+
+			if len(in.Answer) != 3 {
+				r.Fatalf("Expecting 3 A RRs in response, Actual found was %d", len(in.Answer))
+			}
+
+			aRec, ok := in.Answer[0].(*dns.A)
+			if !ok {
+				r.Fatalf("DNS Answer contained a non-A RR")
+			}
+			if actual := aRec.A.String(); actual != "198.18.0.2" {
+				r.Fatalf("Expecting first A RR = 198.18.0.2, Actual RR was %s", actual)
+			}
+		}
+	})
+}
+
 func TestDNS_ServiceLookup_PreparedQueryNamePeriod(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -8355,3 +8441,370 @@ func TestPerfectlyRandomChoices(t *testing.T) {
 		})
 	}
 }
+
+func TestDNS_Over_TLS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	port := freeport.GetOne(t)
+	a := NewTestAgent(t, `
+		ports {
+			dns_tls = `+strconv.Itoa(port)+`
+		}
+		tls {
+			https {
+				cert_file = "../test/key/ourdomain.cer"
+				key_file = "../test/key/ourdomain.key"
+			}
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	m := new(dns.Msg)
+	m.SetQuestion(a.Config.NodeName+".node.consul.", dns.TypeA)
+
+	c := &dns.Client{
+		Net:       "tcp-tls",
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	in, _, err := c.Exchange(m, fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(t, err)
+	require.Len(t, in.Answer, 1)
+	aRec, ok := in.Answer[0].(*dns.A)
+	require.True(t, ok, "Answer is not an A record")
+	require.Equal(t, "127.0.0.1", aRec.A.String())
+}
+
+func TestDNS_Over_HTTPS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	port := freeport.GetOne(t)
+	a := NewTestAgent(t, `
+		ports {
+			dns_https = `+strconv.Itoa(port)+`
+		}
+		tls {
+			https {
+				cert_file = "../test/key/ourdomain.cer"
+				key_file = "../test/key/ourdomain.key"
+			}
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	url := fmt.Sprintf("https://127.0.0.1:%d/dns-query", port)
+
+	m := new(dns.Msg)
+	m.SetQuestion(a.Config.NodeName+".node.consul.", dns.TypeA)
+	m.Id = 0
+	buf, err := m.Pack()
+	require.NoError(t, err)
+
+	readMsg := func(t *testing.T, resp *http.Response) *dns.Msg {
+		t.Helper()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "application/dns-message", resp.Header.Get("Content-Type"))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		in := new(dns.Msg)
+		require.NoError(t, in.Unpack(body))
+		return in
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		resp, err := client.Get(url + "?dns=" + base64.RawURLEncoding.EncodeToString(buf))
+		require.NoError(t, err)
+		in := readMsg(t, resp)
+		require.Len(t, in.Answer, 1)
+		aRec, ok := in.Answer[0].(*dns.A)
+		require.True(t, ok, "Answer is not an A record")
+		require.Equal(t, "127.0.0.1", aRec.A.String())
+		require.Equal(t, "max-age=0", resp.Header.Get("Cache-Control"))
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		resp, err := client.Post(url, "application/dns-message", bytes.NewReader(buf))
+		require.NoError(t, err)
+		in := readMsg(t, resp)
+		require.Len(t, in.Answer, 1)
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = client.Post(url, "text/plain", bytes.NewReader(buf))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+		resp, err = client.Post(url, "application/dns-message", strings.NewReader("nope"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(buf))
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
+
+func TestDNS_ZoneTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	const (
+		keyName = "xfr."
+		secret  = "c2VjcmV0LWtleS1mb3ItenQ="
+	)
+	a := NewTestAgent(t, `
+		dns_config {
+			zone_transfer_keys = [{
+				name = "xfr"
+				secret = "`+secret+`"
+			}]
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	args := &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.2",
+		Service: &structs.NodeService{
+			Service: "db",
+			Tags:    []string{"Primary"},
+			Port:    12345,
+		},
+	}
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+
+	transferWith := func(t *testing.T, m *dns.Msg, keyName, secret string) []dns.RR {
+		t.Helper()
+		return zoneTransfer(t, a.DNSAddr(), m, keyName, secret)
+	}
+	transfer := func(t *testing.T, m *dns.Msg) []dns.RR {
+		t.Helper()
+		return transferWith(t, m, keyName, secret)
+	}
+	refused := func(t *testing.T, keyName, secret string) {
+		t.Helper()
+		m := new(dns.Msg)
+		m.SetAxfr("consul.")
+		m.SetTsig(keyName, dns.HmacSHA256, 300, time.Now().Unix())
+		c := &dns.Client{Net: "tcp", TsigSecret: map[string]string{keyName: secret}}
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		require.Equal(t, dns.RcodeNotAuth, in.Rcode)
+		require.Nil(t, in.IsTsig())
+	}
+
+	var serial uint32
+	t.Run("AXFR", func(t *testing.T) {
+		m := new(dns.Msg)
+		m.SetAxfr("consul.")
+		rrs := transfer(t, m)
+		require.True(t, len(rrs) > 2)
+
+		first, ok := rrs[0].(*dns.SOA)
+		require.True(t, ok, "first record is not an SOA")
+		last, ok := rrs[len(rrs)-1].(*dns.SOA)
+		require.True(t, ok, "last record is not an SOA")
+		require.Equal(t, first.Serial, last.Serial)
+		serial = first.Serial
+
+		var records []string
+		for _, rr := range rrs[1 : len(rrs)-1] {
+			h := rr.Header()
+			records = append(records, h.Name+" "+dns.Type(h.Rrtype).String())
+		}
+		for _, want := range []string{
+			"foo.node.dc1.consul. A",
+			"foo.node.consul. A",
+			"db.service.consul. A",
+			"db.service.dc1.consul. SRV",
+			"primary.db.service.consul. A",
+			"_db._tcp.service.consul. SRV",
+			"_db._primary.service.dc1.consul. SRV",
+		} {
+			require.Contains(t, records, want)
+		}
+	})
+
+	t.Run("SOA query returns the zone serial", func(t *testing.T) {
+		m := new(dns.Msg)
+		m.SetQuestion("consul.", dns.TypeSOA)
+		in, err := dns.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		require.Len(t, in.Answer, 1)
+		require.Equal(t, serial, in.Answer[0].(*dns.SOA).Serial)
+	})
+
+	t.Run("IXFR when up to date", func(t *testing.T) {
+		m := new(dns.Msg)
+		m.SetIxfr("consul.", serial, "ns.consul.", "hostmaster.consul.")
+		rrs := transfer(t, m)
+		require.Len(t, rrs, 1)
+		require.Equal(t, serial, rrs[0].(*dns.SOA).Serial)
+	})
+
+	t.Run("unsigned request is refused", func(t *testing.T) {
+		m := new(dns.Msg)
+		m.SetAxfr("consul.")
+		c := &dns.Client{Net: "tcp"}
+		in, _, err := c.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		require.Equal(t, dns.RcodeRefused, in.Rcode)
+	})
+
+	t.Run("UDP is refused", func(t *testing.T) {
+		m := new(dns.Msg)
+		m.SetAxfr("consul.")
+		in, err := dns.Exchange(m, a.DNSAddr())
+		require.NoError(t, err)
+		require.Equal(t, dns.RcodeRefused, in.Rcode)
+	})
+
+	t.Run("unknown key is refused", func(t *testing.T) {
+		refused(t, "other.", secret)
+	})
+
+	t.Run("bad signature is refused", func(t *testing.T) {
+		refused(t, keyName, "b3RoZXItc2VjcmV0LWtleQ==")
+	})
+
+	t.Run("keys are reloaded", func(t *testing.T) {
+		const (
+			rotated   = "cm90YXRlZC1zZWNyZXQta2V5"
+			addedName = "added."
+			added     = "YWRkZWQtc2VjcmV0LWtleQ=="
+		)
+		newCfg := *a.Config
+		newCfg.DNSZoneTransferKeys = []config.RuntimeDNSTSIGKey{
+			{Name: keyName, Algorithm: dns.HmacSHA256, Secret: rotated},
+			{Name: addedName, Algorithm: dns.HmacSHA256, Secret: added},
+		}
+		require.NoError(t, a.reloadConfigInternal(&newCfg))
+
+		refused(t, keyName, secret)
+
+		m := new(dns.Msg)
+		m.SetAxfr("consul.")
+		require.True(t, len(transferWith(t, m, keyName, rotated)) > 2)
+
+		m = new(dns.Msg)
+		m.SetAxfr("consul.")
+		require.True(t, len(transferWith(t, m, addedName, added)) > 2)
+	})
+}
+
+func TestDNS_ZoneTransfer_Token(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	const secret = "c2VjcmV0LWtleS1mb3ItenQ="
+	a := NewTestAgent(t, `
+		primary_datacenter = "dc1"
+
+		acl {
+			enabled = true
+			default_policy = "deny"
+			down_policy = "deny"
+
+			tokens {
+				initial_management = "root"
+			}
+		}
+
+		dns_config {
+			zone_transfer_keys = [
+				{
+					name = "anonymous"
+					secret = "`+secret+`"
+				},
+				{
+					name = "ops"
+					secret = "`+secret+`"
+					token = "root"
+				},
+			]
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	args := &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.2",
+		Service: &structs.NodeService{
+			Service: "db",
+			Port:    12345,
+		},
+		WriteRequest: structs.WriteRequest{Token: "root"},
+	}
+	var out struct{}
+	require.NoError(t, a.RPC(context.Background(), "Catalog.Register", args, &out))
+
+	names := func(rrs []dns.RR) []string {
+		var names []string
+		for _, rr := range rrs {
+			names = append(names, rr.Header().Name)
+		}
+		return names
+	}
+
+	// Without a token mapped to the key, the transfer is served with the
+	// agent's token, which can't read the catalog.
+	m := new(dns.Msg)
+	m.SetAxfr("consul.")
+	rrs := zoneTransfer(t, a.DNSAddr(), m, "anonymous.", secret)
+	require.NotContains(t, names(rrs), "foo.node.consul.")
+	require.NotContains(t, names(rrs), "db.service.consul.")
+
+	m = new(dns.Msg)
+	m.SetAxfr("consul.")
+	rrs = zoneTransfer(t, a.DNSAddr(), m, "ops.", secret)
+	require.Contains(t, names(rrs), "foo.node.consul.")
+	require.Contains(t, names(rrs), "db.service.consul.")
+}
+
+// zoneTransfer transfers the zone from the server at addr with a request
+// signed with the given TSIG key and returns the records received.
+func zoneTransfer(t *testing.T, addr string, m *dns.Msg, keyName, secret string) []dns.RR {
+	t.Helper()
+	m.SetTsig(keyName, dns.HmacSHA256, 300, time.Now().Unix())
+	tr := &dns.Transfer{TsigSecret: map[string]string{keyName: secret}}
+	env, err := tr.In(m, addr)
+	require.NoError(t, err)
+	var rrs []dns.RR
+	for e := range env {
+		require.NoError(t, e.Error)
+		rrs = append(rrs, e.RR...)
+	}
+	return rrs
+}
@@ -0,0 +1,460 @@
+package agent
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	agentdns "github.com/hashicorp/consul/agent/dns"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+// zoneTransferChunkSize is the number of records sent in each message of a
+// zone transfer, which keeps messages well below the 64k limit of DNS over
+// TCP.
+const zoneTransferChunkSize = 200
+
+// zoneSerialCacheTTL is how long the zone serial returned by SOA queries is
+// cached, so that SOA queries, which don't need to be signed, can't be used
+// to make many catalog requests.
+const zoneSerialCacheTTL = time.Second
+
+// xfrReader is a dns.Reader keeping the raw bytes of the zone transfer
+// requests read over TCP. dns.Server only verifies TSIG signatures with the
+// secrets it was started with, so they are verified by handleZoneTransfer
+// against the current config instead, which requires the raw request.
+type xfrReader struct {
+	dns.Reader
+	d *DNSServer
+}
+
+func (d *DNSServer) decorateXFRReader(r dns.Reader) dns.Reader {
+	return xfrReader{Reader: r, d: d}
+}
+
+func (r xfrReader) ReadTCP(conn net.Conn, timeout time.Duration) ([]byte, error) {
+	raw, err := r.Reader.ReadTCP(conn, timeout)
+	if err != nil || len(raw) < 12 {
+		return raw, err
+	}
+
+	// Only keep the requests the server passes on to the handler, which drops
+	// them in serveDNS.
+	h := dns.Header{
+		Id:      binary.BigEndian.Uint16(raw[0:]),
+		Bits:    binary.BigEndian.Uint16(raw[2:]),
+		Qdcount: binary.BigEndian.Uint16(raw[4:]),
+		Ancount: binary.BigEndian.Uint16(raw[6:]),
+		Nscount: binary.BigEndian.Uint16(raw[8:]),
+		Arcount: binary.BigEndian.Uint16(raw[10:]),
+	}
+	if dns.DefaultMsgAcceptFunc(h) != dns.MsgAccept {
+		return raw, nil
+	}
+	m := new(dns.Msg)
+	if err := m.Unpack(raw); err != nil {
+		return raw, nil
+	}
+	if qtype := m.Question[0].Qtype; qtype == dns.TypeAXFR || qtype == dns.TypeIXFR {
+		r.d.xfrRequests.Store(conn.RemoteAddr().String(), raw)
+	}
+	return raw, nil
+}
+
+// serveDNS dispatches the request to the handler of its domain and drops the
+// raw request kept for it by xfrReader. The server serves the messages of a
+// connection one at a time, so the remote address identifies the request.
+func (d *DNSServer) serveDNS(resp dns.ResponseWriter, req *dns.Msg) {
+	defer d.xfrRequests.Delete(resp.RemoteAddr().String())
+	d.mux.ServeDNS(resp, req)
+}
+
+// zoneSOA returns the SOA record answering an SOA query. When zone transfers
+// are enabled the serial of the zone apex is derived from the Raft index of
+// the catalog so that secondaries only transfer the zone when it changed.
+func (d *DNSServer) zoneSOA(cfg *dnsConfig, questionName string) *dns.SOA {
+	soa := d.soa(cfg, questionName)
+	if len(cfg.ZoneTransferKeys) == 0 || !strings.EqualFold(questionName, soa.Hdr.Name) {
+		return soa
+	}
+	serial, err := d.cachedZoneSerial(cfg)
+	if err != nil {
+		d.logger.Warn("Unable to determine zone serial", "error", err)
+		return soa
+	}
+	soa.Serial = serial
+	return soa
+}
+
+// cachedZoneSerial returns the serial of the zone as seen with the agent's
+// token, which is fetched at most once every zoneSerialCacheTTL.
+func (d *DNSServer) cachedZoneSerial(cfg *dnsConfig) (uint32, error) {
+	d.zoneSerialLock.Lock()
+	defer d.zoneSerialLock.Unlock()
+
+	if time.Since(d.zoneSerialFetched) >= zoneSerialCacheTTL {
+		d.zoneSerialValue, d.zoneSerialErr = d.zoneSerial(cfg, d.agent.tokens.UserToken())
+		d.zoneSerialFetched = time.Now()
+	}
+	return d.zoneSerialValue, d.zoneSerialErr
+}
+
+// zoneSerial returns the serial of the zone, which is the highest Raft index
+// of the node, service and check tables truncated to 32 bits.
+func (d *DNSServer) zoneSerial(cfg *dnsConfig, token string) (uint32, error) {
+	args := structs.DCSpecificRequest{
+		Datacenter:     d.agent.config.Datacenter,
+		QueryOptions:   structs.QueryOptions{Token: token, AllowStale: cfg.AllowStale},
+		EnterpriseMeta: d.defaultEnterpriseMeta,
+	}
+	var nodes structs.IndexedNodes
+	if err := d.agent.RPC(context.Background(), "Catalog.ListNodes", &args, &nodes); err != nil {
+		return 0, err
+	}
+	var services structs.IndexedServices
+	if err := d.agent.RPC(context.Background(), "Catalog.ListServices", &args, &services); err != nil {
+		return 0, err
+	}
+	checksArgs := structs.ChecksInStateRequest{
+		Datacenter:     args.Datacenter,
+		State:          api.HealthCritical,
+		QueryOptions:   args.QueryOptions,
+		EnterpriseMeta: args.EnterpriseMeta,
+	}
+	var checks structs.IndexedHealthChecks
+	if err := d.agent.RPC(context.Background(), "Health.ChecksInState", &checksArgs, &checks); err != nil {
+		return 0, err
+	}
+
+	index := nodes.Index
+	if services.Index > index {
+		index = services.Index
+	}
+	if checks.Index > index {
+		index = checks.Index
+	}
+	return uint32(index), nil
+}
+
+// handleZoneTransfer answers AXFR and IXFR requests for the zone apex. A
+// transfer must be signed with one of the configured TSIG keys and is served
+// with the permissions of the token mapped to that key. Since the history of
+// the catalog isn't kept, an IXFR from an older serial is answered with the
+// full zone as allowed by RFC 1995.
+func (d *DNSServer) handleZoneTransfer(cfg *dnsConfig, network string, resp dns.ResponseWriter, req *dns.Msg) {
+	q := req.Question[0]
+	refuse := func(rcode int, reason string) {
+		d.logger.Warn("Refused zone transfer",
+			"zone", q.Name,
+			"type", dns.Type(q.Qtype),
+			"client", resp.RemoteAddr().String(),
+			"reason", reason,
+		)
+		m := new(dns.Msg)
+		m.SetRcode(req, rcode)
+		if err := resp.WriteMsg(m); err != nil {
+			d.logger.Warn("failed to respond", "error", err)
+		}
+	}
+
+	// Zone transfers span several messages so they are only possible over
+	// TCP.
+	if network != "tcp" {
+		refuse(dns.RcodeRefused, "zone transfers require TCP")
+		return
+	}
+
+	tsig := req.IsTsig()
+	if tsig == nil {
+		refuse(dns.RcodeRefused, "request is not signed with TSIG")
+		return
+	}
+	key, ok := cfg.ZoneTransferKeys[strings.ToLower(tsig.Hdr.Name)]
+	if !ok {
+		refuse(dns.RcodeNotAuth, fmt.Sprintf("unknown TSIG key %q", tsig.Hdr.Name))
+		return
+	}
+	if !strings.EqualFold(tsig.Algorithm, key.Algorithm) {
+		refuse(dns.RcodeNotAuth, fmt.Sprintf("TSIG algorithm %q does not match the key", tsig.Algorithm))
+		return
+	}
+	raw, ok := d.xfrRequests.Load(resp.RemoteAddr().String())
+	if !ok {
+		refuse(dns.RcodeServerFailure, "raw request not available")
+		return
+	}
+	if err := dns.TsigVerify(raw.([]byte), key.Secret, "", false); err != nil {
+		refuse(dns.RcodeNotAuth, fmt.Sprintf("invalid TSIG signature: %v", err))
+		return
+	}
+
+	zone := strings.ToLower(q.Name)
+	if zone != d.domain && zone != d.altDomain {
+		refuse(dns.RcodeNotAuth, "not the zone apex")
+		return
+	}
+
+	token := key.Token
+	if token == "" {
+		token = d.agent.tokens.UserToken()
+	}
+
+	serial, err := d.zoneSerial(cfg, token)
+	if err != nil {
+		d.logger.Warn("Unable to determine zone serial", "error", err)
+		refuse(dns.RcodeServerFailure, "catalog lookup failed")
+		return
+	}
+	soa := d.soa(cfg, zone)
+	soa.Serial = serial
+
+	var records []dns.RR
+	if clientSerial, ok := ixfrSerial(req); ok && clientSerial == serial {
+		// The secondary is up to date, which is indicated by answering with
+		// only the current SOA.
+		records = []dns.RR{soa}
+	} else {
+		records, err = d.zoneRecords(cfg, zone, token)
+		if err != nil {
+			d.logger.Warn("Unable to build zone", "error", err)
+			refuse(dns.RcodeServerFailure, "catalog lookup failed")
+			return
+		}
+		records = append(append([]dns.RR{soa}, records...), soa)
+	}
+
+	// The messages are signed as described in RFC 8945 section 5.3.1, each
+	// one covering the MAC of the previous one.
+	mac := tsig.MAC
+	for first := true; len(records) > 0; first = false {
+		n := zoneTransferChunkSize
+		if n > len(records) {
+			n = len(records)
+		}
+		m := new(dns.Msg)
+		m.SetReply(req)
+		m.Authoritative = true
+		m.Answer = records[:n]
+		m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, tsig.Fudge, time.Now().Unix())
+		records = records[n:]
+
+		var buf []byte
+		buf, mac, err = dns.TsigGenerate(m, key.Secret, mac, !first)
+		if err == nil {
+			_, err = resp.Write(buf)
+		}
+		if err != nil {
+			d.logger.Warn("failed to send zone transfer", "error", err)
+			return
+		}
+	}
+	d.logger.Info("Served zone transfer",
+		"zone", zone,
+		"type", dns.Type(q.Qtype),
+		"serial", serial,
+		"key", key.Name,
+		"client", resp.RemoteAddr().String(),
+	)
+}
+
+// ixfrSerial returns the serial of the SOA a secondary sent in the authority
+// section of an IXFR request.
+func ixfrSerial(req *dns.Msg) (uint32, bool) {
+	if req.Question[0].Qtype != dns.TypeIXFR {
+		return 0, false
+	}
+	for _, rr := range req.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa.Serial, true
+		}
+	}
+	return 0, false
+}
+
+// zoneRecords returns the records of the zone, excluding the SOA. The zone
+// contains the nameservers and the records of the nodes and healthy service
+// instances of the local datacenter, both with and without the datacenter
+// label, as they are returned by regular queries.
+func (d *DNSServer) zoneRecords(cfg *dnsConfig, zone, token string) ([]dns.RR, error) {
+	dc := d.agent.config.Datacenter
+	suffixes := []string{dc + "." + zone, zone}
+
+	// Every record of a service is part of the zone.
+	zoneCfg := *cfg
+	zoneCfg.ARecordLimit = 0
+
+	z := newZoneBuilder(zone)
+	ns, glue := d.nameservers(zone, cfg, maxRecursionLevelDefault)
+	z.add(ns...)
+	z.add(glue...)
+
+	args := structs.DCSpecificRequest{
+		Datacenter:     dc,
+		QueryOptions:   structs.QueryOptions{Token: token, AllowStale: cfg.AllowStale},
+		EnterpriseMeta: d.defaultEnterpriseMeta,
+	}
+	var nodes structs.IndexedNodes
+	if err := d.agent.RPC(context.Background(), "Catalog.ListNodes", &args, &nodes); err != nil {
+		return nil, err
+	}
+	for _, n := range nodes.Nodes {
+		name := strings.ToLower(n.Node)
+		if agentdns.InvalidNameRe.MatchString(name) {
+			continue
+		}
+		for _, suffix := range suffixes {
+			qName := name + ".node." + suffix
+			z.add(d.makeRecordFromNode(n, dns.TypeANY, qName, cfg.NodeTTL, maxRecursionLevelDefault)...)
+			if cfg.NodeMetaTXT {
+				z.add(d.generateMeta(qName, n, cfg.NodeTTL)...)
+			}
+		}
+	}
+
+	var services structs.IndexedServices
+	if err := d.agent.RPC(context.Background(), "Catalog.ListServices", &args, &services); err != nil {
+		return nil, err
+	}
+	for service := range services.Services {
+		name := strings.ToLower(service)
+		if agentdns.InvalidNameRe.MatchString(name) {
+			continue
+		}
+		lookup := serviceLookup{
+			Datacenter:        dc,
+			Service:           service,
+			MaxRecursionLevel: maxRecursionLevelDefault,
+			Token:             token,
+			EnterpriseMeta:    d.defaultEnterpriseMeta,
+		}
+		out, err := d.lookupServiceNodes(cfg, lookup)
+		if err != nil {
+			return nil, err
+		}
+		if len(out.Nodes) == 0 {
+			continue
+		}
+		ttl, _ := cfg.GetTTLForService(service)
+
+		for _, suffix := range suffixes {
+			z.add(d.zoneServiceRecords(&zoneCfg, lookup, out.Nodes, name+".service."+suffix, false, ttl)...)
+			z.add(d.zoneServiceRecords(&zoneCfg, lookup, out.Nodes, "_"+name+"._tcp.service."+suffix, true, ttl)...)
+
+			for _, tag := range serviceTags(out.Nodes) {
+				tagged := filterByTag(out.Nodes, tag)
+				z.add(d.zoneServiceRecords(&zoneCfg, lookup, tagged, tag+"."+name+".service."+suffix, false, ttl)...)
+				z.add(d.zoneServiceRecords(&zoneCfg, lookup, tagged, "_"+name+"._"+tag+".service."+suffix, true, ttl)...)
+			}
+		}
+	}
+	return z.records(), nil
+}
+
+// zoneServiceRecords returns the records answering queries for qName, which
+// are the SRV records and, unless srvOnly is set, the address records.
+func (d *DNSServer) zoneServiceRecords(cfg *dnsConfig, lookup serviceLookup, nodes structs.CheckServiceNodes, qName string, srvOnly bool, ttl time.Duration) []dns.RR {
+	var records []dns.RR
+
+	req := new(dns.Msg)
+	req.SetQuestion(qName, dns.TypeSRV)
+	resp := new(dns.Msg)
+	d.serviceSRVRecords(cfg, lookup, nodes, req, resp, ttl, lookup.MaxRecursionLevel)
+	records = append(append(records, resp.Answer...), resp.Extra...)
+
+	if !srvOnly {
+		req.SetQuestion(qName, dns.TypeANY)
+		resp = new(dns.Msg)
+		d.serviceNodeRecords(cfg, lookup, nodes, req, resp, ttl, lookup.MaxRecursionLevel)
+		records = append(records, resp.Answer...)
+	}
+	return records
+}
+
+// serviceTags returns the valid DNS labels among the tags of the instances.
+func serviceTags(nodes structs.CheckServiceNodes) []string {
+	seen := make(map[string]struct{})
+	for _, n := range nodes {
+		for _, tag := range n.Service.Tags {
+			tag = strings.ToLower(tag)
+			if agentdns.InvalidNameRe.MatchString(tag) || tag == "" {
+				continue
+			}
+			seen[tag] = struct{}{}
+		}
+	}
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// filterByTag returns the instances having the tag, which is matched case
+// insensitively as in tag lookups.
+func filterByTag(nodes structs.CheckServiceNodes, tag string) structs.CheckServiceNodes {
+	var out structs.CheckServiceNodes
+	for _, n := range nodes {
+		for _, t := range n.Service.Tags {
+			if strings.EqualFold(t, tag) {
+				out = append(out, n)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// zoneBuilder collects the records of a zone, dropping duplicates and
+// records outside of the zone such as resolved CNAME targets.
+type zoneBuilder struct {
+	zone   string
+	seen   map[string]struct{}
+	cnames map[string]struct{}
+	rrs    []dns.RR
+}
+
+func newZoneBuilder(zone string) *zoneBuilder {
+	return &zoneBuilder{
+		zone:   zone,
+		seen:   make(map[string]struct{}),
+		cnames: make(map[string]struct{}),
+	}
+}
+
+func (z *zoneBuilder) add(rrs ...dns.RR) {
+	for _, rr := range rrs {
+		if rr == nil || !dns.IsSubDomain(z.zone, rr.Header().Name) {
+			continue
+		}
+		s := rr.String()
+		if _, ok := z.seen[s]; ok {
+			continue
+		}
+		z.seen[s] = struct{}{}
+		if rr.Header().Rrtype == dns.TypeCNAME {
+			z.cnames[strings.ToLower(rr.Header().Name)] = struct{}{}
+		}
+		z.rrs = append(z.rrs, rr)
+	}
+}
+
+// records returns the records of the zone. A name with a CNAME can't have
+// other records, so those are omitted.
+func (z *zoneBuilder) records() []dns.RR {
+	out := make([]dns.RR, 0, len(z.rrs))
+	for _, rr := range z.rrs {
+		if _, ok := z.cnames[strings.ToLower(rr.Header().Name)]; ok && rr.Header().Rrtype != dns.TypeCNAME {
+			continue
+		}
+		out = append(out, rr)
+	}
+	return out
+}
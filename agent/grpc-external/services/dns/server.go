@@ -82,8 +82,9 @@ func (b *BufferResponseWriter) Close() error {
 
 // TsigStatus returns the status of the Tsig.
 func (b *BufferResponseWriter) TsigStatus() error {
-	// TSIG doesn't apply to this response writer.
-	return nil
+	// TSIG signatures of proxied requests can't be verified, so they are
+	// never reported as valid.
+	return dns.ErrSecret
 }
 
 // TsigTimersOnly sets the tsig timers only boolean.
@@ -51,6 +51,9 @@ var StateCounters = []prometheus.CounterDefinition{
 
 const fullSyncReadMaxStale = 2 * time.Second
 
+// checkHistorySize is the number of status transitions kept for each check.
+const checkHistorySize = 32
+
 // Config is the configuration for the State.
 type Config struct {
 	AdvertiseAddr       string
@@ -134,6 +137,18 @@ type CheckState struct {
 	IsLocallyDefined bool
 }
 
+// CheckTransition is a change of the status of a health check.
+type CheckTransition struct {
+	// Status is the status the check changed to.
+	Status string
+
+	// Output is the output of the check when its status changed.
+	Output string
+
+	// Time is when the status changed.
+	Time time.Time
+}
+
 // Clone returns a shallow copy of the object.
 //
 // The defer timer still points to the original value and must not be modified.
@@ -195,9 +210,15 @@ type State struct {
 	// Services tracks the local services
 	services map[structs.ServiceID]*ServiceState
 
-	// Checks tracks the local checks. checkAliases are aliased checks.
-	checks       map[structs.CheckID]*CheckState
-	checkAliases map[structs.ServiceID]map[structs.CheckID]chan<- struct{}
+	// Checks tracks the local checks. checkAliases are aliased checks and
+	// checkAggregates are aggregate checks.
+	checks          map[structs.CheckID]*CheckState
+	checkAliases    map[structs.ServiceID]map[structs.CheckID]chan<- struct{}
+	checkAggregates map[structs.CheckID]chan<- struct{}
+
+	// checkHistory holds the most recent status transitions of each check,
+	// oldest first.
+	checkHistory map[structs.CheckID][]CheckTransition
 
 	// metadata tracks the node metadata fields
 	metadata map[string]string
@@ -225,6 +246,8 @@ func NewState(c Config, logger hclog.Logger, tokens *token.Store) *State {
 		services:            make(map[structs.ServiceID]*ServiceState),
 		checks:              make(map[structs.CheckID]*CheckState),
 		checkAliases:        make(map[structs.ServiceID]map[structs.CheckID]chan<- struct{}),
+		checkAggregates:     make(map[structs.CheckID]chan<- struct{}),
+		checkHistory:        make(map[structs.CheckID][]CheckTransition),
 		metadata:            make(map[string]string),
 		tokens:              tokens,
 		notifyHandlers:      make(map[chan<- struct{}]struct{}),
@@ -580,6 +603,7 @@ func (l *State) addCheckLocked(check *structs.HealthCheck, token string, isLocal
 		Token:            token,
 		IsLocallyDefined: isLocal,
 	})
+	l.recordTransitionLocked(check.CompoundCheckID(), check.Status, check.Output)
 	return nil
 }
 
@@ -633,6 +657,29 @@ func (l *State) RemoveAliasCheck(checkID structs.CheckID, srcServiceID structs.S
 	}
 }
 
+// AddAggregateCheck registers an aggregate check. Whenever a local check
+// changes, notifyCh is notified so that checkID can re-evaluate its
+// status using the semantics of checks.CheckAggregate.
+func (l *State) AddAggregateCheck(checkID structs.CheckID, notifyCh chan<- struct{}) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.agentEnterpriseMeta.PartitionOrDefault() != checkID.PartitionOrDefault() {
+		return fmt.Errorf("cannot add aggregate check ID %q to node in partition %q", checkID.String(), l.config.Partition)
+	}
+
+	l.checkAggregates[checkID] = notifyCh
+	return nil
+}
+
+// RemoveAggregateCheck removes the registration for the aggregate check.
+func (l *State) RemoveAggregateCheck(checkID structs.CheckID) {
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.checkAggregates, checkID)
+}
+
 // RemoveCheck is used to remove a health check from the local state.
 // The agent will make a best effort to ensure it is deregistered
 // todo(fs): RemoveService returns an error for a non-existent service. RemoveCheck should as well.
@@ -651,6 +698,8 @@ func (l *State) removeCheckLocked(id structs.CheckID) error {
 
 	// If this is a check for an aliased service, then notify the waiters.
 	l.notifyIfAliased(c.Check.CompoundServiceID())
+	l.notifyAggregates()
+	delete(l.checkHistory, id)
 
 	// To remove the check on the server we need the token.
 	// Therefore, we mark the service as deleted and keep the
@@ -733,14 +782,47 @@ func (l *State) UpdateCheck(id structs.CheckID, status, output string) {
 
 	// If this is a check for an aliased service, then notify the waiters.
 	l.notifyIfAliased(c.Check.CompoundServiceID())
+	l.notifyAggregates()
 
 	// Update status and mark out of sync
 	c.Check.Status = status
 	c.Check.Output = output
 	c.InSync = false
+	l.recordTransitionLocked(id, status, output)
 	l.TriggerSyncChanges()
 }
 
+// recordTransitionLocked adds a transition to the history of a check if the
+// status differs from the last one recorded, dropping the oldest transition
+// once checkHistorySize is exceeded. This must be called with the lock held.
+func (l *State) recordTransitionLocked(id structs.CheckID, status, output string) {
+	history := l.checkHistory[id]
+	if n := len(history); n > 0 && history[n-1].Status == status {
+		return
+	}
+	history = append(history, CheckTransition{
+		Status: status,
+		Output: output,
+		Time:   time.Now(),
+	})
+	if len(history) > checkHistorySize {
+		history = history[len(history)-checkHistorySize:]
+	}
+	l.checkHistory[id] = history
+}
+
+// CheckHistory returns a copy of the most recent status transitions of a
+// check, oldest first.
+func (l *State) CheckHistory(id structs.CheckID) []CheckTransition {
+	l.RLock()
+	defer l.RUnlock()
+
+	history := l.checkHistory[id]
+	out := make([]CheckTransition, len(history))
+	copy(out, history)
+	return out
+}
+
 // Check returns the locally registered check that the
 // agent is aware of and are being kept in sync with the server
 func (l *State) Check(id structs.CheckID) *structs.HealthCheck {
@@ -840,6 +922,7 @@ func (l *State) setCheckStateLocked(c *CheckState) {
 
 	// If this is a check for an aliased service, then notify the waiters.
 	l.notifyIfAliased(c.Check.CompoundServiceID())
+	l.notifyAggregates()
 
 	l.TriggerSyncChanges()
 }
@@ -1603,6 +1686,19 @@ func (l *State) notifyIfAliased(serviceID structs.ServiceID) {
 	}
 }
 
+// notifyAggregates will notify all aggregate checks of a change to the local
+// checks.
+func (l *State) notifyAggregates() {
+	for _, notifyCh := range l.checkAggregates {
+		// Do not block. See notifyIfAliased for why this is safe. This must
+		// be called with the lock held.
+		select {
+		case notifyCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
 // aclAccessorID is used to convert an ACLToken's secretID to its accessorID for non-
 // critical purposes, such as logging. Therefore we interpret all errors as empty-string
 // so we can safely log it without handling non-critical errors at the usage site.
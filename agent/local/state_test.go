@@ -2274,6 +2274,102 @@ func TestAgent_AliasCheck_ServiceNotification(t *testing.T) {
 	}
 }
 
+func TestAgent_AggregateCheck(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadRuntimeConfig(t, `bind_addr = "127.0.0.1" data_dir = "dummy" node_name = "dummy"`)
+	l := local.NewState(agent.LocalConfig(cfg), nil, new(token.Store))
+	l.TriggerSyncChanges = func() {}
+
+	// Add an aggregate
+	notifyCh := make(chan struct{}, 1)
+	require.NoError(t, l.AddAggregateCheck(structs.NewCheckID(types.CheckID("g1"), nil), notifyCh))
+
+	// Add a check and verify we get notified
+	require.NoError(t, l.AddCheck(&structs.HealthCheck{CheckID: types.CheckID("c1")}, "", false))
+	select {
+	case <-notifyCh:
+	default:
+		t.Fatal("notify not received")
+	}
+
+	// Update and verify we get notified
+	l.UpdateCheck(structs.NewCheckID(types.CheckID("c1"), nil), api.HealthCritical, "")
+	select {
+	case <-notifyCh:
+	default:
+		t.Fatal("notify not received")
+	}
+
+	// Update again and verify we do not get notified
+	l.UpdateCheck(structs.NewCheckID(types.CheckID("c1"), nil), api.HealthCritical, "")
+	select {
+	case <-notifyCh:
+		t.Fatal("notify received")
+	default:
+	}
+
+	// Remove the check and verify we get notified
+	require.NoError(t, l.RemoveCheck(structs.NewCheckID(types.CheckID("c1"), nil)))
+	select {
+	case <-notifyCh:
+	default:
+		t.Fatal("notify not received")
+	}
+
+	// Remove the aggregate and verify we are no longer notified
+	l.RemoveAggregateCheck(structs.NewCheckID(types.CheckID("g1"), nil))
+	require.NoError(t, l.AddCheck(&structs.HealthCheck{CheckID: types.CheckID("c2")}, "", false))
+	select {
+	case <-notifyCh:
+		t.Fatal("notify received")
+	default:
+	}
+}
+
+func TestAgent_CheckHistory(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadRuntimeConfig(t, `bind_addr = "127.0.0.1" data_dir = "dummy" node_name = "dummy"`)
+	l := local.NewState(agent.LocalConfig(cfg), nil, new(token.Store))
+	l.TriggerSyncChanges = func() {}
+
+	id := structs.NewCheckID(types.CheckID("c1"), nil)
+	require.NoError(t, l.AddCheck(&structs.HealthCheck{CheckID: "c1", Status: api.HealthCritical}, "", false))
+
+	// Updates that do not change the status are not recorded
+	l.UpdateCheck(id, api.HealthPassing, "ok")
+	l.UpdateCheck(id, api.HealthPassing, "still ok")
+	l.UpdateCheck(id, api.HealthWarning, "slow")
+
+	history := l.CheckHistory(id)
+	require.Len(t, history, 3)
+	for i, want := range []struct{ status, output string }{
+		{api.HealthCritical, ""},
+		{api.HealthPassing, "ok"},
+		{api.HealthWarning, "slow"},
+	} {
+		require.Equal(t, want.status, history[i].Status)
+		require.Equal(t, want.output, history[i].Output)
+	}
+
+	// The history is bounded
+	for i := 0; i < 100; i++ {
+		status := api.HealthPassing
+		if i%2 == 0 {
+			status = api.HealthCritical
+		}
+		l.UpdateCheck(id, status, fmt.Sprintf("update %d", i))
+	}
+	history = l.CheckHistory(id)
+	require.Len(t, history, 32)
+	require.Equal(t, "update 99", history[31].Output)
+
+	// The history is dropped with the check
+	require.NoError(t, l.RemoveCheck(id))
+	require.Empty(t, l.CheckHistory(id))
+}
+
 func TestAgent_sendCoordinate(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -84,6 +84,7 @@ func (s *HTTPHandlers) PeeringList(resp http.ResponseWriter, req *http.Request)
 	if err != nil {
 		return nil, err
 	}
+	setIndex(resp, pbresp.Index)
 
 	return pbresp.ToAPI(), nil
 }
@@ -115,11 +115,15 @@ func (s *HTTPHandlers) preparedQueryExecute(id string, resp http.ResponseWriter,
 
 		args.Connect = val
 	}
+	if _, ok := params["trace"]; ok {
+		args.Trace = true
+	}
 
 	var reply structs.PreparedQueryExecuteResponse
 	defer setMeta(resp, &reply.QueryMeta)
 
-	if args.QueryOptions.UseCache {
+	// A trace describes a fresh execution, so it never comes from the cache.
+	if args.QueryOptions.UseCache && !args.Trace {
 		raw, m, err := s.agent.cache.Get(req.Context(), cachetype.PreparedQueryName, &args)
 		if err != nil {
 			// Don't return error if StaleIfError is set and we are within it and had
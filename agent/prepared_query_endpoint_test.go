@@ -797,6 +797,36 @@ func TestPreparedQuery_Explain(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 200, resp.Code)
 	})
+
+	// Ensure that Trace is passed through and bypasses the cache
+	t.Run("", func(t *testing.T) {
+		a := NewTestAgent(t, "")
+		defer a.Shutdown()
+
+		m := MockPreparedQuery{
+			executeFn: func(args *structs.PreparedQueryExecuteRequest, reply *structs.PreparedQueryExecuteResponse) error {
+				require.True(t, args.Trace)
+				reply.Trace = []structs.PreparedQueryTraceStep{
+					{Step: "resolve", Datacenter: "dc1", Detail: "Resolved"},
+				}
+				return nil
+			},
+		}
+		require.NoError(t, a.registerEndpoint("PreparedQuery", &m))
+
+		body := bytes.NewBuffer(nil)
+		req, _ := http.NewRequest("GET", "/v1/query/my-id/execute?trace&cached", body)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.PreparedQuerySpecific(resp, req)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.Code)
+		require.Empty(t, resp.Header().Get("X-Cache"))
+
+		r, ok := obj.(structs.PreparedQueryExecuteResponse)
+		require.True(t, ok)
+		require.Len(t, r.Trace, 1)
+		require.Equal(t, "resolve", r.Trace[0].Step)
+	})
 }
 
 func TestPreparedQuery_Get(t *testing.T) {
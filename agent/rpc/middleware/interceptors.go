@@ -160,6 +160,12 @@ func GetNetRPCInterceptor(recorder *RequestRecorder) rpc.ServerServiceCallInterc
 	}
 }
 
+// NetRPCOperationType returns the type of operation (e.g. read or write) the
+// given net/rpc endpoint performs, for rate limiting purposes.
+func NetRPCOperationType(reqServiceMethod string) rpcRate.OperationType {
+	return rpcRateLimitSpecs[reqServiceMethod]
+}
+
 func GetNetRPCRateLimitingInterceptor(requestLimitsHandler rpcRate.RequestLimitsHandler, panicHandler RecoveryHandlerFunc) rpc.PreBodyInterceptor {
 
 	return func(reqServiceMethod string, sourceAddr net.Addr) (retErr error) {
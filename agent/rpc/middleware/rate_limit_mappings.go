@@ -61,11 +61,15 @@ var rpcRateLimitSpecs = map[string]rate.OperationType{
 	"ConfigEntry.ListAll":              rate.OperationTypeRead,
 	"ConfigEntry.ResolveServiceConfig": rate.OperationTypeRead,
 
-	"ConnectCA.ConfigurationGet": rate.OperationTypeRead,
-	"ConnectCA.ConfigurationSet": rate.OperationTypeWrite,
-	"ConnectCA.Roots":            rate.OperationTypeRead,
-	"ConnectCA.Sign":             rate.OperationTypeWrite,
-	"ConnectCA.SignIntermediate": rate.OperationTypeWrite,
+	"ConnectCA.ConfigurationDryRun": rate.OperationTypeWrite,
+	"ConnectCA.ConfigurationGet":    rate.OperationTypeRead,
+	"ConnectCA.ConfigurationSet":    rate.OperationTypeWrite,
+	"ConnectCA.RevokeLeaf":          rate.OperationTypeWrite,
+	"ConnectCA.RevokedLeafs":        rate.OperationTypeRead,
+	"ConnectCA.Roots":               rate.OperationTypeRead,
+	"ConnectCA.RotationStatus":      rate.OperationTypeRead,
+	"ConnectCA.Sign":                rate.OperationTypeWrite,
+	"ConnectCA.SignIntermediate":    rate.OperationTypeWrite,
 
 	"Coordinate.ListDatacenters": rate.OperationTypeRead,
 	"Coordinate.ListNodes":       rate.OperationTypeRead,
@@ -87,6 +87,11 @@ type Config struct {
 	Datacenter     string
 	ConnectEnabled bool
 	PeeringEnabled bool
+
+	// MaxQueryTime and DefaultQueryTime bound how long a blocking PeeringList
+	// request waits for a change, as for blocking net/rpc queries.
+	MaxQueryTime     time.Duration
+	DefaultQueryTime time.Duration
 }
 
 func NewServer(cfg Config) *Server {
@@ -97,6 +102,12 @@ func NewServer(cfg Config) *Server {
 	if cfg.Datacenter == "" {
 		panic("Datacenter is required")
 	}
+	if cfg.MaxQueryTime == 0 {
+		cfg.MaxQueryTime = 600 * time.Second
+	}
+	if cfg.DefaultQueryTime == 0 {
+		cfg.DefaultQueryTime = 300 * time.Second
+	}
 	return &Server{
 		Config: cfg,
 	}
@@ -180,6 +191,7 @@ type Store interface {
 	PeeringRead(ws memdb.WatchSet, q state.Query) (uint64, *pbpeering.Peering, error)
 	PeeringReadByID(ws memdb.WatchSet, id string) (uint64, *pbpeering.Peering, error)
 	PeeringList(ws memdb.WatchSet, entMeta acl.EnterpriseMeta) (uint64, []*pbpeering.Peering, error)
+	AbandonCh() <-chan struct{}
 	PeeringTrustBundleRead(ws memdb.WatchSet, q state.Query) (uint64, *pbpeering.PeeringTrustBundle, error)
 	PeeringTrustBundleList(ws memdb.WatchSet, entMeta acl.EnterpriseMeta) (uint64, []*pbpeering.PeeringTrustBundle, error)
 	TrustBundleListByService(ws memdb.WatchSet, service, dc string, entMeta acl.EnterpriseMeta) (uint64, []*pbpeering.PeeringTrustBundle, error)
@@ -642,7 +654,9 @@ func (s *Server) PeeringRead(ctx context.Context, req *pbpeering.PeeringReadRequ
 	return &pbpeering.PeeringReadResponse{Peering: cp}, nil
 }
 
-// OPTIMIZE: Handle blocking queries
+// PeeringList lists the peerings in a partition. When a MinQueryIndex is given
+// it blocks until the peerings change or the query times out, so the list can
+// be watched.
 func (s *Server) PeeringList(ctx context.Context, req *pbpeering.PeeringListRequest) (*pbpeering.PeeringListResponse, error) {
 	if !s.Config.PeeringEnabled {
 		return nil, peeringNotEnabledErr
@@ -681,7 +695,7 @@ func (s *Server) PeeringList(ctx context.Context, req *pbpeering.PeeringListRequ
 
 	defer metrics.MeasureSince([]string{"peering", "list"}, time.Now())
 
-	idx, peerings, err := s.Backend.Store().PeeringList(nil, *entMeta)
+	idx, peerings, err := s.blockingPeeringList(ctx, options, *entMeta)
 	if err != nil {
 		return nil, err
 	}
@@ -696,6 +710,37 @@ func (s *Server) PeeringList(ctx context.Context, req *pbpeering.PeeringListRequ
 	return &pbpeering.PeeringListResponse{Peerings: cPeerings, Index: idx}, nil
 }
 
+func (s *Server) blockingPeeringList(ctx context.Context, options structs.QueryOptions, entMeta acl.EnterpriseMeta) (uint64, []*pbpeering.Peering, error) {
+	if options.MinQueryIndex == 0 {
+		return s.Backend.Store().PeeringList(nil, entMeta)
+	}
+
+	timeout := options.BlockingTimeout(s.Config.MaxQueryTime, s.Config.DefaultQueryTime)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		// Use the same store for the query and its abandon channel, so we are
+		// woken up if the state store is replaced by a snapshot restore.
+		store := s.Backend.Store()
+		ws := memdb.NewWatchSet()
+		ws.Add(store.AbandonCh())
+
+		idx, peerings, err := store.PeeringList(ws, entMeta)
+		if err != nil {
+			return 0, nil, err
+		}
+		if idx > options.MinQueryIndex {
+			return idx, peerings, nil
+		}
+		if err := ws.WatchCtx(ctx); err != nil {
+			// The query timed out or the request was canceled, return the
+			// current results.
+			return idx, peerings, nil
+		}
+	}
+}
+
 // TODO(peering): Get rid of this func when we stop using the stream tracker for imported/ exported services and the peering state
 // reconcilePeering enriches the peering with the following information:
 // -- PeeringState.Active if the peering is active
@@ -1023,6 +1023,60 @@ func TestPeeringService_List(t *testing.T) {
 	prototest.AssertDeepEqual(t, expect, resp)
 }
 
+func TestPeeringService_List_Blocking(t *testing.T) {
+	// TODO(peering): see note on newTestServer, refactor to not use this
+	s := newTestServer(t, nil)
+
+	foo := &pbpeering.Peering{
+		ID:                  testUUID(t),
+		Name:                "foo",
+		State:               pbpeering.PeeringState_ESTABLISHING,
+		PeerServerName:      "fooservername",
+		PeerServerAddresses: []string{"addr1"},
+	}
+	require.NoError(t, s.Server.FSM().State().PeeringWrite(10, &pbpeering.PeeringWriteRequest{Peering: foo}))
+
+	client := pbpeering.NewPeeringServiceClient(s.ClientConn(t))
+
+	list := func(t *testing.T, options structs.QueryOptions) *pbpeering.PeeringListResponse {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		t.Cleanup(cancel)
+
+		ctx, err := external.ContextWithQueryOptions(ctx, options)
+		require.NoError(t, err)
+		resp, err := client.PeeringList(ctx, &pbpeering.PeeringListRequest{})
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("returns on change", func(t *testing.T) {
+		bar := &pbpeering.Peering{
+			ID:                  testUUID(t),
+			Name:                "bar",
+			State:               pbpeering.PeeringState_ACTIVE,
+			PeerServerName:      "barservername",
+			PeerServerAddresses: []string{"addr1"},
+		}
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_ = s.Server.FSM().State().PeeringWrite(20, &pbpeering.PeeringWriteRequest{Peering: bar})
+		}()
+
+		start := time.Now()
+		resp := list(t, structs.QueryOptions{MinQueryIndex: 10, MaxQueryTime: 5 * time.Second})
+		require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		require.Equal(t, uint64(20), resp.Index)
+		require.Len(t, resp.Peerings, 2)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		start := time.Now()
+		resp := list(t, structs.QueryOptions{MinQueryIndex: 20, MaxQueryTime: 100 * time.Millisecond})
+		require.Less(t, time.Since(start), 5*time.Second)
+		require.Equal(t, uint64(20), resp.Index)
+	})
+}
+
 func TestPeeringService_List_ACLEnforcement(t *testing.T) {
 	// TODO(peering): see note on newTestServer, refactor to not use this
 	s := newTestServer(t, func(conf *consul.Config) {
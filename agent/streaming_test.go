@@ -3,11 +3,16 @@ package agent
 import (
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/api/watch"
 	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 )
 
@@ -108,3 +113,119 @@ func TestGRPCWithTLSConfigs(t *testing.T) {
 		})
 	}
 }
+
+// backendRecorder records the query backend of the responses of the agent.
+type backendRecorder struct {
+	lock     sync.Mutex
+	backends []string
+}
+
+func (r *backendRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil {
+		r.lock.Lock()
+		r.backends = append(r.backends, resp.Header.Get("X-Consul-Query-Backend"))
+		r.lock.Unlock()
+	}
+	return resp, err
+}
+
+func (r *backendRecorder) Backends() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string(nil), r.backends...)
+}
+
+func TestServiceWatch_StreamingBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	startWatch := func(t *testing.T, a *TestAgent) (*backendRecorder, chan []*api.ServiceEntry) {
+		plan, err := watch.Parse(map[string]interface{}{
+			"type":      "service",
+			"service":   "web",
+			"streaming": true,
+		})
+		require.NoError(t, err)
+
+		notifyCh := make(chan []*api.ServiceEntry, 2)
+		plan.HybridHandler = func(_ watch.BlockingParamVal, raw interface{}) {
+			entries, ok := raw.([]*api.ServiceEntry)
+			if !ok {
+				return
+			}
+			notifyCh <- entries
+		}
+
+		recorder := &backendRecorder{}
+		conf := api.DefaultConfig()
+		conf.HttpClient = &http.Client{Transport: recorder}
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- plan.RunWithConfig(a.HTTPAddr(), conf)
+		}()
+		t.Cleanup(func() {
+			plan.Stop()
+			require.NoError(t, <-errCh)
+		})
+		return recorder, notifyCh
+	}
+
+	t.Run("streaming backend", func(t *testing.T) {
+		a := NewTestAgent(t, `use_streaming_backend = true`)
+		defer a.Shutdown()
+		testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+		recorder, notifyCh := startWatch(t, a)
+
+		select {
+		case entries := <-notifyCh:
+			require.Len(t, entries, 0)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the first handler call")
+		}
+
+		require.NoError(t, a.Client().Agent().ServiceRegister(&api.AgentServiceRegistration{
+			Name: "web",
+			Port: 8080,
+		}))
+
+		select {
+		case entries := <-notifyCh:
+			require.Len(t, entries, 1)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the service to be registered")
+		}
+
+		backends := recorder.Backends()
+		require.NotEmpty(t, backends)
+		for _, backend := range backends {
+			require.Equal(t, "streaming", backend)
+		}
+	})
+
+	// Without streaming on the agent the query is served from the agent
+	// cache, which makes blocking queries against the servers, so the watch
+	// errors instead of delivering the result.
+	t.Run("streaming disabled", func(t *testing.T) {
+		a := NewTestAgent(t, `use_streaming_backend = false`)
+		defer a.Shutdown()
+		testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+		recorder, notifyCh := startWatch(t, a)
+
+		retry.Run(t, func(r *retry.R) {
+			require.NotEmpty(r, recorder.Backends())
+		})
+		require.NotContains(t, recorder.Backends(), "streaming")
+		select {
+		case entries := <-notifyCh:
+			t.Fatalf("unexpected handler call: %v", entries)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
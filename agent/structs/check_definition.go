@@ -32,6 +32,11 @@ type CheckDefinition struct {
 	Method                         string
 	Body                           string
 	DisableRedirects               bool
+	ResponseBodyRegex              string
+	ResponseJSONPath               string
+	ResponseJSONValue              string
+	ResponseHeaders                map[string][]string
+	ResponseTimeWarning            time.Duration
 	TCP                            string
 	UDP                            string
 	Interval                       time.Duration
@@ -40,15 +45,35 @@ type CheckDefinition struct {
 	GRPC                           string
 	GRPCUseTLS                     bool
 	OSService                      string
+	DNS                            string
+	DNSQuery                       string
+	DNSRecordType                  string
+	DNSRcode                       string
+	DNSMinAnswers                  int
+	DNSExpectedValues              []string
+	DNSUseTCP                      bool
+	TLS                            string
+	TLSStartTLS                    string
+	TLSCAFile                      string
+	TLSExpectedSANs                []string
+	TLSExpiryWarning               time.Duration
+	TLSExpiryCritical              time.Duration
 	TLSServerName                  string
 	TLSSkipVerify                  bool
 	AliasNode                      string
 	AliasService                   string
+	AggregateCheckIDs              []string
+	AggregateService               string
+	AggregateServiceTag            string
+	AggregateRule                  string
+	AggregateThreshold             int
 	Timeout                        time.Duration
 	TTL                            time.Duration
 	SuccessBeforePassing           int
 	FailuresBeforeWarning          int
 	FailuresBeforeCritical         int
+	FlapThreshold                  int
+	FlapWindow                     time.Duration
 	DeregisterCriticalServiceAfter time.Duration
 	OutputMaxSize                  int
 
@@ -63,20 +88,47 @@ func (t *CheckDefinition) UnmarshalJSON(data []byte) (err error) {
 		Timeout                        interface{}
 		TTL                            interface{}
 		DeregisterCriticalServiceAfter interface{}
+		TLSExpiryWarning               interface{}
+		TLSExpiryCritical              interface{}
+		ResponseTimeWarning            interface{}
+		FlapWindow                     interface{}
 
 		// Translate fields
 
 		// "args" -> ScriptArgs
-		Args                                []string    `json:"args"`
-		ScriptArgsSnake                     []string    `json:"script_args"`
-		DeregisterCriticalServiceAfterSnake interface{} `json:"deregister_critical_service_after"`
-		DockerContainerIDSnake              string      `json:"docker_container_id"`
-		TLSServerNameSnake                  string      `json:"tls_server_name"`
-		TLSSkipVerifySnake                  bool        `json:"tls_skip_verify"`
-		GRPCUseTLSSnake                     bool        `json:"grpc_use_tls"`
-		ServiceIDSnake                      string      `json:"service_id"`
-		H2PingUseTLSSnake                   bool        `json:"h2ping_use_tls"`
-		DisableRedirectsSnake               bool        `json:"disable_redirects"`
+		Args                                []string            `json:"args"`
+		ScriptArgsSnake                     []string            `json:"script_args"`
+		DeregisterCriticalServiceAfterSnake interface{}         `json:"deregister_critical_service_after"`
+		DockerContainerIDSnake              string              `json:"docker_container_id"`
+		TLSServerNameSnake                  string              `json:"tls_server_name"`
+		TLSSkipVerifySnake                  bool                `json:"tls_skip_verify"`
+		GRPCUseTLSSnake                     bool                `json:"grpc_use_tls"`
+		ServiceIDSnake                      string              `json:"service_id"`
+		H2PingUseTLSSnake                   bool                `json:"h2ping_use_tls"`
+		DisableRedirectsSnake               bool                `json:"disable_redirects"`
+		DNSQuerySnake                       string              `json:"dns_query"`
+		DNSRecordTypeSnake                  string              `json:"dns_record_type"`
+		DNSRcodeSnake                       string              `json:"dns_rcode"`
+		DNSMinAnswersSnake                  int                 `json:"dns_min_answers"`
+		DNSExpectedValuesSnake              []string            `json:"dns_expected_values"`
+		DNSUseTCPSnake                      bool                `json:"dns_use_tcp"`
+		TLSStartTLSSnake                    string              `json:"tls_starttls"`
+		TLSCAFileSnake                      string              `json:"tls_ca_file"`
+		TLSExpectedSANsSnake                []string            `json:"tls_expected_sans"`
+		TLSExpiryWarningSnake               interface{}         `json:"tls_expiry_warning"`
+		TLSExpiryCriticalSnake              interface{}         `json:"tls_expiry_critical"`
+		ResponseBodyRegexSnake              string              `json:"response_body_regex"`
+		ResponseJSONPathSnake               string              `json:"response_json_path"`
+		ResponseJSONValueSnake              string              `json:"response_json_value"`
+		ResponseHeadersSnake                map[string][]string `json:"response_headers"`
+		ResponseTimeWarningSnake            interface{}         `json:"response_time_warning"`
+		AggregateCheckIDsSnake              []string            `json:"aggregate_check_ids"`
+		AggregateServiceSnake               string              `json:"aggregate_service"`
+		AggregateServiceTagSnake            string              `json:"aggregate_service_tag"`
+		AggregateRuleSnake                  string              `json:"aggregate_rule"`
+		AggregateThresholdSnake             int                 `json:"aggregate_threshold"`
+		FlapThresholdSnake                  int                 `json:"flap_threshold"`
+		FlapWindowSnake                     interface{}         `json:"flap_window"`
 
 		*Alias
 	}{
@@ -125,6 +177,63 @@ func (t *CheckDefinition) UnmarshalJSON(data []byte) (err error) {
 	if aux.DisableRedirectsSnake {
 		t.DisableRedirects = aux.DisableRedirectsSnake
 	}
+	if t.DNSQuery == "" {
+		t.DNSQuery = aux.DNSQuerySnake
+	}
+	if t.DNSRecordType == "" {
+		t.DNSRecordType = aux.DNSRecordTypeSnake
+	}
+	if t.DNSRcode == "" {
+		t.DNSRcode = aux.DNSRcodeSnake
+	}
+	if t.DNSMinAnswers == 0 {
+		t.DNSMinAnswers = aux.DNSMinAnswersSnake
+	}
+	if len(t.DNSExpectedValues) == 0 {
+		t.DNSExpectedValues = aux.DNSExpectedValuesSnake
+	}
+	if aux.DNSUseTCPSnake {
+		t.DNSUseTCP = aux.DNSUseTCPSnake
+	}
+	if t.TLSStartTLS == "" {
+		t.TLSStartTLS = aux.TLSStartTLSSnake
+	}
+	if t.TLSCAFile == "" {
+		t.TLSCAFile = aux.TLSCAFileSnake
+	}
+	if len(t.TLSExpectedSANs) == 0 {
+		t.TLSExpectedSANs = aux.TLSExpectedSANsSnake
+	}
+	if t.ResponseBodyRegex == "" {
+		t.ResponseBodyRegex = aux.ResponseBodyRegexSnake
+	}
+	if t.ResponseJSONPath == "" {
+		t.ResponseJSONPath = aux.ResponseJSONPathSnake
+	}
+	if t.ResponseJSONValue == "" {
+		t.ResponseJSONValue = aux.ResponseJSONValueSnake
+	}
+	if len(t.ResponseHeaders) == 0 {
+		t.ResponseHeaders = aux.ResponseHeadersSnake
+	}
+	if len(t.AggregateCheckIDs) == 0 {
+		t.AggregateCheckIDs = aux.AggregateCheckIDsSnake
+	}
+	if t.AggregateService == "" {
+		t.AggregateService = aux.AggregateServiceSnake
+	}
+	if t.AggregateServiceTag == "" {
+		t.AggregateServiceTag = aux.AggregateServiceTagSnake
+	}
+	if t.AggregateRule == "" {
+		t.AggregateRule = aux.AggregateRuleSnake
+	}
+	if t.AggregateThreshold == 0 {
+		t.AggregateThreshold = aux.AggregateThresholdSnake
+	}
+	if t.FlapThreshold == 0 {
+		t.FlapThreshold = aux.FlapThresholdSnake
+	}
 
 	if (aux.H2PING != "" && !aux.H2PingUseTLSSnake) || (aux.H2PING == "" && aux.H2PingUseTLSSnake) {
 		t.H2PingUseTLS = aux.H2PingUseTLSSnake
@@ -171,6 +280,58 @@ func (t *CheckDefinition) UnmarshalJSON(data []byte) (err error) {
 			t.DeregisterCriticalServiceAfter = time.Duration(v)
 		}
 	}
+	if aux.TLSExpiryWarning == nil {
+		aux.TLSExpiryWarning = aux.TLSExpiryWarningSnake
+	}
+	if aux.TLSExpiryWarning != nil {
+		switch v := aux.TLSExpiryWarning.(type) {
+		case string:
+			if t.TLSExpiryWarning, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.TLSExpiryWarning = time.Duration(v)
+		}
+	}
+	if aux.TLSExpiryCritical == nil {
+		aux.TLSExpiryCritical = aux.TLSExpiryCriticalSnake
+	}
+	if aux.TLSExpiryCritical != nil {
+		switch v := aux.TLSExpiryCritical.(type) {
+		case string:
+			if t.TLSExpiryCritical, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.TLSExpiryCritical = time.Duration(v)
+		}
+	}
+	if aux.FlapWindow == nil {
+		aux.FlapWindow = aux.FlapWindowSnake
+	}
+	if aux.FlapWindow != nil {
+		switch v := aux.FlapWindow.(type) {
+		case string:
+			if t.FlapWindow, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.FlapWindow = time.Duration(v)
+		}
+	}
+	if aux.ResponseTimeWarning == nil {
+		aux.ResponseTimeWarning = aux.ResponseTimeWarningSnake
+	}
+	if aux.ResponseTimeWarning != nil {
+		switch v := aux.ResponseTimeWarning.(type) {
+		case string:
+			if t.ResponseTimeWarning, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.ResponseTimeWarning = time.Duration(v)
+		}
+	}
 
 	return nil
 }
@@ -206,6 +367,11 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		ScriptArgs:                     c.ScriptArgs,
 		AliasNode:                      c.AliasNode,
 		AliasService:                   c.AliasService,
+		AggregateCheckIDs:              c.AggregateCheckIDs,
+		AggregateService:               c.AggregateService,
+		AggregateServiceTag:            c.AggregateServiceTag,
+		AggregateRule:                  c.AggregateRule,
+		AggregateThreshold:             c.AggregateThreshold,
 		HTTP:                           c.HTTP,
 		H2PING:                         c.H2PING,
 		H2PingUseTLS:                   c.H2PingUseTLS,
@@ -215,6 +381,11 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		Method:                         c.Method,
 		Body:                           c.Body,
 		DisableRedirects:               c.DisableRedirects,
+		ResponseBodyRegex:              c.ResponseBodyRegex,
+		ResponseJSONPath:               c.ResponseJSONPath,
+		ResponseJSONValue:              c.ResponseJSONValue,
+		ResponseHeaders:                c.ResponseHeaders,
+		ResponseTimeWarning:            c.ResponseTimeWarning,
 		OutputMaxSize:                  c.OutputMaxSize,
 		TCP:                            c.TCP,
 		UDP:                            c.UDP,
@@ -222,6 +393,19 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		DockerContainerID:              c.DockerContainerID,
 		Shell:                          c.Shell,
 		OSService:                      c.OSService,
+		DNS:                            c.DNS,
+		DNSQuery:                       c.DNSQuery,
+		DNSRecordType:                  c.DNSRecordType,
+		DNSRcode:                       c.DNSRcode,
+		DNSMinAnswers:                  c.DNSMinAnswers,
+		DNSExpectedValues:              c.DNSExpectedValues,
+		DNSUseTCP:                      c.DNSUseTCP,
+		TLS:                            c.TLS,
+		TLSStartTLS:                    c.TLSStartTLS,
+		TLSCAFile:                      c.TLSCAFile,
+		TLSExpectedSANs:                c.TLSExpectedSANs,
+		TLSExpiryWarning:               c.TLSExpiryWarning,
+		TLSExpiryCritical:              c.TLSExpiryCritical,
 		TLSServerName:                  c.TLSServerName,
 		TLSSkipVerify:                  c.TLSSkipVerify,
 		Timeout:                        c.Timeout,
@@ -229,6 +413,8 @@ func (c *CheckDefinition) CheckType() *CheckType {
 		SuccessBeforePassing:           c.SuccessBeforePassing,
 		FailuresBeforeWarning:          c.FailuresBeforeWarning,
 		FailuresBeforeCritical:         c.FailuresBeforeCritical,
+		FlapThreshold:                  c.FlapThreshold,
+		FlapWindow:                     c.FlapWindow,
 		DeregisterCriticalServiceAfter: c.DeregisterCriticalServiceAfter,
 	}
 }
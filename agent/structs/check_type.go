@@ -3,19 +3,38 @@ package structs
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"time"
 
 	"github.com/hashicorp/consul/lib"
+	"github.com/hashicorp/consul/lib/jsonpath"
 	"github.com/hashicorp/consul/types"
 )
 
 type CheckTypes []*CheckType
 
+// The rules used by Aggregate checks to derive their status from the
+// status of the selected checks.
+const (
+	// AggregateCriticalIfAny is critical if any selected check is critical,
+	// warning if any is warning and passing otherwise. This is the default.
+	AggregateCriticalIfAny = "critical-if-any"
+
+	// AggregateCriticalIfMoreThan is critical if more than AggregateThreshold
+	// selected checks are critical, warning if any selected check is not
+	// passing and passing otherwise.
+	AggregateCriticalIfMoreThan = "critical-if-more-than"
+
+	// AggregateWarningIfQuorumLost is warning if fewer than a majority of the
+	// selected checks are passing and passing otherwise.
+	AggregateWarningIfQuorumLost = "warning-if-quorum-lost"
+)
+
 // CheckType is used to create either the CheckMonitor or the CheckTTL.
-// The following types are supported: Script, HTTP, TCP, Docker, TTL, GRPC, Alias, H2PING. Script,
+// The following types are supported: Script, HTTP, TCP, Docker, TTL, GRPC, Alias, Aggregate, H2PING. Script,
 // HTTP, Docker, TCP, GRPC, and H2PING all require Interval. Only one of the types may
 // to be provided: TTL or Script/Interval or HTTP/Interval or TCP/Interval or
-// Docker/Interval or GRPC/Interval or AliasService or H2PING/Interval.
+// Docker/Interval or GRPC/Interval or AliasService or an Aggregate selector or H2PING/Interval.
 // Since types like CheckHTTP and CheckGRPC derive from CheckType, there are
 // helper conversion methods that do the reverse conversion. ie. checkHTTP.CheckType()
 type CheckType struct {
@@ -38,16 +57,39 @@ type CheckType struct {
 	Method                 string
 	Body                   string
 	DisableRedirects       bool
+	ResponseBodyRegex      string
+	ResponseJSONPath       string
+	ResponseJSONValue      string
+	ResponseHeaders        map[string][]string
+	ResponseTimeWarning    time.Duration
 	TCP                    string
 	UDP                    string
 	Interval               time.Duration
 	AliasNode              string
 	AliasService           string
+	AggregateCheckIDs      []string
+	AggregateService       string
+	AggregateServiceTag    string
+	AggregateRule          string
+	AggregateThreshold     int
 	DockerContainerID      string
 	Shell                  string
 	GRPC                   string
 	GRPCUseTLS             bool
 	OSService              string
+	DNS                    string
+	DNSQuery               string
+	DNSRecordType          string
+	DNSRcode               string
+	DNSMinAnswers          int
+	DNSExpectedValues      []string
+	DNSUseTCP              bool
+	TLS                    string
+	TLSStartTLS            string
+	TLSCAFile              string
+	TLSExpectedSANs        []string
+	TLSExpiryWarning       time.Duration
+	TLSExpiryCritical      time.Duration
 	TLSServerName          string
 	TLSSkipVerify          bool
 	Timeout                time.Duration
@@ -55,6 +97,8 @@ type CheckType struct {
 	SuccessBeforePassing   int
 	FailuresBeforeWarning  int
 	FailuresBeforeCritical int
+	FlapThreshold          int
+	FlapWindow             time.Duration
 
 	// Definition fields used when exposing checks through a proxy
 	ProxyHTTP string
@@ -74,18 +118,45 @@ func (t *CheckType) UnmarshalJSON(data []byte) (err error) {
 		Timeout                        interface{}
 		TTL                            interface{}
 		DeregisterCriticalServiceAfter interface{}
+		TLSExpiryWarning               interface{}
+		TLSExpiryCritical              interface{}
+		ResponseTimeWarning            interface{}
+		FlapWindow                     interface{}
 
 		// Translate fields
 
 		// "args" -> ScriptArgs
-		Args                                []string    `json:"args"`
-		ScriptArgsSnake                     []string    `json:"script_args"`
-		DeregisterCriticalServiceAfterSnake interface{} `json:"deregister_critical_service_after"`
-		DockerContainerIDSnake              string      `json:"docker_container_id"`
-		TLSServerNameSnake                  string      `json:"tls_server_name"`
-		TLSSkipVerifySnake                  bool        `json:"tls_skip_verify"`
-		GRPCUseTLSSnake                     bool        `json:"grpc_use_tls"`
-		H2PingUseTLSSnake                   bool        `json:"h2ping_use_tls"`
+		Args                                []string            `json:"args"`
+		ScriptArgsSnake                     []string            `json:"script_args"`
+		DeregisterCriticalServiceAfterSnake interface{}         `json:"deregister_critical_service_after"`
+		DockerContainerIDSnake              string              `json:"docker_container_id"`
+		TLSServerNameSnake                  string              `json:"tls_server_name"`
+		TLSSkipVerifySnake                  bool                `json:"tls_skip_verify"`
+		GRPCUseTLSSnake                     bool                `json:"grpc_use_tls"`
+		H2PingUseTLSSnake                   bool                `json:"h2ping_use_tls"`
+		DNSQuerySnake                       string              `json:"dns_query"`
+		DNSRecordTypeSnake                  string              `json:"dns_record_type"`
+		DNSRcodeSnake                       string              `json:"dns_rcode"`
+		DNSMinAnswersSnake                  int                 `json:"dns_min_answers"`
+		DNSExpectedValuesSnake              []string            `json:"dns_expected_values"`
+		DNSUseTCPSnake                      bool                `json:"dns_use_tcp"`
+		TLSStartTLSSnake                    string              `json:"tls_starttls"`
+		TLSCAFileSnake                      string              `json:"tls_ca_file"`
+		TLSExpectedSANsSnake                []string            `json:"tls_expected_sans"`
+		TLSExpiryWarningSnake               interface{}         `json:"tls_expiry_warning"`
+		TLSExpiryCriticalSnake              interface{}         `json:"tls_expiry_critical"`
+		ResponseBodyRegexSnake              string              `json:"response_body_regex"`
+		ResponseJSONPathSnake               string              `json:"response_json_path"`
+		ResponseJSONValueSnake              string              `json:"response_json_value"`
+		ResponseHeadersSnake                map[string][]string `json:"response_headers"`
+		ResponseTimeWarningSnake            interface{}         `json:"response_time_warning"`
+		AggregateCheckIDsSnake              []string            `json:"aggregate_check_ids"`
+		AggregateServiceSnake               string              `json:"aggregate_service"`
+		AggregateServiceTagSnake            string              `json:"aggregate_service_tag"`
+		AggregateRuleSnake                  string              `json:"aggregate_rule"`
+		AggregateThresholdSnake             int                 `json:"aggregate_threshold"`
+		FlapThresholdSnake                  int                 `json:"flap_threshold"`
+		FlapWindowSnake                     interface{}         `json:"flap_window"`
 
 		// These are going to be ignored but since we are disallowing unknown fields
 		// during parsing we have to be explicit about parsing but not using these.
@@ -131,6 +202,63 @@ func (t *CheckType) UnmarshalJSON(data []byte) (err error) {
 	if aux.GRPCUseTLSSnake {
 		t.GRPCUseTLS = aux.GRPCUseTLSSnake
 	}
+	if t.DNSQuery == "" {
+		t.DNSQuery = aux.DNSQuerySnake
+	}
+	if t.DNSRecordType == "" {
+		t.DNSRecordType = aux.DNSRecordTypeSnake
+	}
+	if t.DNSRcode == "" {
+		t.DNSRcode = aux.DNSRcodeSnake
+	}
+	if t.DNSMinAnswers == 0 {
+		t.DNSMinAnswers = aux.DNSMinAnswersSnake
+	}
+	if len(t.DNSExpectedValues) == 0 {
+		t.DNSExpectedValues = aux.DNSExpectedValuesSnake
+	}
+	if aux.DNSUseTCPSnake {
+		t.DNSUseTCP = aux.DNSUseTCPSnake
+	}
+	if t.TLSStartTLS == "" {
+		t.TLSStartTLS = aux.TLSStartTLSSnake
+	}
+	if t.TLSCAFile == "" {
+		t.TLSCAFile = aux.TLSCAFileSnake
+	}
+	if len(t.TLSExpectedSANs) == 0 {
+		t.TLSExpectedSANs = aux.TLSExpectedSANsSnake
+	}
+	if t.ResponseBodyRegex == "" {
+		t.ResponseBodyRegex = aux.ResponseBodyRegexSnake
+	}
+	if t.ResponseJSONPath == "" {
+		t.ResponseJSONPath = aux.ResponseJSONPathSnake
+	}
+	if t.ResponseJSONValue == "" {
+		t.ResponseJSONValue = aux.ResponseJSONValueSnake
+	}
+	if len(t.ResponseHeaders) == 0 {
+		t.ResponseHeaders = aux.ResponseHeadersSnake
+	}
+	if len(t.AggregateCheckIDs) == 0 {
+		t.AggregateCheckIDs = aux.AggregateCheckIDsSnake
+	}
+	if t.AggregateService == "" {
+		t.AggregateService = aux.AggregateServiceSnake
+	}
+	if t.AggregateServiceTag == "" {
+		t.AggregateServiceTag = aux.AggregateServiceTagSnake
+	}
+	if t.AggregateRule == "" {
+		t.AggregateRule = aux.AggregateRuleSnake
+	}
+	if t.AggregateThreshold == 0 {
+		t.AggregateThreshold = aux.AggregateThresholdSnake
+	}
+	if t.FlapThreshold == 0 {
+		t.FlapThreshold = aux.FlapThresholdSnake
+	}
 	if aux.Interval != nil {
 		switch v := aux.Interval.(type) {
 		case string:
@@ -171,6 +299,58 @@ func (t *CheckType) UnmarshalJSON(data []byte) (err error) {
 			t.DeregisterCriticalServiceAfter = time.Duration(v)
 		}
 	}
+	if aux.TLSExpiryWarning == nil {
+		aux.TLSExpiryWarning = aux.TLSExpiryWarningSnake
+	}
+	if aux.TLSExpiryWarning != nil {
+		switch v := aux.TLSExpiryWarning.(type) {
+		case string:
+			if t.TLSExpiryWarning, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.TLSExpiryWarning = time.Duration(v)
+		}
+	}
+	if aux.TLSExpiryCritical == nil {
+		aux.TLSExpiryCritical = aux.TLSExpiryCriticalSnake
+	}
+	if aux.TLSExpiryCritical != nil {
+		switch v := aux.TLSExpiryCritical.(type) {
+		case string:
+			if t.TLSExpiryCritical, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.TLSExpiryCritical = time.Duration(v)
+		}
+	}
+	if aux.FlapWindow == nil {
+		aux.FlapWindow = aux.FlapWindowSnake
+	}
+	if aux.FlapWindow != nil {
+		switch v := aux.FlapWindow.(type) {
+		case string:
+			if t.FlapWindow, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.FlapWindow = time.Duration(v)
+		}
+	}
+	if aux.ResponseTimeWarning == nil {
+		aux.ResponseTimeWarning = aux.ResponseTimeWarningSnake
+	}
+	if aux.ResponseTimeWarning != nil {
+		switch v := aux.ResponseTimeWarning.(type) {
+		case string:
+			if t.ResponseTimeWarning, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.ResponseTimeWarning = time.Duration(v)
+		}
+	}
 	if (aux.H2PING != "" && !aux.H2PingUseTLSSnake) || (aux.H2PING == "" && aux.H2PingUseTLSSnake) {
 		t.H2PingUseTLS = aux.H2PingUseTLSSnake
 	}
@@ -181,13 +361,13 @@ func (t *CheckType) UnmarshalJSON(data []byte) (err error) {
 
 // Validate returns an error message if the check is invalid
 func (c *CheckType) Validate() error {
-	intervalCheck := c.IsScript() || c.HTTP != "" || c.TCP != "" || c.UDP != "" || c.GRPC != "" || c.H2PING != "" || c.OSService != ""
+	intervalCheck := c.IsScript() || c.HTTP != "" || c.TCP != "" || c.UDP != "" || c.GRPC != "" || c.H2PING != "" || c.OSService != "" || c.DNS != "" || c.TLS != ""
 
 	if c.Interval > 0 && c.TTL > 0 {
 		return fmt.Errorf("Interval and TTL cannot both be specified")
 	}
 	if intervalCheck && c.Interval <= 0 {
-		return fmt.Errorf("Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP or OSService checks")
+		return fmt.Errorf("Interval must be > 0 for Script, HTTP, H2PING, TCP, UDP, OSService, DNS or TLS checks")
 	}
 	if intervalCheck && c.IsAlias() {
 		return fmt.Errorf("Interval cannot be set for Alias checks")
@@ -195,15 +375,72 @@ func (c *CheckType) Validate() error {
 	if c.IsAlias() && c.TTL > 0 {
 		return fmt.Errorf("TTL must be not be set for Alias checks")
 	}
-	if !intervalCheck && !c.IsAlias() && c.TTL <= 0 {
+	if intervalCheck && c.IsAggregate() {
+		return fmt.Errorf("Interval cannot be set for Aggregate checks")
+	}
+	if c.IsAggregate() && c.TTL > 0 {
+		return fmt.Errorf("TTL must be not be set for Aggregate checks")
+	}
+	if c.IsAlias() && c.IsAggregate() {
+		return fmt.Errorf("Alias and Aggregate cannot both be specified")
+	}
+	if !intervalCheck && !c.IsAlias() && !c.IsAggregate() && c.TTL <= 0 {
 		return fmt.Errorf("TTL must be > 0 for TTL checks")
 	}
+	switch c.AggregateRule {
+	case "", AggregateCriticalIfAny, AggregateCriticalIfMoreThan, AggregateWarningIfQuorumLost:
+	default:
+		return fmt.Errorf("AggregateRule must be one of %s, %s or %s",
+			AggregateCriticalIfAny, AggregateCriticalIfMoreThan, AggregateWarningIfQuorumLost)
+	}
+	if c.AggregateThreshold < 0 {
+		return fmt.Errorf("AggregateThreshold must be positive")
+	}
+	if c.DNS != "" && c.DNSQuery == "" {
+		return fmt.Errorf("DNSQuery must be set for DNS checks")
+	}
+	if c.DNSMinAnswers < 0 {
+		return fmt.Errorf("DNSMinAnswers must be positive")
+	}
+	switch c.TLSStartTLS {
+	case "", "smtp", "postgres":
+	default:
+		return fmt.Errorf("TLSStartTLS must be one of smtp or postgres")
+	}
+	if c.ResponseBodyRegex != "" {
+		if _, err := regexp.Compile(c.ResponseBodyRegex); err != nil {
+			return fmt.Errorf("ResponseBodyRegex is invalid: %v", err)
+		}
+	}
+	if c.ResponseJSONPath != "" {
+		if _, err := jsonpath.Parse(c.ResponseJSONPath); err != nil {
+			return fmt.Errorf("ResponseJSONPath is invalid: %v", err)
+		}
+	}
+	if c.ResponseJSONValue != "" && c.ResponseJSONPath == "" {
+		return fmt.Errorf("ResponseJSONValue requires ResponseJSONPath to be set")
+	}
+	if c.ResponseTimeWarning < 0 {
+		return fmt.Errorf("ResponseTimeWarning must be positive")
+	}
+	if c.TLSExpiryWarning < 0 || c.TLSExpiryCritical < 0 {
+		return fmt.Errorf("TLSExpiryWarning and TLSExpiryCritical must be positive")
+	}
+	if c.TLSExpiryWarning > 0 && c.TLSExpiryCritical > c.TLSExpiryWarning {
+		return fmt.Errorf("TLSExpiryCritical can't be longer than TLSExpiryWarning")
+	}
 	if c.OutputMaxSize < 0 {
 		return fmt.Errorf("MaxOutputMaxSize must be positive")
 	}
 	if c.FailuresBeforeWarning > c.FailuresBeforeCritical {
 		return fmt.Errorf("FailuresBeforeWarning can't be higher than FailuresBeforeCritical")
 	}
+	if c.FlapThreshold < 0 || c.FlapWindow < 0 {
+		return fmt.Errorf("FlapThreshold and FlapWindow must be positive")
+	}
+	if c.FlapThreshold > 0 && (c.IsTTL() || c.IsAlias() || c.IsAggregate()) {
+		return fmt.Errorf("FlapThreshold cannot be set for TTL, Alias or Aggregate checks")
+	}
 
 	return nil
 }
@@ -218,6 +455,11 @@ func (c *CheckType) IsAlias() bool {
 	return c.AliasNode != "" || c.AliasService != ""
 }
 
+// IsAggregate checks if this is an aggregate check.
+func (c *CheckType) IsAggregate() bool {
+	return len(c.AggregateCheckIDs) > 0 || c.AggregateService != "" || c.AggregateServiceTag != ""
+}
+
 // IsScript checks if this is a check that execs some kind of script.
 func (c *CheckType) IsScript() bool {
 	return len(c.ScriptArgs) > 0
@@ -267,6 +509,16 @@ func (c *CheckType) IsOSService() bool {
 	return c.OSService != "" && c.Interval > 0
 }
 
+// IsDNS checks if this is a DNS type
+func (c *CheckType) IsDNS() bool {
+	return c.DNS != "" && c.Interval > 0
+}
+
+// IsTLS checks if this is a TLS type
+func (c *CheckType) IsTLS() bool {
+	return c.TLS != "" && c.Interval > 0
+}
+
 func (c *CheckType) Type() string {
 	switch {
 	case c.IsGRPC():
@@ -281,6 +533,8 @@ func (c *CheckType) Type() string {
 		return "udp"
 	case c.IsAlias():
 		return "alias"
+	case c.IsAggregate():
+		return "aggregate"
 	case c.IsDocker():
 		return "docker"
 	case c.IsScript():
@@ -289,6 +543,10 @@ func (c *CheckType) Type() string {
 		return "h2ping"
 	case c.IsOSService():
 		return "os_service"
+	case c.IsDNS():
+		return "dns"
+	case c.IsTLS():
+		return "tls"
 	default:
 		return ""
 	}
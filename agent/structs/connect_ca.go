@@ -53,6 +53,21 @@ type IndexedCARoots struct {
 	// Roots is a list of root CA certs to trust.
 	Roots []*CARoot
 
+	// RevokedLeafs is the list of leaf certificates and identities revoked in
+	// the cluster. Leaf certificates matching one of them must not be trusted
+	// even though they chain to one of the Roots. They are only used by agents
+	// and are not returned by the HTTP API, which lists them with the
+	// ConnectCA.RevokedLeafs RPC instead.
+	RevokedLeafs []*CARevokedLeaf `json:"-"`
+
+	// LeafCRLs are the PEM encoded CRLs published by every datacenter for its
+	// leaf signing certificates, revoking the leaf certificates in
+	// RevokedLeafs by serial number. Proxies reject the leaf certificates of
+	// any CA they don't have a CRL for, so they are only set while leaf
+	// certificates are revoked by serial number and every datacenter
+	// publishes its CRLs. They are not returned by the HTTP API.
+	LeafCRLs []string `json:"-"`
+
 	// QueryMeta contains the meta sent via a header. We ignore for JSON
 	// so this whole structure can be returned.
 	QueryMeta `json:"-"`
@@ -67,6 +82,20 @@ func (r IndexedCARoots) Active() *CARoot {
 	return nil
 }
 
+// LeafRevoked returns the revocation matching the leaf certificate with the
+// given serial number or SPIFFE ID, or nil if it isn't revoked.
+func (r IndexedCARoots) LeafRevoked(serialNumber, spiffeID string) *CARevokedLeaf {
+	for _, revoked := range r.RevokedLeafs {
+		if revoked.SerialNumber != "" && revoked.SerialNumber == serialNumber {
+			return revoked
+		}
+		if revoked.SpiffeID != "" && revoked.SpiffeID == spiffeID {
+			return revoked
+		}
+	}
+	return nil
+}
+
 // CARoot represents a root CA certificate that is trusted.
 type CARoot struct {
 	// ID is a globally unique ID (UUID) representing this CA chain. It is
@@ -166,6 +195,92 @@ func (c *CARoot) Clone() *CARoot {
 // CARoots is a list of CARoot structures.
 type CARoots []*CARoot
 
+// CARevokedLeaf is a leaf certificate revoked before its expiration, or an
+// identity for which all the leaf certificates are revoked.
+type CARevokedLeaf struct {
+	// ID uniquely identifies the revocation. It is the SerialNumber or the
+	// SpiffeID, whichever is set.
+	ID string
+
+	// SerialNumber is the serial number of the revoked leaf certificate,
+	// encoded like IssuedCert.SerialNumber.
+	SerialNumber string `json:",omitempty"`
+
+	// SpiffeID is the URI of the revoked identity. Every leaf certificate
+	// issued for it is revoked and servers refuse to sign new ones until the
+	// revocation is removed.
+	SpiffeID string `json:",omitempty"`
+
+	// Reason is an optional description of why the leaf was revoked.
+	Reason string `json:",omitempty"`
+
+	// RevokedAt is the time the leaf was revoked.
+	RevokedAt time.Time
+
+	RaftIndex
+}
+
+// CALeafCRL is a certificate revocation list signed by one of the leaf
+// signing certificates of a datacenter.
+type CALeafCRL struct {
+	// SigningKeyID is the id of the key that signed the CRL and the leaf
+	// certificates it applies to, encoded like CARoot.SigningKeyID.
+	SigningKeyID string
+
+	// SerialNumbers are the serial numbers of the leaf certificates revoked
+	// by the CRL, encoded like IssuedCert.SerialNumber.
+	SerialNumbers []string `json:",omitempty"`
+
+	// CRL is the PEM encoded CRL.
+	CRL string
+
+	// RetiredAt is the time the signing key stopped signing leaf
+	// certificates after a CA rotation. The CRL is kept until the leaf
+	// certificates it signed have expired. It is zero for the active key.
+	RetiredAt time.Time `json:",omitempty"`
+}
+
+// IsSame returns whether two CRLs are equal.
+func (c *CALeafCRL) IsSame(other *CALeafCRL) bool {
+	return c.SigningKeyID == other.SigningKeyID &&
+		c.CRL == other.CRL &&
+		c.RetiredAt.Equal(other.RetiredAt) &&
+		stringslice.Equal(c.SerialNumbers, other.SerialNumbers)
+}
+
+// IndexedCARevokedLeafs is the list of revoked leaf certificates and
+// identities.
+type IndexedCARevokedLeafs struct {
+	RevokedLeafs []*CARevokedLeaf
+
+	// QueryMeta contains the meta sent via a header. We ignore for JSON
+	// so this whole structure can be returned.
+	QueryMeta `json:"-"`
+}
+
+// CARevokeLeafRequest is the request to revoke a leaf certificate or to
+// remove a revocation.
+type CARevokeLeafRequest struct {
+	// Datacenter is the target for this request.
+	Datacenter string
+
+	// Op is either CAOpRevokeLeaf or CAOpDeleteRevokedLeaf.
+	Op CAOp
+
+	// RevokedLeaf identifies the leaf to revoke, or the revocation to remove,
+	// by either its SerialNumber or its SpiffeID.
+	RevokedLeaf CARevokedLeaf
+
+	// WriteRequest is a common struct containing ACL tokens and other
+	// write-related common elements for requests.
+	WriteRequest
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (q *CARevokeLeafRequest) RequestDatacenter() string {
+	return q.Datacenter
+}
+
 // Active returns the single CARoot that is marked as active, or nil if there
 // is no active root (ex: when they are no roots).
 func (c CARoots) Active() *CARoot {
@@ -188,6 +303,10 @@ type CASignRequest struct {
 	// CSR is the PEM-encoded CSR.
 	CSR string
 
+	// Node is the name of the node requesting the certificate, used to
+	// report the progress of root rotations.
+	Node string
+
 	// WriteRequest is a common struct containing ACL tokens and other
 	// write-related common elements for requests.
 	WriteRequest
@@ -254,6 +373,9 @@ const (
 	CAOpDeleteProviderState           CAOp = "delete-provider-state"
 	CAOpSetRootsAndConfig             CAOp = "set-roots-config"
 	CAOpIncrementProviderSerialNumber CAOp = "increment-provider-serial"
+	CAOpRevokeLeaf                    CAOp = "revoke-leaf"
+	CAOpDeleteRevokedLeaf             CAOp = "delete-revoked-leaf"
+	CAOpSetRevokedLeafs               CAOp = "set-revoked-leafs"
 )
 
 // CARequest is used to modify connect CA data. This is used by the
@@ -279,6 +401,11 @@ type CARequest struct {
 	// ProviderState is the state for the builtin CA provider.
 	ProviderState *CAConsulProviderState
 
+	// RevokedLeafs is the revocation to add or remove for CAOpRevokeLeaf and
+	// CAOpDeleteRevokedLeaf, and the full list of revocations replacing the
+	// existing ones for CAOpSetRevokedLeafs.
+	RevokedLeafs []*CARevokedLeaf
+
 	// WriteRequest is a common struct containing ACL tokens and other
 	// write-related common elements for requests.
 	WriteRequest
@@ -293,6 +420,8 @@ const (
 	ConsulCAProvider = "consul"
 	VaultCAProvider  = "vault"
 	AWSCAProvider    = "aws-pca"
+	PKCS11CAProvider = "pkcs11"
+	ACMECAProvider   = "acme"
 )
 
 // CAConfiguration is the configuration for the current CA plugin.
@@ -333,6 +462,113 @@ type CAConfiguration struct {
 	RaftIndex
 }
 
+// CARotationPlan is the result of a dry-run of a CA configuration change. It
+// describes what applying the configuration would do without changing
+// anything.
+type CARotationPlan struct {
+	// Provider is the CA provider of the new configuration.
+	Provider string
+
+	// CurrentRootID is the ID of the active root.
+	CurrentRootID string
+
+	// RootRotation is true when applying the configuration rotates the root
+	// CA.
+	RootRotation bool
+
+	// NewRootID, NewRootCert and NewIntermediateCerts are the root that would
+	// become active and the chain of intermediates that would be attached to
+	// the leaf certificates it signs, excluding the cross-signed certificate.
+	// They are only set when the root can be generated without side effects,
+	// otherwise they are empty and a warning explains why.
+	NewRootID            string   `json:",omitempty"`
+	NewRootCert          string   `json:",omitempty"`
+	NewIntermediateCerts []string `json:",omitempty"`
+
+	// CrossSign is true when the current root would cross-sign the new root
+	// so that the leaf certificates it signs are trusted by the services which
+	// have not yet observed the new root.
+	CrossSign bool
+
+	// Warnings describes the parts of the change which could disrupt traffic
+	// or could not be checked.
+	Warnings []string `json:",omitempty"`
+}
+
+// CARotationStatus reports how far the leaf certificates of a datacenter have
+// moved to the active root since the last rotation.
+type CARotationStatus struct {
+	// ActiveRootID is the ID of the active root.
+	ActiveRootID string
+
+	// TrackingSince is the time the leader started to record the leaf
+	// certificates it signs. The records are only kept in memory on the
+	// leader, so they are lost and tracking starts again when leadership
+	// changes. Leaf certificates signed before then are unknown.
+	TrackingSince time.Time
+
+	// Complete is true once every instance uses a leaf certificate signed by
+	// the active root and no leaf certificate signed by another root may
+	// still be in use. It is false until LeafCertTTL has passed since
+	// TrackingSince, since the leaf certificates signed before then may still
+	// be in use.
+	Complete bool
+
+	// Instances is the number of service instances in the catalog that use a
+	// leaf certificate: connect proxies, natively integrated services, mesh
+	// gateways and ingress gateways. UpdatedInstances is the number of them
+	// whose agent got a leaf certificate signed by the active root since
+	// TrackingSince.
+	Instances        int
+	UpdatedInstances int
+
+	// Roots reports the leaf certificates signed by each of the trusted roots.
+	Roots []*CARootRotationStatus
+
+	// PendingInstances are the instances which are not known to use a leaf
+	// certificate signed by the active root.
+	PendingInstances []*CARotationInstance
+
+	QueryMeta
+}
+
+// CARootRotationStatus is the rotation status of a single root.
+type CARootRotationStatus struct {
+	ID     string
+	Name   string
+	Active bool
+
+	// RotatedOutAt is the time the root stopped being active and PruneAfter
+	// the time after which it is no longer trusted. Both are zero for the
+	// active root.
+	RotatedOutAt time.Time
+	PruneAfter   time.Time
+
+	// Services, MeshGateways, Agents and Servers count the most recent
+	// unexpired leaf certificates signed by this root for each node and
+	// identity. The instances of a service on the same node share a leaf
+	// certificate and are counted once.
+	Services     int
+	MeshGateways int
+	Agents       int
+	Servers      int
+}
+
+// CARotationInstance is a service instance which is not known to use a leaf
+// certificate signed by the active root.
+type CARotationInstance struct {
+	Node      string
+	ServiceID string
+
+	// Identity is the SPIFFE ID of the leaf certificate of the instance.
+	Identity string
+
+	// RootID is the ID of the root which signed the most recent leaf
+	// certificate of the instance, or empty if none was signed since
+	// TrackingSince.
+	RootID string `json:",omitempty"`
+}
+
 func (c *CAConfiguration) UnmarshalJSON(data []byte) (err error) {
 	type Alias CAConfiguration
 
@@ -560,6 +796,58 @@ type AWSCAProviderConfig struct {
 	DeleteOnExit bool
 }
 
+type PKCS11CAProviderConfig struct {
+	CommonCAProviderConfig `mapstructure:",squash"`
+
+	// Lib is the path to the PKCS#11 module of the token.
+	Lib string
+
+	// TokenLabel and TokenSerial select the token holding the CA keys. Exactly
+	// one of them must be set.
+	TokenLabel  string
+	TokenSerial string
+
+	// PIN is the user PIN used to log into the token.
+	PIN string
+
+	// KeyLabel is the prefix of the labels given to the keys generated in the
+	// token.
+	KeyLabel string
+}
+
+type ACMECAProviderConfig struct {
+	CommonCAProviderConfig `mapstructure:",squash"`
+
+	// DirectoryURL is the URL of the directory of the ACME server issuing the
+	// signing intermediate.
+	DirectoryURL string
+
+	// Domain is the DNS identifier the intermediate is ordered for. The ACME
+	// server must issue CA certificates for it.
+	Domain string
+
+	// Email is an optional contact address for the ACME account.
+	Email string
+
+	// EABKeyID and EABHMACKey are the external account binding credentials
+	// required by some ACME servers to register an account. The HMAC key is
+	// base64url encoded.
+	EABKeyID   string
+	EABHMACKey string
+
+	// HTTPChallengeAddr is the address the leader listens on to answer http-01
+	// challenges while an order is pending.
+	HTTPChallengeAddr string
+
+	// RootCert is the trust anchor of the chain returned by the ACME server.
+	// The last certificate of the chain is used when empty.
+	RootCert string
+
+	// CAFile is a PEM bundle used to verify the TLS certificate of the ACME
+	// server in addition to the system roots.
+	CAFile string
+}
+
 // CALeafOp is the operation for a request related to leaf certificates.
 type CALeafOp string
 
@@ -56,6 +56,10 @@ type FederationState struct {
 	// this datacenter.
 	MeshGateways CheckServiceNodes `json:",omitempty"`
 
+	// LeafCRLs are the CRLs of the leaf signing certificates of this
+	// datacenter, revoking the leaf certificates revoked by serial number.
+	LeafCRLs []*CALeafCRL `json:",omitempty"`
+
 	// UpdatedAt keeps track of when this record was modified.
 	UpdatedAt time.Time
 
@@ -110,6 +114,15 @@ func (c *FederationState) IsSame(other *FederationState) bool {
 		}
 	}
 
+	if len(c.LeafCRLs) != len(other.LeafCRLs) {
+		return false
+	}
+	for i := range c.LeafCRLs {
+		if !c.LeafCRLs[i].IsSame(other.LeafCRLs[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
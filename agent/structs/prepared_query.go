@@ -25,8 +25,27 @@ type QueryFailoverOptions struct {
 	// Targets is a fixed list of datacenters and peers to try. This field cannot
 	// be populated with NearestN or Datacenters.
 	Targets []QueryFailoverTarget
+
+	// Policy controls how the failover targets are chosen from. The default
+	// ordered policy tries them one after the other, while the
+	// health-weighted policy queries all of them and prefers the one with
+	// the best fraction of healthy instances, scaled by its Weight and its
+	// position in the list.
+	Policy string `json:",omitempty"`
 }
 
+const (
+	// QueryFailoverPolicyOrdered tries the failover targets in order and uses
+	// the first one with healthy instances. This is the default.
+	QueryFailoverPolicyOrdered = "ordered"
+
+	// QueryFailoverPolicyHealthWeighted queries all the failover targets and
+	// uses the one with the highest fraction of healthy instances multiplied
+	// by its weight, discounted by its position in the list so that nearer
+	// targets are preferred. Ties go to the target that comes first.
+	QueryFailoverPolicyHealthWeighted = "health-weighted"
+)
+
 // AsTargets either returns Targets as is or Datacenters converted into
 // Targets.
 func (f *QueryFailoverOptions) AsTargets() []QueryFailoverTarget {
@@ -47,8 +66,41 @@ type QueryFailoverTarget struct {
 
 	// Datacenter specifies a datacenter to try during failover.
 	Datacenter string
+
+	// Weight is the relative preference for this target when using the
+	// health-weighted failover policy. Zero is treated as 1.
+	Weight int `json:",omitempty"`
 }
 
+// EffectiveWeight returns the weight of the target, applying the default.
+func (t QueryFailoverTarget) EffectiveWeight() int {
+	if t.Weight == 0 {
+		return 1
+	}
+	return t.Weight
+}
+
+// QueryLocalityOptions controls how results are ordered by the locality of
+// their nodes.
+type QueryLocalityOptions struct {
+	// NodeMetaKeys are node metadata keys describing the locality of a node,
+	// from the most to the least specific, for example ["zone", "region"].
+	// Nodes sharing the value of the first key with the source node are
+	// returned first, followed by those sharing the value of the second key
+	// and so on, with all other nodes last.
+	NodeMetaKeys []string `json:",omitempty"`
+}
+
+const (
+	// QueryOrderingShuffle returns the results in a random order, or sorted
+	// by distance when Near is used. This is the default.
+	QueryOrderingShuffle = "shuffle"
+
+	// QueryOrderingWeighted returns the results in a random order where the
+	// instances with a higher service weight are more likely to be first.
+	QueryOrderingWeighted = "weighted"
+)
+
 // QueryDNSOptions controls settings when query results are served over DNS.
 type QueryDNSOptions struct {
 	// TTL is the time to live for the served DNS results.
@@ -82,6 +134,16 @@ type ServiceQuery struct {
 	// is supported to sort near the agent which initiated the request.
 	Near string
 
+	// Ordering controls how the results are ordered, see the QueryOrdering*
+	// constants. Distance sorting with Near takes precedence over it.
+	Ordering string `json:",omitempty"`
+
+	// Locality orders the results by the locality of their nodes relative
+	// to the source node of the query, which is Near if given or the agent
+	// executing the query otherwise. This is applied after the other
+	// ordering, which is kept within each locality tier.
+	Locality QueryLocalityOptions
+
 	// Tags are a set of required and/or disallowed tags. If a tag is in
 	// this list it must be present. If the tag is preceded with "!" then
 	// it is disallowed.
@@ -263,6 +325,10 @@ type PreparedQueryExecuteRequest struct {
 	// the execute request. Used to distance-sort relative to the local node.
 	Agent QuerySource
 
+	// Trace asks for a trace of each evaluation step of the query to be
+	// returned with the results, including the failover targets consulted.
+	Trace bool
+
 	// QueryOptions (unfortunately named here) controls the consistency
 	// settings for the query lookup itself, as well as the service lookups.
 	QueryOptions
@@ -295,6 +361,7 @@ func (q *PreparedQueryExecuteRequest) CacheInfo() cache.RequestInfo {
 		q.QueryIDOrName,
 		q.Limit,
 		q.Connect,
+		q.Trace,
 	}, nil)
 	if err == nil {
 		// If there is an error, we don't set the key. A blank key forces
@@ -322,6 +389,9 @@ type PreparedQueryExecuteRemoteRequest struct {
 	// Connect is the same as ExecuteRequest.
 	Connect bool
 
+	// Trace is the same as ExecuteRequest.
+	Trace bool
+
 	// QueryOptions (unfortunately named here) controls the consistency
 	// settings for the the service lookups.
 	QueryOptions
@@ -356,10 +426,36 @@ type PreparedQueryExecuteResponse struct {
 	// datacenter.
 	Failovers int
 
+	// HealthyFraction is the fraction of the instances matching the query
+	// which passed the health filters, before ACLs and limits were applied.
+	// It is only used to rank datacenters during failover.
+	HealthyFraction float64 `json:"-"`
+
+	// Trace has the evaluation steps of the query, if requested.
+	Trace []PreparedQueryTraceStep `json:",omitempty"`
+
 	// QueryMeta has freshness information about the query.
 	QueryMeta
 }
 
+// PreparedQueryTraceStep is a single evaluation step of a prepared query
+// execution, recorded when a trace is requested.
+type PreparedQueryTraceStep struct {
+	// Step is the kind of step, such as "resolve", "filter-tags" or
+	// "failover-target".
+	Step string
+
+	// Datacenter and PeerName are where the step was evaluated.
+	Datacenter string
+	PeerName   string `json:",omitempty"`
+
+	// Nodes is the number of candidate nodes left after the step.
+	Nodes int
+
+	// Detail is a human-readable description of what the step did.
+	Detail string
+}
+
 // PreparedQueryExplainResponse has the results when explaining a query/
 type PreparedQueryExplainResponse struct {
 	// Query has the fully-rendered query.
@@ -44,6 +44,18 @@ func TestAgentStructs_CheckTypes(t *testing.T) {
 		{&CheckType{HTTP: "http://foo/baz"}, fmt.Errorf("Interval must be > 0 for Script, HTTP, or TCP checks"), "Missing interval"},
 		{&CheckType{TTL: -1}, fmt.Errorf("TTL must be > 0 for TTL checks"), "Negative TTL"},
 		{&CheckType{TTL: 20 * time.Second, Interval: 10 * time.Second}, fmt.Errorf("Interval and TTL cannot both be specified"), "Interval and TTL both set"},
+		{&CheckType{DNS: "127.0.0.1", Interval: 10 * time.Second}, fmt.Errorf("DNSQuery must be set for DNS checks"), "DNS without query"},
+		{&CheckType{TLS: "localhost:443", TLSStartTLS: "imap", Interval: 10 * time.Second}, fmt.Errorf("TLSStartTLS must be one of smtp or postgres"), "TLS with unsupported STARTTLS"},
+		{&CheckType{TLS: "localhost:443", TLSExpiryWarning: time.Hour, TLSExpiryCritical: 2 * time.Hour, Interval: 10 * time.Second}, fmt.Errorf("TLSExpiryCritical can't be longer than TLSExpiryWarning"), "TLS expiry windows reversed"},
+		{&CheckType{HTTP: "http://foo/baz", ResponseBodyRegex: "(", Interval: 10 * time.Second}, fmt.Errorf("ResponseBodyRegex is invalid"), "HTTP with invalid body regex"},
+		{&CheckType{HTTP: "http://foo/baz", ResponseJSONPath: "$.a[0", Interval: 10 * time.Second}, fmt.Errorf("ResponseJSONPath is invalid"), "HTTP with invalid JSON path"},
+		{&CheckType{HTTP: "http://foo/baz", ResponseJSONValue: "ok", Interval: 10 * time.Second}, fmt.Errorf("ResponseJSONValue requires ResponseJSONPath to be set"), "HTTP with JSON value but no path"},
+		{&CheckType{AggregateService: "db", Interval: 10 * time.Second, TCP: "localhost:5432"}, fmt.Errorf("Interval cannot be set for Aggregate checks"), "Aggregate with interval"},
+		{&CheckType{AggregateService: "db", AliasService: "web"}, fmt.Errorf("Alias and Aggregate cannot both be specified"), "Aggregate and Alias both set"},
+		{&CheckType{AggregateService: "db", AggregateRule: "critical-if-all"}, fmt.Errorf("AggregateRule must be one of critical-if-any, critical-if-more-than or warning-if-quorum-lost"), "Aggregate with unknown rule"},
+		{&CheckType{TTL: 10 * time.Second, FlapThreshold: -1}, fmt.Errorf("FlapThreshold and FlapWindow must be positive"), "Negative flap threshold"},
+		{&CheckType{TTL: 10 * time.Second, FlapThreshold: 3}, fmt.Errorf("FlapThreshold cannot be set for TTL, Alias or Aggregate checks"), "TTL with flap threshold"},
+		{&CheckType{AliasService: "web", FlapThreshold: 3}, fmt.Errorf("FlapThreshold cannot be set for TTL, Alias or Aggregate checks"), "Alias with flap threshold"},
 	}
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"regexp"
@@ -83,6 +84,7 @@ const (
 	PeeringTrustBundleWriteType                 = 38
 	PeeringTrustBundleDeleteType                = 39
 	PeeringSecretsWriteType                     = 40
+	ConnectCARevokedLeafType                    = 41 // FSM snapshots only.
 )
 
 const (
@@ -149,6 +151,7 @@ var requestTypeStrings = map[MessageType]string{
 	PeeringTrustBundleWriteType:     "PeeringTrustBundle",
 	PeeringTrustBundleDeleteType:    "PeeringTrustBundleDelete",
 	PeeringSecretsWriteType:         "PeeringSecret",
+	ConnectCARevokedLeafType:        "ConnectCARevokedLeaf", // FSM snapshots only.
 }
 
 const (
@@ -1813,10 +1816,28 @@ type HealthCheckDefinition struct {
 	Method                         string              `json:",omitempty"`
 	Body                           string              `json:",omitempty"`
 	DisableRedirects               bool                `json:",omitempty"`
+	ResponseBodyRegex              string              `json:",omitempty"`
+	ResponseJSONPath               string              `json:",omitempty"`
+	ResponseJSONValue              string              `json:",omitempty"`
+	ResponseHeaders                map[string][]string `json:",omitempty"`
+	ResponseTimeWarning            time.Duration       `json:",omitempty"`
 	TCP                            string              `json:",omitempty"`
 	UDP                            string              `json:",omitempty"`
 	H2PING                         string              `json:",omitempty"`
 	OSService                      string              `json:",omitempty"`
+	DNS                            string              `json:",omitempty"`
+	DNSQuery                       string              `json:",omitempty"`
+	DNSRecordType                  string              `json:",omitempty"`
+	DNSRcode                       string              `json:",omitempty"`
+	DNSMinAnswers                  int                 `json:",omitempty"`
+	DNSExpectedValues              []string            `json:",omitempty"`
+	DNSUseTCP                      bool                `json:",omitempty"`
+	TLS                            string              `json:",omitempty"`
+	TLSStartTLS                    string              `json:",omitempty"`
+	TLSCAFile                      string              `json:",omitempty"`
+	TLSExpectedSANs                []string            `json:",omitempty"`
+	TLSExpiryWarning               time.Duration       `json:",omitempty"`
+	TLSExpiryCritical              time.Duration       `json:",omitempty"`
 	H2PingUseTLS                   bool                `json:",omitempty"`
 	Interval                       time.Duration       `json:",omitempty"`
 	OutputMaxSize                  uint                `json:",omitempty"`
@@ -1829,6 +1850,11 @@ type HealthCheckDefinition struct {
 	GRPCUseTLS                     bool                `json:",omitempty"`
 	AliasNode                      string              `json:",omitempty"`
 	AliasService                   string              `json:",omitempty"`
+	AggregateCheckIDs              []string            `json:",omitempty"`
+	AggregateService               string              `json:",omitempty"`
+	AggregateServiceTag            string              `json:",omitempty"`
+	AggregateRule                  string              `json:",omitempty"`
+	AggregateThreshold             int                 `json:",omitempty"`
 	TTL                            time.Duration       `json:",omitempty"`
 }
 
@@ -1839,12 +1865,18 @@ func (d *HealthCheckDefinition) MarshalJSON() ([]byte, error) {
 		OutputMaxSize                  uint   `json:",omitempty"`
 		Timeout                        string `json:",omitempty"`
 		DeregisterCriticalServiceAfter string `json:",omitempty"`
+		TLSExpiryWarning               string `json:",omitempty"`
+		TLSExpiryCritical              string `json:",omitempty"`
+		ResponseTimeWarning            string `json:",omitempty"`
 		*Alias
 	}{
 		Interval:                       d.Interval.String(),
 		OutputMaxSize:                  d.OutputMaxSize,
 		Timeout:                        d.Timeout.String(),
 		DeregisterCriticalServiceAfter: d.DeregisterCriticalServiceAfter.String(),
+		TLSExpiryWarning:               d.TLSExpiryWarning.String(),
+		TLSExpiryCritical:              d.TLSExpiryCritical.String(),
+		ResponseTimeWarning:            d.ResponseTimeWarning.String(),
 		Alias:                          (*Alias)(d),
 	}
 	if d.Interval == 0 {
@@ -1856,6 +1888,15 @@ func (d *HealthCheckDefinition) MarshalJSON() ([]byte, error) {
 	if d.DeregisterCriticalServiceAfter == 0 {
 		exported.DeregisterCriticalServiceAfter = ""
 	}
+	if d.TLSExpiryWarning == 0 {
+		exported.TLSExpiryWarning = ""
+	}
+	if d.TLSExpiryCritical == 0 {
+		exported.TLSExpiryCritical = ""
+	}
+	if d.ResponseTimeWarning == 0 {
+		exported.ResponseTimeWarning = ""
+	}
 
 	return json.Marshal(exported)
 }
@@ -1867,6 +1908,9 @@ func (t *HealthCheckDefinition) UnmarshalJSON(data []byte) (err error) {
 		Timeout                        interface{}
 		DeregisterCriticalServiceAfter interface{}
 		TTL                            interface{}
+		TLSExpiryWarning               interface{}
+		TLSExpiryCritical              interface{}
+		ResponseTimeWarning            interface{}
 		*Alias
 	}{
 		Alias: (*Alias)(t),
@@ -1914,6 +1958,36 @@ func (t *HealthCheckDefinition) UnmarshalJSON(data []byte) (err error) {
 			t.TTL = time.Duration(v)
 		}
 	}
+	if aux.TLSExpiryWarning != nil {
+		switch v := aux.TLSExpiryWarning.(type) {
+		case string:
+			if t.TLSExpiryWarning, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.TLSExpiryWarning = time.Duration(v)
+		}
+	}
+	if aux.TLSExpiryCritical != nil {
+		switch v := aux.TLSExpiryCritical.(type) {
+		case string:
+			if t.TLSExpiryCritical, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.TLSExpiryCritical = time.Duration(v)
+		}
+	}
+	if aux.ResponseTimeWarning != nil {
+		switch v := aux.ResponseTimeWarning.(type) {
+		case string:
+			if t.ResponseTimeWarning, err = time.ParseDuration(v); err != nil {
+				return err
+			}
+		case float64:
+			t.ResponseTimeWarning = time.Duration(v)
+		}
+	}
 	return nil
 }
 
@@ -1958,6 +2032,11 @@ func (c *HealthCheck) CheckType() *CheckType {
 		ScriptArgs:                     c.Definition.ScriptArgs,
 		AliasNode:                      c.Definition.AliasNode,
 		AliasService:                   c.Definition.AliasService,
+		AggregateCheckIDs:              c.Definition.AggregateCheckIDs,
+		AggregateService:               c.Definition.AggregateService,
+		AggregateServiceTag:            c.Definition.AggregateServiceTag,
+		AggregateRule:                  c.Definition.AggregateRule,
+		AggregateThreshold:             c.Definition.AggregateThreshold,
 		HTTP:                           c.Definition.HTTP,
 		GRPC:                           c.Definition.GRPC,
 		GRPCUseTLS:                     c.Definition.GRPCUseTLS,
@@ -1965,10 +2044,28 @@ func (c *HealthCheck) CheckType() *CheckType {
 		Method:                         c.Definition.Method,
 		Body:                           c.Definition.Body,
 		DisableRedirects:               c.Definition.DisableRedirects,
+		ResponseBodyRegex:              c.Definition.ResponseBodyRegex,
+		ResponseJSONPath:               c.Definition.ResponseJSONPath,
+		ResponseJSONValue:              c.Definition.ResponseJSONValue,
+		ResponseHeaders:                c.Definition.ResponseHeaders,
+		ResponseTimeWarning:            c.Definition.ResponseTimeWarning,
 		TCP:                            c.Definition.TCP,
 		UDP:                            c.Definition.UDP,
 		H2PING:                         c.Definition.H2PING,
 		OSService:                      c.Definition.OSService,
+		DNS:                            c.Definition.DNS,
+		DNSQuery:                       c.Definition.DNSQuery,
+		DNSRecordType:                  c.Definition.DNSRecordType,
+		DNSRcode:                       c.Definition.DNSRcode,
+		DNSMinAnswers:                  c.Definition.DNSMinAnswers,
+		DNSExpectedValues:              c.Definition.DNSExpectedValues,
+		DNSUseTCP:                      c.Definition.DNSUseTCP,
+		TLS:                            c.Definition.TLS,
+		TLSStartTLS:                    c.Definition.TLSStartTLS,
+		TLSCAFile:                      c.Definition.TLSCAFile,
+		TLSExpectedSANs:                c.Definition.TLSExpectedSANs,
+		TLSExpiryWarning:               c.Definition.TLSExpiryWarning,
+		TLSExpiryCritical:              c.Definition.TLSExpiryCritical,
 		H2PingUseTLS:                   c.Definition.H2PingUseTLS,
 		Interval:                       c.Definition.Interval,
 		DockerContainerID:              c.Definition.DockerContainerID,
@@ -2051,6 +2148,60 @@ func (nodes CheckServiceNodes) Shuffle() {
 	}
 }
 
+// WeightedShuffle does an in-place weighted random shuffle, so that nodes
+// with a higher service weight are more likely to be ordered first. Nodes with
+// a weight of zero are always ordered last.
+func (nodes CheckServiceNodes) WeightedShuffle() {
+	// This draws a random key for each node which is biased by its weight
+	// and sorts by it, see Efraimidis and Spirakis, "Weighted random sampling
+	// with a reservoir".
+	keys := make([]float64, len(nodes))
+	for i, node := range nodes {
+		if w := node.weight(); w > 0 {
+			keys[i] = math.Pow(rand.Float64(), 1/float64(w))
+		}
+	}
+	sort.Sort(&weightedNodes{nodes: nodes, keys: keys})
+}
+
+// weight returns the service weight for the node according to its health.
+// Critical nodes are expected to be filtered out already, so any node without
+// a warning check uses the passing weight.
+func (csn CheckServiceNode) weight() int {
+	weights := Weights{Passing: 1, Warning: 1}
+	if csn.Service != nil && csn.Service.Weights != nil {
+		weights = *csn.Service.Weights
+	}
+	for _, check := range csn.Checks {
+		if check.ServiceID != "" && csn.Service != nil && check.ServiceID != csn.Service.ID {
+			continue
+		}
+		if check.Status == api.HealthWarning {
+			return weights.Warning
+		}
+	}
+	return weights.Passing
+}
+
+// weightedNodes sorts nodes by their random keys, in descending order.
+type weightedNodes struct {
+	nodes CheckServiceNodes
+	keys  []float64
+}
+
+func (w *weightedNodes) Len() int {
+	return len(w.nodes)
+}
+
+func (w *weightedNodes) Less(i, j int) bool {
+	return w.keys[i] > w.keys[j]
+}
+
+func (w *weightedNodes) Swap(i, j int) {
+	w.nodes[i], w.nodes[j] = w.nodes[j], w.nodes[i]
+	w.keys[i], w.keys[j] = w.keys[j], w.keys[i]
+}
+
 func (nodes CheckServiceNodes) ToServiceDump() ServiceDump {
 	var ret ServiceDump
 	for i := range nodes {
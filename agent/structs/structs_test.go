@@ -1611,6 +1611,37 @@ func TestCheckServiceNodes_Shuffle(t *testing.T) {
 	}
 }
 
+func TestCheckServiceNodes_WeightedShuffle(t *testing.T) {
+	newNode := func(name string, weights *Weights, status string) CheckServiceNode {
+		return CheckServiceNode{
+			Node:    &Node{Node: name},
+			Service: &NodeService{ID: "web", Service: "web", Weights: weights},
+			Checks: HealthChecks{
+				&HealthCheck{CheckID: "web", ServiceID: "web", Status: status},
+				&HealthCheck{CheckID: "other", ServiceID: "other", Status: api.HealthWarning},
+			},
+		}
+	}
+	nodes := CheckServiceNodes{
+		newNode("zero", &Weights{Passing: 0, Warning: 0}, api.HealthPassing),
+		newNode("default", nil, api.HealthPassing),
+		newNode("heavy", &Weights{Passing: 1000, Warning: 1}, api.HealthPassing),
+		newNode("warning", &Weights{Passing: 1000, Warning: 1}, api.HealthWarning),
+	}
+
+	first := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		nodes.WeightedShuffle()
+		first[nodes[0].Node.Node]++
+		require.Equal(t, "zero", nodes[len(nodes)-1].Node.Node)
+	}
+
+	// The checks of other services on the node don't affect the weight, so
+	// the heavy node should almost always be first.
+	require.GreaterOrEqual(t, first["heavy"], 90)
+	require.Zero(t, first["zero"])
+}
+
 func TestCheckServiceNodes_Filter(t *testing.T) {
 	nodes := CheckServiceNodes{
 		CheckServiceNode{
@@ -309,10 +309,33 @@ func (s *HTTPHandlers) convertOps(resp http.ResponseWriter, req *http.Request) (
 							Header:                         check.Definition.Header,
 							Method:                         check.Definition.Method,
 							Body:                           check.Definition.Body,
+							ResponseBodyRegex:              check.Definition.ResponseBodyRegex,
+							ResponseJSONPath:               check.Definition.ResponseJSONPath,
+							ResponseJSONValue:              check.Definition.ResponseJSONValue,
+							ResponseHeaders:                check.Definition.ResponseHeaders,
+							ResponseTimeWarning:            check.Definition.ResponseTimeWarning.Duration(),
 							TCP:                            check.Definition.TCP,
 							GRPC:                           check.Definition.GRPC,
 							GRPCUseTLS:                     check.Definition.GRPCUseTLS,
 							OSService:                      check.Definition.OSService,
+							DNS:                            check.Definition.DNS,
+							DNSQuery:                       check.Definition.DNSQuery,
+							DNSRecordType:                  check.Definition.DNSRecordType,
+							DNSRcode:                       check.Definition.DNSRcode,
+							DNSMinAnswers:                  check.Definition.DNSMinAnswers,
+							DNSExpectedValues:              check.Definition.DNSExpectedValues,
+							DNSUseTCP:                      check.Definition.DNSUseTCP,
+							TLS:                            check.Definition.TLS,
+							TLSStartTLS:                    check.Definition.TLSStartTLS,
+							TLSCAFile:                      check.Definition.TLSCAFile,
+							TLSExpectedSANs:                check.Definition.TLSExpectedSANs,
+							TLSExpiryWarning:               check.Definition.TLSExpiryWarning.Duration(),
+							TLSExpiryCritical:              check.Definition.TLSExpiryCritical.Duration(),
+							AggregateCheckIDs:              check.Definition.AggregateCheckIDs,
+							AggregateService:               check.Definition.AggregateService,
+							AggregateServiceTag:            check.Definition.AggregateServiceTag,
+							AggregateRule:                  check.Definition.AggregateRule,
+							AggregateThreshold:             check.Definition.AggregateThreshold,
 							Interval:                       interval,
 							Timeout:                        timeout,
 							DeregisterCriticalServiceAfter: deregisterCriticalServiceAfter,
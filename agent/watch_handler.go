@@ -101,8 +101,11 @@ func makeWatchHandler(logger hclog.Logger, handler interface{}) watch.HandlerFun
 	return fn
 }
 
-func makeHTTPWatchHandler(logger hclog.Logger, config *watch.HttpHandlerConfig) watch.HandlerFunc {
-	fn := func(idx uint64, data interface{}) {
+// makeHTTPWatchHandler returns a handler which posts the data to the
+// configured endpoint. Failed requests and non-2xx responses are returned as
+// errors so that the watch plan can retry them.
+func makeHTTPWatchHandler(logger hclog.Logger, config *watch.HttpHandlerConfig) watch.RetryHandlerFunc {
+	fn := func(blockParamVal watch.BlockingParamVal, data interface{}) error {
 		trans := cleanhttp.DefaultTransport()
 
 		// Skip SSL certificate verification if TLSSkipVerify is true
@@ -131,17 +134,19 @@ func makeHTTPWatchHandler(logger hclog.Logger, config *watch.HttpHandlerConfig)
 				"watch", config.Path,
 				"error", err,
 			)
-			return
+			return nil
 		}
 
 		req, err := http.NewRequest(config.Method, config.Path, &inp)
 		if err != nil {
 			logger.Error("Failed to setup http watch", "error", err)
-			return
+			return nil
 		}
 		req = req.WithContext(ctx)
 		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("X-Consul-Index", strconv.FormatUint(idx, 10))
+		if idx, ok := blockParamVal.(watch.WaitIndexVal); ok {
+			req.Header.Add("X-Consul-Index", strconv.FormatUint(uint64(idx), 10))
+		}
 		for key, values := range config.Header {
 			for _, val := range values {
 				req.Header.Add(key, val)
@@ -149,11 +154,7 @@ func makeHTTPWatchHandler(logger hclog.Logger, config *watch.HttpHandlerConfig)
 		}
 		resp, err := httpClient.Do(req)
 		if err != nil {
-			logger.Error("Failed to invoke http watch handler",
-				"watch", config.Path,
-				"error", err,
-			)
-			return
+			return fmt.Errorf("failed to invoke http watch handler %q: %w", config.Path, err)
 		}
 		defer resp.Body.Close()
 
@@ -180,7 +181,9 @@ func makeHTTPWatchHandler(logger hclog.Logger, config *watch.HttpHandlerConfig)
 				"status", resp.Status,
 				"output", outputStr,
 			)
+			return fmt.Errorf("http watch handler %q returned %s", config.Path, resp.Status)
 		}
+		return nil
 	}
 	return fn
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -63,7 +64,25 @@ func TestMakeHTTPWatchHandler(t *testing.T) {
 		Timeout: time.Minute,
 	}
 	handler := makeHTTPWatchHandler(testutil.Logger(t), &config)
-	handler(100, []string{"foo", "bar", "baz"})
+	if err := handler(watch.WaitIndexVal(100), []string{"foo", "bar", "baz"}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+}
+
+func TestMakeHTTPWatchHandler_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	config := watch.HttpHandlerConfig{
+		Path:    server.URL,
+		Timeout: time.Minute,
+	}
+	handler := makeHTTPWatchHandler(testutil.Logger(t), &config)
+	err := handler(watch.WaitIndexVal(100), []string{"foo"})
+	if err == nil || !strings.Contains(err.Error(), "503 Service Unavailable") {
+		t.Fatalf("err: %v", err)
+	}
 }
 
 type raw map[string]interface{}
@@ -877,6 +877,9 @@ func setupTLSRootsAndLeaf(t *testing.T, snap *proxycfg.ConfigSnapshot) {
 	}
 	if snap.Roots != nil {
 		snap.Roots.Roots[0].RootCert = loadTestResource(t, "test-root-cert")
+		if len(snap.Roots.LeafCRLs) > 0 {
+			snap.Roots.LeafCRLs = []string{loadTestResource(t, "test-leaf-crl")}
+		}
 	}
 }
 
@@ -1007,6 +1007,23 @@ func (s *ResourceGenerator) injectConnectFilters(cfgSnap *proxycfg.ConfigSnapsho
 	return nil
 }
 
+// injectRevokedLeafsFilter inserts a filter denying the revoked SPIFFE IDs as
+// the first filter in each filter chain of a public listener.
+func (s *ResourceGenerator) injectRevokedLeafsFilter(cfgSnap *proxycfg.ConfigSnapshot, listener *envoy_listener_v3.Listener) error {
+	revokedFilter, err := makeRevokedLeafsRBACNetworkFilter(cfgSnap.Roots)
+	if err != nil || revokedFilter == nil {
+		return err
+	}
+
+	for idx := range listener.FilterChains {
+		listener.FilterChains[idx].Filters =
+			append([]*envoy_listener_v3.Filter{
+				revokedFilter,
+			}, listener.FilterChains[idx].Filters...)
+	}
+	return nil
+}
+
 const (
 	httpConnectionManagerOldName = "envoy.http_connection_manager"
 	httpConnectionManagerNewName = "envoy.filters.network.http_connection_manager"
@@ -1192,6 +1209,9 @@ func createDownstreamTransportSocketForConnectTLS(cfgSnap *proxycfg.ConfigSnapsh
 			Name:        "envoy.tls.cert_validator.spiffe",
 			TypedConfig: spiffeConfig,
 		}
+	} else {
+		// The SPIFFE validator doesn't support CRLs.
+		injectLeafCRLs(tlsContext, cfgSnap.Roots)
 	}
 
 	return makeDownstreamTLSTransportSocket(&envoy_tls_v3.DownstreamTlsContext{
@@ -1430,6 +1450,11 @@ func (s *ResourceGenerator) finalizePublicListenerFromConfig(l *envoy_listener_v
 		}
 	}
 
+	// Deny revoked identities before any other filter runs.
+	if err := s.injectRevokedLeafsFilter(cfgSnap, l); err != nil {
+		return err
+	}
+
 	// Always apply TLS certificates
 	if err := s.injectConnectTLSForPublicListener(cfgSnap, l); err != nil {
 		return nil
@@ -1693,12 +1718,14 @@ type terminatingGatewayFilterChainOpts struct {
 }
 
 func (s *ResourceGenerator) makeFilterChainTerminatingGateway(cfgSnap *proxycfg.ConfigSnapshot, tgtwyOpts terminatingGatewayFilterChainOpts) (*envoy_listener_v3.FilterChain, error) {
+	commonTLSContext := makeCommonTLSContext(
+		cfgSnap.TerminatingGateway.ServiceLeaves[tgtwyOpts.service],
+		cfgSnap.RootPEMs(),
+		makeTLSParametersFromProxyTLSConfig(cfgSnap.MeshConfigTLSIncoming()),
+	)
+	injectLeafCRLs(commonTLSContext, cfgSnap.Roots)
 	tlsContext := &envoy_tls_v3.DownstreamTlsContext{
-		CommonTlsContext: makeCommonTLSContext(
-			cfgSnap.TerminatingGateway.ServiceLeaves[tgtwyOpts.service],
-			cfgSnap.RootPEMs(),
-			makeTLSParametersFromProxyTLSConfig(cfgSnap.MeshConfigTLSIncoming()),
-		),
+		CommonTlsContext:         commonTLSContext,
 		RequireClientCertificate: &wrapperspb.BoolValue{Value: true},
 	}
 	transportSocket, err := makeDownstreamTLSTransportSocket(tlsContext)
@@ -1715,6 +1742,15 @@ func (s *ResourceGenerator) makeFilterChainTerminatingGateway(cfgSnap *proxycfg.
 	// This controls if we do L4 or L7 intention checks.
 	useHTTPFilter := structs.IsProtocolHTTPLike(tgtwyOpts.protocol)
 
+	// Connections from revoked identities are denied before anything else.
+	revokedFilter, err := makeRevokedLeafsRBACNetworkFilter(cfgSnap.Roots)
+	if err != nil {
+		return nil, err
+	}
+	if revokedFilter != nil {
+		filterChain.Filters = append(filterChain.Filters, revokedFilter)
+	}
+
 	// If this is L4, the first filter we setup is to do intention checks.
 	if !useHTTPFilter {
 		authFilter, err := makeRBACNetworkFilter(
@@ -2603,6 +2639,32 @@ func makeCommonTLSContext(
 	}
 }
 
+// injectLeafCRLs configures the validation context of the TLS context to
+// reject the leaf certificates revoked by serial number, using the CRLs
+// published by the datacenters.
+func injectLeafCRLs(tlsContext *envoy_tls_v3.CommonTlsContext, roots *structs.IndexedCARoots) {
+	if tlsContext == nil || roots == nil || len(roots.LeafCRLs) == 0 {
+		return
+	}
+	typ, ok := tlsContext.ValidationContextType.(*envoy_tls_v3.CommonTlsContext_ValidationContext)
+	if !ok {
+		return
+	}
+
+	var crls string
+	for _, crl := range roots.LeafCRLs {
+		crls += lib.EnsureTrailingNewline(crl)
+	}
+	typ.ValidationContext.Crl = &envoy_core_v3.DataSource{
+		Specifier: &envoy_core_v3.DataSource_InlineString{
+			InlineString: crls,
+		},
+	}
+	// There are only CRLs for the leaf signing certificates, the
+	// intermediates in the chain would be rejected otherwise.
+	typ.ValidationContext.OnlyVerifyLeafCertCrl = true
+}
+
 func makeDownstreamTLSTransportSocket(tlsContext *envoy_tls_v3.DownstreamTlsContext) (*envoy_core_v3.TransportSocket, error) {
 	if tlsContext == nil {
 		return nil, nil
@@ -170,6 +170,38 @@ func TestListenersFromSnapshot(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name: "listener-revoked-leafs",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				snap := proxycfg.TestConfigSnapshot(t, nil, nil)
+				snap.Roots.RevokedLeafs = []*structs.CARevokedLeaf{
+					{
+						ID:           "0a:0b",
+						SerialNumber: "0a:0b",
+					},
+					{
+						ID:       "spiffe://11111111-2222-3333-4444-555555555555.consul/ns/default/dc/dc1/svc/web",
+						SpiffeID: "spiffe://11111111-2222-3333-4444-555555555555.consul/ns/default/dc/dc1/svc/web",
+					},
+				}
+				return snap
+			},
+		},
+		{
+			name: "listener-revoked-leaf-serial",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				snap := proxycfg.TestConfigSnapshot(t, nil, nil)
+				snap.Roots.RevokedLeafs = []*structs.CARevokedLeaf{
+					{
+						ID:           "0a:0b",
+						SerialNumber: "0a:0b",
+					},
+				}
+				// Replaced by the test CRL in setupTLSRootsAndLeaf.
+				snap.Roots.LeafCRLs = []string{"crl"}
+				return snap
+			},
+		},
 		{
 			name: "http2-public-listener",
 			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
@@ -599,6 +631,21 @@ func TestListenersFromSnapshot(t *testing.T) {
 				return proxycfg.TestConfigSnapshotTerminatingGateway(t, true, nil, nil)
 			},
 		},
+		{
+			name: "terminating-gateway-revoked-leaf-serial",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				snap := proxycfg.TestConfigSnapshotTerminatingGateway(t, true, nil, nil)
+				snap.Roots.RevokedLeafs = []*structs.CARevokedLeaf{
+					{
+						ID:           "0a:0b",
+						SerialNumber: "0a:0b",
+					},
+				}
+				// Replaced by the test CRL in setupTLSRootsAndLeaf.
+				snap.Roots.LeafCRLs = []string{"crl"}
+				return snap
+			},
+		},
 		{
 			name: "terminating-gateway-with-tls-incoming-min-version",
 			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
@@ -34,6 +34,51 @@ func makeRBACNetworkFilter(
 	return makeFilter("envoy.filters.network.rbac", cfg)
 }
 
+// makeRevokedLeafsRBACNetworkFilter returns a network RBAC filter denying
+// connections from the SPIFFE IDs whose leaf certificates have been revoked,
+// or nil if there are none. Revocations by serial number are enforced by the
+// CRLs in the TLS validation context instead, see injectLeafCRLs.
+func makeRevokedLeafsRBACNetworkFilter(roots *structs.IndexedCARoots) (*envoy_listener_v3.Filter, error) {
+	if roots == nil {
+		return nil, nil
+	}
+
+	var principals []*envoy_rbac_v3.Principal
+	for _, revoked := range roots.RevokedLeafs {
+		if revoked.SpiffeID == "" {
+			continue
+		}
+		principals = append(principals, &envoy_rbac_v3.Principal{
+			Identifier: &envoy_rbac_v3.Principal_Authenticated_{
+				Authenticated: &envoy_rbac_v3.Principal_Authenticated{
+					PrincipalName: &envoy_matcher_v3.StringMatcher{
+						MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
+							Exact: revoked.SpiffeID,
+						},
+					},
+				},
+			},
+		})
+	}
+	if len(principals) == 0 {
+		return nil, nil
+	}
+
+	cfg := &envoy_network_rbac_v3.RBAC{
+		StatPrefix: "connect_revoked",
+		Rules: &envoy_rbac_v3.RBAC{
+			Action: envoy_rbac_v3.RBAC_DENY,
+			Policies: map[string]*envoy_rbac_v3.Policy{
+				"consul-revoked-leafs": {
+					Permissions: []*envoy_rbac_v3.Permission{anyPermission()},
+					Principals:  principals,
+				},
+			},
+		},
+	}
+	return makeFilter("envoy.filters.network.rbac", cfg)
+}
+
 func makeRBACHTTPFilter(
 	intentions structs.Intentions,
 	intentionDefaultAllow bool,
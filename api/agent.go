@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 // ServiceKind is the kind of service being registered.
@@ -68,6 +69,13 @@ type AgentCheck struct {
 	Partition   string `json:",omitempty"`
 }
 
+// AgentCheckTransition is a change of the status of a check known to the agent
+type AgentCheckTransition struct {
+	Status string
+	Output string
+	Time   time.Time
+}
+
 // AgentWeights represent optional weights for a service
 type AgentWeights struct {
 	Passing int
@@ -334,8 +342,26 @@ type AgentServiceCheck struct {
 	Header                 map[string][]string `json:",omitempty"`
 	Method                 string              `json:",omitempty"`
 	Body                   string              `json:",omitempty"`
+	ResponseBodyRegex      string              `json:",omitempty"`
+	ResponseJSONPath       string              `json:",omitempty"`
+	ResponseJSONValue      string              `json:",omitempty"`
+	ResponseHeaders        map[string][]string `json:",omitempty"`
+	ResponseTimeWarning    string              `json:",omitempty"`
 	TCP                    string              `json:",omitempty"`
 	UDP                    string              `json:",omitempty"`
+	DNS                    string              `json:",omitempty"`
+	DNSQuery               string              `json:",omitempty"`
+	DNSRecordType          string              `json:",omitempty"`
+	DNSRcode               string              `json:",omitempty"`
+	DNSMinAnswers          int                 `json:",omitempty"`
+	DNSExpectedValues      []string            `json:",omitempty"`
+	DNSUseTCP              bool                `json:",omitempty"`
+	TLS                    string              `json:",omitempty"`
+	TLSStartTLS            string              `json:",omitempty"`
+	TLSCAFile              string              `json:",omitempty"`
+	TLSExpectedSANs        []string            `json:",omitempty"`
+	TLSExpiryWarning       string              `json:",omitempty"`
+	TLSExpiryCritical      string              `json:",omitempty"`
 	Status                 string              `json:",omitempty"`
 	Notes                  string              `json:",omitempty"`
 	TLSServerName          string              `json:",omitempty"`
@@ -346,9 +372,16 @@ type AgentServiceCheck struct {
 	H2PingUseTLS           bool                `json:",omitempty"`
 	AliasNode              string              `json:",omitempty"`
 	AliasService           string              `json:",omitempty"`
+	AggregateCheckIDs      []string            `json:",omitempty"`
+	AggregateService       string              `json:",omitempty"`
+	AggregateServiceTag    string              `json:",omitempty"`
+	AggregateRule          string              `json:",omitempty"`
+	AggregateThreshold     int                 `json:",omitempty"`
 	SuccessBeforePassing   int                 `json:",omitempty"`
 	FailuresBeforeWarning  int                 `json:",omitempty"`
 	FailuresBeforeCritical int                 `json:",omitempty"`
+	FlapThreshold          int                 `json:",omitempty"`
+	FlapWindow             string              `json:",omitempty"`
 
 	// In Consul 0.7 and later, checks that are associated with a service
 	// may also contain this optional DeregisterCriticalServiceAfter field,
@@ -993,6 +1026,26 @@ func (a *Agent) CheckDeregisterOpts(checkID string, q *QueryOptions) error {
 	return nil
 }
 
+// CheckHistory returns the most recent status transitions of a check
+// registered with the local agent, oldest first.
+func (a *Agent) CheckHistory(checkID string, q *QueryOptions) ([]*AgentCheckTransition, error) {
+	r := a.c.newRequest("GET", "/v1/agent/check/"+checkID+"/history")
+	r.setQueryOptions(q)
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+	var out []*AgentCheckTransition
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Join is used to instruct the agent to attempt a join to
 // another cluster member
 func (a *Agent) Join(addr string, wan bool) error {
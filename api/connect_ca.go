@@ -104,6 +104,114 @@ type CARoot struct {
 	ModifyIndex uint64
 }
 
+// CARevokedLeaf is a leaf certificate, or all the leaf certificates of a
+// SPIFFE ID, that has been revoked. Exactly one of SerialNumber and SpiffeID
+// is set.
+type CARevokedLeaf struct {
+	// ID uniquely identifies the revocation, it is the canonical form of
+	// either SerialNumber or SpiffeID.
+	ID string `json:",omitempty"`
+
+	// SerialNumber is the serial number of the revoked certificate, encoded
+	// in hex separated by :.
+	SerialNumber string `json:",omitempty"`
+
+	// SpiffeID is the URI SAN of the revoked certificates.
+	SpiffeID string `json:",omitempty"`
+
+	// Reason is an optional free-form explanation of the revocation.
+	Reason string `json:",omitempty"`
+
+	// RevokedAt is the time at which the revocation was written.
+	RevokedAt time.Time
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// CARotationPlan is what applying a CA configuration would do, as returned
+// by a dry-run.
+type CARotationPlan struct {
+	// Provider is the CA provider of the new configuration.
+	Provider string
+
+	// CurrentRootID is the ID of the active root.
+	CurrentRootID string
+
+	// RootRotation is true when applying the configuration rotates the root
+	// CA.
+	RootRotation bool
+
+	// NewRootID, NewRootCert and NewIntermediateCerts are the root that would
+	// become active and its chain of intermediates. They are only set when the
+	// provider can generate its root without side effects.
+	NewRootID            string   `json:",omitempty"`
+	NewRootCert          string   `json:",omitempty"`
+	NewIntermediateCerts []string `json:",omitempty"`
+
+	// CrossSign is true when the current root would cross-sign the new root.
+	CrossSign bool
+
+	// Warnings describes the parts of the change which could disrupt traffic
+	// or could not be checked.
+	Warnings []string `json:",omitempty"`
+}
+
+// CARotationStatus reports which roots signed the leaf certificates in use
+// in a datacenter.
+type CARotationStatus struct {
+	// ActiveRootID is the ID of the active root.
+	ActiveRootID string
+
+	// TrackingSince is the time the leader started to record the leaf
+	// certificates it signs. It is reset when leadership changes.
+	TrackingSince time.Time
+
+	// Complete is true once every instance uses a leaf certificate signed by
+	// the active root and no leaf certificate signed by another root may
+	// still be in use.
+	Complete bool
+
+	// Instances is the number of service instances using a leaf certificate
+	// and UpdatedInstances how many of them use one signed by the active
+	// root.
+	Instances        int
+	UpdatedInstances int
+
+	Roots []*CARootRotationStatus
+
+	// PendingInstances are the instances which are not known to use a leaf
+	// certificate signed by the active root.
+	PendingInstances []*CARotationInstance
+}
+
+// CARootRotationStatus counts the leaf certificates signed by a root for
+// each node and identity.
+type CARootRotationStatus struct {
+	ID           string
+	Name         string
+	Active       bool
+	RotatedOutAt time.Time
+	PruneAfter   time.Time
+
+	Services     int
+	MeshGateways int
+	Agents       int
+	Servers      int
+}
+
+// CARotationInstance is a service instance which is not known to use a leaf
+// certificate signed by the active root.
+type CARotationInstance struct {
+	Node      string
+	ServiceID string
+	Identity  string
+
+	// RootID is the root which signed the most recent leaf certificate of
+	// the instance, or empty if unknown.
+	RootID string `json:",omitempty"`
+}
+
 // LeafCert is a certificate that has been issued by a Connect CA.
 type LeafCert struct {
 	// SerialNumber is the unique serial number for this certificate.
@@ -196,3 +304,124 @@ func (h *Connect) CASetConfig(conf *CAConfig, q *WriteOptions) (*WriteMeta, erro
 	wm.RequestTime = rtt
 	return wm, nil
 }
+
+// CADryRunConfig validates a CA configuration and returns what applying it
+// with CASetConfig would do, without applying it.
+func (h *Connect) CADryRunConfig(conf *CAConfig, q *WriteOptions) (*CARotationPlan, *WriteMeta, error) {
+	r := h.c.newRequest("PUT", "/v1/connect/ca/configuration")
+	r.setWriteOptions(q)
+	r.params.Set("dry-run", "")
+	r.obj = conf
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+
+	var out CARotationPlan
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
+
+// CARotationStatus returns how many identities use a leaf certificate signed
+// by each of the trusted roots.
+func (h *Connect) CARotationStatus(q *QueryOptions) (*CARotationStatus, *QueryMeta, error) {
+	r := h.c.newRequest("GET", "/v1/connect/ca/rotation")
+	r.setQueryOptions(q)
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out CARotationStatus
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
+
+// CARevocations queries the list of revoked leaf certificates.
+func (h *Connect) CARevocations(q *QueryOptions) ([]*CARevokedLeaf, *QueryMeta, error) {
+	r := h.c.newRequest("GET", "/v1/connect/ca/revocations")
+	r.setQueryOptions(q)
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out []*CARevokedLeaf
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
+
+// CARevokeLeaf revokes a leaf certificate by serial number, or all the leaf
+// certificates of a SPIFFE ID. Exactly one of SerialNumber and SpiffeID must
+// be set.
+func (h *Connect) CARevokeLeaf(leaf *CARevokedLeaf, q *WriteOptions) (*WriteMeta, error) {
+	r := h.c.newRequest("PUT", "/v1/connect/ca/revocations")
+	r.setWriteOptions(q)
+	r.obj = leaf
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+	return wm, nil
+}
+
+// CADeleteRevocation removes the revocation of a serial number or SPIFFE ID.
+// Exactly one of serialNumber and spiffeID must be set.
+func (h *Connect) CADeleteRevocation(serialNumber, spiffeID string, q *WriteOptions) (*WriteMeta, error) {
+	r := h.c.newRequest("DELETE", "/v1/connect/ca/revocations")
+	r.setWriteOptions(q)
+	if serialNumber != "" {
+		r.params.Set("serial_number", serialNumber)
+	}
+	if spiffeID != "" {
+		r.params.Set("spiffe_id", spiffeID)
+	}
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+	return wm, nil
+}
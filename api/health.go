@@ -61,6 +61,11 @@ type HealthCheckDefinition struct {
 	Header                                 map[string][]string
 	Method                                 string
 	Body                                   string
+	ResponseBodyRegex                      string
+	ResponseJSONPath                       string
+	ResponseJSONValue                      string
+	ResponseHeaders                        map[string][]string
+	ResponseTimeWarning                    ReadableDuration
 	TLSServerName                          string
 	TLSSkipVerify                          bool
 	TCP                                    string
@@ -68,6 +73,24 @@ type HealthCheckDefinition struct {
 	GRPC                                   string
 	OSService                              string
 	GRPCUseTLS                             bool
+	DNS                                    string
+	DNSQuery                               string
+	DNSRecordType                          string
+	DNSRcode                               string
+	DNSMinAnswers                          int
+	DNSExpectedValues                      []string
+	DNSUseTCP                              bool
+	TLS                                    string
+	TLSStartTLS                            string
+	TLSCAFile                              string
+	TLSExpectedSANs                        []string
+	TLSExpiryWarning                       ReadableDuration
+	TLSExpiryCritical                      ReadableDuration
+	AggregateCheckIDs                      []string
+	AggregateService                       string
+	AggregateServiceTag                    string
+	AggregateRule                          string
+	AggregateThreshold                     int
 	IntervalDuration                       time.Duration `json:"-"`
 	TimeoutDuration                        time.Duration `json:"-"`
 	DeregisterCriticalServiceAfterDuration time.Duration `json:"-"`
@@ -15,6 +15,10 @@ type QueryFailoverOptions struct {
 	// Targets is a fixed list of datacenters and peers to try. This field cannot
 	// be populated with NearestN or Datacenters.
 	Targets []QueryFailoverTarget
+
+	// Policy controls how the failover targets are chosen from, either
+	// "ordered" (the default) or "health-weighted".
+	Policy string `json:",omitempty"`
 }
 
 // Deprecated: use QueryFailoverOptions instead.
@@ -26,6 +30,18 @@ type QueryFailoverTarget struct {
 
 	// Datacenter specifies a datacenter to try during failover.
 	Datacenter string
+
+	// Weight is the relative preference for this target when using the
+	// health-weighted failover policy. Zero is treated as 1.
+	Weight int `json:",omitempty"`
+}
+
+// QueryLocalityOptions controls how results are ordered by the locality of
+// their nodes.
+type QueryLocalityOptions struct {
+	// NodeMetaKeys are node metadata keys describing the locality of a node,
+	// from the most to the least specific, for example ["zone", "region"].
+	NodeMetaKeys []string `json:",omitempty"`
 }
 
 // QueryDNSOptions controls settings when query results are served over DNS.
@@ -48,6 +64,15 @@ type ServiceQuery struct {
 	// the agent which initiated the request by default.
 	Near string
 
+	// Ordering controls how the results are ordered, either "shuffle" (the
+	// default) or "weighted" to honor the service weights. Distance sorting
+	// with Near takes precedence over it.
+	Ordering string `json:",omitempty"`
+
+	// Locality orders the results by the locality of their nodes relative
+	// to Near, or to the agent executing the query if Near isn't set.
+	Locality QueryLocalityOptions
+
 	// Failover controls what we do if there are no healthy nodes in the
 	// local datacenter.
 	Failover QueryFailoverOptions
@@ -152,6 +177,28 @@ type PreparedQueryExecuteResponse struct {
 	// Failovers is a count of how many times we had to query a remote
 	// datacenter.
 	Failovers int
+
+	// Trace has the evaluation steps of the query, if requested with
+	// ExecuteTrace.
+	Trace []PreparedQueryTraceStep `json:",omitempty"`
+}
+
+// PreparedQueryTraceStep is a single evaluation step of a prepared query
+// execution.
+type PreparedQueryTraceStep struct {
+	// Step is the kind of step, such as "resolve", "filter-tags" or
+	// "failover-target".
+	Step string
+
+	// Datacenter and PeerName are where the step was evaluated.
+	Datacenter string
+	PeerName   string `json:",omitempty"`
+
+	// Nodes is the number of candidate nodes left after the step.
+	Nodes int
+
+	// Detail is a human-readable description of what the step did.
+	Detail string
 }
 
 // PreparedQuery can be used to query the prepared query endpoints.
@@ -242,3 +289,30 @@ func (c *PreparedQuery) Execute(queryIDOrName string, q *QueryOptions) (*Prepare
 	}
 	return out, qm, nil
 }
+
+// ExecuteTrace executes a prepared query like Execute, and also returns a
+// trace of each evaluation step in the response, including the failover
+// targets that were consulted.
+func (c *PreparedQuery) ExecuteTrace(queryIDOrName string, q *QueryOptions) (*PreparedQueryExecuteResponse, *QueryMeta, error) {
+	r := c.c.newRequest("GET", "/v1/query/"+queryIDOrName+"/execute")
+	r.setQueryOptions(q)
+	r.params.Set("trace", "")
+	rtt, resp, err := c.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out *PreparedQueryExecuteResponse
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
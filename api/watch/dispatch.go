@@ -0,0 +1,137 @@
+package watch
+
+import (
+	"sync"
+	"time"
+)
+
+// maxPendingUpdates bounds the number of results waiting to be delivered when
+// every result is delivered in order. The oldest ones are dropped beyond it.
+const maxPendingUpdates = 1000
+
+// update is a watch result waiting to be delivered to the handler.
+type update struct {
+	paramVal BlockingParamVal
+	result   interface{}
+}
+
+// dispatcher delivers watch results to a handler in the background, applying
+// the plan's debounce, max wait, min interval and coalesce settings. When
+// debouncing, only the latest result is delivered, as with coalesce.
+type dispatcher struct {
+	debounce    time.Duration
+	maxWait     time.Duration
+	minInterval time.Duration
+	coalesce    bool
+	handle      HybridHandlerFunc
+
+	lock     sync.Mutex
+	pending  []update
+	first    time.Time // when the oldest pending update arrived
+	last     time.Time // when the newest pending update arrived
+	notifyCh chan struct{}
+}
+
+func newDispatcher(p *Plan, handle HybridHandlerFunc) *dispatcher {
+	return &dispatcher{
+		debounce:    p.Debounce,
+		maxWait:     p.MaxWait,
+		minInterval: p.MinInterval,
+		coalesce:    p.Coalesce || p.Debounce > 0,
+		handle:      handle,
+		notifyCh:    make(chan struct{}, 1),
+	}
+}
+
+// enqueue adds a result to be delivered. It never blocks on the handler.
+func (d *dispatcher) enqueue(paramVal BlockingParamVal, result interface{}) {
+	d.lock.Lock()
+	now := time.Now()
+	if len(d.pending) == 0 {
+		d.first = now
+	}
+	d.last = now
+	if d.coalesce {
+		d.pending = d.pending[:0]
+	} else if len(d.pending) >= maxPendingUpdates {
+		d.pending[0] = update{}
+		d.pending = d.pending[1:]
+	}
+	d.pending = append(d.pending, update{paramVal: paramVal, result: result})
+	d.lock.Unlock()
+
+	select {
+	case d.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers pending results as they become due until stopCh is closed.
+func (d *dispatcher) run(stopCh <-chan struct{}) {
+	var lastRun time.Time
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		u, wait, ok := d.next(lastRun)
+		if ok {
+			d.handle(u.paramVal, u.result)
+			lastRun = time.Now()
+			continue
+		}
+
+		var timer *time.Timer
+		var timerCh <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			timerCh = timer.C
+		}
+		select {
+		case <-d.notifyCh:
+		case <-timerCh:
+		case <-stopCh:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// next pops the oldest pending result if it is due. Otherwise it returns how
+// long until it is due, or zero if there is nothing pending.
+func (d *dispatcher) next(lastRun time.Time) (update, time.Duration, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if len(d.pending) == 0 {
+		return update{}, 0, false
+	}
+
+	due := d.last.Add(d.debounce)
+	if d.maxWait > 0 {
+		if deadline := d.first.Add(d.maxWait); deadline.Before(due) {
+			due = deadline
+		}
+	}
+	if !lastRun.IsZero() {
+		if earliest := lastRun.Add(d.minInterval); earliest.After(due) {
+			due = earliest
+		}
+	}
+	if wait := time.Until(due); wait > 0 {
+		return update{}, wait, false
+	}
+
+	u := d.pending[0]
+	d.pending[0] = update{}
+	d.pending = d.pending[1:]
+
+	// The results still pending start a new max wait window, otherwise the
+	// deadline would stay in the past and cancel the debounce under
+	// continuous changes.
+	d.first = time.Now()
+	return u, 0, true
+}
@@ -26,6 +26,9 @@ func init() {
 		"connect_roots": connectRootsWatch,
 		"connect_leaf":  connectLeafWatch,
 		"agent_service": agentServiceWatch,
+		"config_entry":  configEntryWatch,
+		"intentions":    intentionsWatch,
+		"peerings":      peeringsWatch,
 	}
 }
 
@@ -152,14 +155,30 @@ func serviceWatch(params map[string]interface{}) (WatcherFunc, error) {
 		return nil, err
 	}
 
+	// When streaming is set every query, including the first, must be served
+	// from the agent's materialized view of the service's health, which the
+	// agent keeps up to date from a single subscription to the servers. Only
+	// agents with use_streaming_backend enabled have that view, the watch
+	// errors rather than silently making blocking queries against the servers
+	// on the others.
+	streaming := false
+	if err := assignValueBool(params, "streaming", &streaming); err != nil {
+		return nil, err
+	}
+
 	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
 		health := p.client.Health()
 		opts := makeQueryOptionsWithContext(p, stale)
+		opts.UseCache = streaming
 		defer p.cancelFunc()
 		nodes, meta, err := health.ServiceMultipleTags(service, tags, passingOnly, &opts)
 		if err != nil {
 			return nil, nil, err
 		}
+		if streaming && meta.QueryBackend != consulapi.QueryBackendStreaming {
+			return nil, nil, fmt.Errorf("agent served the watch from the %q query backend instead of streaming, use_streaming_backend must be enabled on the agent",
+				meta.QueryBackend)
+		}
 		return WaitIndexVal(meta.LastIndex), nodes, err
 	}
 	return fn, nil
@@ -307,6 +326,113 @@ func agentServiceWatch(params map[string]interface{}) (WatcherFunc, error) {
 	return fn, nil
 }
 
+// configEntryWatch is used to watch the config entries of a kind, or a single
+// config entry if a name is given.
+func configEntryWatch(params map[string]interface{}) (WatcherFunc, error) {
+	stale := false
+	if err := assignValueBool(params, "stale", &stale); err != nil {
+		return nil, err
+	}
+
+	var kind, name string
+	if err := assignValue(params, "kind", &kind); err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		return nil, fmt.Errorf("Must specify a config entry kind to watch")
+	}
+	if err := assignValue(params, "name", &name); err != nil {
+		return nil, err
+	}
+
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		configEntries := p.client.ConfigEntries()
+		opts := makeQueryOptionsWithContext(p, stale)
+		defer p.cancelFunc()
+
+		// A single entry is also read by listing its kind, as reading an entry
+		// that doesn't exist is an error which can't be blocked on. This way
+		// the handler is invoked with nil once the entry is deleted, and again
+		// when it is recreated.
+		entries, meta, err := configEntries.List(kind, &opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if name == "" {
+			return WaitIndexVal(meta.LastIndex), entries, err
+		}
+
+		var entry consulapi.ConfigEntry
+		for _, e := range entries {
+			if e.GetName() == name {
+				entry = e
+				break
+			}
+		}
+		return WaitIndexVal(meta.LastIndex), entry, err
+	}
+	return fn, nil
+}
+
+// intentionsWatch is used to watch intentions, optionally only those which
+// apply to a given destination service.
+func intentionsWatch(params map[string]interface{}) (WatcherFunc, error) {
+	stale := false
+	if err := assignValueBool(params, "stale", &stale); err != nil {
+		return nil, err
+	}
+
+	var destination string
+	if err := assignValue(params, "destination", &destination); err != nil {
+		return nil, err
+	}
+
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		connect := p.client.Connect()
+		opts := makeQueryOptionsWithContext(p, stale)
+		defer p.cancelFunc()
+
+		if destination == "" {
+			intentions, meta, err := connect.Intentions(&opts)
+			if err != nil {
+				return nil, nil, err
+			}
+			return WaitIndexVal(meta.LastIndex), intentions, err
+		}
+
+		// Matching by destination includes any wildcard intentions which
+		// apply to the service, ordered by precedence.
+		matches, meta, err := connect.IntentionMatch(&consulapi.IntentionMatch{
+			By:    consulapi.IntentionMatchDestination,
+			Names: []string{destination},
+		}, &opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return WaitIndexVal(meta.LastIndex), matches[destination], err
+	}
+	return fn, nil
+}
+
+// peeringsWatch is used to watch the cluster peerings.
+func peeringsWatch(params map[string]interface{}) (WatcherFunc, error) {
+	// We don't support stale since peering reads are always served by the
+	// leader.
+
+	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
+		peerings := p.client.Peerings()
+		opts := makeQueryOptionsWithContext(p, false)
+		defer p.cancelFunc()
+
+		list, meta, err := peerings.List(opts.Context(), &opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return WaitIndexVal(meta.LastIndex), list, err
+	}
+	return fn, nil
+}
+
 func makeQueryOptionsWithContext(p *Plan, stale bool) consulapi.QueryOptions {
 	ctx, cancel := context.WithCancel(context.Background())
 	p.setCancelFunc(cancel)
@@ -12,14 +12,19 @@ import (
 	"encoding/pem"
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/api/watch"
 	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/require"
 )
 
@@ -520,6 +525,156 @@ func TestServiceWatch(t *testing.T) {
 	}
 }
 
+func TestServiceWatch_Streaming(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock    sync.Mutex
+		queries []url.Values
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/health/service/foo", r.URL.Path)
+
+		lock.Lock()
+		queries = append(queries, r.URL.Query())
+		n := len(queries)
+		lock.Unlock()
+
+		entries := []*api.ServiceEntry{}
+		if n > 1 {
+			entries = append(entries, &api.ServiceEntry{
+				Node:    &api.Node{Node: "node1"},
+				Service: &api.AgentService{ID: "foo", Service: "foo"},
+			})
+		}
+		w.Header().Set("X-Consul-Index", fmt.Sprintf("%d", n))
+		w.Header().Set("X-Consul-LastContact", "0")
+		w.Header().Set("X-Cache", "HIT")
+		w.Header().Set("X-Consul-Query-Backend", "streaming")
+		require.NoError(t, json.NewEncoder(w).Encode(entries))
+	}))
+	defer srv.Close()
+
+	var (
+		wakeups  [][]*api.ServiceEntry
+		notifyCh = make(chan struct{})
+	)
+
+	plan := mustParse(t, `{"type":"service", "service":"foo", "streaming":true}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		v, ok := raw.([]*api.ServiceEntry)
+		if !ok {
+			return // ignore
+		}
+		wakeups = append(wakeups, v)
+		notifyCh <- struct{}{}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(srv.Listener.Addr().String()); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+	defer plan.Stop()
+
+	<-notifyCh
+	<-notifyCh
+
+	plan.Stop()
+	wg.Wait()
+
+	require.Len(t, wakeups, 2)
+	require.Len(t, wakeups[0], 0)
+	require.Len(t, wakeups[1], 1)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.GreaterOrEqual(t, len(queries), 2)
+	for _, q := range queries {
+		// Every query, including the first non-blocking one, must be served
+		// from the agent's view rather than going to the servers.
+		_, ok := q["cached"]
+		require.True(t, ok, "expected cached query param: %v", q)
+	}
+	require.Equal(t, "1", queries[1].Get("index"))
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func TestServiceWatch_StreamingUnavailable(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// An agent without use_streaming_backend serves the query from its
+		// cache, which makes blocking queries against the servers.
+		w.Header().Set("X-Consul-Index", "1")
+		w.Header().Set("X-Consul-LastContact", "0")
+		w.Header().Set("X-Cache", "MISS")
+		w.Header().Set("X-Consul-Query-Backend", "blocking-query")
+		require.NoError(t, json.NewEncoder(w).Encode([]*api.ServiceEntry{}))
+	}))
+	defer srv.Close()
+
+	logs := &lockedBuffer{}
+	plan := mustParse(t, `{"type":"service", "service":"foo", "streaming":true}`)
+	plan.Logger = hclog.New(&hclog.LoggerOptions{Output: logs})
+	var handled int32
+	plan.Handler = func(idx uint64, raw interface{}) {
+		atomic.AddInt32(&handled, 1)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- plan.Run(srv.Listener.Addr().String())
+	}()
+	defer plan.Stop()
+
+	retry.Run(t, func(r *retry.R) {
+		require.Contains(r, logs.String(), `"blocking-query" query backend instead of streaming`)
+	})
+
+	plan.Stop()
+	require.NoError(t, <-errCh)
+	require.Zero(t, atomic.LoadInt32(&handled))
+}
+
+func TestServiceWatch_StreamingInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := watch.Parse(map[string]interface{}{
+		"type":      "service",
+		"service":   "foo",
+		"streaming": "yes",
+	})
+	require.Error(t, err)
+
+	_, err = watch.Parse(map[string]interface{}{
+		"type":      "nodes",
+		"streaming": true,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid parameters: [streaming]")
+}
+
 func TestServiceMultipleTagsWatch(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
@@ -832,6 +987,78 @@ func TestEventWatch(t *testing.T) {
 	}
 }
 
+func TestConfigEntryWatch(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	var (
+		wakeups  []api.ConfigEntry
+		notifyCh = make(chan struct{})
+	)
+
+	plan := mustParse(t, `{"type":"config_entry", "kind":"service-defaults", "name":"web"}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		var v api.ConfigEntry
+		if raw != nil {
+			var ok bool
+			if v, ok = raw.(api.ConfigEntry); !ok {
+				return // ignore
+			}
+		}
+		wakeups = append(wakeups, v)
+		notifyCh <- struct{}{}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(s.HTTPAddr); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+	defer plan.Stop()
+
+	// Wait for first wakeup.
+	<-notifyCh
+	{
+		entry := &api.ServiceConfigEntry{
+			Kind:     api.ServiceDefaults,
+			Name:     "web",
+			Protocol: "http",
+		}
+		if _, _, err := c.ConfigEntries().Set(entry, nil); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	// Wait for second wakeup.
+	<-notifyCh
+
+	plan.Stop()
+	wg.Wait()
+
+	require.Len(t, wakeups, 2)
+	require.Nil(t, wakeups[0])
+
+	entry, ok := wakeups[1].(*api.ServiceConfigEntry)
+	require.True(t, ok)
+	require.Equal(t, "web", entry.Name)
+	require.Equal(t, "http", entry.Protocol)
+}
+
+func TestConfigEntryWatch_MissingKind(t *testing.T) {
+	t.Parallel()
+
+	_, err := watch.Parse(map[string]interface{}{
+		"type": "config_entry",
+		"name": "web",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Must specify a config entry kind to watch")
+}
+
 func TestConnectRootsWatch(t *testing.T) {
 	t.Parallel()
 	// makeClient will bootstrap a CA
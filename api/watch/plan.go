@@ -67,6 +67,22 @@ func (p *Plan) RunWithClientAndHclog(client *consulapi.Client, logger hclog.Logg
 
 	p.client = client
 
+	deliver := func(blockParamVal BlockingParamVal, result interface{}) {
+		p.handle(blockParamVal, result, watchLogger)
+	}
+	if p.Debounce > 0 || p.MinInterval > 0 || p.Coalesce {
+		d := newDispatcher(p, deliver)
+		doneCh := make(chan struct{})
+		go func() {
+			defer close(doneCh)
+			d.run(p.stopCh)
+		}()
+		// The loop below only exits once the plan is stopped, so wait for
+		// any running handler to return before we do.
+		defer func() { <-doneCh }()
+		deliver = d.enqueue
+	}
+
 	// Loop until we are canceled
 	failures := 0
 OUTER:
@@ -119,22 +135,56 @@ OUTER:
 
 		// Handle the updated result
 		p.lastResult = result
-		// If a hybrid handler exists use that
-		if p.HybridHandler != nil {
-			p.HybridHandler(blockParamVal, result)
-		} else if p.Handler != nil {
-			idx, ok := blockParamVal.(WaitIndexVal)
-			if !ok {
-				watchLogger.Error("Handler only supports index-based " +
-					" watches but non index-based watch run. Skipping Handler.")
+		deliver(blockParamVal, result)
+	}
+	return nil
+}
+
+// handle invokes the plan's handler with a result, retrying a failed
+// RetryHandler invocation with a backoff.
+func (p *Plan) handle(blockParamVal BlockingParamVal, result interface{}, logger hclog.Logger) {
+	if p.RetryHandler != nil {
+		interval := p.HandlerRetryInterval
+		if interval <= 0 {
+			interval = retryInterval
+		}
+		for failures := 1; ; failures++ {
+			err := p.RetryHandler(blockParamVal, result)
+			if err == nil {
+				return
+			}
+			if failures > p.HandlerRetries {
+				logger.Error("Watch handler failed", "type", p.Type, "error", err)
+				return
+			}
+			retry := interval * time.Duration(failures*failures)
+			if retry > maxBackoffTime {
+				retry = maxBackoffTime
+			}
+			logger.Warn("Watch handler failed", "type", p.Type, "error", err, "retry", retry)
+			select {
+			case <-time.After(retry):
+			case <-p.stopCh:
+				return
 			}
-			p.Handler(uint64(idx), result)
 		}
 	}
-	return nil
+
+	// If a hybrid handler exists use that
+	if p.HybridHandler != nil {
+		p.HybridHandler(blockParamVal, result)
+	} else if p.Handler != nil {
+		idx, ok := blockParamVal.(WaitIndexVal)
+		if !ok {
+			logger.Error("Handler only supports index-based " +
+				" watches but non index-based watch run. Skipping Handler.")
+		}
+		p.Handler(uint64(idx), result)
+	}
 }
 
-// Deprecated: Use RunwithClientAndHclog
+// Deprecated: Use RunwithClientAndHclog. RetryHandler failures are not retried
+// and the Debounce, MaxWait, MinInterval and Coalesce settings are ignored.
 func (p *Plan) RunWithClientAndLogger(client *consulapi.Client, logger *log.Logger) error {
 
 	p.client = client
@@ -192,8 +242,13 @@ OUTER:
 
 		// Handle the updated result
 		p.lastResult = result
-		// If a hybrid handler exists use that
-		if p.HybridHandler != nil {
+		// If a retry or hybrid handler exists use that
+		if p.RetryHandler != nil {
+			if err := p.RetryHandler(blockParamVal, result); err != nil {
+				logger.Printf("[ERR] consul.watch: Watch (type: %s) handler failed: %v",
+					p.Type, err)
+			}
+		} else if p.HybridHandler != nil {
 			p.HybridHandler(blockParamVal, result)
 		} else if p.Handler != nil {
 			idx, ok := blockParamVal.(WaitIndexVal)
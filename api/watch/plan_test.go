@@ -1,6 +1,8 @@
 package watch
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -148,3 +150,237 @@ func TestRunWithClientAndLogger_NilLogger(t *testing.T) {
 		t.Fatalf("watcher didn't exit")
 	}
 }
+
+// sequenceWatch returns a watcher which produces the given results in order,
+// one per call, and then blocks until the plan is stopped.
+func sequenceWatch(results ...string) WatcherFunc {
+	var idx int
+	return func(p *Plan) (BlockingParamVal, interface{}, error) {
+		if idx < len(results) {
+			idx++
+			return WaitIndexVal(idx), results[idx-1], nil
+		}
+		<-p.stopCh
+		return WaitIndexVal(idx), results[idx-1], nil
+	}
+}
+
+func runPlan(t *testing.T, plan *Plan) {
+	t.Helper()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- plan.Run("127.0.0.1:8500")
+	}()
+	t.Cleanup(func() {
+		plan.Stop()
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("watcher didn't exit")
+		}
+	})
+}
+
+func TestRun_Debounce(t *testing.T) {
+	t.Parallel()
+
+	// Debouncing always delivers only the latest result, so a burst of
+	// changes results in a single handler invocation.
+	for name, tc := range map[string]struct {
+		coalesce bool
+		expect   []string
+	}{
+		"debounce": {coalesce: false, expect: []string{"c"}},
+		"coalesce": {coalesce: true, expect: []string{"c"}},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			plan := mustParse(t, `{"type":"noop", "debounce":"100ms"}`)
+			plan.Coalesce = tc.coalesce
+			plan.Watcher = sequenceWatch("a", "b", "c")
+
+			resultCh := make(chan string, 10)
+			plan.Handler = func(idx uint64, val interface{}) {
+				resultCh <- val.(string)
+			}
+			runPlan(t, plan)
+
+			var got []string
+			for len(got) < len(tc.expect) {
+				select {
+				case v := <-resultCh:
+					got = append(got, v)
+				case <-time.After(2 * time.Second):
+					t.Fatalf("handler never ran, got %v", got)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.expect) {
+				t.Fatalf("Bad: %v", got)
+			}
+
+			select {
+			case v := <-resultCh:
+				t.Fatalf("unexpected result: %v", v)
+			case <-time.After(200 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestRun_DebounceMaxWait(t *testing.T) {
+	t.Parallel()
+	// The noop watch changes continuously, so without max_wait the handler
+	// would never run.
+	plan := mustParse(t, `{"type":"noop", "debounce":"1s", "max_wait":"100ms", "coalesce":true}`)
+
+	doneCh := make(chan struct{})
+	var calls int
+	plan.Handler = func(idx uint64, val interface{}) {
+		calls++
+		if calls == 2 {
+			close(doneCh)
+		}
+	}
+	runPlan(t, plan)
+
+	select {
+	case <-doneCh:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("handler never ran")
+	}
+}
+
+func TestRun_DebounceContinuousChanges(t *testing.T) {
+	t.Parallel()
+	// Changes keep arriving during the debounce window, so the handler must
+	// run about once per max_wait rather than for every change.
+	plan := mustParse(t, `{"type":"noop", "debounce":"1s", "max_wait":"100ms"}`)
+
+	timeCh := make(chan time.Time, 100)
+	plan.Handler = func(idx uint64, val interface{}) {
+		select {
+		case timeCh <- time.Now():
+		default:
+		}
+	}
+	runPlan(t, plan)
+
+	var last time.Time
+	for i := 0; i < 4; i++ {
+		select {
+		case now := <-timeCh:
+			if !last.IsZero() && now.Sub(last) < 90*time.Millisecond {
+				t.Fatalf("handler ran after %v", now.Sub(last))
+			}
+			last = now
+		case <-time.After(2 * time.Second):
+			t.Fatalf("handler never ran")
+		}
+	}
+}
+
+func TestRun_MinIntervalInOrder(t *testing.T) {
+	t.Parallel()
+	// Without debounce or coalesce every result is delivered in order.
+	plan := mustParse(t, `{"type":"noop", "min_interval":"10ms"}`)
+	plan.Watcher = sequenceWatch("a", "b", "c")
+
+	resultCh := make(chan string, 10)
+	plan.Handler = func(idx uint64, val interface{}) {
+		resultCh <- val.(string)
+	}
+	runPlan(t, plan)
+
+	var got []string
+	for len(got) < 3 {
+		select {
+		case v := <-resultCh:
+			got = append(got, v)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("handler never ran, got %v", got)
+		}
+	}
+	if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("Bad: %v", got)
+	}
+}
+
+func TestDispatcher_MaxPending(t *testing.T) {
+	t.Parallel()
+	d := newDispatcher(&Plan{MinInterval: time.Hour}, nil)
+	for i := 0; i < maxPendingUpdates+10; i++ {
+		d.enqueue(WaitIndexVal(i), i)
+	}
+	if len(d.pending) != maxPendingUpdates {
+		t.Fatalf("Bad: %d pending", len(d.pending))
+	}
+	// The oldest results are dropped.
+	if v := d.pending[0].result; v != 10 {
+		t.Fatalf("Bad: %v", v)
+	}
+}
+
+func TestRun_MinInterval(t *testing.T) {
+	t.Parallel()
+	plan := mustParse(t, `{"type":"noop", "min_interval":"100ms", "coalesce":true}`)
+
+	timeCh := make(chan time.Time, 10)
+	plan.Handler = func(idx uint64, val interface{}) {
+		select {
+		case timeCh <- time.Now():
+		default:
+		}
+	}
+	runPlan(t, plan)
+
+	var last time.Time
+	for i := 0; i < 3; i++ {
+		select {
+		case now := <-timeCh:
+			if !last.IsZero() && now.Sub(last) < 100*time.Millisecond {
+				t.Fatalf("handler ran after %v", now.Sub(last))
+			}
+			last = now
+		case <-time.After(2 * time.Second):
+			t.Fatalf("handler never ran")
+		}
+	}
+}
+
+func TestRun_RetryHandler(t *testing.T) {
+	t.Parallel()
+	plan := mustParse(t, `{"type":"noop"}`)
+	plan.Watcher = sequenceWatch("a")
+	plan.HandlerRetries = 2
+	plan.HandlerRetryInterval = 10 * time.Millisecond
+
+	callCh := make(chan int, 10)
+	var calls int
+	plan.RetryHandler = func(blockParamVal BlockingParamVal, val interface{}) error {
+		calls++
+		callCh <- calls
+		return fmt.Errorf("failed")
+	}
+	runPlan(t, plan)
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case n := <-callCh:
+			if n != i {
+				t.Fatalf("Bad: %d", n)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("handler wasn't retried")
+		}
+	}
+
+	select {
+	case n := <-callCh:
+		t.Fatalf("handler retried too often: %d", n)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
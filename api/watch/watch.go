@@ -30,6 +30,24 @@ type Plan struct {
 	// on index param. To support hash based watches, set HybridHandler instead.
 	Handler       HandlerFunc
 	HybridHandler HybridHandlerFunc
+	// RetryHandler takes precedence over the other handlers. An invocation
+	// that returns an error is retried up to HandlerRetries times, backing
+	// off from HandlerRetryInterval.
+	RetryHandler         RetryHandlerFunc
+	HandlerRetries       int
+	HandlerRetryInterval time.Duration
+
+	// Debounce delays invoking the handler until the watched data has been
+	// unchanged for the given duration, and then delivers only the latest
+	// result. MaxWait bounds how long continuous changes can delay it.
+	// MinInterval is the minimum time between handler invocations. When
+	// results are pending, Coalesce delivers only the latest one instead of
+	// every result in order, which Debounce implies. If any of these are set
+	// the handler is invoked in the background so it never blocks the watch.
+	Debounce    time.Duration
+	MaxWait     time.Duration
+	MinInterval time.Duration
+	Coalesce    bool
 
 	Logger hclog.Logger
 	// Deprecated: use Logger
@@ -53,6 +71,12 @@ type HttpHandlerConfig struct {
 	TimeoutRaw    string              `mapstructure:"timeout"`
 	Header        map[string][]string `mapstructure:"header"`
 	TLSSkipVerify bool                `mapstructure:"tls_skip_verify"`
+
+	// Retries is the number of times a request which fails or returns a
+	// non-2xx status is retried.
+	Retries          int           `mapstructure:"retries"`
+	RetryInterval    time.Duration `mapstructure:"-"`
+	RetryIntervalRaw string        `mapstructure:"retry_interval"`
 }
 
 // BlockingParamVal is an interface representing the common operations needed for
@@ -128,6 +152,11 @@ type HandlerFunc func(uint64, interface{})
 // index-based or hash-based watches via the BlockingParamVal.
 type HybridHandlerFunc func(BlockingParamVal, interface{})
 
+// RetryHandlerFunc is used to handle new data like HybridHandlerFunc, but
+// returns an error if the data couldn't be handled so that the invocation
+// can be retried.
+type RetryHandlerFunc func(BlockingParamVal, interface{}) error
+
 // Parse takes a watch query and compiles it into a WatchPlan or an error
 func Parse(params map[string]interface{}) (*Plan, error) {
 	return ParseExempt(params, nil)
@@ -156,6 +185,23 @@ func ParseExempt(params map[string]interface{}, exempt []string) (*Plan, error)
 		return nil, fmt.Errorf("Watch type must be specified")
 	}
 
+	// Parse the handler delivery parameters
+	if err := assignValueDuration(params, "debounce", &plan.Debounce); err != nil {
+		return nil, err
+	}
+	if err := assignValueDuration(params, "max_wait", &plan.MaxWait); err != nil {
+		return nil, err
+	}
+	if err := assignValueDuration(params, "min_interval", &plan.MinInterval); err != nil {
+		return nil, err
+	}
+	if err := assignValueBool(params, "coalesce", &plan.Coalesce); err != nil {
+		return nil, err
+	}
+	if plan.MaxWait > 0 && plan.Debounce == 0 {
+		return nil, fmt.Errorf("max_wait requires debounce to be set")
+	}
+
 	// Get the specific handler
 	if err := assignValue(params, "handler_type", &plan.HandlerType); err != nil {
 		return nil, err
@@ -170,6 +216,8 @@ func ParseExempt(params map[string]interface{}, exempt []string) (*Plan, error)
 			return nil, fmt.Errorf(fmt.Sprintf("Failed to parse 'http_handler_config': %v", err))
 		}
 		plan.Exempt["http_handler_config"] = config
+		plan.HandlerRetries = config.Retries
+		plan.HandlerRetryInterval = config.RetryInterval
 		delete(params, "http_handler_config")
 
 	case "script":
@@ -237,6 +285,27 @@ func assignValueBool(params map[string]interface{}, name string, out *bool) erro
 	return nil
 }
 
+// assignValueDuration is used to extract a value ensuring it is a
+// non-negative duration string
+func assignValueDuration(params map[string]interface{}, name string, out *time.Duration) error {
+	var raw string
+	if err := assignValue(params, name, &raw); err != nil {
+		return err
+	}
+	if raw == "" {
+		return nil
+	}
+	val, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("Failed to parse %s: %v", name, err)
+	}
+	if val < 0 {
+		return fmt.Errorf("Expecting %s to not be negative", name)
+	}
+	*out = val
+	return nil
+}
+
 // assignValueStringSlice is used to extract a value ensuring it is either a string or a slice of strings
 func assignValueStringSlice(params map[string]interface{}, name string, out *[]string) error {
 	if raw, ok := params[name]; ok {
@@ -288,6 +357,16 @@ func parseHttpHandlerConfig(configParams interface{}) (*HttpHandlerConfig, error
 	} else {
 		config.Timeout = timeout
 	}
+	if config.Retries < 0 {
+		return nil, fmt.Errorf("Expecting retries to not be negative")
+	}
+	if config.RetryIntervalRaw != "" {
+		retryInterval, err := time.ParseDuration(config.RetryIntervalRaw)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse retry_interval: %v", err)
+		}
+		config.RetryInterval = retryInterval
+	}
 
 	return &config, nil
 }
@@ -3,7 +3,9 @@ package watch
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseBasic(t *testing.T) {
@@ -40,6 +42,51 @@ func TestParse_exempt(t *testing.T) {
 	}
 }
 
+func TestParse_delivery(t *testing.T) {
+	t.Parallel()
+	params := makeParams(t, `{"type":"key", "key":"foo", "debounce":"2s", "max_wait":"10s", "min_interval":"1s", "coalesce":true}`)
+	p, err := Parse(params)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if p.Debounce != 2*time.Second || p.MaxWait != 10*time.Second || p.MinInterval != time.Second || !p.Coalesce {
+		t.Fatalf("Bad: %#v", p)
+	}
+
+	cases := map[string]string{
+		`{"type":"key", "key":"foo", "debounce":"soon"}`:     "Failed to parse debounce",
+		`{"type":"key", "key":"foo", "min_interval":"-1s"}`:  "Expecting min_interval to not be negative",
+		`{"type":"key", "key":"foo", "max_wait":"1s"}`:       "max_wait requires debounce to be set",
+		`{"type":"key", "key":"foo", "coalesce":"yes"}`:      "Expecting coalesce to be a boolean",
+		`{"type":"key", "key":"foo", "debounce":1000000000}`: "Expecting debounce to be a string",
+	}
+	for q, expect := range cases {
+		_, err := Parse(makeParams(t, q))
+		if err == nil || !strings.Contains(err.Error(), expect) {
+			t.Fatalf("%s: expected error %q, got %v", q, expect, err)
+		}
+	}
+}
+
+func TestParse_httpHandlerRetries(t *testing.T) {
+	t.Parallel()
+	params := makeParams(t, `{"type":"key", "key":"foo", "handler_type":"http",
+		"http_handler_config":{"path":"http://127.0.0.1:8080", "retries":3, "retry_interval":"2s"}}`)
+	p, err := Parse(params)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if p.HandlerRetries != 3 || p.HandlerRetryInterval != 2*time.Second {
+		t.Fatalf("Bad: %#v", p)
+	}
+
+	params = makeParams(t, `{"type":"key", "key":"foo", "handler_type":"http",
+		"http_handler_config":{"path":"http://127.0.0.1:8080", "retries":-1}}`)
+	if _, err := Parse(params); err == nil || !strings.Contains(err.Error(), "Expecting retries to not be negative") {
+		t.Fatalf("err: %v", err)
+	}
+}
+
 func makeParams(t *testing.T, s string) map[string]interface{} {
 	var out map[string]interface{}
 	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
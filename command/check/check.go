@@ -0,0 +1,34 @@
+package check
+
+import (
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New() *cmd {
+	return &cmd{}
+}
+
+type cmd struct{}
+
+func (c *cmd) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(help, nil)
+}
+
+const synopsis = "Interact with checks"
+const help = `
+Usage: consul check <subcommand> [options] [args]
+
+  This command has subcommands for interacting with the health checks
+  registered with the local agent.
+
+  For more examples, ask for subcommand help or view the documentation.
+`
@@ -0,0 +1,13 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New().Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
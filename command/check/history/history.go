@@ -0,0 +1,93 @@
+package history
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error(fmt.Sprintf("Expected exactly one check ID, got %d arguments", len(args)))
+		return 1
+	}
+	checkID := args[0]
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	history, err := client.Agent().CheckHistory(checkID, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error retrieving history of check %q: %s", checkID, err))
+		return 1
+	}
+
+	result := []string{"Time\x1fStatus\x1fOutput"}
+	for _, t := range history {
+		// Only the first line of the output keeps the table readable.
+		output := strings.TrimSpace(t.Output)
+		if i := strings.IndexByte(output, '\n'); i >= 0 {
+			output = output[:i] + " ..."
+		}
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%s",
+			t.Time.Format(time.RFC3339), t.Status, output))
+	}
+	c.UI.Output(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Show the status history of a check"
+const help = `
+Usage: consul check history [options] CHECK_ID
+
+  Shows the most recent status transitions of a check registered with the
+  local agent, oldest first, along with the output of the check at the time
+  of each transition.
+
+      $ consul check history web-health
+
+  For a full list of options and examples, please see the Consul documentation.
+`
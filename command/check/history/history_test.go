@@ -0,0 +1,67 @@
+package history
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestHistoryCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestHistoryCommand_Validation(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "Expected exactly one check ID")
+}
+
+func TestHistoryCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	client := a.Client()
+	require.NoError(t, client.Agent().CheckRegister(&api.AgentCheckRegistration{
+		ID:   "web",
+		Name: "web",
+		AgentServiceCheck: api.AgentServiceCheck{
+			TTL: "10m",
+		},
+	}))
+	require.NoError(t, client.Agent().UpdateTTL("web", "listening\nport 80", api.HealthPassing))
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "web"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Regexp(t, `^Time\s+Status\s+Output$`, lines[0])
+	require.Regexp(t, `\s+critical\s*$`, lines[1])
+	require.Regexp(t, `\s+passing\s+listening \.\.\.$`, lines[2])
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+	code = c.Run([]string{"-http-addr=" + a.HTTPAddr(), "missing"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Unknown check ID")
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
@@ -26,6 +27,7 @@ type cmd struct {
 	// flags
 	configFile               flags.StringValue
 	forceWithoutCrossSigning bool
+	dryRun                   bool
 }
 
 func (c *cmd) init() {
@@ -38,6 +40,10 @@ func (c *cmd) init() {
 			"failures during the rollout as new leafs will be rejected by proxies that "+
 			"have not yet observed the new root cert but is the only option if a CA that "+
 			"doesn't support cross signing needs to be reconfigured or mirated away from.")
+	c.flags.BoolVar(&c.dryRun, "dry-run", false,
+		"Validate the configuration and print the root rotation it would cause, "+
+			"including the new root and intermediate certificates when they can be "+
+			"generated in advance, without applying it.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -79,6 +85,16 @@ func (c *cmd) Run(args []string) int {
 	}
 	config.ForceWithoutCrossSigning = c.forceWithoutCrossSigning
 
+	if c.dryRun {
+		plan, _, err := client.Connect().CADryRunConfig(&config, nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error validating CA configuration: %s", err))
+			return 1
+		}
+		c.UI.Output(formatPlan(plan))
+		return 0
+	}
+
 	// Set the new configuration.
 	if _, err := client.Connect().CASetConfig(&config, nil); err != nil {
 		c.UI.Error(fmt.Sprintf("Error setting CA configuration: %s", err))
@@ -88,6 +104,30 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func formatPlan(plan *api.CARotationPlan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Provider:        %s\n", plan.Provider)
+	fmt.Fprintf(&b, "Current Root ID: %s\n", plan.CurrentRootID)
+	fmt.Fprintf(&b, "Root Rotation:   %t\n", plan.RootRotation)
+	if plan.RootRotation {
+		fmt.Fprintf(&b, "Cross-Signed:    %t\n", plan.CrossSign)
+	}
+	if plan.NewRootID != "" {
+		fmt.Fprintf(&b, "New Root ID:     %s\n", plan.NewRootID)
+	}
+	for _, w := range plan.Warnings {
+		fmt.Fprintf(&b, "Warning:         %s\n", w)
+	}
+	if plan.NewRootCert != "" {
+		fmt.Fprintf(&b, "\nNew Root Certificate:\n%s", plan.NewRootCert)
+	}
+	for _, cert := range plan.NewIntermediateCerts {
+		fmt.Fprintf(&b, "\nNew Intermediate Certificate:\n%s", cert)
+	}
+	b.WriteString("\nThe configuration has not been applied.")
+	return b.String()
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -101,4 +141,9 @@ const help = `
 Usage: consul connect ca set-config [options]
 
   Modifies the current Connect Certificate Authority (CA) configuration.
+
+  Use -dry-run to validate the configuration and preview the root rotation
+  it would cause:
+
+      $ consul connect ca set-config -config-file=ca.json -dry-run
 `
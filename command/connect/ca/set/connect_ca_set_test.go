@@ -56,3 +56,41 @@ func TestConnectCASetConfigCommand(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 288*time.Hour, parsed.IntermediateCertTTL)
 }
+
+func TestConnectCASetConfigCommand_dryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-config-file=test-fixtures/ca_config.json",
+		"-dry-run",
+	}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	// Changing the TTL keeps the private key, hence the root.
+	require.Contains(t, output, "Root Rotation:   false")
+	require.Contains(t, output, "New Root Certificate:")
+	require.Contains(t, output, "The configuration has not been applied.")
+
+	req := structs.DCSpecificRequest{
+		Datacenter: "dc1",
+	}
+	var reply structs.CAConfiguration
+	require.NoError(t, a.RPC(context.Background(), "ConnectCA.ConfigurationGet", &req, &reply))
+
+	parsed, err := ca.ParseConsulCAConfig(reply.Config)
+	require.NoError(t, err)
+	require.NotEqual(t, 288*time.Hour, parsed.IntermediateCertTTL)
+}
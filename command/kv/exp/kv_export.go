@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
@@ -17,15 +18,42 @@ func New(ui cli.Ui) *cmd {
 	return c
 }
 
+// maxTxnOps is the number of keys read in each transaction when streaming
+// an export.
+const maxTxnOps = 64
+
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
 type cmd struct {
 	UI    cli.Ui
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
 	help  string
+
+	// flags
+	format          string
+	raw             bool
+	filter          string
+	excludePrefixes flags.AppendSliceValue
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.format, "format", formatJSON,
+		"Output format. The default \"json\" format writes a single JSON array. "+
+			"The \"ndjson\" format writes one JSON entry per line as the keys are "+
+			"read, without holding the whole tree in memory.")
+	c.flags.BoolVar(&c.raw, "raw", false,
+		"Stores values which are valid UTF-8 as plain text instead of base64.")
+	c.flags.StringVar(&c.filter, "filter", "",
+		"Filter expression to select the key-value pairs to export, such as "+
+			"'Flags == 42'.")
+	c.flags.Var(&c.excludePrefixes, "exclude-prefix",
+		"Excludes the keys with the given prefix from the export. This flag may "+
+			"be specified multiple times.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -58,6 +86,17 @@ func (c *cmd) Run(args []string) int {
 		key = key[1:]
 	}
 
+	if c.format != formatJSON && c.format != formatNDJSON {
+		c.UI.Error(fmt.Sprintf("Invalid format %q, must be one of %q or %q", c.format, formatJSON, formatNDJSON))
+		return 1
+	}
+
+	filter, err := impexp.NewFilter(c.filter, c.excludePrefixes)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -65,6 +104,14 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.format == formatNDJSON {
+		if err := c.stream(client, key, filter); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		return 0
+	}
+
 	pairs, _, err := client.KV().List(key, &api.QueryOptions{
 		AllowStale: c.http.Stale(),
 	})
@@ -73,9 +120,16 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	exported := make([]*impexp.Entry, len(pairs))
-	for i, pair := range pairs {
-		exported[i] = impexp.ToEntry(pair)
+	exported := make([]*impexp.Entry, 0, len(pairs))
+	for _, pair := range pairs {
+		matched, err := filter.Match(pair)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		if matched {
+			exported = append(exported, c.toEntry(pair))
+		}
 	}
 
 	marshaled, err := json.MarshalIndent(exported, "", "\t")
@@ -89,6 +143,74 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// stream writes the pairs under the prefix as newline-delimited JSON. Only the
+// keys are listed up front; values are read in batches as they are written.
+// Unlike the JSON format this isn't a point-in-time view of the tree, and keys
+// deleted while the export runs are skipped.
+func (c *cmd) stream(client *api.Client, prefix string, filter *impexp.Filter) error {
+	q := &api.QueryOptions{AllowStale: c.http.Stale()}
+	keys, _, err := client.KV().Keys(prefix, "", q)
+	if err != nil {
+		return fmt.Errorf("Error querying Consul agent: %s", err)
+	}
+
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > maxTxnOps {
+			n = maxTxnOps
+		}
+		ops := make(api.TxnOps, 0, n)
+		for _, key := range keys[:n] {
+			ops = append(ops, &api.TxnOp{KV: &api.KVTxnOp{Verb: api.KVGetOrEmpty, Key: key}})
+		}
+		keys = keys[n:]
+
+		ok, resp, _, err := client.Txn().Txn(ops, q)
+		if err != nil {
+			return fmt.Errorf("Error querying Consul agent: %s", err)
+		}
+		if !ok {
+			return fmt.Errorf("Error querying Consul agent: %s", txnErrors(resp))
+		}
+
+		for _, result := range resp.Results {
+			pair := result.KV
+			// The key was deleted after it was listed.
+			if pair == nil || pair.ModifyIndex == 0 {
+				continue
+			}
+			matched, err := filter.Match(pair)
+			if err != nil {
+				return err
+			}
+			if !matched {
+				continue
+			}
+			line, err := json.Marshal(c.toEntry(pair))
+			if err != nil {
+				return fmt.Errorf("Error exporting KV data: %s", err)
+			}
+			c.UI.Output(string(line))
+		}
+	}
+	return nil
+}
+
+func (c *cmd) toEntry(pair *api.KVPair) *impexp.Entry {
+	if c.raw {
+		return impexp.ToRawEntry(pair)
+	}
+	return impexp.ToEntry(pair)
+}
+
+func txnErrors(resp *api.TxnResponse) string {
+	var errs []string
+	for _, e := range resp.Errors {
+		errs = append(errs, e.What)
+	}
+	return strings.Join(errs, ", ")
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -100,7 +222,7 @@ func (c *cmd) Help() string {
 const (
 	synopsis = "Exports a tree from the KV store as JSON"
 	help     = `
-Usage: consul kv export [KEY_OR_PREFIX]
+Usage: consul kv export [options] [KEY_OR_PREFIX]
 
   Retrieves key-value pairs for the given prefix from Consul's key-value store,
   and writes a JSON representation to stdout. This can be used with the command
@@ -108,6 +230,11 @@ Usage: consul kv export [KEY_OR_PREFIX]
 
       $ consul kv export vault
 
+  To export a large tree without holding it in memory, write one entry per
+  line with values stored as plain text where possible:
+
+      $ consul kv export -format=ndjson -raw vault > vault.ndjson
+
   For a full list of options and examples, please see the Consul documentation.
 `
 )
@@ -3,6 +3,7 @@ package exp
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/kv/impexp"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKVExportCommand_noTabs(t *testing.T) {
@@ -73,3 +75,68 @@ func TestKVExportCommand(t *testing.T) {
 		}
 	}
 }
+
+func TestKVExportCommand_NDJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	// Write more keys than are read in a single transaction.
+	for i := 0; i < maxTxnOps+10; i++ {
+		pair := &api.KVPair{Key: fmt.Sprintf("foo/%03d", i), Value: []byte(fmt.Sprintf("v%d", i))}
+		if i%2 == 0 {
+			pair.Flags = 2
+		}
+		_, err := client.KV().Put(pair, nil)
+		require.NoError(t, err)
+	}
+	_, err := client.KV().Put(&api.KVPair{Key: "foo/skip/a", Flags: 2, Value: []byte("a")}, nil)
+	require.NoError(t, err)
+	_, err = client.KV().Put(&api.KVPair{Key: "foo/binary", Flags: 2, Value: []byte{0xff}}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-format=ndjson",
+		"-raw",
+		"-filter=Flags == 2",
+		"-exclude-prefix=foo/skip/",
+		"foo",
+	}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+	require.Len(t, lines, (maxTxnOps+10)/2+1)
+
+	exported := make(map[string]*impexp.Entry)
+	for _, line := range lines {
+		var entry impexp.Entry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		exported[entry.Key] = &entry
+	}
+
+	require.Equal(t, &impexp.Entry{Key: "foo/000", Flags: 2, Value: "v0", Encoding: impexp.EncodingRaw}, exported["foo/000"])
+	require.NotContains(t, exported, "foo/001")
+	require.Equal(t, &impexp.Entry{Key: "foo/binary", Flags: 2, Value: "/w=="}, exported["foo/binary"])
+}
+
+func TestKVExportCommand_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	code := c.Run([]string{"-format=yaml"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), `Invalid format "yaml"`)
+}
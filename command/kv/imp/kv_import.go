@@ -2,14 +2,13 @@ package imp
 
 import (
 	"bytes"
-	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
@@ -23,6 +22,13 @@ func New(ui cli.Ui) *cmd {
 	return c
 }
 
+const (
+	// maxTxnOps and maxTxnBytes bound the size of each transaction used to
+	// write the imported entries, keeping them within the server's limits.
+	maxTxnOps   = 64
+	maxTxnBytes = 256 * 1024
+)
+
 type cmd struct {
 	UI     cli.Ui
 	flags  *flag.FlagSet
@@ -30,6 +36,10 @@ type cmd struct {
 	help   string
 	prefix string
 
+	// flags
+	filter          string
+	excludePrefixes flags.AppendSliceValue
+
 	// testStdin is the input for testing.
 	testStdin io.Reader
 }
@@ -37,6 +47,13 @@ type cmd struct {
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.StringVar(&c.prefix, "prefix", "", "Key prefix for imported data")
+	c.flags.StringVar(&c.filter, "filter", "",
+		"Filter expression to select the key-value pairs to import, such as "+
+			"'Flags == 42'. It is evaluated against the keys in the data, "+
+			"before -prefix is applied.")
+	c.flags.Var(&c.excludePrefixes, "exclude-prefix",
+		"Excludes the keys with the given prefix in the data from the import. "+
+			"This flag may be specified multiple times.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -56,51 +73,167 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error! %s", err))
 		return 1
 	}
+	defer data.Close()
 
-	// Create and test the HTTP client
-	client, err := c.http.APIClient()
+	filter, err := impexp.NewFilter(c.filter, c.excludePrefixes)
 	if err != nil {
-		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		c.UI.Error(err.Error())
 		return 1
 	}
 
-	var entries []*impexp.Entry
-	if err := json.Unmarshal([]byte(data), &entries); err != nil {
-		c.UI.Error(fmt.Sprintf("Cannot unmarshal data: %s", err))
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
 		return 1
 	}
 
-	for _, entry := range entries {
-		value, err := base64.StdEncoding.DecodeString(entry.Value)
+	// Entries are decoded and written in batches as they are read so that
+	// large imports don't need to be held in memory.
+	var (
+		dec   = impexp.NewDecoder(data)
+		batch []*api.KVPair
+		size  int
+	)
+	for {
+		entry, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error base 64 decoding value for key %s: %s", entry.Key, err))
+			c.UI.Error(err.Error())
 			return 1
 		}
 
-		pair := &api.KVPair{
-			Key:   path.Join(c.prefix, entry.Key),
-			Flags: entry.Flags,
-			Value: value,
+		pair, err := c.toPair(entry, filter)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		if pair == nil {
+			continue
 		}
 
-		// if the key is a directory, we need to append /
-		if len(entry.Key) > 0 && entry.Key[len(entry.Key)-1] == '/' {
-			pair.Key += "/"
+		if len(batch) > 0 && (len(batch) == maxTxnOps || size+len(pair.Value) > maxTxnBytes) {
+			if err := c.importBatch(client, batch); err != nil {
+				c.UI.Error(err.Error())
+				return 1
+			}
+			batch, size = nil, 0
 		}
+		batch = append(batch, pair)
+		size += len(pair.Value)
+	}
+	if err := c.importBatch(client, batch); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
 
-		w := api.WriteOptions{Namespace: entry.Namespace}
-		if _, err := client.KV().Put(pair, &w); err != nil {
-			c.UI.Error(fmt.Sprintf("Error! Failed writing data for key %s: %s", pair.Key, err))
-			return 1
+	return 0
+}
+
+// toPair converts an entry to the pair to write, or returns nil if the
+// entry is filtered out.
+func (c *cmd) toPair(entry *impexp.Entry, filter *impexp.Filter) (*api.KVPair, error) {
+	value, err := entry.DecodeValue()
+	if err != nil {
+		return nil, err
+	}
+
+	pair := &api.KVPair{
+		Key:       entry.Key,
+		Flags:     entry.Flags,
+		Value:     value,
+		Namespace: entry.Namespace,
+		Partition: entry.Partition,
+	}
+	matched, err := filter.Match(pair)
+	if err != nil || !matched {
+		return nil, err
+	}
+
+	pair.Key = path.Join(c.prefix, entry.Key)
+	// if the key is a directory, we need to append /
+	if len(entry.Key) > 0 && entry.Key[len(entry.Key)-1] == '/' {
+		pair.Key += "/"
+	}
+	return pair, nil
+}
+
+// importBatch writes the pairs in a single transaction. Each pair is written
+// with a check-and-set against its current modify index, so the batch fails
+// rather than overwriting a concurrent change. Pairs which already hold the
+// imported value are skipped, which allows an import that failed part way
+// through to be resumed by running it again.
+func (c *cmd) importBatch(client *api.Client, pairs []*api.KVPair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	ops := make(api.TxnOps, 0, len(pairs))
+	for _, pair := range pairs {
+		ops = append(ops, &api.TxnOp{KV: &api.KVTxnOp{
+			Verb:      api.KVGetOrEmpty,
+			Key:       pair.Key,
+			Namespace: pair.Namespace,
+			Partition: pair.Partition,
+		}})
+	}
+	ok, resp, _, err := client.Txn().Txn(ops, nil)
+	if err != nil {
+		return fmt.Errorf("Error! Failed reading keys starting at %s: %s", pairs[0].Key, err)
+	}
+	if !ok {
+		return fmt.Errorf("Error! Failed reading keys starting at %s: %s", pairs[0].Key, txnErrors(resp))
+	}
+
+	ops = ops[:0]
+	var written []string
+	for i, pair := range pairs {
+		current := resp.Results[i].KV
+		if current.ModifyIndex != 0 && current.Flags == pair.Flags && bytes.Equal(current.Value, pair.Value) {
+			continue
 		}
+		ops = append(ops, &api.TxnOp{KV: &api.KVTxnOp{
+			Verb:      api.KVCAS,
+			Key:       pair.Key,
+			Flags:     pair.Flags,
+			Value:     pair.Value,
+			Index:     current.ModifyIndex,
+			Namespace: pair.Namespace,
+			Partition: pair.Partition,
+		}})
+		written = append(written, pair.Key)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	ok, resp, _, err = client.Txn().Txn(ops, nil)
+	if err != nil {
+		return fmt.Errorf("Error! Failed writing data for keys starting at %s: %s", written[0], err)
+	}
+	if !ok {
+		return fmt.Errorf("Error! Failed writing data for keys starting at %s: %s", written[0], txnErrors(resp))
+	}
 
-		c.UI.Info(fmt.Sprintf("Imported: %s", pair.Key))
+	for _, key := range written {
+		c.UI.Info(fmt.Sprintf("Imported: %s", key))
 	}
+	return nil
+}
 
-	return 0
+func txnErrors(resp *api.TxnResponse) string {
+	var errs []string
+	for _, e := range resp.Errors {
+		errs = append(errs, e.What)
+	}
+	return strings.Join(errs, ", ")
 }
 
-func (c *cmd) dataFromArgs(args []string) (string, error) {
+// dataFromArgs returns a reader for the data to import, which the caller
+// must close.
+func (c *cmd) dataFromArgs(args []string) (io.ReadCloser, error) {
 	var stdin io.Reader = os.Stdin
 	if c.testStdin != nil {
 		stdin = c.testStdin
@@ -108,36 +241,32 @@ func (c *cmd) dataFromArgs(args []string) (string, error) {
 
 	switch len(args) {
 	case 0:
-		return "", errors.New("Missing DATA argument")
+		return nil, errors.New("Missing DATA argument")
 	case 1:
 	default:
-		return "", fmt.Errorf("Too many arguments (expected 1, got %d)", len(args))
+		return nil, fmt.Errorf("Too many arguments (expected 1, got %d)", len(args))
 	}
 
 	data := args[0]
 
 	if len(data) == 0 {
-		return "", errors.New("Empty DATA argument")
+		return nil, errors.New("Empty DATA argument")
 	}
 
 	switch data[0] {
 	case '@':
-		data, err := os.ReadFile(data[1:])
+		f, err := os.Open(data[1:])
 		if err != nil {
-			return "", fmt.Errorf("Failed to read file: %s", err)
+			return nil, fmt.Errorf("Failed to read file: %s", err)
 		}
-		return string(data), nil
+		return f, nil
 	case '-':
 		if len(data) > 1 {
-			return data, nil
-		}
-		var b bytes.Buffer
-		if _, err := io.Copy(&b, stdin); err != nil {
-			return "", fmt.Errorf("Failed to read stdin: %s", err)
+			return io.NopCloser(strings.NewReader(data)), nil
 		}
-		return b.String(), nil
+		return io.NopCloser(stdin), nil
 	default:
-		return data, nil
+		return io.NopCloser(strings.NewReader(data)), nil
 	}
 }
 
@@ -152,10 +281,14 @@ func (c *cmd) Help() string {
 const (
 	synopsis = "Imports a tree stored as JSON to the KV store"
 	help     = `
-Usage: consul kv import [DATA]
+Usage: consul kv import [options] [DATA]
 
-  Imports key-value pairs to the key-value store from the JSON representation
-  generated by the "consul kv export" command.
+  Imports key-value pairs to the key-value store from the JSON or
+  newline-delimited JSON representation generated by the "consul kv export"
+  command. Entries are written in batched transactions as they are read.
+  Each key is written with a check-and-set against its current modify index,
+  and keys which already hold the imported value are skipped, so an import
+  that fails part way through can be resumed by running it again.
 
   The data can be read from a file by prefixing the filename with the "@"
   symbol. For example:
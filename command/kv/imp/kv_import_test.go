@@ -1,10 +1,12 @@
 package imp
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
 )
@@ -164,3 +166,101 @@ func TestKVImportPrefixCommand(t *testing.T) {
 		t.Fatalf("bad: expected: bar, got %s", pair.Value)
 	}
 }
+
+func TestKVImportCommand_NDJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	// Write more entries than fit in a single transaction, with one of them
+	// already present as though a previous import had failed part way.
+	var data strings.Builder
+	for i := 0; i < maxTxnOps+10; i++ {
+		fmt.Fprintf(&data, `{"key": "foo/%03d", "flags": 0, "value": "v%d", "encoding": "raw"}`+"\n", i, i)
+	}
+	data.WriteString(`{"key": "skip/a", "flags": 0, "value": "a", "encoding": "raw"}` + "\n")
+	data.WriteString(`{"key": "flagged", "flags": 7, "value": "eA=="}` + "\n")
+
+	_, err := client.KV().Put(&api.KVPair{Key: "foo/005", Value: []byte("v5")}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(data.String())
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-exclude-prefix=skip/",
+		"-",
+	}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, "Imported: foo/073\n")
+	require.NotContains(t, output, "Imported: foo/005\n")
+
+	pairs, _, err := client.KV().List("", nil)
+	require.NoError(t, err)
+	require.Len(t, pairs, maxTxnOps+11)
+	for _, pair := range pairs {
+		require.False(t, strings.HasPrefix(pair.Key, "skip/"))
+		if pair.Key == "flagged" {
+			require.Equal(t, uint64(7), pair.Flags)
+			require.Equal(t, []byte("x"), pair.Value)
+		}
+	}
+
+	pair, _, err := client.KV().Get("foo/042", nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("v42"), pair.Value)
+
+	// Running the import again writes nothing.
+	ui = cli.NewMockUi()
+	c = New(ui)
+	c.testStdin = strings.NewReader(data.String())
+
+	code = c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Empty(t, ui.OutputWriter.String())
+}
+
+func TestKVImportCommand_Filter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	const json = `[
+		{"key": "a", "flags": 1, "value": "a", "encoding": "raw"},
+		{"key": "b", "flags": 2, "value": "b", "encoding": "raw"}
+	]`
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(json)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-filter=Flags == 2",
+		"-prefix=sub",
+		"-",
+	}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	keys, _, err := client.KV().Keys("", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sub/b"}, keys)
+}
@@ -0,0 +1,49 @@
+package impexp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-bexpr"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// Filter selects the key-value pairs to export or import, using a filter
+// expression over the pair and a list of key prefixes to exclude.
+type Filter struct {
+	expr    *bexpr.Filter
+	exclude []string
+}
+
+// NewFilter returns a filter for the given expression and excluded prefixes.
+// Either may be empty.
+func NewFilter(expr string, excludePrefixes []string) (*Filter, error) {
+	f := &Filter{exclude: excludePrefixes}
+	if expr != "" {
+		var err error
+		f.expr, err = bexpr.CreateFilter(expr, nil, []*api.KVPair{})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create filter: %s", err)
+		}
+	}
+	return f, nil
+}
+
+// Match returns whether the pair is selected by the filter.
+func (f *Filter) Match(pair *api.KVPair) (bool, error) {
+	for _, prefix := range f.exclude {
+		if strings.HasPrefix(pair.Key, prefix) {
+			return false, nil
+		}
+	}
+	if f.expr == nil {
+		return true, nil
+	}
+
+	matched, err := f.expr.Execute([]*api.KVPair{pair})
+	if err != nil {
+		return false, fmt.Errorf("Failed to filter key %s: %s", pair.Key, err)
+	}
+	return len(matched.([]*api.KVPair)) == 1, nil
+}
@@ -2,14 +2,21 @@ package impexp
 
 import (
 	"encoding/base64"
+	"fmt"
+	"unicode/utf8"
 
 	"github.com/hashicorp/consul/api"
 )
 
+// EncodingRaw marks an entry whose value is stored as plain UTF-8 text rather
+// than base64.
+const EncodingRaw = "raw"
+
 type Entry struct {
 	Key       string `json:"key"`
 	Flags     uint64 `json:"flags"`
 	Value     string `json:"value"`
+	Encoding  string `json:"encoding,omitempty"`
 	Namespace string `json:"namespace,omitempty"`
 	Partition string `json:"partition,omitempty"`
 }
@@ -23,3 +30,31 @@ func ToEntry(pair *api.KVPair) *Entry {
 		Partition: pair.Partition,
 	}
 }
+
+// ToRawEntry is like ToEntry, but stores values which are valid UTF-8 as
+// plain text. Other values are still base64 encoded.
+func ToRawEntry(pair *api.KVPair) *Entry {
+	entry := ToEntry(pair)
+	if utf8.Valid(pair.Value) {
+		entry.Value = string(pair.Value)
+		entry.Encoding = EncodingRaw
+	}
+	return entry
+}
+
+// DecodeValue returns the entry's value, decoding it according to its
+// encoding.
+func (e *Entry) DecodeValue() ([]byte, error) {
+	switch e.Encoding {
+	case "":
+		value, err := base64.StdEncoding.DecodeString(e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("Error base 64 decoding value for key %s: %s", e.Key, err)
+		}
+		return value, nil
+	case EncodingRaw:
+		return []byte(e.Value), nil
+	default:
+		return nil, fmt.Errorf("Unknown encoding %q for key %s", e.Encoding, e.Key)
+	}
+}
@@ -0,0 +1,105 @@
+package impexp
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+)
+
+func TestToRawEntry(t *testing.T) {
+	t.Parallel()
+
+	entry := ToRawEntry(&api.KVPair{Key: "foo", Value: []byte("bar")})
+	require.Equal(t, "bar", entry.Value)
+	require.Equal(t, EncodingRaw, entry.Encoding)
+
+	value, err := entry.DecodeValue()
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), value)
+
+	// Values which aren't valid UTF-8 fall back to base64.
+	entry = ToRawEntry(&api.KVPair{Key: "foo", Value: []byte{0xff, 0xfe}})
+	require.Equal(t, "//4=", entry.Value)
+	require.Empty(t, entry.Encoding)
+
+	value, err = entry.DecodeValue()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xff, 0xfe}, value)
+
+	entry.Encoding = "hex"
+	_, err = entry.DecodeValue()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Unknown encoding "hex" for key foo`)
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"array": `[
+			{"key": "a", "flags": 0, "value": "MQ=="},
+			{"key": "b", "flags": 2, "value": "2", "encoding": "raw"}
+		]`,
+		"ndjson": `{"key": "a", "flags": 0, "value": "MQ=="}
+{"key": "b", "flags": 2, "value": "2", "encoding": "raw"}
+`,
+	}
+	for name, data := range cases {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			dec := NewDecoder(strings.NewReader(data))
+
+			var keys []string
+			for {
+				entry, err := dec.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				keys = append(keys, entry.Key)
+			}
+			require.Equal(t, []string{"a", "b"}, keys)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := NewDecoder(strings.NewReader(" \n")).Next()
+		require.Equal(t, io.EOF, err)
+	})
+
+	t.Run("truncated array", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`[{"key": "a", "value": ""},`))
+		_, err := dec.Next()
+		require.NoError(t, err)
+		_, err = dec.Next()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Cannot unmarshal data")
+	})
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFilter(`Flags == 2`, []string{"skip/"})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		pair   *api.KVPair
+		expect bool
+	}{
+		{&api.KVPair{Key: "keep", Flags: 2}, true},
+		{&api.KVPair{Key: "keep", Flags: 1}, false},
+		{&api.KVPair{Key: "skip/keep", Flags: 2}, false},
+	} {
+		matched, err := f.Match(tc.pair)
+		require.NoError(t, err)
+		require.Equal(t, tc.expect, matched, tc.pair.Key)
+	}
+
+	_, err = NewFilter(`Flags ==`, nil)
+	require.Error(t, err)
+}
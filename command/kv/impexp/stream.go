@@ -0,0 +1,80 @@
+package impexp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// Decoder reads entries one at a time from either the JSON array written by
+// "consul kv export" or newline-delimited JSON with one entry per line, so
+// that large exports don't need to be held in memory.
+type Decoder struct {
+	r     *bufio.Reader
+	dec   *json.Decoder
+	array bool
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Next returns the next entry, or io.EOF once all entries have been read.
+func (d *Decoder) Next() (*Entry, error) {
+	if d.dec == nil {
+		if err := d.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.array && !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil {
+			return nil, fmt.Errorf("Cannot unmarshal data: %s", err)
+		}
+		return nil, io.EOF
+	}
+
+	var entry Entry
+	if err := d.dec.Decode(&entry); err != nil {
+		if err == io.EOF && !d.array {
+			return nil, io.EOF
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("Cannot unmarshal data: %s", err)
+	}
+	return &entry, nil
+}
+
+// start detects the format from the first non-space character and consumes
+// the opening bracket of a JSON array.
+func (d *Decoder) start() error {
+	for {
+		r, _, err := d.r.ReadRune()
+		if err == io.EOF {
+			return io.EOF
+		}
+		if err != nil {
+			return fmt.Errorf("Cannot read data: %s", err)
+		}
+		if unicode.IsSpace(r) {
+			continue
+		}
+		if err := d.r.UnreadRune(); err != nil {
+			return fmt.Errorf("Cannot read data: %s", err)
+		}
+		d.array = r == '['
+		break
+	}
+
+	d.dec = json.NewDecoder(d.r)
+	if d.array {
+		if _, err := d.dec.Token(); err != nil {
+			return fmt.Errorf("Cannot unmarshal data: %s", err)
+		}
+	}
+	return nil
+}
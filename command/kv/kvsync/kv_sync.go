@@ -0,0 +1,228 @@
+package kvsync
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	reverse        bool
+	prune          bool
+	allowRootPrune bool
+	dryRun         bool
+	modeFlags      bool
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.reverse, "reverse", false,
+		"Mirror the KV prefix to the local directory instead of the directory "+
+			"to the prefix.")
+	c.flags.BoolVar(&c.prune, "prune", false,
+		"Delete the keys under the prefix, or with -reverse the files in the "+
+			"directory, which don't exist in the source.")
+	c.flags.BoolVar(&c.allowRootPrune, "allow-root-prune", false,
+		"Allow -prune with an empty prefix, which syncs the directory with the "+
+			"whole KV store.")
+	c.flags.BoolVar(&c.dryRun, "dry-run", false,
+		"List the changes that would be made without making them.")
+	c.flags.BoolVar(&c.modeFlags, "mode-flags", false,
+		"Store each file's permission bits in the flags of its key, such as 420 "+
+			"for a mode of 0644. With -reverse, files are written with the mode "+
+			"held in the flags of their key.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 2 {
+		c.UI.Error(fmt.Sprintf("Expected DIR and PREFIX arguments (got %d arguments)", len(args)))
+		return 1
+	}
+	dir, prefix := args[0], normalizePrefix(args[1])
+	if c.prune && prefix == "" && !c.allowRootPrune {
+		c.UI.Error("Refusing to prune with an empty prefix, which syncs the whole KV store. Pass -allow-root-prune to do so anyway.")
+		return 1
+	}
+
+	local, err := c.readLocal(dir)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading directory: %s", err))
+		return 1
+	}
+
+	// Create and test the HTTP client
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	pairs, _, err := client.KV().List(prefix, &api.QueryOptions{
+		AllowStale: c.http.Stale(),
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error querying Consul agent: %s", err))
+		return 1
+	}
+	remote := relativePairs(pairs, prefix)
+
+	var changes []*change
+	if c.reverse {
+		changes, err = pullChanges(local, remote, dir, c.prune, c.modeFlags)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error: %s", err))
+			return 1
+		}
+	} else {
+		changes = pushChanges(local, remote, prefix, c.prune, c.modeFlags)
+	}
+
+	if c.dryRun {
+		for _, ch := range changes {
+			c.UI.Output(c.describe(ch))
+		}
+		c.UI.Info(fmt.Sprintf("Dry run: %s", summary(changes)))
+		return 0
+	}
+
+	if c.reverse {
+		err = c.applyLocal(changes)
+	} else {
+		err = c.applyRemote(client, changes)
+	}
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Info(fmt.Sprintf("Synced: %s", summary(changes)))
+	return 0
+}
+
+// readLocal returns the files in the directory. A reverse sync may create the
+// directory, so it's treated as empty if it doesn't exist.
+func (c *cmd) readLocal(dir string) (map[string]*file, error) {
+	info, err := os.Stat(dir)
+	switch {
+	case c.reverse && errors.Is(err, fs.ErrNotExist):
+		return make(map[string]*file), nil
+	case err != nil:
+		return nil, err
+	case !info.IsDir():
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return readDir(dir)
+}
+
+// applyRemote writes the changes to the KV store in transactions. Each key is
+// written with a check-and-set against the modify index it had when the diff
+// was computed, so a transaction fails rather than overwrite a concurrent
+// change. Transactions applied before a failure are kept, and running the sync
+// again picks up where it stopped.
+func (c *cmd) applyRemote(client *api.Client, changes []*change) error {
+	i := 0
+	for _, ops := range txnBatches(changes) {
+		ok, resp, _, err := client.Txn().Txn(ops, nil)
+		if err != nil {
+			return fmt.Errorf("Error! Failed syncing keys starting at %s: %s", changes[i].key, err)
+		}
+		if !ok {
+			var errs []string
+			for _, e := range resp.Errors {
+				errs = append(errs, e.What)
+			}
+			return fmt.Errorf("Error! Failed syncing keys starting at %s: %s", changes[i].key, strings.Join(errs, ", "))
+		}
+
+		for range ops {
+			c.UI.Output(c.describe(changes[i]))
+			i++
+		}
+	}
+	return nil
+}
+
+// applyLocal writes the changes to the local directory.
+func (c *cmd) applyLocal(changes []*change) error {
+	for _, ch := range changes {
+		var err error
+		if ch.op == opRemove {
+			err = os.Remove(ch.path)
+		} else {
+			err = writeFile(ch.path, ch.value, ch.mode)
+		}
+		if err != nil {
+			return fmt.Errorf("Error! Failed syncing %s: %s", ch.path, err)
+		}
+		c.UI.Output(c.describe(ch))
+	}
+	return nil
+}
+
+func (c *cmd) describe(ch *change) string {
+	if c.reverse {
+		return fmt.Sprintf("%s %s", ch.op, ch.path)
+	}
+	return fmt.Sprintf("%s %s", ch.op, ch.key)
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Mirrors a local directory to a KV prefix"
+	help     = `
+Usage: consul kv sync [options] DIR PREFIX
+
+  Mirrors the files under a local directory to the keys under a prefix in the
+  key-value store. Each regular file is stored in the key named by its path
+  relative to the directory. Only the keys which differ are written, in
+  batched transactions, with a check-and-set against their modify index.
+
+  To preview syncing the "config" directory to the "app/config" prefix and
+  deleting keys with no matching file:
+
+      $ consul kv sync -prune -dry-run ./config app/config
+
+  To mirror the prefix back to the directory instead:
+
+      $ consul kv sync -reverse ./config app/config
+
+  For a full list of options and examples, please see the Consul documentation.
+`
+)
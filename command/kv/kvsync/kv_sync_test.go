@@ -0,0 +1,130 @@
+package kvsync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+)
+
+func TestKVSyncCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestKVSyncCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	code := c.Run([]string{"dir"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Expected DIR and PREFIX arguments (got 1 arguments)")
+
+	ui = cli.NewMockUi()
+	c = New(ui)
+
+	code = c.Run([]string{filepath.Join(t.TempDir(), "missing"), "app"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error reading directory")
+
+	for _, prefix := range []string{"", "/"} {
+		ui = cli.NewMockUi()
+		c = New(ui)
+
+		code = c.Run([]string{"-prune", t.TempDir(), prefix})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Refusing to prune with an empty prefix")
+	}
+}
+
+func TestKVSyncCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "db"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "web"), []byte("w"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db", "conn"), []byte("5"), 0600))
+
+	for _, pair := range []*api.KVPair{
+		{Key: "app/web", Value: []byte("old")},
+		{Key: "app/stale", Value: []byte("s")},
+		{Key: "other", Value: []byte("o")},
+	} {
+		_, err := client.KV().Put(pair, nil)
+		require.NoError(t, err)
+	}
+
+	run := func(t *testing.T, args ...string) string {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		code := c.Run(append([]string{"-http-addr=" + a.HTTPAddr()}, args...))
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		return ui.OutputWriter.String()
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		output := run(t, "-prune", "-dry-run", dir, "app")
+		require.Equal(t, "+ app/db/conn\n- app/stale\n~ app/web\nDry run: 1 added, 1 updated, 1 removed\n", output)
+
+		pair, _, err := client.KV().Get("app/web", nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte("old"), pair.Value)
+	})
+
+	t.Run("push", func(t *testing.T) {
+		output := run(t, "-prune", "-mode-flags", dir, "app")
+		require.Contains(t, output, "Synced: 1 added, 1 updated, 1 removed")
+
+		keys, _, err := client.KV().Keys("", "", nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"app/db/conn", "app/web", "other"}, keys)
+
+		pair, _, err := client.KV().Get("app/db/conn", nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte("5"), pair.Value)
+		require.Equal(t, uint64(0600), pair.Flags)
+
+		// Nothing changes when syncing again.
+		output = run(t, "-prune", "-mode-flags", dir, "app")
+		require.Equal(t, "Synced: 0 added, 0 updated, 0 removed\n", output)
+	})
+
+	t.Run("allow root prune", func(t *testing.T) {
+		output := run(t, "-prune", "-allow-root-prune", "-dry-run", dir, "")
+		require.Equal(t, "- app/db/conn\n- app/web\n+ db/conn\n- other\n+ web\nDry run: 2 added, 0 updated, 3 removed\n", output)
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+		_, err := client.KV().Put(&api.KVPair{Key: "app/web", Value: []byte("new")}, nil)
+		require.NoError(t, err)
+
+		out := filepath.Join(t.TempDir(), "out")
+		output := run(t, "-reverse", "-mode-flags", out, "app")
+		require.Contains(t, output, "Synced: 2 added, 0 updated, 0 removed")
+
+		value, err := os.ReadFile(filepath.Join(out, "web"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("new"), value)
+
+		info, err := os.Stat(filepath.Join(out, "db", "conn"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+}
@@ -0,0 +1,281 @@
+package kvsync
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	// maxTxnOps and maxTxnBytes bound the size of each transaction used to
+	// apply changes to the KV store, keeping them within the server's limits.
+	maxTxnOps   = 64
+	maxTxnBytes = 256 * 1024
+
+	// defaultFileMode is used for files created by a reverse sync when the
+	// key's flags don't hold a file mode.
+	defaultFileMode fs.FileMode = 0644
+)
+
+// vcsDirs are the version control directories which are never synced.
+var vcsDirs = map[string]bool{
+	".git": true,
+	".hg":  true,
+	".svn": true,
+}
+
+type changeOp string
+
+const (
+	opAdd    changeOp = "+"
+	opUpdate changeOp = "~"
+	opRemove changeOp = "-"
+)
+
+// change is a single difference to apply to the destination, which is the
+// KV prefix or, for a reverse sync, the local directory.
+type change struct {
+	op    changeOp
+	key   string
+	path  string
+	value []byte
+	flags uint64
+	mode  fs.FileMode
+
+	// index is the current modify index of the key, used to check-and-set
+	// when syncing to the KV store.
+	index uint64
+}
+
+// file is a regular file in the local directory.
+type file struct {
+	value []byte
+	mode  fs.FileMode
+}
+
+// normalizePrefix strips any leading slash, which keys cannot start with, and
+// ensures a non-empty prefix ends with one.
+func normalizePrefix(prefix string) string {
+	prefix = strings.TrimLeft(prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// readDir returns the regular files under root, keyed by their slash
+// separated path relative to it. Version control directories are skipped.
+func readDir(root string) (map[string]*file, error) {
+	files := make(map[string]*file)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p != root && vcsDirs[d.Name()] {
+			return fs.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		value, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = &file{value: value, mode: info.Mode().Perm()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// relativePairs returns the pairs keyed by their path relative to the prefix.
+// Folder keys, which end in a slash, are not synced.
+func relativePairs(pairs api.KVPairs, prefix string) map[string]*api.KVPair {
+	remote := make(map[string]*api.KVPair, len(pairs))
+	for _, pair := range pairs {
+		if strings.HasSuffix(pair.Key, "/") {
+			continue
+		}
+		remote[strings.TrimPrefix(pair.Key, prefix)] = pair
+	}
+	return remote
+}
+
+// pushChanges returns the changes needed to make the KV prefix match the local
+// files. Without modeFlags the existing flags of a key are left as they are.
+func pushChanges(local map[string]*file, remote map[string]*api.KVPair, prefix string, prune, modeFlags bool) []*change {
+	var changes []*change
+	for rel, f := range local {
+		c := &change{key: prefix + rel, value: f.value}
+		if modeFlags {
+			c.flags = uint64(f.mode)
+		}
+
+		pair, ok := remote[rel]
+		switch {
+		case !ok:
+			c.op = opAdd
+		case !bytes.Equal(pair.Value, f.value) || (modeFlags && pair.Flags != c.flags):
+			c.op = opUpdate
+			c.index = pair.ModifyIndex
+			if !modeFlags {
+				c.flags = pair.Flags
+			}
+		default:
+			continue
+		}
+		changes = append(changes, c)
+	}
+
+	if prune {
+		for rel, pair := range remote {
+			if _, ok := local[rel]; !ok {
+				changes = append(changes, &change{op: opRemove, key: pair.Key, index: pair.ModifyIndex})
+			}
+		}
+	}
+
+	sortChanges(changes, func(c *change) string { return c.key })
+	return changes
+}
+
+// pullChanges returns the changes needed to make the local directory match the
+// KV prefix. With modeFlags, non-zero flags are used as the mode of the file.
+func pullChanges(local map[string]*file, remote map[string]*api.KVPair, dir string, prune, modeFlags bool) ([]*change, error) {
+	var changes []*change
+	for rel, pair := range remote {
+		p, err := localPath(dir, rel)
+		if err != nil {
+			return nil, err
+		}
+		c := &change{key: pair.Key, path: p, value: pair.Value, mode: defaultFileMode}
+
+		f, ok := local[rel]
+		if ok {
+			c.mode = f.mode
+		}
+		if modeFlags && pair.Flags != 0 {
+			c.mode = fs.FileMode(pair.Flags).Perm()
+		}
+
+		switch {
+		case !ok:
+			c.op = opAdd
+		case !bytes.Equal(pair.Value, f.value) || f.mode != c.mode:
+			c.op = opUpdate
+		default:
+			continue
+		}
+		changes = append(changes, c)
+	}
+
+	if prune {
+		for rel := range local {
+			if _, ok := remote[rel]; !ok {
+				changes = append(changes, &change{op: opRemove, path: filepath.Join(dir, filepath.FromSlash(rel))})
+			}
+		}
+	}
+
+	sortChanges(changes, func(c *change) string { return c.path })
+	return changes, nil
+}
+
+// localPath returns the path of the file for a key, ensuring it can't escape
+// the directory.
+func localPath(dir, rel string) (string, error) {
+	clean := path.Clean(rel)
+	if rel == "" || path.IsAbs(rel) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("Key %q cannot be written to a file under %s", rel, dir)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
+
+func sortChanges(changes []*change, name func(*change) string) {
+	sort.Slice(changes, func(i, j int) bool {
+		return name(changes[i]) < name(changes[j])
+	})
+}
+
+// txnBatches splits the changes into transactions to apply to the KV store.
+func txnBatches(changes []*change) []api.TxnOps {
+	var (
+		batches []api.TxnOps
+		ops     api.TxnOps
+		size    int
+	)
+	for _, c := range changes {
+		if len(ops) > 0 && (len(ops) == maxTxnOps || size+len(c.value) > maxTxnBytes) {
+			batches = append(batches, ops)
+			ops, size = nil, 0
+		}
+
+		op := &api.KVTxnOp{Key: c.key, Index: c.index}
+		if c.op == opRemove {
+			op.Verb = api.KVDeleteCAS
+		} else {
+			op.Verb = api.KVCAS
+			op.Value = c.value
+			op.Flags = c.flags
+		}
+		ops = append(ops, &api.TxnOp{KV: op})
+		size += len(c.value)
+	}
+	if len(ops) > 0 {
+		batches = append(batches, ops)
+	}
+	return batches
+}
+
+// writeFile atomically replaces the file at path by renaming a temporary file
+// into place.
+func writeFile(p string, value []byte, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(value); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// summary describes the number of changes of each kind.
+func summary(changes []*change) string {
+	counts := make(map[changeOp]int)
+	for _, c := range changes {
+		counts[c.op]++
+	}
+	return fmt.Sprintf("%d added, %d updated, %d removed", counts[opAdd], counts[opUpdate], counts[opRemove])
+}
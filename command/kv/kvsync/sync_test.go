@@ -0,0 +1,137 @@
+package kvsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+)
+
+func TestNormalizePrefix(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "", normalizePrefix(""))
+	require.Equal(t, "", normalizePrefix("/"))
+	require.Equal(t, "app/", normalizePrefix("/app"))
+	require.Equal(t, "app/", normalizePrefix("app/"))
+}
+
+func TestPushChanges(t *testing.T) {
+	t.Parallel()
+
+	local := map[string]*file{
+		"new":       {value: []byte("n"), mode: 0644},
+		"same":      {value: []byte("s"), mode: 0644},
+		"changed":   {value: []byte("c2"), mode: 0644},
+		"nested/ok": {value: []byte("o"), mode: 0600},
+	}
+	remote := map[string]*api.KVPair{
+		"same":      {Key: "app/same", Value: []byte("s"), Flags: 7, ModifyIndex: 10},
+		"changed":   {Key: "app/changed", Value: []byte("c1"), Flags: 7, ModifyIndex: 11},
+		"nested/ok": {Key: "app/nested/ok", Value: []byte("o"), ModifyIndex: 12},
+		"stale":     {Key: "app/stale", Value: []byte("x"), ModifyIndex: 13},
+	}
+
+	changes := pushChanges(local, remote, "app/", false, false)
+	require.Equal(t, []*change{
+		{op: opUpdate, key: "app/changed", value: []byte("c2"), flags: 7, index: 11},
+		{op: opAdd, key: "app/new", value: []byte("n")},
+	}, changes)
+
+	// Pruning removes the stale key, and the file modes are compared with the
+	// flags.
+	changes = pushChanges(local, remote, "app/", true, true)
+	require.Equal(t, []*change{
+		{op: opUpdate, key: "app/changed", value: []byte("c2"), flags: 0644, index: 11},
+		{op: opUpdate, key: "app/nested/ok", value: []byte("o"), flags: 0600, index: 12},
+		{op: opAdd, key: "app/new", value: []byte("n"), flags: 0644},
+		{op: opUpdate, key: "app/same", value: []byte("s"), flags: 0644, index: 10},
+		{op: opRemove, key: "app/stale", index: 13},
+	}, changes)
+}
+
+func TestPullChanges(t *testing.T) {
+	t.Parallel()
+
+	local := map[string]*file{
+		"same":    {value: []byte("s"), mode: 0600},
+		"changed": {value: []byte("c1"), mode: 0644},
+		"extra":   {value: []byte("e"), mode: 0644},
+	}
+	remote := map[string]*api.KVPair{
+		"same":    {Key: "app/same", Value: []byte("s")},
+		"changed": {Key: "app/changed", Value: []byte("c2")},
+		"new/a":   {Key: "app/new/a", Value: []byte("n"), Flags: 0755},
+	}
+
+	changes, err := pullChanges(local, remote, "dir", true, true)
+	require.NoError(t, err)
+	require.Equal(t, []*change{
+		{op: opUpdate, key: "app/changed", path: filepath.Join("dir", "changed"), value: []byte("c2"), mode: 0644},
+		{op: opRemove, path: filepath.Join("dir", "extra")},
+		{op: opAdd, key: "app/new/a", path: filepath.Join("dir", "new", "a"), value: []byte("n"), mode: 0755},
+	}, changes)
+
+	_, err = pullChanges(nil, map[string]*api.KVPair{
+		"../escape": {Key: "app/../escape"},
+	}, "dir", false, false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Key "../escape" cannot be written to a file under dir`)
+}
+
+func TestTxnBatches(t *testing.T) {
+	t.Parallel()
+
+	var changes []*change
+	for i := 0; i < maxTxnOps+1; i++ {
+		changes = append(changes, &change{op: opAdd, key: "k"})
+	}
+	changes = append(changes, &change{op: opAdd, key: "big", value: make([]byte, maxTxnBytes+1)})
+	changes = append(changes, &change{op: opRemove, key: "gone", index: 5})
+
+	batches := txnBatches(changes)
+	require.Len(t, batches, 4)
+	require.Len(t, batches[0], maxTxnOps)
+	require.Len(t, batches[1], 1)
+	require.Len(t, batches[2], 1)
+	require.Equal(t, &api.KVTxnOp{Verb: api.KVDeleteCAS, Key: "gone", Index: 5}, batches[3][0].KV)
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	p := filepath.Join(t.TempDir(), "a", "b")
+	require.NoError(t, writeFile(p, []byte("one"), 0600))
+	require.NoError(t, writeFile(p, []byte("two"), 0644))
+
+	value, err := os.ReadFile(p)
+	require.NoError(t, err)
+	require.Equal(t, []byte("two"), value)
+
+	info, err := os.Stat(p)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(p))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestReadDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, p := range []string{"web", "db/conn", ".git/HEAD", ".git/objects/ab/cd", "db/.svn/entries", ".hg/store"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, p)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, p), []byte(p), 0644))
+	}
+
+	files, err := readDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[string]*file{
+		"web":     {value: []byte("web"), mode: 0644},
+		"db/conn": {value: []byte("db/conn"), mode: 0644},
+	}, files)
+}
@@ -0,0 +1,68 @@
+package login
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod/ldapauth"
+)
+
+type LDAPLogin struct {
+	username     string
+	passwordFile string
+}
+
+func (l *LDAPLogin) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.StringVar(&l.username, "ldap-username", "",
+		"Username to login to the LDAP auth method with. The password is read from "+
+			"-ldap-password-file, or prompted for when it is not set. [ldap only]")
+
+	fs.StringVar(&l.passwordFile, "ldap-password-file", "",
+		"Path to a file containing the password of -ldap-username. [ldap only]")
+	return fs
+}
+
+// checkFlags validates flags for the ldap auth method.
+func (l *LDAPLogin) checkFlags() error {
+	if l.passwordFile != "" && l.username == "" {
+		return fmt.Errorf("Missing '-ldap-username' flag")
+	}
+	return nil
+}
+
+// createLDAPBearerToken generates a bearer token string for the LDAP auth
+// method, holding the username and password of the user.
+func (l *LDAPLogin) createLDAPBearerToken(ui cli.Ui) (string, error) {
+	var password string
+	if l.passwordFile != "" {
+		data, err := os.ReadFile(l.passwordFile)
+		if err != nil {
+			return "", err
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	} else {
+		var err error
+		password, err = ui.AskSecret(fmt.Sprintf("Password for %s:", l.username))
+		if err != nil {
+			return "", err
+		}
+	}
+	if password == "" {
+		return "", fmt.Errorf("No password provided for %s", l.username)
+	}
+
+	token, err := json.Marshal(ldapauth.Credentials{
+		Username: l.username,
+		Password: password,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
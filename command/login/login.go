@@ -36,7 +36,8 @@ type cmd struct {
 	tokenSinkFile   string
 	meta            map[string]string
 
-	aws AWSLogin
+	aws  AWSLogin
+	ldap LDAPLogin
 
 	enterpriseCmd
 }
@@ -63,6 +64,7 @@ func (c *cmd) init() {
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.aws.flags())
+	flags.Merge(c.flags, c.ldap.flags())
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
 	flags.Merge(c.flags, c.http.MultiTenancyFlags())
@@ -95,6 +97,15 @@ func (c *cmd) bearerTokenLogin() int {
 		c.UI.Error(err.Error())
 		return 1
 	}
+	if err := c.ldap.checkFlags(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.aws.autoBearerToken && c.ldap.username != "" {
+		c.UI.Error("Cannot use '-ldap-username' flag with '-aws-auto-bearer-token'")
+		return 1
+	}
 
 	if c.aws.autoBearerToken {
 		if c.bearerTokenFile != "" {
@@ -108,6 +119,18 @@ func (c *cmd) bearerTokenLogin() int {
 		} else {
 			c.bearerToken = token
 		}
+	} else if c.ldap.username != "" {
+		if c.bearerTokenFile != "" {
+			c.UI.Error("Cannot use '-bearer-token-file' flag with '-ldap-username'")
+			return 1
+		}
+
+		if token, err := c.ldap.createLDAPBearerToken(c.UI); err != nil {
+			c.UI.Error(fmt.Sprintf("Error with ldap auth method: %s", err))
+			return 1
+		} else {
+			c.bearerToken = token
+		}
 	} else if c.bearerTokenFile == "" {
 		c.UI.Error("Missing required '-bearer-token-file' flag")
 		return 1
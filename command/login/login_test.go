@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/consul-awsauth/iamauthtest"
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/agent/consul/authmethod/kubeauth"
+	"github.com/hashicorp/consul/agent/consul/authmethod/ldapauth"
 	"github.com/hashicorp/consul/agent/consul/authmethod/testauth"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
@@ -675,6 +676,102 @@ func TestLoginCommand_aws_iam(t *testing.T) {
 	}
 }
 
+func TestLoginCommand_ldap(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := newTestAgent(t)
+	client := a.Client()
+
+	ldapServer := ldapauth.StartTestServer(t)
+	aliceDN := "cn=alice,ou=users,dc=example,dc=org"
+	ldapServer.AddEntry(t, aliceDN, map[string][]string{
+		"departmentNumber": {"billing"},
+		"memberOf":         {"cn=ops,ou=groups,dc=example,dc=org"},
+	})
+	ldapServer.SetPassword(aliceDN, "s3cret")
+
+	_, _, err := client.ACL().AuthMethodCreate(
+		&api.ACLAuthMethod{
+			Name: "ldap",
+			Type: "ldap",
+			Config: map[string]interface{}{
+				"URL":            ldapServer.URL(),
+				"UserDN":         "ou=users,dc=example,dc=org",
+				"UserAttributes": []string{"departmentNumber"},
+			},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.ACL().BindingRuleCreate(
+		&api.ACLBindingRule{
+			AuthMethod: "ldap",
+			BindType:   api.BindingRuleBindTypeService,
+			BindName:   "${attributes.departmentNumber}-${username}",
+			Selector:   `"ops" in groups`,
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	testDir := testutil.TempDir(t, "acl")
+	passwordFile := filepath.Join(testDir, "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("s3cret\n"), 0600))
+
+	login := func(t *testing.T, extraArgs ...string) (*cli.MockUi, int, string) {
+		tokenSinkFile := filepath.Join(testDir, "test.token")
+		t.Cleanup(func() { _ = os.Remove(tokenSinkFile) })
+
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-method=ldap",
+			"-token-sink-file", tokenSinkFile,
+		}
+		code := cmd.Run(append(args, extraArgs...))
+		return ui, code, tokenSinkFile
+	}
+
+	t.Run("password file without username", func(t *testing.T) {
+		ui, code, _ := login(t, "-ldap-password-file", passwordFile)
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Missing '-ldap-username' flag")
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		ui, code, _ := login(t, "-ldap-username", "alice", "-ldap-password-file", os.DevNull)
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "No password provided for alice")
+
+		wrongFile := filepath.Join(testDir, "wrong")
+		require.NoError(t, os.WriteFile(wrongFile, []byte("wrong"), 0600))
+		ui, code, _ = login(t, "-ldap-username", "alice", "-ldap-password-file", wrongFile)
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "invalid username or password")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ui, code, tokenSinkFile := login(t, "-ldap-username", "alice", "-ldap-password-file", passwordFile)
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		raw, err := os.ReadFile(tokenSinkFile)
+		require.NoError(t, err)
+		token := strings.TrimSpace(string(raw))
+
+		tokenRead, _, err := client.ACL().TokenReadSelf(&api.QueryOptions{Token: token})
+		require.NoError(t, err)
+		require.Len(t, tokenRead.ServiceIdentities, 1)
+		require.Equal(t, "billing-alice", tokenRead.ServiceIdentities[0].ServiceName)
+	})
+}
+
 func newTestAgent(t *testing.T) *agent.TestAgent {
 	a := agent.NewTestAgent(t, `
 	primary_datacenter = "dc1"
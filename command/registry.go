@@ -45,6 +45,8 @@ import (
 	catlistdc "github.com/hashicorp/consul/command/catalog/list/dc"
 	catlistnodes "github.com/hashicorp/consul/command/catalog/list/nodes"
 	catlistsvc "github.com/hashicorp/consul/command/catalog/list/services"
+	"github.com/hashicorp/consul/command/check"
+	checkhistory "github.com/hashicorp/consul/command/check/history"
 	"github.com/hashicorp/consul/command/config"
 	configdelete "github.com/hashicorp/consul/command/config/delete"
 	configlist "github.com/hashicorp/consul/command/config/list"
@@ -79,6 +81,7 @@ import (
 	kvexp "github.com/hashicorp/consul/command/kv/exp"
 	kvget "github.com/hashicorp/consul/command/kv/get"
 	kvimp "github.com/hashicorp/consul/command/kv/imp"
+	"github.com/hashicorp/consul/command/kv/kvsync"
 	kvput "github.com/hashicorp/consul/command/kv/put"
 	"github.com/hashicorp/consul/command/leave"
 	"github.com/hashicorp/consul/command/lock"
@@ -108,7 +111,9 @@ import (
 	svcsderegister "github.com/hashicorp/consul/command/services/deregister"
 	svcsregister "github.com/hashicorp/consul/command/services/register"
 	"github.com/hashicorp/consul/command/snapshot"
+	snapdiff "github.com/hashicorp/consul/command/snapshot/diff"
 	snapinspect "github.com/hashicorp/consul/command/snapshot/inspect"
+	snapquery "github.com/hashicorp/consul/command/snapshot/query"
 	snaprestore "github.com/hashicorp/consul/command/snapshot/restore"
 	snapsave "github.com/hashicorp/consul/command/snapshot/save"
 	"github.com/hashicorp/consul/command/tls"
@@ -169,6 +174,8 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"catalog datacenters", func(ui cli.Ui) (cli.Command, error) { return catlistdc.New(ui), nil }},
 		entry{"catalog nodes", func(ui cli.Ui) (cli.Command, error) { return catlistnodes.New(ui), nil }},
 		entry{"catalog services", func(ui cli.Ui) (cli.Command, error) { return catlistsvc.New(ui), nil }},
+		entry{"check", func(cli.Ui) (cli.Command, error) { return check.New(), nil }},
+		entry{"check history", func(ui cli.Ui) (cli.Command, error) { return checkhistory.New(ui), nil }},
 		entry{"config", func(ui cli.Ui) (cli.Command, error) { return config.New(), nil }},
 		entry{"config delete", func(ui cli.Ui) (cli.Command, error) { return configdelete.New(ui), nil }},
 		entry{"config list", func(ui cli.Ui) (cli.Command, error) { return configlist.New(ui), nil }},
@@ -204,6 +211,7 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"kv get", func(ui cli.Ui) (cli.Command, error) { return kvget.New(ui), nil }},
 		entry{"kv import", func(ui cli.Ui) (cli.Command, error) { return kvimp.New(ui), nil }},
 		entry{"kv put", func(ui cli.Ui) (cli.Command, error) { return kvput.New(ui), nil }},
+		entry{"kv sync", func(ui cli.Ui) (cli.Command, error) { return kvsync.New(ui), nil }},
 		entry{"leave", func(ui cli.Ui) (cli.Command, error) { return leave.New(ui), nil }},
 		entry{"lock", func(ui cli.Ui) (cli.Command, error) { return lock.New(ui, MakeShutdownCh()), nil }},
 		entry{"login", func(ui cli.Ui) (cli.Command, error) { return login.New(ui), nil }},
@@ -232,7 +240,9 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"services register", func(ui cli.Ui) (cli.Command, error) { return svcsregister.New(ui), nil }},
 		entry{"services deregister", func(ui cli.Ui) (cli.Command, error) { return svcsderegister.New(ui), nil }},
 		entry{"snapshot", func(cli.Ui) (cli.Command, error) { return snapshot.New(), nil }},
+		entry{"snapshot diff", func(ui cli.Ui) (cli.Command, error) { return snapdiff.New(ui), nil }},
 		entry{"snapshot inspect", func(ui cli.Ui) (cli.Command, error) { return snapinspect.New(ui), nil }},
+		entry{"snapshot query", func(ui cli.Ui) (cli.Command, error) { return snapquery.New(ui), nil }},
 		entry{"snapshot restore", func(ui cli.Ui) (cli.Command, error) { return snaprestore.New(ui), nil }},
 		entry{"snapshot save", func(ui cli.Ui) (cli.Command, error) { return snapsave.New(ui), nil }},
 		entry{"tls", func(ui cli.Ui) (cli.Command, error) { return tls.New(), nil }},
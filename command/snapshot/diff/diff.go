@@ -0,0 +1,266 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// Changes lists the identifiers of the items of a single type that were
+// added, removed or changed between two snapshots.
+type Changes struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty returns true if no items were added, removed or changed.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// StateDiff holds the differences between the state stored in two snapshots.
+type StateDiff struct {
+	KV            Changes
+	Nodes         Changes
+	Services      Changes
+	ConfigEntries Changes
+	ACLTokens     Changes
+	ACLPolicies   Changes
+	Intentions    Changes
+}
+
+// Empty returns true if the two snapshots hold the same state.
+func (d *StateDiff) Empty() bool {
+	for _, s := range d.sections() {
+		if !s.Changes.Empty() {
+			return false
+		}
+	}
+	return true
+}
+
+type section struct {
+	Name    string
+	Changes Changes
+}
+
+// sections returns the changes in the order they are displayed.
+func (d *StateDiff) sections() []section {
+	return []section{
+		{"KV", d.KV},
+		{"Nodes", d.Nodes},
+		{"Services", d.Services},
+		{"Config Entries", d.ConfigEntries},
+		{"ACL Tokens", d.ACLTokens},
+		{"ACL Policies", d.ACLPolicies},
+		{"Intentions", d.Intentions},
+	}
+}
+
+// items maps the identifier of each item of a single type to its value.
+type items map[string]interface{}
+
+// stateItems holds all of the items read from a state store which take part
+// in the diff.
+type stateItems struct {
+	kv            items
+	nodes         items
+	services      items
+	configEntries items
+	aclTokens     items
+	aclPolicies   items
+	intentions    items
+}
+
+// Diff computes the differences between the from and to state stores.
+func Diff(from, to *state.Store) (*StateDiff, error) {
+	a, err := readState(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := readState(to)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &StateDiff{}
+	for _, c := range []struct {
+		out  *Changes
+		a, b items
+	}{
+		{&d.KV, a.kv, b.kv},
+		{&d.Nodes, a.nodes, b.nodes},
+		{&d.Services, a.services, b.services},
+		{&d.ConfigEntries, a.configEntries, b.configEntries},
+		{&d.ACLTokens, a.aclTokens, b.aclTokens},
+		{&d.ACLPolicies, a.aclPolicies, b.aclPolicies},
+		{&d.Intentions, a.intentions, b.intentions},
+	} {
+		changes, err := diffItems(c.a, c.b)
+		if err != nil {
+			return nil, err
+		}
+		*c.out = changes
+	}
+	return d, nil
+}
+
+func readState(s *state.Store) (*stateItems, error) {
+	entMeta := structs.WildcardEnterpriseMetaInPartition(acl.WildcardPartitionName)
+	out := &stateItems{
+		kv:            make(items),
+		nodes:         make(items),
+		services:      make(items),
+		configEntries: make(items),
+		aclTokens:     make(items),
+		aclPolicies:   make(items),
+		intentions:    make(items),
+	}
+
+	_, entries, err := s.KVSList(nil, "", entMeta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KV entries: %w", err)
+	}
+	for _, e := range entries {
+		out.kv[e.Key] = e
+	}
+
+	_, nodes, err := s.Nodes(nil, entMeta, structs.DefaultPeerKeyword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for _, n := range nodes {
+		out.nodes[n.Node] = n
+
+		_, ns, err := s.NodeServices(nil, n.Node, entMeta, structs.DefaultPeerKeyword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services for node %q: %w", n.Node, err)
+		}
+		if ns == nil {
+			continue
+		}
+		for id, svc := range ns.Services {
+			out.services[n.Node+"/"+id] = svc
+		}
+	}
+
+	_, confEntries, err := s.ConfigEntries(nil, entMeta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config entries: %w", err)
+	}
+	for _, e := range confEntries {
+		out.configEntries[e.GetKind()+"/"+e.GetName()] = e
+	}
+
+	_, tokens, err := s.ACLTokenList(nil, true, true, "", "", "", nil, entMeta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ACL tokens: %w", err)
+	}
+	for _, t := range tokens {
+		out.aclTokens[t.AccessorID] = t
+	}
+
+	_, policies, err := s.ACLPolicyList(nil, entMeta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ACL policies: %w", err)
+	}
+	for _, p := range policies {
+		out.aclPolicies[p.Name] = p
+	}
+
+	_, ixns, _, err := s.Intentions(nil, entMeta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intentions: %w", err)
+	}
+	for _, ixn := range ixns {
+		out.intentions[intentionID(ixn)] = ixn
+	}
+
+	return out, nil
+}
+
+// intentionID identifies an intention by its source and destination, as the
+// ID of an intention is not preserved when it is migrated to a config entry.
+func intentionID(ixn *structs.Intention) string {
+	src := ixn.SourceServiceName().String()
+	if ixn.SourcePeer != "" {
+		src = "peer(" + ixn.SourcePeer + ")/" + src
+	}
+	return src + " => " + ixn.DestinationServiceName().String()
+}
+
+func diffItems(from, to items) (Changes, error) {
+	c := Changes{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	for id, a := range from {
+		b, ok := to[id]
+		if !ok {
+			c.Removed = append(c.Removed, id)
+			continue
+		}
+		equal, err := equalIgnoringIndexes(a, b)
+		if err != nil {
+			return c, fmt.Errorf("failed to compare %q: %w", id, err)
+		}
+		if !equal {
+			c.Changed = append(c.Changed, id)
+		}
+	}
+	for id := range to {
+		if _, ok := from[id]; !ok {
+			c.Added = append(c.Added, id)
+		}
+	}
+	sort.Strings(c.Added)
+	sort.Strings(c.Removed)
+	sort.Strings(c.Changed)
+	return c, nil
+}
+
+// equalIgnoringIndexes compares two items by their JSON representation with
+// any raft indexes removed, so that an item which was rewritten without
+// modification isn't reported as changed.
+func equalIgnoringIndexes(a, b interface{}) (bool, error) {
+	na, err := normalize(a)
+	if err != nil {
+		return false, err
+	}
+	nb, err := normalize(b)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(na, nb), nil
+}
+
+func normalize(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	stripIndexes(out)
+	return out, nil
+}
+
+func stripIndexes(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		delete(v, "CreateIndex")
+		delete(v, "ModifyIndex")
+		for _, e := range v {
+			stripIndexes(e)
+		}
+	case []interface{}:
+		for _, e := range v {
+			stripIndexes(e)
+		}
+	}
+}
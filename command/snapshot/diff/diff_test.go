@@ -0,0 +1,64 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestDiff(t *testing.T) {
+	from := state.NewStateStore(nil)
+	to := state.NewStateStore(nil)
+
+	// Seed both stores with the same baseline, using different raft indexes
+	// so that only changes in content are reported.
+	for i, s := range []*state.Store{from, to} {
+		idx := uint64(10 * (i + 1))
+		require.NoError(t, s.KVSSet(idx, &structs.DirEntry{Key: "same", Value: []byte("v")}))
+		require.NoError(t, s.KVSSet(idx, &structs.DirEntry{Key: "changed", Value: []byte("v")}))
+		require.NoError(t, s.EnsureNode(idx, &structs.Node{Node: "node1", Address: "10.0.0.1"}))
+		require.NoError(t, s.EnsureService(idx, "node1", &structs.NodeService{ID: "web1", Service: "web", Port: 8080}))
+		require.NoError(t, s.EnsureConfigEntry(idx, &structs.ServiceConfigEntry{Kind: structs.ServiceDefaults, Name: "web", Protocol: "http"}))
+		require.NoError(t, s.ACLPolicySet(idx, &structs.ACLPolicy{ID: "a3e8c4f2-4a36-4b4f-a1a8-0a4b2ab8f1c5", Name: "ops", Rules: `key_prefix "" { policy = "read" }`}))
+		require.NoError(t, s.LegacyIntentionSet(idx, &structs.Intention{
+			ID:              "f5a8f6b4-3cd4-4c6e-a2d4-b1ec4a5c8e52",
+			SourceNS:        "default",
+			SourceName:      "web",
+			DestinationNS:   "default",
+			DestinationName: "db",
+			Action:          structs.IntentionActionAllow,
+		}))
+	}
+
+	require.NoError(t, from.KVSSet(20, &structs.DirEntry{Key: "removed", Value: []byte("v")}))
+	require.NoError(t, to.KVSSet(30, &structs.DirEntry{Key: "added", Value: []byte("v")}))
+	require.NoError(t, to.KVSSet(30, &structs.DirEntry{Key: "changed", Value: []byte("v2")}))
+	require.NoError(t, to.EnsureNode(30, &structs.Node{Node: "node2", Address: "10.0.0.2"}))
+	require.NoError(t, to.EnsureService(30, "node1", &structs.NodeService{ID: "web1", Service: "web", Port: 9090}))
+	require.NoError(t, to.EnsureConfigEntry(30, &structs.ServiceConfigEntry{Kind: structs.ServiceDefaults, Name: "api", Protocol: "grpc"}))
+	require.NoError(t, to.ACLTokenSet(30, &structs.ACLToken{
+		AccessorID: "0d9f5b3a-6f34-4c8b-a3f8-1b7a4c0e2d61",
+		SecretID:   "6a1d3c2e-8d2f-4b8e-9e5c-3f4a1b2c3d4e",
+		Policies:   []structs.ACLTokenPolicyLink{{ID: "a3e8c4f2-4a36-4b4f-a1a8-0a4b2ab8f1c5"}},
+	}))
+	require.NoError(t, to.LegacyIntentionDelete(30, "f5a8f6b4-3cd4-4c6e-a2d4-b1ec4a5c8e52"))
+
+	d, err := Diff(from, to)
+	require.NoError(t, err)
+
+	require.Equal(t, Changes{Added: []string{"added"}, Removed: []string{"removed"}, Changed: []string{"changed"}}, d.KV)
+	require.Equal(t, Changes{Added: []string{"node2"}, Removed: []string{}, Changed: []string{}}, d.Nodes)
+	require.Equal(t, Changes{Added: []string{}, Removed: []string{}, Changed: []string{"node1/web1"}}, d.Services)
+	require.Equal(t, Changes{Added: []string{"service-defaults/api"}, Removed: []string{}, Changed: []string{}}, d.ConfigEntries)
+	require.Equal(t, Changes{Added: []string{"0d9f5b3a-6f34-4c8b-a3f8-1b7a4c0e2d61"}, Removed: []string{}, Changed: []string{}}, d.ACLTokens)
+	require.True(t, d.ACLPolicies.Empty())
+	require.Equal(t, Changes{Added: []string{}, Removed: []string{"web => db"}, Changed: []string{}}, d.Intentions)
+	require.False(t, d.Empty())
+
+	d, err = Diff(from, from)
+	require.NoError(t, err)
+	require.True(t, d.Empty())
+}
@@ -0,0 +1,87 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+)
+
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
+type Formatter interface {
+	Format(*OutputFormat) (string, error)
+}
+
+func GetSupportedFormats() []string {
+	return []string{PrettyFormat, JSONFormat}
+}
+
+func NewFormatter(format string) (Formatter, error) {
+	switch format {
+	case PrettyFormat:
+		return newPrettyFormatter(), nil
+	case JSONFormat:
+		return newJSONFormatter(), nil
+	default:
+		return nil, fmt.Errorf("Unknown format: %s", format)
+	}
+}
+
+type prettyFormatter struct{}
+
+func newPrettyFormatter() Formatter {
+	return &prettyFormatter{}
+}
+
+func (_ *prettyFormatter) Format(info *OutputFormat) (string, error) {
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 8, 8, 6, ' ', 0)
+
+	fmt.Fprintf(tw, " From\t%s\t(index %d)", info.From.ID, info.From.Index)
+	fmt.Fprintf(tw, "\n To\t%s\t(index %d)", info.To.ID, info.To.Index)
+	if err := tw.Flush(); err != nil {
+		return b.String(), err
+	}
+
+	if info.StateDiff.Empty() {
+		fmt.Fprintf(&b, "\n\n No differences found")
+		return b.String(), nil
+	}
+
+	for _, s := range info.StateDiff.sections() {
+		if s.Changes.Empty() {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n %s (%d added, %d removed, %d changed)",
+			s.Name, len(s.Changes.Added), len(s.Changes.Removed), len(s.Changes.Changed))
+		for _, id := range s.Changes.Added {
+			fmt.Fprintf(&b, "\n   + %s", id)
+		}
+		for _, id := range s.Changes.Removed {
+			fmt.Fprintf(&b, "\n   - %s", id)
+		}
+		for _, id := range s.Changes.Changed {
+			fmt.Fprintf(&b, "\n   ~ %s", id)
+		}
+	}
+
+	return b.String(), nil
+}
+
+type jsonFormatter struct{}
+
+func newJSONFormatter() Formatter {
+	return &jsonFormatter{}
+}
+
+func (_ *jsonFormatter) Format(info *OutputFormat) (string, error) {
+	b, err := json.MarshalIndent(info, "", "   ")
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal snapshot diff: %v", err)
+	}
+	return string(b), nil
+}
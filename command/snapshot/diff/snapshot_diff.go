@@ -0,0 +1,135 @@
+package diff
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/raft"
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/snapshot/internal/archive"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI     cli.Ui
+	flags  *flag.FlagSet
+	help   string
+	format string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(GetSupportedFormats(), "|")))
+
+	c.help = flags.Usage(help, c.flags)
+}
+
+// MetadataInfo is used for passing information
+// through the formatter
+type MetadataInfo struct {
+	ID      string
+	Index   uint64
+	Term    uint64
+	Version raft.SnapshotVersion
+}
+
+// OutputFormat is used for passing information
+// through the formatter
+type OutputFormat struct {
+	From *MetadataInfo
+	To   *MetadataInfo
+	*StateDiff
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 2 {
+		c.UI.Error(fmt.Sprintf("Expected two FILE arguments (got %d)", len(args)))
+		return 1
+	}
+
+	formatter, err := NewFormatter(c.format)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	from, fromMeta, err := archive.LoadState(args[0])
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading %q: %s", args[0], err))
+		return 1
+	}
+	to, toMeta, err := archive.LoadState(args[1])
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading %q: %s", args[1], err))
+		return 1
+	}
+
+	d, err := Diff(from, to)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error comparing snapshots: %s", err))
+		return 1
+	}
+
+	out, err := formatter.Format(&OutputFormat{
+		From:      metadataInfo(fromMeta),
+		To:        metadataInfo(toMeta),
+		StateDiff: d,
+	})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(out)
+	return 0
+}
+
+func metadataInfo(meta *raft.SnapshotMeta) *MetadataInfo {
+	return &MetadataInfo{
+		ID:      meta.ID,
+		Index:   meta.Index,
+		Term:    meta.Term,
+		Version: meta.Version,
+	}
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Compares the contents of two Consul snapshot files"
+const help = `
+Usage: consul snapshot diff [options] FROM TO
+
+  Compares the state stored in two snapshot files on disk and reports the
+  KV entries, nodes, services, config entries, ACL tokens, ACL policies and
+  intentions which were added, removed or changed between them.
+
+  To compare "before.snap" with "after.snap":
+
+    $ consul snapshot diff before.snap after.snap
+
+  For a full list of options and examples, please see the Consul documentation.
+`
@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestSnapshotDiffCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestSnapshotDiffCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		output string
+	}{
+		"no file": {
+			[]string{},
+			"Expected two FILE arguments (got 0)",
+		},
+		"one file": {
+			[]string{"foo"},
+			"Expected two FILE arguments (got 1)",
+		},
+		"extra args": {
+			[]string{"foo", "bar", "baz"},
+			"Expected two FILE arguments (got 3)",
+		},
+		"bad format": {
+			[]string{"-format=yaml", "foo", "bar"},
+			"Unknown format: yaml",
+		},
+	}
+
+	for name, tc := range cases {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(tc.args)
+		if code == 0 {
+			t.Errorf("%s: expected non-zero exit", name)
+		}
+
+		output := ui.ErrorWriter.String()
+		if !strings.Contains(output, tc.output) {
+			t.Errorf("%s: expected %q to contain %q", name, output, tc.output)
+		}
+	}
+}
+
+func TestSnapshotDiffCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+	dir := testutil.TempDir(t, "snapshot")
+
+	save := func(name string) string {
+		file := filepath.Join(dir, name)
+		snap, _, err := client.Snapshot().Save(nil)
+		require.NoError(t, err)
+		defer snap.Close()
+
+		f, err := os.Create(file)
+		require.NoError(t, err)
+		defer f.Close()
+		_, err = io.Copy(f, snap)
+		require.NoError(t, err)
+		return file
+	}
+
+	kv := client.KV()
+	_, err := kv.Put(&api.KVPair{Key: "removed", Value: []byte("a")}, nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "changed", Value: []byte("a")}, nil)
+	require.NoError(t, err)
+	before := save("before.snap")
+
+	_, err = kv.Delete("removed", nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "changed", Value: []byte("b")}, nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "added", Value: []byte("c")}, nil)
+	require.NoError(t, err)
+	_, err = client.Catalog().Register(&api.CatalogRegistration{
+		Node:    "external",
+		Address: "10.1.2.3",
+		Service: &api.AgentService{ID: "ext1", Service: "ext"},
+	}, nil)
+	require.NoError(t, err)
+	after := save("after.snap")
+
+	t.Run("pretty", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{before, after})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		out := ui.OutputWriter.String()
+		require.Contains(t, out, "KV (1 added, 1 removed, 1 changed)")
+		require.Contains(t, out, "+ added")
+		require.Contains(t, out, "- removed")
+		require.Contains(t, out, "~ changed")
+		require.Contains(t, out, "+ external")
+		require.Contains(t, out, "+ external/ext1")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-format=json", before, after})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var out OutputFormat
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+		require.Equal(t, []string{"added"}, out.KV.Added)
+		require.Equal(t, []string{"removed"}, out.KV.Removed)
+		require.Equal(t, []string{"changed"}, out.KV.Changed)
+		require.Equal(t, []string{"external"}, out.Nodes.Added)
+		require.Equal(t, []string{"external/ext1"}, out.Services.Added)
+		require.True(t, out.Intentions.Empty())
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{after, after})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "No differences found")
+	})
+}
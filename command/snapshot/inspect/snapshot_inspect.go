@@ -1,12 +1,9 @@
 package inspect
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
-	"os"
-	"path"
 	"sort"
 	"strings"
 
@@ -14,8 +11,7 @@ import (
 	"github.com/hashicorp/consul/agent/consul/fsm"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/command/flags"
-	"github.com/hashicorp/consul/snapshot"
-	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/consul/command/snapshot/internal/archive"
 	"github.com/hashicorp/raft"
 	"github.com/mitchellh/cli"
 )
@@ -105,52 +101,19 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	// Open the file.
-	f, err := os.Open(file)
+	f, err := archive.Open(file)
 	if err != nil {
-		c.UI.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		c.UI.Error(err.Error())
 		return 1
 	}
-	defer f.Close()
-
-	var readFile *os.File
-	var meta *raft.SnapshotMeta
-
-	if strings.ToLower(path.Base(file)) == "state.bin" {
-		// This is an internal raw raft snapshot not a gzipped archive one
-		// downloaded from the API, we can read it directly
-		readFile = f
-
-		// Assume the meta is colocated and error if not.
-		metaRaw, err := os.ReadFile(path.Join(path.Dir(file), "meta.json"))
-		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error reading meta.json from internal snapshot dir: %s", err))
-			return 1
-		}
-		var metaDecoded raft.SnapshotMeta
-		err = json.Unmarshal(metaRaw, &metaDecoded)
-		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error parsing meta.json from internal snapshot dir: %s", err))
-			return 1
-		}
-		meta = &metaDecoded
-	} else {
-		readFile, meta, err = snapshot.Read(hclog.New(nil), f)
-		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error reading snapshot: %s", err))
-			return 1
+	defer func() {
+		if err := f.Close(); err != nil {
+			c.UI.Error(err.Error())
 		}
-		defer func() {
-			if err := readFile.Close(); err != nil {
-				c.UI.Error(fmt.Sprintf("Failed to close temp snapshot: %v", err))
-			}
-			if err := os.Remove(readFile.Name()); err != nil {
-				c.UI.Error(fmt.Sprintf("Failed to clean up temp snapshot: %v", err))
-			}
-		}()
-	}
+	}()
+	meta := f.Meta
 
-	info, err := c.enhance(readFile)
+	info, err := c.enhance(f.Data)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error extracting snapshot data: %s", err))
 		return 1
@@ -0,0 +1,114 @@
+// Package archive contains helpers shared by the snapshot subcommands for
+// opening snapshot files and decoding them into an in-memory state store.
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+
+	"github.com/hashicorp/consul/agent/consul/fsm"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/snapshot"
+)
+
+// File is an opened snapshot. Data holds the raw FSM snapshot stream which can
+// be passed to fsm.ReadSnapshot or an FSM's Restore method.
+type File struct {
+	Data *os.File
+	Meta *raft.SnapshotMeta
+
+	source *os.File
+	temp   bool
+}
+
+// Open opens the snapshot at the given path. Both gzipped archives, as saved
+// by "consul snapshot save", and the raw state.bin files found in a server's
+// raft snapshot directory are supported. For the latter, the meta.json file
+// is expected to be colocated with the state file.
+func Open(file string) (*File, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening snapshot file: %w", err)
+	}
+
+	if strings.ToLower(path.Base(file)) == "state.bin" {
+		// This is an internal raw raft snapshot not a gzipped archive one
+		// downloaded from the API, we can read it directly
+		metaRaw, err := os.ReadFile(path.Join(path.Dir(file), "meta.json"))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("Error reading meta.json from internal snapshot dir: %w", err)
+		}
+		var meta raft.SnapshotMeta
+		if err := json.Unmarshal(metaRaw, &meta); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("Error parsing meta.json from internal snapshot dir: %w", err)
+		}
+		return &File{Data: f, Meta: &meta, source: f}, nil
+	}
+
+	data, meta, err := snapshot.Read(hclog.NewNullLogger(), f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("Error reading snapshot: %w", err)
+	}
+	return &File{Data: data, Meta: meta, source: f, temp: true}, nil
+}
+
+// Close closes the snapshot and removes any temporary file created while
+// extracting it.
+func (f *File) Close() error {
+	var errs []string
+	if f.temp {
+		if err := f.Data.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("Failed to close temp snapshot: %v", err))
+		}
+		if err := os.Remove(f.Data.Name()); err != nil {
+			errs = append(errs, fmt.Sprintf("Failed to clean up temp snapshot: %v", err))
+		}
+	}
+	if err := f.source.Close(); err != nil {
+		errs = append(errs, fmt.Sprintf("Failed to close snapshot file: %v", err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// LoadState opens the snapshot at the given path and restores it into a new
+// state store using the same restore logic as a Consul server.
+func LoadState(file string) (*state.Store, *raft.SnapshotMeta, error) {
+	f, err := Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	store, err := Restore(f.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, f.Meta, nil
+}
+
+// Restore decodes a raw FSM snapshot stream into a new state store.
+func Restore(r io.Reader) (*state.Store, error) {
+	m := fsm.NewFromDeps(fsm.Deps{
+		Logger: hclog.NewNullLogger(),
+		NewStateStore: func() *state.Store {
+			return state.NewStateStore(nil)
+		},
+	})
+	if err := m.Restore(io.NopCloser(r)); err != nil {
+		return nil, fmt.Errorf("Error restoring snapshot: %w", err)
+	}
+	return m.State(), nil
+}
@@ -0,0 +1,187 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/go-bexpr"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// endpoint describes a read which can be run against a restored snapshot.
+// Paths mirror the corresponding HTTP API endpoints without the "/v1/" prefix.
+type endpoint struct {
+	// path is the fixed part of the path. If hasArg is set, the remainder of
+	// the path after a trailing slash is passed to read as its argument.
+	path        string
+	hasArg      bool
+	optionalArg bool
+	argName     string
+	read        func(s *state.Store, arg string) (interface{}, error)
+}
+
+var endpoints = []endpoint{
+	{
+		path: "catalog/nodes",
+		read: func(s *state.Store, _ string) (interface{}, error) {
+			_, nodes, err := s.Nodes(nil, entMeta(), structs.DefaultPeerKeyword)
+			return nodes, err
+		},
+	},
+	{
+		path:    "catalog/service",
+		hasArg:  true,
+		argName: "service",
+		read: func(s *state.Store, name string) (interface{}, error) {
+			_, nodes, err := s.ServiceNodes(nil, name, entMeta(), structs.DefaultPeerKeyword)
+			return nodes, err
+		},
+	},
+	{
+		path:    "catalog/node-services",
+		hasArg:  true,
+		argName: "node",
+		read: func(s *state.Store, name string) (interface{}, error) {
+			_, services, err := s.NodeServiceList(nil, name, entMeta(), structs.DefaultPeerKeyword)
+			if err != nil || services == nil {
+				return structs.NodeServiceList{}, err
+			}
+			return services.Services, nil
+		},
+	},
+	{
+		path:    "health/service",
+		hasArg:  true,
+		argName: "service",
+		read: func(s *state.Store, name string) (interface{}, error) {
+			_, nodes, err := s.CheckServiceNodes(nil, name, entMeta(), structs.DefaultPeerKeyword)
+			return nodes, err
+		},
+	},
+	{
+		path:    "health/checks",
+		hasArg:  true,
+		argName: "service",
+		read: func(s *state.Store, name string) (interface{}, error) {
+			_, checks, err := s.ServiceChecks(nil, name, entMeta(), structs.DefaultPeerKeyword)
+			return checks, err
+		},
+	},
+	{
+		path:    "health/node",
+		hasArg:  true,
+		argName: "node",
+		read: func(s *state.Store, name string) (interface{}, error) {
+			_, checks, err := s.NodeChecks(nil, name, entMeta(), structs.DefaultPeerKeyword)
+			return checks, err
+		},
+	},
+	{
+		path:    "health/state",
+		hasArg:  true,
+		argName: "state",
+		read: func(s *state.Store, checkState string) (interface{}, error) {
+			switch checkState {
+			case "any", "passing", "warning", "critical":
+			default:
+				return nil, fmt.Errorf("Invalid check state %q", checkState)
+			}
+			_, checks, err := s.ChecksInState(nil, checkState, entMeta(), structs.DefaultPeerKeyword)
+			return checks, err
+		},
+	},
+	{
+		path:        "kv",
+		hasArg:      true,
+		optionalArg: true,
+		argName:     "prefix",
+		read: func(s *state.Store, prefix string) (interface{}, error) {
+			_, entries, err := s.KVSList(nil, prefix, entMeta())
+			return entries, err
+		},
+	},
+	{
+		path:    "config",
+		hasArg:  true,
+		argName: "kind",
+		read: func(s *state.Store, kind string) (interface{}, error) {
+			if _, err := structs.MakeConfigEntry(kind, ""); err != nil {
+				return nil, fmt.Errorf("Invalid config entry kind %q", kind)
+			}
+			_, entries, err := s.ConfigEntriesByKind(nil, kind, entMeta())
+			return entries, err
+		},
+	},
+}
+
+func entMeta() *acl.EnterpriseMeta {
+	return structs.WildcardEnterpriseMetaInPartition(acl.WildcardPartitionName)
+}
+
+// findEndpoint returns the endpoint serving the given path along with the
+// argument parsed from it.
+func findEndpoint(path string) (*endpoint, string, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "/"), "v1/")
+	for i := range endpoints {
+		e := &endpoints[i]
+
+		var arg string
+		switch {
+		case path == e.path:
+		case e.hasArg && strings.HasPrefix(path, e.path+"/"):
+			arg = strings.TrimPrefix(path, e.path+"/")
+		default:
+			continue
+		}
+
+		if e.hasArg && arg == "" && !e.optionalArg {
+			return nil, "", fmt.Errorf("Missing %s in path %q (expected %s/<%s>)", e.argName, path, e.path, e.argName)
+		}
+		return e, arg, nil
+	}
+	return nil, "", fmt.Errorf("Unsupported path %q", path)
+}
+
+// run executes the read for the given path against the state store, applying
+// the filter expression to the results.
+func run(s *state.Store, path, filterExpr string) (interface{}, error) {
+	e, arg, err := findEndpoint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.read(s, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Config entries are returned as a list of interfaces, so for those the
+	// expression is validated against the concrete type of the first result
+	// when it is evaluated.
+	dataType := result
+	if t := reflect.TypeOf(result); t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Interface {
+		dataType = nil
+	}
+	filter, err := bexpr.CreateFilter(filterExpr, nil, dataType)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Execute(result)
+}
+
+// paths returns a description of each supported path for use in help output.
+func paths() []string {
+	var out []string
+	for _, e := range endpoints {
+		if e.hasArg {
+			out = append(out, fmt.Sprintf("%s/<%s>", e.path, e.argName))
+		} else {
+			out = append(out, e.path)
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+func TestFindEndpoint(t *testing.T) {
+	cases := map[string]struct {
+		path string
+		want string
+		arg  string
+		err  string
+	}{
+		"no arg":              {path: "catalog/nodes", want: "catalog/nodes"},
+		"api prefix":          {path: "/v1/catalog/nodes", want: "catalog/nodes"},
+		"with arg":            {path: "health/service/web", want: "health/service", arg: "web"},
+		"kv all keys":         {path: "kv", want: "kv"},
+		"kv prefix":           {path: "kv/app/config", want: "kv", arg: "app/config"},
+		"missing arg":         {path: "catalog/service", err: "Missing service in path"},
+		"missing arg slash":   {path: "config/", err: "Missing kind in path"},
+		"unsupported":         {path: "agent/self", err: `Unsupported path "agent/self"`},
+		"unsupported similar": {path: "catalog/nodesx", err: "Unsupported path"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, arg, err := findEndpoint(tc.path)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, e.path)
+			require.Equal(t, tc.arg, arg)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	s := state.NewStateStore(nil)
+	require.NoError(t, s.EnsureNode(1, &structs.Node{Node: "node1", Address: "10.0.0.1", Meta: map[string]string{"env": "prod"}}))
+	require.NoError(t, s.EnsureNode(2, &structs.Node{Node: "node2", Address: "10.0.0.2", Meta: map[string]string{"env": "dev"}}))
+	require.NoError(t, s.EnsureService(3, "node1", &structs.NodeService{ID: "web1", Service: "web", Port: 80}))
+	require.NoError(t, s.EnsureService(4, "node2", &structs.NodeService{ID: "web2", Service: "web", Port: 81}))
+	require.NoError(t, s.EnsureCheck(5, &structs.HealthCheck{Node: "node2", CheckID: "web2", ServiceID: "web2", Status: api.HealthCritical}))
+	require.NoError(t, s.KVSSet(6, &structs.DirEntry{Key: "app/a", Flags: 1}))
+	require.NoError(t, s.KVSSet(7, &structs.DirEntry{Key: "app/b", Flags: 2}))
+	require.NoError(t, s.KVSSet(8, &structs.DirEntry{Key: "other"}))
+	require.NoError(t, s.EnsureConfigEntry(9, &structs.ServiceConfigEntry{Kind: structs.ServiceDefaults, Name: "web", Protocol: "http"}))
+	require.NoError(t, s.EnsureConfigEntry(10, &structs.ServiceConfigEntry{Kind: structs.ServiceDefaults, Name: "db", Protocol: "tcp"}))
+
+	t.Run("catalog nodes", func(t *testing.T) {
+		out, err := run(s, "catalog/nodes", "Meta.env == prod")
+		require.NoError(t, err)
+		nodes := out.(structs.Nodes)
+		require.Len(t, nodes, 1)
+		require.Equal(t, "node1", nodes[0].Node)
+	})
+
+	t.Run("catalog service", func(t *testing.T) {
+		out, err := run(s, "catalog/service/web", "ServicePort == 81")
+		require.NoError(t, err)
+		nodes := out.(structs.ServiceNodes)
+		require.Len(t, nodes, 1)
+		require.Equal(t, "node2", nodes[0].Node)
+	})
+
+	t.Run("health state", func(t *testing.T) {
+		out, err := run(s, "health/state/critical", "")
+		require.NoError(t, err)
+		checks := out.(structs.HealthChecks)
+		require.Len(t, checks, 1)
+		require.Equal(t, "web2", checks[0].ServiceID)
+
+		_, err = run(s, "health/state/bogus", "")
+		require.ErrorContains(t, err, `Invalid check state "bogus"`)
+	})
+
+	t.Run("kv", func(t *testing.T) {
+		out, err := run(s, "kv/app/", "Flags == 2")
+		require.NoError(t, err)
+		entries := out.(structs.DirEntries)
+		require.Len(t, entries, 1)
+		require.Equal(t, "app/b", entries[0].Key)
+	})
+
+	t.Run("config entries", func(t *testing.T) {
+		out, err := run(s, "config/service-defaults", "Protocol == tcp")
+		require.NoError(t, err)
+		entries := out.([]structs.ConfigEntry)
+		require.Len(t, entries, 1)
+		require.Equal(t, "db", entries[0].GetName())
+
+		_, err = run(s, "config/bogus", "")
+		require.ErrorContains(t, err, `Invalid config entry kind "bogus"`)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := run(s, "catalog/nodes", "Bogus == 1")
+		require.ErrorContains(t, err, `Selector "Bogus" is not valid`)
+	})
+}
@@ -0,0 +1,106 @@
+package query
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/snapshot/internal/archive"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	help  string
+
+	// flags
+	filter string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.filter, "filter", "",
+		"Filter expression used to select the results, using the same syntax as "+
+			"the HTTP API's filter parameter.")
+
+	c.help = flags.Usage(fmt.Sprintf(help, strings.Join(paths(), "\n    ")), c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 2 {
+		c.UI.Error(fmt.Sprintf("Expected FILE and PATH arguments (got %d arguments)", len(args)))
+		return 1
+	}
+	file, path := args[0], args[1]
+
+	// Check the path before doing the more expensive work of loading the
+	// snapshot.
+	if _, _, err := findEndpoint(path); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	store, _, err := archive.LoadState(file)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	result, err := run(store, path, c.filter)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error querying snapshot: %s", err))
+		return 1
+	}
+
+	out, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error encoding results: %s", err))
+		return 1
+	}
+
+	c.UI.Output(string(out))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Queries the state stored in a Consul snapshot file"
+const help = `
+Usage: consul snapshot query [options] FILE PATH
+
+  Loads a snapshot file into memory and runs a catalog, health, KV or config
+  entry read against it without contacting a Consul agent. Results are
+  output as JSON in the same form as the corresponding HTTP API endpoint.
+
+  The supported paths are:
+
+    %s
+
+  To list the nodes registered in "backup.snap" with a given metadata value:
+
+    $ consul snapshot query -filter='Meta.env == prod' backup.snap catalog/nodes
+
+  For a full list of options and examples, please see the Consul documentation.
+`
@@ -0,0 +1,108 @@
+package query
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestSnapshotQueryCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestSnapshotQueryCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		output string
+	}{
+		"no args": {
+			[]string{},
+			"Expected FILE and PATH arguments (got 0 arguments)",
+		},
+		"no path": {
+			[]string{"foo"},
+			"Expected FILE and PATH arguments (got 1 arguments)",
+		},
+		"unsupported path": {
+			[]string{"foo", "agent/self"},
+			`Unsupported path "agent/self"`,
+		},
+		"missing file": {
+			[]string{filepath.Join(t.TempDir(), "missing.snap"), "catalog/nodes"},
+			"Error opening snapshot file",
+		},
+	}
+
+	for name, tc := range cases {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(tc.args)
+		if code == 0 {
+			t.Errorf("%s: expected non-zero exit", name)
+		}
+
+		output := ui.ErrorWriter.String()
+		if !strings.Contains(output, tc.output) {
+			t.Errorf("%s: expected %q to contain %q", name, output, tc.output)
+		}
+	}
+}
+
+func TestSnapshotQueryCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	_, err := client.Catalog().Register(&api.CatalogRegistration{
+		Node:     "external",
+		Address:  "10.1.2.3",
+		NodeMeta: map[string]string{"env": "prod"},
+		Service:  &api.AgentService{ID: "ext1", Service: "ext"},
+	}, nil)
+	require.NoError(t, err)
+
+	file := filepath.Join(testutil.TempDir(t, "snapshot"), "backup.snap")
+	{
+		snap, _, err := client.Snapshot().Save(nil)
+		require.NoError(t, err)
+		defer snap.Close()
+
+		f, err := os.Create(file)
+		require.NoError(t, err)
+		_, err = io.Copy(f, snap)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	// The agent is no longer needed once the snapshot is saved.
+	a.Shutdown()
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{"-filter=Meta.env == prod", file, "catalog/nodes"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	var nodes []*api.Node
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &nodes))
+	require.Len(t, nodes, 1)
+	require.Equal(t, "external", nodes[0].Node)
+	require.Equal(t, "10.1.2.3", nodes[0].Address)
+}
@@ -0,0 +1,220 @@
+package restore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/state"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	// maxTxnOps limits the number of KV entries written in a single
+	// transaction, keeping well under the limit enforced by the txn endpoint.
+	maxTxnOps = 64
+
+	// maxTxnBytes limits the total size of the values written in a single
+	// transaction so we stay well within the txn endpoint's request size limit.
+	maxTxnBytes = 256 * 1024
+)
+
+// selection describes the data to restore from a snapshot when performing a
+// selective restore.
+type selection struct {
+	kvPrefixes       []string
+	configEntryKinds []string
+	aclPolicies      []string
+}
+
+func (s *selection) empty() bool {
+	return len(s.kvPrefixes) == 0 && len(s.configEntryKinds) == 0 && len(s.aclPolicies) == 0
+}
+
+func (s *selection) validate() error {
+	for _, kind := range s.configEntryKinds {
+		if _, err := structs.MakeConfigEntry(kind, ""); err != nil {
+			return fmt.Errorf("invalid config entry kind %q", kind)
+		}
+	}
+	return nil
+}
+
+// restorePlan holds the records read from a snapshot which will be written
+// back to the cluster.
+type restorePlan struct {
+	kv            structs.DirEntries
+	configEntries []structs.ConfigEntry
+	aclPolicies   structs.ACLPolicies
+}
+
+// buildPlan reads the selected records from the state restored from a
+// snapshot.
+func buildPlan(s *state.Store, sel *selection) (*restorePlan, error) {
+	entMeta := structs.WildcardEnterpriseMetaInPartition(acl.WildcardPartitionName)
+	plan := &restorePlan{}
+
+	seen := make(map[string]struct{})
+	for _, prefix := range sel.kvPrefixes {
+		_, entries, err := s.KVSList(nil, prefix, entMeta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list KV entries under %q: %w", prefix, err)
+		}
+		for _, e := range entries {
+			// Overlapping prefixes could otherwise write the same key twice.
+			if _, ok := seen[e.Key]; ok {
+				continue
+			}
+			seen[e.Key] = struct{}{}
+			plan.kv = append(plan.kv, e)
+		}
+	}
+	sort.Slice(plan.kv, func(i, j int) bool { return plan.kv[i].Key < plan.kv[j].Key })
+
+	for _, kind := range sel.configEntryKinds {
+		_, entries, err := s.ConfigEntriesByKind(nil, kind, entMeta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s config entries: %w", kind, err)
+		}
+		plan.configEntries = append(plan.configEntries, entries...)
+	}
+
+	for _, name := range sel.aclPolicies {
+		_, policy, err := s.ACLPolicyGetByName(nil, name, entMeta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ACL policy %q: %w", name, err)
+		}
+		if policy == nil {
+			return nil, fmt.Errorf("ACL policy %q not found in snapshot", name)
+		}
+		plan.aclPolicies = append(plan.aclPolicies, policy)
+	}
+
+	return plan, nil
+}
+
+// describe returns a line for each record in the plan, used to preview a
+// restore.
+func (p *restorePlan) describe() []string {
+	var lines []string
+	for _, e := range p.kv {
+		lines = append(lines, "KV: "+e.Key)
+	}
+	for _, e := range p.configEntries {
+		lines = append(lines, fmt.Sprintf("Config entry: %s/%s", e.GetKind(), e.GetName()))
+	}
+	for _, p := range p.aclPolicies {
+		lines = append(lines, "ACL policy: "+p.Name)
+	}
+	return lines
+}
+
+func (p *restorePlan) summary() string {
+	return fmt.Sprintf("%d KV entries, %d config entries and %d ACL policies",
+		len(p.kv), len(p.configEntries), len(p.aclPolicies))
+}
+
+// apply writes the records in the plan to the cluster using the regular write
+// endpoints. KV entries are written in batched transactions, so each batch is
+// applied atomically but the restore as a whole is not.
+func (p *restorePlan) apply(client *api.Client) error {
+	for _, batch := range kvBatches(p.kv) {
+		ok, resp, _, err := client.Txn().Txn(batch, nil)
+		if err != nil {
+			return fmt.Errorf("failed to restore KV entries: %w", err)
+		}
+		if !ok {
+			var errs []string
+			for _, e := range resp.Errors {
+				errs = append(errs, e.What)
+			}
+			return fmt.Errorf("failed to restore KV entries: %s", strings.Join(errs, ", "))
+		}
+	}
+
+	for _, entry := range p.configEntries {
+		apiEntry, err := toAPIConfigEntry(entry)
+		if err != nil {
+			return err
+		}
+		if _, _, err := client.ConfigEntries().Set(apiEntry, nil); err != nil {
+			return fmt.Errorf("failed to restore config entry %s/%s: %w", entry.GetKind(), entry.GetName(), err)
+		}
+	}
+
+	for _, policy := range p.aclPolicies {
+		if err := restoreACLPolicy(client, policy); err != nil {
+			return fmt.Errorf("failed to restore ACL policy %q: %w", policy.Name, err)
+		}
+	}
+	return nil
+}
+
+func kvBatches(entries structs.DirEntries) []api.TxnOps {
+	var batches []api.TxnOps
+	var batch api.TxnOps
+	var size int
+	for _, e := range entries {
+		if len(batch) > 0 && (len(batch) == maxTxnOps || size+len(e.Value) > maxTxnBytes) {
+			batches = append(batches, batch)
+			batch, size = nil, 0
+		}
+		batch = append(batch, &api.TxnOp{
+			KV: &api.KVTxnOp{
+				Verb:      api.KVSet,
+				Key:       e.Key,
+				Value:     e.Value,
+				Flags:     e.Flags,
+				Namespace: e.EnterpriseMeta.NamespaceOrEmpty(),
+				Partition: e.EnterpriseMeta.PartitionOrEmpty(),
+			},
+		})
+		size += len(e.Value)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func toAPIConfigEntry(entry structs.ConfigEntry) (api.ConfigEntry, error) {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config entry %s/%s: %w", entry.GetKind(), entry.GetName(), err)
+	}
+	out, err := api.DecodeConfigEntryFromJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config entry %s/%s: %w", entry.GetKind(), entry.GetName(), err)
+	}
+	return out, nil
+}
+
+// restoreACLPolicy updates the policy with the same name if it exists in the
+// cluster, otherwise it is created.
+func restoreACLPolicy(client *api.Client, policy *structs.ACLPolicy) error {
+	out := &api.ACLPolicy{
+		Name:        policy.Name,
+		Description: policy.Description,
+		Rules:       policy.Rules,
+		Datacenters: policy.Datacenters,
+		Namespace:   policy.EnterpriseMeta.NamespaceOrEmpty(),
+		Partition:   policy.EnterpriseMeta.PartitionOrEmpty(),
+	}
+
+	opts := &api.QueryOptions{Namespace: out.Namespace, Partition: out.Partition}
+	existing, _, err := client.ACL().PolicyReadByName(policy.Name, opts)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		_, _, err = client.ACL().PolicyCreate(out, nil)
+		return err
+	}
+
+	out.ID = existing.ID
+	_, _, err = client.ACL().PolicyUpdate(out, nil)
+	return err
+}
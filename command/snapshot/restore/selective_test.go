@@ -0,0 +1,35 @@
+package restore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func TestKVBatches(t *testing.T) {
+	var entries structs.DirEntries
+	for i := 0; i < maxTxnOps+1; i++ {
+		entries = append(entries, &structs.DirEntry{Key: "small", Value: []byte("v")})
+	}
+	batches := kvBatches(entries)
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], maxTxnOps)
+	require.Len(t, batches[1], 1)
+
+	big := []byte(strings.Repeat("x", maxTxnBytes/2+1))
+	entries = structs.DirEntries{
+		{Key: "a", Value: big},
+		{Key: "b", Value: big},
+		{Key: "c", Value: []byte("v")},
+	}
+	batches = kvBatches(entries)
+	require.Len(t, batches, 2)
+	require.Equal(t, "a", batches[0][0].KV.Key)
+	require.Equal(t, "b", batches[1][0].KV.Key)
+	require.Equal(t, "c", batches[1][1].KV.Key)
+
+	require.Empty(t, kvBatches(nil))
+}
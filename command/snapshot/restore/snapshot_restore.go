@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/flags"
+	"github.com/hashicorp/consul/command/snapshot/internal/archive"
 	"github.com/mitchellh/cli"
 )
 
@@ -20,10 +22,31 @@ type cmd struct {
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
 	help  string
+
+	// flags
+	kvPrefixes       flags.AppendSliceValue
+	configEntryKinds flags.AppendSliceValue
+	aclPolicies      flags.AppendSliceValue
+	dryRun           bool
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.Var(&c.kvPrefixes, "kv-prefix",
+		"Restores only the KV entries under the given prefix by writing them to "+
+			"the cluster rather than replacing its entire state. An empty prefix "+
+			"selects all entries. This flag may be specified multiple times.")
+	c.flags.Var(&c.configEntryKinds, "config-entry-kind",
+		"Restores only the config entries of the given kind by writing them to "+
+			"the cluster rather than replacing its entire state. This flag may be "+
+			"specified multiple times.")
+	c.flags.Var(&c.aclPolicies, "acl-policy",
+		"Restores only the ACL policy with the given name by writing it to the "+
+			"cluster rather than replacing its entire state. This flag may be "+
+			"specified multiple times.")
+	c.flags.BoolVar(&c.dryRun, "dry-run", false,
+		"Lists the data a selective restore would write without writing it. Can "+
+			"only be used with -kv-prefix, -config-entry-kind or -acl-policy.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -49,6 +72,20 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	sel := &selection{
+		kvPrefixes:       c.kvPrefixes,
+		configEntryKinds: c.configEntryKinds,
+		aclPolicies:      c.aclPolicies,
+	}
+	if sel.empty() && c.dryRun {
+		c.UI.Error("The -dry-run flag can only be used with -kv-prefix, -config-entry-kind or -acl-policy")
+		return 1
+	}
+	if err := sel.validate(); err != nil {
+		c.UI.Error(fmt.Sprintf("Error: %s", err))
+		return 1
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -56,6 +93,10 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if !sel.empty() {
+		return c.restoreSelected(client, file, sel)
+	}
+
 	// Open the file.
 	f, err := os.Open(file)
 	if err != nil {
@@ -75,6 +116,38 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// restoreSelected restores the selected data from the snapshot by replaying
+// it as regular writes, leaving the rest of the cluster's state untouched.
+func (c *cmd) restoreSelected(client *api.Client, file string, sel *selection) int {
+	store, _, err := archive.LoadState(file)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	plan, err := buildPlan(store, sel)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading snapshot: %s", err))
+		return 1
+	}
+
+	if c.dryRun {
+		for _, line := range plan.describe() {
+			c.UI.Output(line)
+		}
+		c.UI.Info(fmt.Sprintf("Dry run: would restore %s", plan.summary()))
+		return 0
+	}
+
+	if err := plan.apply(client); err != nil {
+		c.UI.Error(fmt.Sprintf("Error restoring snapshot: %s", err))
+		return 1
+	}
+
+	c.UI.Info(fmt.Sprintf("Restored %s", plan.summary()))
+	return 0
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -103,5 +176,11 @@ Usage: consul snapshot restore [options] FILE
 
     $ consul snapshot restore backup.snap
 
+  Selected data can instead be restored into a live cluster by writing it
+  through the regular KV, config entry and ACL endpoints, leaving all other
+  state untouched. To preview restoring the KV entries under "app/":
+
+    $ consul snapshot restore -kv-prefix=app/ -dry-run backup.snap
+
   For a full list of options and examples, please see the Consul documentation.
 `
@@ -63,6 +63,37 @@ func TestSnapshotRestoreCommand_Validation(t *testing.T) {
 	}
 }
 
+func TestSnapshotRestoreCommand_SelectiveValidation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		output string
+	}{
+		"dry run without selection": {
+			[]string{"-dry-run", "foo"},
+			"The -dry-run flag can only be used with",
+		},
+		"invalid config entry kind": {
+			[]string{"-config-entry-kind=bogus", "foo"},
+			`invalid config entry kind "bogus"`,
+		},
+	}
+
+	for name, tc := range cases {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(tc.args)
+		if code == 0 {
+			t.Errorf("%s: expected non-zero exit", name)
+		}
+
+		output := ui.ErrorWriter.String()
+		if !strings.Contains(output, tc.output) {
+			t.Errorf("%s: expected %q to contain %q", name, output, tc.output)
+		}
+	}
+}
+
 func TestSnapshotRestoreCommand(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -164,3 +195,93 @@ func TestSnapshotRestoreCommand_TruncatedSnapshot(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotRestoreCommand_Selective(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+	kv := client.KV()
+
+	for _, key := range []string{"app/a", "app/b", "other"} {
+		_, err := kv.Put(&api.KVPair{Key: key, Value: []byte("old"), Flags: 42}, nil)
+		require.NoError(t, err)
+	}
+	_, _, err := client.ConfigEntries().Set(&api.ServiceConfigEntry{
+		Kind:     api.ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+	}, nil)
+	require.NoError(t, err)
+
+	file := filepath.Join(testutil.TempDir(t, "snapshot"), "backup.snap")
+	{
+		snap, _, err := client.Snapshot().Save(nil)
+		require.NoError(t, err)
+		defer snap.Close()
+
+		f, err := os.Create(file)
+		require.NoError(t, err)
+		_, err = io.Copy(f, snap)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	_, err = kv.DeleteTree("app/", nil)
+	require.NoError(t, err)
+	_, err = kv.Put(&api.KVPair{Key: "other", Value: []byte("new")}, nil)
+	require.NoError(t, err)
+	_, err = client.ConfigEntries().Delete(api.ServiceDefaults, "web", nil)
+	require.NoError(t, err)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-kv-prefix=app/",
+		"-config-entry-kind=" + api.ServiceDefaults,
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(append(args, "-dry-run", file))
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		out := ui.OutputWriter.String()
+		require.Contains(t, out, "KV: app/a")
+		require.Contains(t, out, "KV: app/b")
+		require.Contains(t, out, "Config entry: service-defaults/web")
+		require.NotContains(t, out, "other")
+		require.Contains(t, out, "would restore 2 KV entries, 1 config entries and 0 ACL policies")
+
+		keys, _, err := kv.Keys("app/", "", nil)
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run(append(args, file))
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "Restored 2 KV entries, 1 config entries and 0 ACL policies")
+
+		for _, key := range []string{"app/a", "app/b"} {
+			pair, _, err := kv.Get(key, nil)
+			require.NoError(t, err)
+			require.NotNil(t, pair, key)
+			require.Equal(t, []byte("old"), pair.Value)
+			require.Equal(t, uint64(42), pair.Flags)
+		}
+
+		// Data outside of the selection is left untouched.
+		pair, _, err := kv.Get("other", nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte("new"), pair.Value)
+
+		entry, _, err := client.ConfigEntries().Get(api.ServiceDefaults, "web", nil)
+		require.NoError(t, err)
+		require.Equal(t, "http", entry.(*api.ServiceConfigEntry).Protocol)
+	})
+}
@@ -47,6 +47,14 @@ Usage: consul snapshot <subcommand> [options] [args]
 
       $ consul snapshot inspect backup.snap
 
+  Compare two snapshots:
+
+      $ consul snapshot diff before.snap after.snap
+
+  Query the catalog stored in a snapshot:
+
+      $ consul snapshot query backup.snap catalog/nodes
+
   Run a daemon process that locally saves a snapshot every hour (available only in
   Consul Enterprise) :
 
@@ -9,6 +9,7 @@ import (
 	osexec "os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/agent/exec"
@@ -39,35 +40,65 @@ type cmd struct {
 	service     string
 	tag         []string
 	passingOnly string
+	streaming   bool
 	state       string
 	name        string
+	kind        string
+	destination string
 	shell       bool
+	debounce    time.Duration
+	maxWait     time.Duration
+	minInterval time.Duration
+	coalesce    bool
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.StringVar(&c.watchType, "type", "",
 		"Specifies the watch type. One of key, keyprefix, services, nodes, "+
-			"service, checks, or event.")
+			"service, checks, event, config_entry, intentions, connect_roots, "+
+			"connect_leaf, or peerings.")
 	c.flags.StringVar(&c.key, "key", "",
 		"Specifies the key to watch. Only for 'key' type.")
 	c.flags.StringVar(&c.prefix, "prefix", "",
 		"Specifies the key prefix to watch. Only for 'keyprefix' type.")
 	c.flags.StringVar(&c.service, "service", "",
-		"Specifies the service to watch. Required for 'service' type, "+
-			"optional for 'checks' type.")
+		"Specifies the service to watch. Required for 'service' and "+
+			"'connect_leaf' types, optional for 'checks' type.")
 	c.flags.Var((*flags.AppendSliceValue)(&c.tag), "tag", "Specifies the service tag(s) to filter on. "+
 		"Optional for 'service' type. May be specified multiple times")
 	c.flags.StringVar(&c.passingOnly, "passingonly", "",
 		"Specifies if only hosts passing all checks are displayed. "+
 			"Optional for 'service' type, must be one of `[true|false]`. Defaults false.")
+	c.flags.BoolVar(&c.streaming, "streaming", false,
+		"Requires the watch to be served from the agent's streaming view of the "+
+			"service's health rather than blocking queries against the servers. "+
+			"Optional for 'service' type. The watch errors and retries if "+
+			"use_streaming_backend isn't enabled on the agent.")
 	c.flags.BoolVar(&c.shell, "shell", true,
 		"Use a shell to run the command (can set a custom shell via the SHELL "+
 			"environment variable).")
 	c.flags.StringVar(&c.state, "state", "",
 		"Specifies the states to watch. Optional for 'checks' type.")
 	c.flags.StringVar(&c.name, "name", "",
-		"Specifies an event name to watch. Only for 'event' type.")
+		"Specifies an event name to watch for 'event' type, or a config entry "+
+			"name to watch for 'config_entry' type.")
+	c.flags.StringVar(&c.kind, "kind", "",
+		"Specifies the config entry kind to watch. Required for 'config_entry' type.")
+	c.flags.StringVar(&c.destination, "destination", "",
+		"Specifies the destination service to watch intentions for. Optional "+
+			"for 'intentions' type.")
+	c.flags.DurationVar(&c.debounce, "debounce", 0,
+		"Waits until the watched data has been unchanged for this duration "+
+			"before invoking the handler with the latest data. Implies -coalesce.")
+	c.flags.DurationVar(&c.maxWait, "max-wait", 0,
+		"Maximum duration continuous changes can delay the handler. Requires "+
+			"-debounce.")
+	c.flags.DurationVar(&c.minInterval, "min-interval", 0,
+		"Minimum duration between handler invocations.")
+	c.flags.BoolVar(&c.coalesce, "coalesce", false,
+		"Invokes the handler with only the latest data when several changes are "+
+			"pending, instead of once for each change in order.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -137,6 +168,12 @@ func (c *cmd) Run(args []string) int {
 	if c.name != "" {
 		params["name"] = c.name
 	}
+	if c.kind != "" {
+		params["kind"] = c.kind
+	}
+	if c.destination != "" {
+		params["destination"] = c.destination
+	}
 	if c.passingOnly != "" {
 		b, err := strconv.ParseBool(c.passingOnly)
 		if err != nil {
@@ -145,6 +182,21 @@ func (c *cmd) Run(args []string) int {
 		}
 		params["passingonly"] = b
 	}
+	if c.streaming {
+		params["streaming"] = true
+	}
+	if c.debounce != 0 {
+		params["debounce"] = c.debounce.String()
+	}
+	if c.maxWait != 0 {
+		params["max_wait"] = c.maxWait.String()
+	}
+	if c.minInterval != 0 {
+		params["min_interval"] = c.minInterval.String()
+	}
+	if c.coalesce {
+		params["coalesce"] = true
+	}
 
 	// Create the watch
 	wp, err := consulwatch.Parse(params)
@@ -153,7 +205,7 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	if strings.HasPrefix(wp.Type, "connect_") || strings.HasPrefix(wp.Type, "agent_") {
+	if strings.HasPrefix(wp.Type, "agent_") {
 		c.UI.Error(fmt.Sprintf("Type %s is not supported in the CLI tool", wp.Type))
 		return 1
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
@@ -44,6 +45,37 @@ func TestWatchCommand(t *testing.T) {
 	}
 }
 
+func TestWatchCommand_Streaming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, `use_streaming_backend = true`)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	t.Run("service", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui, nil)
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-type=service", "-service=consul", "-streaming"}
+
+		code := c.Run(args)
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), a.Config.NodeName)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui, nil)
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-type=nodes", "-streaming"}
+
+		code := c.Run(args)
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Invalid parameters: [streaming]")
+	})
+}
+
 func TestWatchCommand_loadToken(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -141,7 +173,7 @@ func TestWatchCommand_loadToken(t *testing.T) {
 	})
 }
 
-func TestWatchCommandNoConnect(t *testing.T) {
+func TestWatchCommand_ConnectRoots(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
 	}
@@ -149,21 +181,44 @@ func TestWatchCommandNoConnect(t *testing.T) {
 	t.Parallel()
 	a := agent.NewTestAgent(t, ``)
 	defer a.Shutdown()
-	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	testrpc.WaitForActiveCARoot(t, a.RPC, "dc1", nil)
 
 	ui := cli.NewMockUi()
 	c := New(ui, nil)
-	args := []string{"-http-addr=" + a.HTTPAddr(), "-type=connect_leaf"}
+	args := []string{"-http-addr=" + a.HTTPAddr(), "-type=connect_roots"}
 
 	code := c.Run(args)
-	if code != 1 {
-		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
-	}
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "ActiveRootID")
+}
 
-	if !strings.Contains(ui.ErrorWriter.String(),
-		"Type connect_leaf is not supported in the CLI tool") {
-		t.Fatalf("bad: %#v", ui.ErrorWriter.String())
+func TestWatchCommand_ConfigEntry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
 	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, `
+		config_entries {
+			bootstrap {
+				kind = "service-defaults"
+				name = "web"
+				protocol = "http"
+			}
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	retry.Run(t, func(r *retry.R) {
+		ui := cli.NewMockUi()
+		c := New(ui, nil)
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-type=config_entry", "-kind=service-defaults", "-name=web"}
+
+		code := c.Run(args)
+		require.Equal(r, 0, code, ui.ErrorWriter.String())
+		require.Contains(r, ui.OutputWriter.String(), `"Protocol": "http"`)
+	})
 }
 
 func TestWatchCommandNoAgentService(t *testing.T) {
@@ -189,3 +244,33 @@ func TestWatchCommandNoAgentService(t *testing.T) {
 		t.Fatalf("bad: %#v", ui.ErrorWriter.String())
 	}
 }
+
+func TestWatchCommand_Debounce(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	ui := cli.NewMockUi()
+	c := New(ui, nil)
+	args := []string{"-http-addr=" + a.HTTPAddr(), "-type=nodes", "-debounce=50ms", "-coalesce"}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), a.Config.NodeName)
+}
+
+func TestWatchCommand_MaxWaitRequiresDebounce(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui, nil)
+	args := []string{"-type=nodes", "-max-wait=10s"}
+
+	code := c.Run(args)
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "max_wait requires debounce to be set")
+}
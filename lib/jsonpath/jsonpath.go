@@ -0,0 +1,100 @@
+// Package jsonpath implements the subset of JSONPath supported by HTTP check
+// assertions.
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a parsed JSONPath expression.
+type Path []segment
+
+// segment is a single step of a JSONPath expression, either a member of an
+// object or an index into an array.
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Parse parses the subset of JSONPath supported by HTTP checks: the root ($)
+// followed by members (.name or ['name']) and array indexes ([0]). Negative
+// indexes count back from the end of an array.
+func Parse(path string) (Path, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", path)
+	}
+
+	var segments Path
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty member name", path)
+			}
+			segments = append(segments, segment{key: key})
+			rest = rest[end+1:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q has an unterminated [", path)
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, segment{key: inner[1 : len(inner)-1]})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("JSONPath %q has an invalid index %q", path, inner)
+				}
+				segments = append(segments, segment{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("JSONPath %q has an unexpected %q", path, rest[0])
+		}
+	}
+	return segments, nil
+}
+
+// Eval returns the value at the path within a decoded JSON document, and
+// whether it exists.
+func (p Path) Eval(doc interface{}) (interface{}, bool) {
+	v := doc
+	for _, s := range p {
+		if s.isIndex {
+			arr, ok := v.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i < 0 || i >= len(arr) {
+				return nil, false
+			}
+			v = arr[i]
+			continue
+		}
+
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[s.key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
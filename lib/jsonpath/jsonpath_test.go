@@ -0,0 +1,50 @@
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b.c": []interface{}{"x", "y"},
+		},
+	}
+
+	tests := []struct {
+		path   string
+		want   interface{}
+		exists bool
+		err    string
+	}{
+		{path: "$", want: doc, exists: true},
+		{path: "$['a']['b.c'][0]", want: "x", exists: true},
+		{path: `$.a["b.c"][-1]`, want: "y", exists: true},
+		{path: "$.a['b.c'][2]"},
+		{path: "$.a.b"},
+		{path: "$.a[0]"},
+		{path: "a.b", err: "must start with $"},
+		{path: "$..a", err: "empty member name"},
+		{path: "$.a[0", err: "unterminated ["},
+		{path: "$.a[x]", err: "invalid index"},
+		{path: "$a", err: "unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := Parse(tt.path)
+			if tt.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			got, ok := path.Eval(doc)
+			require.Equal(t, tt.exists, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
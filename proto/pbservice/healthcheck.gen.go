@@ -20,16 +20,39 @@ func CheckTypeToStructs(s *CheckType, t *structs.CheckType) {
 	t.Method = s.Method
 	t.Body = s.Body
 	t.DisableRedirects = s.DisableRedirects
+	t.ResponseBodyRegex = s.ResponseBodyRegex
+	t.ResponseJSONPath = s.ResponseJSONPath
+	t.ResponseJSONValue = s.ResponseJSONValue
+	t.ResponseHeaders = MapHeadersToStructs(s.ResponseHeaders)
+	t.ResponseTimeWarning = structs.DurationFromProto(s.ResponseTimeWarning)
 	t.TCP = s.TCP
 	t.UDP = s.UDP
 	t.Interval = structs.DurationFromProto(s.Interval)
 	t.AliasNode = s.AliasNode
 	t.AliasService = s.AliasService
+	t.AggregateCheckIDs = s.AggregateCheckIDs
+	t.AggregateService = s.AggregateService
+	t.AggregateServiceTag = s.AggregateServiceTag
+	t.AggregateRule = s.AggregateRule
+	t.AggregateThreshold = int(s.AggregateThreshold)
 	t.DockerContainerID = s.DockerContainerID
 	t.Shell = s.Shell
 	t.GRPC = s.GRPC
 	t.GRPCUseTLS = s.GRPCUseTLS
 	t.OSService = s.OSService
+	t.DNS = s.DNS
+	t.DNSQuery = s.DNSQuery
+	t.DNSRecordType = s.DNSRecordType
+	t.DNSRcode = s.DNSRcode
+	t.DNSMinAnswers = int(s.DNSMinAnswers)
+	t.DNSExpectedValues = s.DNSExpectedValues
+	t.DNSUseTCP = s.DNSUseTCP
+	t.TLS = s.TLS
+	t.TLSStartTLS = s.TLSStartTLS
+	t.TLSCAFile = s.TLSCAFile
+	t.TLSExpectedSANs = s.TLSExpectedSANs
+	t.TLSExpiryWarning = structs.DurationFromProto(s.TLSExpiryWarning)
+	t.TLSExpiryCritical = structs.DurationFromProto(s.TLSExpiryCritical)
 	t.TLSServerName = s.TLSServerName
 	t.TLSSkipVerify = s.TLSSkipVerify
 	t.Timeout = structs.DurationFromProto(s.Timeout)
@@ -37,6 +60,8 @@ func CheckTypeToStructs(s *CheckType, t *structs.CheckType) {
 	t.SuccessBeforePassing = int(s.SuccessBeforePassing)
 	t.FailuresBeforeWarning = int(s.FailuresBeforeWarning)
 	t.FailuresBeforeCritical = int(s.FailuresBeforeCritical)
+	t.FlapThreshold = int(s.FlapThreshold)
+	t.FlapWindow = structs.DurationFromProto(s.FlapWindow)
 	t.ProxyHTTP = s.ProxyHTTP
 	t.ProxyGRPC = s.ProxyGRPC
 	t.DeregisterCriticalServiceAfter = structs.DurationFromProto(s.DeregisterCriticalServiceAfter)
@@ -58,16 +83,39 @@ func CheckTypeFromStructs(t *structs.CheckType, s *CheckType) {
 	s.Method = t.Method
 	s.Body = t.Body
 	s.DisableRedirects = t.DisableRedirects
+	s.ResponseBodyRegex = t.ResponseBodyRegex
+	s.ResponseJSONPath = t.ResponseJSONPath
+	s.ResponseJSONValue = t.ResponseJSONValue
+	s.ResponseHeaders = NewMapHeadersFromStructs(t.ResponseHeaders)
+	s.ResponseTimeWarning = structs.DurationToProto(t.ResponseTimeWarning)
 	s.TCP = t.TCP
 	s.UDP = t.UDP
 	s.Interval = structs.DurationToProto(t.Interval)
 	s.AliasNode = t.AliasNode
 	s.AliasService = t.AliasService
+	s.AggregateCheckIDs = t.AggregateCheckIDs
+	s.AggregateService = t.AggregateService
+	s.AggregateServiceTag = t.AggregateServiceTag
+	s.AggregateRule = t.AggregateRule
+	s.AggregateThreshold = int32(t.AggregateThreshold)
 	s.DockerContainerID = t.DockerContainerID
 	s.Shell = t.Shell
 	s.GRPC = t.GRPC
 	s.GRPCUseTLS = t.GRPCUseTLS
 	s.OSService = t.OSService
+	s.DNS = t.DNS
+	s.DNSQuery = t.DNSQuery
+	s.DNSRecordType = t.DNSRecordType
+	s.DNSRcode = t.DNSRcode
+	s.DNSMinAnswers = int32(t.DNSMinAnswers)
+	s.DNSExpectedValues = t.DNSExpectedValues
+	s.DNSUseTCP = t.DNSUseTCP
+	s.TLS = t.TLS
+	s.TLSStartTLS = t.TLSStartTLS
+	s.TLSCAFile = t.TLSCAFile
+	s.TLSExpectedSANs = t.TLSExpectedSANs
+	s.TLSExpiryWarning = structs.DurationToProto(t.TLSExpiryWarning)
+	s.TLSExpiryCritical = structs.DurationToProto(t.TLSExpiryCritical)
 	s.TLSServerName = t.TLSServerName
 	s.TLSSkipVerify = t.TLSSkipVerify
 	s.Timeout = structs.DurationToProto(t.Timeout)
@@ -75,6 +123,8 @@ func CheckTypeFromStructs(t *structs.CheckType, s *CheckType) {
 	s.SuccessBeforePassing = int32(t.SuccessBeforePassing)
 	s.FailuresBeforeWarning = int32(t.FailuresBeforeWarning)
 	s.FailuresBeforeCritical = int32(t.FailuresBeforeCritical)
+	s.FlapThreshold = int32(t.FlapThreshold)
+	s.FlapWindow = structs.DurationToProto(t.FlapWindow)
 	s.ProxyHTTP = t.ProxyHTTP
 	s.ProxyGRPC = t.ProxyGRPC
 	s.DeregisterCriticalServiceAfter = structs.DurationToProto(t.DeregisterCriticalServiceAfter)
@@ -141,10 +191,28 @@ func HealthCheckDefinitionToStructs(s *HealthCheckDefinition, t *structs.HealthC
 	t.Method = s.Method
 	t.Body = s.Body
 	t.DisableRedirects = s.DisableRedirects
+	t.ResponseBodyRegex = s.ResponseBodyRegex
+	t.ResponseJSONPath = s.ResponseJSONPath
+	t.ResponseJSONValue = s.ResponseJSONValue
+	t.ResponseHeaders = MapHeadersToStructs(s.ResponseHeaders)
+	t.ResponseTimeWarning = structs.DurationFromProto(s.ResponseTimeWarning)
 	t.TCP = s.TCP
 	t.UDP = s.UDP
 	t.H2PING = s.H2PING
 	t.OSService = s.OSService
+	t.DNS = s.DNS
+	t.DNSQuery = s.DNSQuery
+	t.DNSRecordType = s.DNSRecordType
+	t.DNSRcode = s.DNSRcode
+	t.DNSMinAnswers = int(s.DNSMinAnswers)
+	t.DNSExpectedValues = s.DNSExpectedValues
+	t.DNSUseTCP = s.DNSUseTCP
+	t.TLS = s.TLS
+	t.TLSStartTLS = s.TLSStartTLS
+	t.TLSCAFile = s.TLSCAFile
+	t.TLSExpectedSANs = s.TLSExpectedSANs
+	t.TLSExpiryWarning = structs.DurationFromProto(s.TLSExpiryWarning)
+	t.TLSExpiryCritical = structs.DurationFromProto(s.TLSExpiryCritical)
 	t.H2PingUseTLS = s.H2PingUseTLS
 	t.Interval = structs.DurationFromProto(s.Interval)
 	t.OutputMaxSize = uint(s.OutputMaxSize)
@@ -157,6 +225,11 @@ func HealthCheckDefinitionToStructs(s *HealthCheckDefinition, t *structs.HealthC
 	t.GRPCUseTLS = s.GRPCUseTLS
 	t.AliasNode = s.AliasNode
 	t.AliasService = s.AliasService
+	t.AggregateCheckIDs = s.AggregateCheckIDs
+	t.AggregateService = s.AggregateService
+	t.AggregateServiceTag = s.AggregateServiceTag
+	t.AggregateRule = s.AggregateRule
+	t.AggregateThreshold = int(s.AggregateThreshold)
 	t.TTL = structs.DurationFromProto(s.TTL)
 }
 func HealthCheckDefinitionFromStructs(t *structs.HealthCheckDefinition, s *HealthCheckDefinition) {
@@ -170,10 +243,28 @@ func HealthCheckDefinitionFromStructs(t *structs.HealthCheckDefinition, s *Healt
 	s.Method = t.Method
 	s.Body = t.Body
 	s.DisableRedirects = t.DisableRedirects
+	s.ResponseBodyRegex = t.ResponseBodyRegex
+	s.ResponseJSONPath = t.ResponseJSONPath
+	s.ResponseJSONValue = t.ResponseJSONValue
+	s.ResponseHeaders = NewMapHeadersFromStructs(t.ResponseHeaders)
+	s.ResponseTimeWarning = structs.DurationToProto(t.ResponseTimeWarning)
 	s.TCP = t.TCP
 	s.UDP = t.UDP
 	s.H2PING = t.H2PING
 	s.OSService = t.OSService
+	s.DNS = t.DNS
+	s.DNSQuery = t.DNSQuery
+	s.DNSRecordType = t.DNSRecordType
+	s.DNSRcode = t.DNSRcode
+	s.DNSMinAnswers = int32(t.DNSMinAnswers)
+	s.DNSExpectedValues = t.DNSExpectedValues
+	s.DNSUseTCP = t.DNSUseTCP
+	s.TLS = t.TLS
+	s.TLSStartTLS = t.TLSStartTLS
+	s.TLSCAFile = t.TLSCAFile
+	s.TLSExpectedSANs = t.TLSExpectedSANs
+	s.TLSExpiryWarning = structs.DurationToProto(t.TLSExpiryWarning)
+	s.TLSExpiryCritical = structs.DurationToProto(t.TLSExpiryCritical)
 	s.H2PingUseTLS = t.H2PingUseTLS
 	s.Interval = structs.DurationToProto(t.Interval)
 	s.OutputMaxSize = uint32(t.OutputMaxSize)
@@ -186,5 +277,10 @@ func HealthCheckDefinitionFromStructs(t *structs.HealthCheckDefinition, s *Healt
 	s.GRPCUseTLS = t.GRPCUseTLS
 	s.AliasNode = t.AliasNode
 	s.AliasService = t.AliasService
+	s.AggregateCheckIDs = t.AggregateCheckIDs
+	s.AggregateService = t.AggregateService
+	s.AggregateServiceTag = t.AggregateServiceTag
+	s.AggregateRule = t.AggregateRule
+	s.AggregateThreshold = int32(t.AggregateThreshold)
 	s.TTL = structs.DurationToProto(t.TTL)
 }